@@ -3,8 +3,10 @@ module github.com/traas-stack/chaosmeta/chaosmeta-common
 go 1.19
 
 require (
+	github.com/go-logr/logr v1.2.3
 	k8s.io/api v0.26.0
 	k8s.io/apimachinery v0.26.3
+	k8s.io/client-go v0.26.0
 	sigs.k8s.io/controller-runtime v0.14.1
 )
 
@@ -15,7 +17,6 @@ require (
 	github.com/emicklei/go-restful/v3 v3.9.0 // indirect
 	github.com/evanphx/json-patch/v5 v5.6.0 // indirect
 	github.com/fsnotify/fsnotify v1.6.0 // indirect
-	github.com/go-logr/logr v1.2.3 // indirect
 	github.com/go-logr/zapr v1.2.3 // indirect
 	github.com/go-openapi/jsonpointer v0.19.5 // indirect
 	github.com/go-openapi/jsonreference v0.20.0 // indirect
@@ -0,0 +1,187 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	PolicyTimeFormat  = "2006-01-02 15:04:05"
+	PolicyDailyFormat = "15:04"
+	AllFaults         = "*"
+)
+
+// Contains checks whether t is in the window
+func (w *TimeWindow) Contains(t time.Time) (bool, error) {
+	if start, err := time.ParseInLocation(PolicyTimeFormat, w.Start, t.Location()); err == nil {
+		end, err := time.ParseInLocation(PolicyTimeFormat, w.End, t.Location())
+		if err != nil {
+			return false, fmt.Errorf("end[%s] of one-time window is invalid: %s", w.End, err.Error())
+		}
+
+		return !t.Before(start) && t.Before(end), nil
+	}
+
+	start, err := time.Parse(PolicyDailyFormat, w.Start)
+	if err != nil {
+		return false, fmt.Errorf("start[%s] of window is invalid, only support \"%s\" or \"%s\"", w.Start, PolicyTimeFormat, PolicyDailyFormat)
+	}
+
+	end, err := time.Parse(PolicyDailyFormat, w.End)
+	if err != nil {
+		return false, fmt.Errorf("end[%s] of daily window is invalid: %s", w.End, err.Error())
+	}
+
+	var (
+		startMinute = start.Hour()*60 + start.Minute()
+		endMinute   = end.Hour()*60 + end.Minute()
+		nowMinute   = t.Hour()*60 + t.Minute()
+		day         = t.Weekday()
+		isIn        bool
+	)
+
+	if startMinute <= endMinute {
+		isIn = nowMinute >= startMinute && nowMinute < endMinute
+	} else {
+		// cross midnight, the part after midnight belongs to the window started yesterday
+		if nowMinute < endMinute {
+			isIn, day = true, (day+6)%7
+		} else {
+			isIn = nowMinute >= startMinute
+		}
+	}
+
+	if !isIn || len(w.Weekdays) == 0 {
+		return isIn, nil
+	}
+
+	for _, unitDay := range w.Weekdays {
+		if strings.EqualFold(unitDay, day.String()[:3]) || strings.EqualFold(unitDay, day.String()) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// CheckTime return error when t is in any blackout window
+func (s *ChaosPolicySpec) CheckTime(t time.Time) error {
+	for _, w := range s.BlackoutWindows {
+		isIn, err := w.Contains(t)
+		if err != nil {
+			return fmt.Errorf("blackout window is invalid: %s", err.Error())
+		}
+
+		if isIn {
+			return fmt.Errorf("now is in blackout window[%s, %s)", w.Start, w.End)
+		}
+	}
+
+	return nil
+}
+
+// CheckFault return error when the namespace limits faults and target/fault is not allowed
+func (s *ChaosPolicySpec) CheckFault(namespace, target, fault string) error {
+	for _, unitNs := range s.AllowedFaults {
+		if unitNs.Namespace != namespace {
+			continue
+		}
+
+		for _, allowed := range unitNs.Faults {
+			if allowed == AllFaults || allowed == fmt.Sprintf("%s/%s", target, fault) || allowed == fmt.Sprintf("%s/%s", target, AllFaults) {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("fault %s/%s is not allowed in namespace %s", target, fault, namespace)
+	}
+
+	return nil
+}
+
+func (s *ChaosPolicySpec) IsProtectedNamespace(namespace string) bool {
+	for _, unitNs := range s.ProtectedNamespaces {
+		if unitNs == namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetProtectedLabel return the first protected label like "k=v" in labels, return "" if not found
+func (s *ChaosPolicySpec) GetProtectedLabel(labels map[string]string) string {
+	for k, v := range s.ProtectedLabels {
+		if realV, ok := labels[k]; ok && realV == v {
+			return fmt.Sprintf("%s%s%s", k, LabelListSplit, v)
+		}
+	}
+
+	return ""
+}
+
+// CheckExperiment checks the experiment before selecting targets, namespaces and labels come from the selector
+func (s *ChaosPolicySpec) CheckExperiment(spec *ExperimentSpec, t time.Time) error {
+	if err := s.CheckTime(t); err != nil {
+		return err
+	}
+
+	if spec.Experiment == nil {
+		return nil
+	}
+
+	for _, unitSelector := range spec.Selector {
+		namespaces := []string{unitSelector.Namespace}
+		if spec.Scope == KubernetesScopeType && CloudTargetType(spec.Experiment.Target) == NamespaceCloudTarget {
+			namespaces = unitSelector.Name
+		}
+
+		for _, ns := range namespaces {
+			if ns == "" {
+				continue
+			}
+
+			if s.IsProtectedNamespace(ns) {
+				return fmt.Errorf("namespace %s is protected", ns)
+			}
+
+			if err := s.CheckFault(ns, spec.Experiment.Target, spec.Experiment.Fault); err != nil {
+				return err
+			}
+		}
+
+		if label := s.GetProtectedLabel(unitSelector.Label); label != "" {
+			return fmt.Errorf("label %s in selector is protected", label)
+		}
+	}
+
+	return nil
+}
+
+// CheckExperiment checks the experiment with all policies
+func (l *ChaosPolicyList) CheckExperiment(spec *ExperimentSpec, t time.Time) error {
+	for _, policy := range l.Items {
+		if err := policy.Spec.CheckExperiment(spec, t); err != nil {
+			return fmt.Errorf("rejected by ChaosPolicy[%s]: %s", policy.Name, err.Error())
+		}
+	}
+
+	return nil
+}
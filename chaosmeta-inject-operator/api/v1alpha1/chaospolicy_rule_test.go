@@ -0,0 +1,178 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeWindow_Contains(t *testing.T) {
+	// 2023-11-11 is Saturday
+	loc := time.Local
+	tests := []struct {
+		name    string
+		window  TimeWindow
+		t       time.Time
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:   "one-time in",
+			window: TimeWindow{Start: "2023-11-11 00:00:00", End: "2023-11-12 00:00:00"},
+			t:      time.Date(2023, 11, 11, 12, 0, 0, 0, loc),
+			want:   true,
+		},
+		{
+			name:   "one-time end is excluded",
+			window: TimeWindow{Start: "2023-11-11 00:00:00", End: "2023-11-12 00:00:00"},
+			t:      time.Date(2023, 11, 12, 0, 0, 0, 0, loc),
+			want:   false,
+		},
+		{
+			name:   "daily in",
+			window: TimeWindow{Start: "09:00", End: "18:00"},
+			t:      time.Date(2023, 11, 13, 10, 30, 0, 0, loc),
+			want:   true,
+		},
+		{
+			name:   "daily out",
+			window: TimeWindow{Start: "09:00", End: "18:00"},
+			t:      time.Date(2023, 11, 13, 18, 0, 0, 0, loc),
+			want:   false,
+		},
+		{
+			name:   "daily cross midnight belongs to yesterday",
+			window: TimeWindow{Start: "22:00", End: "02:00", Weekdays: []string{"Sat"}},
+			t:      time.Date(2023, 11, 12, 1, 0, 0, 0, loc),
+			want:   true,
+		},
+		{
+			name:   "daily weekday not match",
+			window: TimeWindow{Start: "22:00", End: "02:00", Weekdays: []string{"Saturday"}},
+			t:      time.Date(2023, 11, 12, 23, 0, 0, 0, loc),
+			want:   false,
+		},
+		{
+			name:    "invalid start",
+			window:  TimeWindow{Start: "9am", End: "18:00"},
+			t:       time.Date(2023, 11, 13, 10, 30, 0, 0, loc),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.window.Contains(tt.t)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Contains() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Contains() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChaosPolicyList_CheckExperiment(t *testing.T) {
+	policyList := &ChaosPolicyList{
+		Items: []ChaosPolicy{
+			{
+				Spec: ChaosPolicySpec{
+					ProtectedNamespaces: []string{"kube-system"},
+					ProtectedLabels:     map[string]string{"chaosmeta.io/protected": "true"},
+					AllowedFaults: []NamespaceFaults{
+						{Namespace: "app", Faults: []string{"cpu/burn", "pod/*"}},
+					},
+					BlackoutWindows: []TimeWindow{
+						{Start: "2023-11-11 00:00:00", End: "2023-11-12 00:00:00"},
+					},
+				},
+			},
+		},
+	}
+	policyList.Items[0].Name = "default"
+
+	newSpec := func(scope ScopeType, target, fault string, selector SelectorUnit) *ExperimentSpec {
+		return &ExperimentSpec{
+			Scope:      scope,
+			Experiment: &ExperimentCommon{Target: target, Fault: fault},
+			Selector:   []SelectorUnit{selector},
+		}
+	}
+	normalTime := time.Date(2023, 11, 13, 10, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		spec    *ExperimentSpec
+		t       time.Time
+		wantErr bool
+	}{
+		{
+			name: "allowed fault",
+			spec: newSpec(PodScopeType, "cpu", "burn", SelectorUnit{Namespace: "app"}),
+			t:    normalTime,
+		},
+		{
+			name: "allowed all faults of target",
+			spec: newSpec(KubernetesScopeType, "pod", "delete", SelectorUnit{Namespace: "app"}),
+			t:    normalTime,
+		},
+		{
+			name:    "not allowed fault",
+			spec:    newSpec(PodScopeType, "mem", "fill", SelectorUnit{Namespace: "app"}),
+			t:       normalTime,
+			wantErr: true,
+		},
+		{
+			name: "namespace not limited",
+			spec: newSpec(PodScopeType, "mem", "fill", SelectorUnit{Namespace: "other"}),
+			t:    normalTime,
+		},
+		{
+			name:    "protected namespace",
+			spec:    newSpec(PodScopeType, "cpu", "burn", SelectorUnit{Namespace: "kube-system"}),
+			t:       normalTime,
+			wantErr: true,
+		},
+		{
+			name:    "protected namespace target",
+			spec:    newSpec(KubernetesScopeType, "namespace", "finalizer", SelectorUnit{Name: []string{"app", "kube-system"}}),
+			t:       normalTime,
+			wantErr: true,
+		},
+		{
+			name:    "protected label",
+			spec:    newSpec(PodScopeType, "cpu", "burn", SelectorUnit{Namespace: "other", Label: map[string]string{"chaosmeta.io/protected": "true"}}),
+			t:       normalTime,
+			wantErr: true,
+		},
+		{
+			name:    "blackout",
+			spec:    newSpec(NodeScopeType, "cpu", "burn", SelectorUnit{Name: []string{"node-1"}}),
+			t:       time.Date(2023, 11, 11, 10, 0, 0, 0, time.Local),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := policyList.CheckExperiment(tt.spec, tt.t); (err != nil) != tt.wantErr {
+				t.Errorf("CheckExperiment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
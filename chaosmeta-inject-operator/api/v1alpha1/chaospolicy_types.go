@@ -0,0 +1,73 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ChaosPolicySpec defines the protection rules which all experiments must obey
+type ChaosPolicySpec struct {
+	// ProtectedNamespaces can not be injected
+	ProtectedNamespaces []string `json:"protectedNamespaces,omitempty"`
+	// ProtectedLabels the objects with any of the labels can not be injected
+	ProtectedLabels map[string]string `json:"protectedLabels,omitempty"`
+	// MaxPodPercent is the maximum percentage of a workload's pods that may be hit at once, 0 means no limit
+	MaxPodPercent int `json:"maxPodPercent,omitempty"`
+	// AllowedFaults limits the faults in the namespaces, namespaces not listed are not limited
+	AllowedFaults []NamespaceFaults `json:"allowedFaults,omitempty"`
+	// BlackoutWindows experiments can not be created or injected during the windows
+	BlackoutWindows []TimeWindow `json:"blackoutWindows,omitempty"`
+}
+
+type NamespaceFaults struct {
+	Namespace string `json:"namespace"`
+	// Faults format is "target/fault", such as "cpu/burn"、"pod/*", "*" means all faults
+	Faults []string `json:"faults"`
+}
+
+type TimeWindow struct {
+	// Start and End support "2006-01-02 15:04:05" for a one-time window, or "15:04" for a daily window which can cross midnight
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Weekdays limits the daily window to the days such as "Sat"、"Sun", empty means every day
+	Weekdays []string `json:"weekdays,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+
+// ChaosPolicy is the Schema for the chaospolicies API
+type ChaosPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ChaosPolicySpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ChaosPolicyList contains a list of ChaosPolicy
+type ChaosPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ChaosPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ChaosPolicy{}, &ChaosPolicyList{})
+}
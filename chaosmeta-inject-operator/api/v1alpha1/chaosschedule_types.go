@@ -0,0 +1,93 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ConcurrencyPolicyType string
+
+const (
+	// AllowConcurrent allows experiments of one schedule to run concurrently
+	AllowConcurrent ConcurrencyPolicyType = "allow"
+	// ForbidConcurrent skips the new run if previous experiment is not finished
+	ForbidConcurrent ConcurrencyPolicyType = "forbid"
+	// ReplaceConcurrent deletes the unfinished experiments and creates the new one
+	ReplaceConcurrent ConcurrencyPolicyType = "replace"
+)
+
+const (
+	// ScheduleLabelKey is set on the experiments created by ChaosSchedule, value is the name of schedule
+	ScheduleLabelKey = "chaosmeta.io/schedule"
+
+	DefaultSuccessfulHistoryLimit int32 = 3
+	DefaultFailedHistoryLimit     int32 = 1
+)
+
+// ChaosScheduleSpec defines the desired state of ChaosSchedule
+type ChaosScheduleSpec struct {
+	// Schedule is a standard cron expression with 5 fields, such as "0 2 * * *"
+	Schedule string `json:"schedule"`
+	// ConcurrencyPolicy support "allow"(default), "forbid", "replace"
+	// +kubebuilder:validation:Enum=allow;forbid;replace
+	ConcurrencyPolicy ConcurrencyPolicyType `json:"concurrencyPolicy,omitempty"`
+	// Suspend stops creating new experiments, the running ones are not affected
+	Suspend bool `json:"suspend,omitempty"`
+	// SuccessfulHistoryLimit is the count of recovered successfully experiments to keep, default 3
+	SuccessfulHistoryLimit *int32 `json:"successfulHistoryLimit,omitempty"`
+	// FailedHistoryLimit is the count of finished experiments which are not successful to keep, default 1
+	FailedHistoryLimit *int32 `json:"failedHistoryLimit,omitempty"`
+	// Template is the spec of experiments to create, "targetPhase" is always set to "inject"
+	Template ExperimentSpec `json:"template"`
+}
+
+// ChaosScheduleStatus defines the observed state of ChaosSchedule
+type ChaosScheduleStatus struct {
+	// Active is the names of experiments which are not finished
+	Active []string `json:"active,omitempty"`
+	// LastScheduleTime is the last time the schedule is triggered, include the skipped one
+	LastScheduleTime string `json:"lastScheduleTime,omitempty"`
+	// LastExperiment is the name of the last created experiment
+	LastExperiment string `json:"lastExperiment,omitempty"`
+	Message        string `json:"message,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// ChaosSchedule is the Schema for the chaosschedules API
+type ChaosSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ChaosScheduleSpec   `json:"spec,omitempty"`
+	Status ChaosScheduleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ChaosScheduleList contains a list of ChaosSchedule
+type ChaosScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ChaosSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ChaosSchedule{}, &ChaosScheduleList{})
+}
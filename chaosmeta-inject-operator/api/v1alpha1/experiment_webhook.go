@@ -17,10 +17,12 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
 	"fmt"
 	"k8s.io/apimachinery/pkg/runtime"
 	"reflect"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"strconv"
@@ -30,9 +32,12 @@ import (
 // log is for logging in this package.
 var (
 	experimentlog = logf.Log.WithName("experiment-resource")
+	// policyReader is used to query ChaosPolicy, policy is not checked when nil
+	policyReader client.Reader
 )
 
 func (r *Experiment) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	policyReader = mgr.GetAPIReader()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -129,6 +134,11 @@ func (r *Experiment) ValidateCreate() error {
 			//	return fmt.Errorf("must provide one of \"name\"、\"label\"、\"ip\" in selector")
 			//}
 
+			// all conditions are "AND" when extended selector is used
+			if len(unitSelector.MatchExpressions) != 0 || len(unitSelector.Annotation) != 0 || unitSelector.Field != "" || unitSelector.Owner != nil {
+				continue
+			}
+
 			var emptyCount int
 			if len(unitSelector.Name) == 0 {
 				emptyCount++
@@ -150,7 +160,20 @@ func (r *Experiment) ValidateCreate() error {
 		}
 	}
 
-	return nil
+	return checkChaosPolicy(&r.Spec)
+}
+
+func checkChaosPolicy(spec *ExperimentSpec) error {
+	if policyReader == nil {
+		return nil
+	}
+
+	policyList := &ChaosPolicyList{}
+	if err := policyReader.List(context.Background(), policyList); err != nil {
+		return fmt.Errorf("list ChaosPolicy error: %s", err.Error())
+	}
+
+	return policyList.CheckExperiment(spec, time.Now())
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -161,24 +184,80 @@ func (r *Experiment) ValidateUpdate(old runtime.Object) error {
 		return nil
 	}
 
-	if !reflect.DeepEqual(r.Spec.Experiment, oldExp.Spec.Experiment) ||
+	if r.Spec.Experiment == nil || oldExp.Spec.Experiment == nil {
+		return fmt.Errorf("\"experiment\" must not be empty")
+	}
+
+	newCommon := r.Spec.Experiment.DeepCopy()
+	newCommon.Duration = oldExp.Spec.Experiment.Duration
+	if !reflect.DeepEqual(newCommon, oldExp.Spec.Experiment) ||
 		!reflect.DeepEqual(r.Spec.Selector, oldExp.Spec.Selector) ||
 		!reflect.DeepEqual(r.Spec.RangeMode, oldExp.Spec.RangeMode) ||
 		r.Spec.Scope != oldExp.Spec.Scope {
-		return fmt.Errorf("spec only support update \"targetPhase\"")
+		return fmt.Errorf("spec only support update \"targetPhase\" and \"experiment.duration\"")
+	}
+
+	if r.Spec.Experiment.Duration != oldExp.Spec.Experiment.Duration {
+		if err := validateDurationUpdate(oldExp, r.Spec.Experiment.Duration); err != nil {
+			return err
+		}
+	}
+
+	if r.Spec.TargetPhase != oldExp.Spec.TargetPhase {
+		return validateTargetPhaseUpdate(oldExp, r.Spec.TargetPhase)
+	}
+
+	return nil
+}
+
+func validateDurationUpdate(oldExp *Experiment, duration string) error {
+	if oldExp.Status.Phase != InjectPhaseType && oldExp.Status.Phase != PausePhaseType {
+		return fmt.Errorf("only support update \"experiment.duration\" when \"status.phase\" is inject/pause")
 	}
 
-	if !(oldExp.Status.Phase == InjectPhaseType && (oldExp.Status.Status == SuccessStatusType || oldExp.Status.Status == FailedStatusType || oldExp.Status.Status == PartSuccessStatusType)) {
-		return fmt.Errorf("only support update when \"status.phase == inject and status.status == success/failed/partSuccess\"")
+	// injected objects of node and pod scope are recovered by chaosmetad with the timeout given at inject time,
+	// so they only take the new duration after being resumed
+	if oldExp.Status.Phase == InjectPhaseType && oldExp.Spec.Scope != KubernetesScopeType {
+		return fmt.Errorf("only support update \"experiment.duration\" of scope %s when \"status.phase\" is pause", oldExp.Spec.Scope)
 	}
 
-	if r.Spec.TargetPhase != RecoverPhaseType {
-		return fmt.Errorf("only can update \"targetPhase\" to \"recover\"")
+	if duration == "" {
+		return fmt.Errorf("experiment's duration is empty")
+	}
+
+	if _, err := ConvertDuration(duration); err != nil {
+		return fmt.Errorf("experiment's duration is invalid: %s", err.Error())
 	}
 
 	return nil
 }
 
+func validateTargetPhaseUpdate(oldExp *Experiment, targetPhase PhaseType) error {
+	if oldExp.Status.Status != SuccessStatusType && oldExp.Status.Status != FailedStatusType && oldExp.Status.Status != PartSuccessStatusType {
+		return fmt.Errorf("only support update \"targetPhase\" when \"status.status\" is success/failed/partSuccess")
+	}
+
+	var fromPhases []PhaseType
+	switch targetPhase {
+	case RecoverPhaseType:
+		fromPhases = []PhaseType{InjectPhaseType, PausePhaseType}
+	case PausePhaseType:
+		fromPhases = []PhaseType{InjectPhaseType}
+	case ResumePhaseType:
+		fromPhases = []PhaseType{PausePhaseType}
+	default:
+		return fmt.Errorf("\"targetPhase\" only can be updated to: %s, %s, %s", RecoverPhaseType, PausePhaseType, ResumePhaseType)
+	}
+
+	for _, phase := range fromPhases {
+		if oldExp.Status.Phase == phase {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("\"targetPhase\" can not be updated to %s when \"status.phase\" is %s", targetPhase, oldExp.Status.Phase)
+}
+
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
 func (r *Experiment) ValidateDelete() error {
 	experimentlog.Info("validate delete", "name", r.Name)
@@ -83,3 +83,121 @@ func TestConvertDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestExperiment_ValidateUpdate(t *testing.T) {
+	newExp := func(targetPhase, phase PhaseType, status StatusType, duration string) *Experiment {
+		return &Experiment{
+			Spec: ExperimentSpec{
+				Scope:       PodScopeType,
+				TargetPhase: targetPhase,
+				Experiment: &ExperimentCommon{
+					Duration: duration,
+					Target:   "cpu",
+					Fault:    "burn",
+				},
+			},
+			Status: ExperimentStatus{
+				Phase:  phase,
+				Status: status,
+			},
+		}
+	}
+	newKubernetesExp := func(targetPhase, phase PhaseType, status StatusType, duration string) *Experiment {
+		exp := newExp(targetPhase, phase, status, duration)
+		exp.Spec.Scope = KubernetesScopeType
+		exp.Spec.Experiment.Target, exp.Spec.Experiment.Fault = "pod", "delete"
+		return exp
+	}
+
+	tests := []struct {
+		name    string
+		old     *Experiment
+		new     *Experiment
+		wantErr bool
+	}{
+		{
+			name: "inject to recover",
+			old:  newExp(InjectPhaseType, InjectPhaseType, SuccessStatusType, "5m"),
+			new:  newExp(RecoverPhaseType, InjectPhaseType, SuccessStatusType, "5m"),
+		},
+		{
+			name: "inject to pause",
+			old:  newExp(InjectPhaseType, InjectPhaseType, PartSuccessStatusType, "5m"),
+			new:  newExp(PausePhaseType, InjectPhaseType, PartSuccessStatusType, "5m"),
+		},
+		{
+			name:    "pause when running",
+			old:     newExp(InjectPhaseType, InjectPhaseType, RunningStatusType, "5m"),
+			new:     newExp(PausePhaseType, InjectPhaseType, RunningStatusType, "5m"),
+			wantErr: true,
+		},
+		{
+			name: "pause to resume",
+			old:  newExp(PausePhaseType, PausePhaseType, SuccessStatusType, "5m"),
+			new:  newExp(ResumePhaseType, PausePhaseType, SuccessStatusType, "5m"),
+		},
+		{
+			name: "pause to recover",
+			old:  newExp(PausePhaseType, PausePhaseType, FailedStatusType, "5m"),
+			new:  newExp(RecoverPhaseType, PausePhaseType, FailedStatusType, "5m"),
+		},
+		{
+			name:    "resume without pause",
+			old:     newExp(InjectPhaseType, InjectPhaseType, SuccessStatusType, "5m"),
+			new:     newExp(ResumePhaseType, InjectPhaseType, SuccessStatusType, "5m"),
+			wantErr: true,
+		},
+		{
+			name: "resumed to pause",
+			old:  newExp(ResumePhaseType, InjectPhaseType, SuccessStatusType, "5m"),
+			new:  newExp(PausePhaseType, InjectPhaseType, SuccessStatusType, "5m"),
+		},
+		{
+			name:    "back to inject",
+			old:     newExp(PausePhaseType, PausePhaseType, SuccessStatusType, "5m"),
+			new:     newExp(InjectPhaseType, PausePhaseType, SuccessStatusType, "5m"),
+			wantErr: true,
+		},
+		{
+			name:    "extend injected pod",
+			old:     newExp(InjectPhaseType, InjectPhaseType, RunningStatusType, "5m"),
+			new:     newExp(InjectPhaseType, InjectPhaseType, RunningStatusType, "10m"),
+			wantErr: true,
+		},
+		{
+			name: "extend injected kubernetes",
+			old:  newKubernetesExp(InjectPhaseType, InjectPhaseType, RunningStatusType, "5m"),
+			new:  newKubernetesExp(InjectPhaseType, InjectPhaseType, RunningStatusType, "10m"),
+		},
+		{
+			name: "extend when pause",
+			old:  newExp(PausePhaseType, PausePhaseType, SuccessStatusType, "5m"),
+			new:  newExp(PausePhaseType, PausePhaseType, SuccessStatusType, "1h"),
+		},
+		{
+			name:    "extend when recover",
+			old:     newExp(RecoverPhaseType, RecoverPhaseType, RunningStatusType, "5m"),
+			new:     newExp(RecoverPhaseType, RecoverPhaseType, RunningStatusType, "10m"),
+			wantErr: true,
+		},
+		{
+			name:    "invalid duration",
+			old:     newExp(PausePhaseType, PausePhaseType, SuccessStatusType, "5m"),
+			new:     newExp(PausePhaseType, PausePhaseType, SuccessStatusType, "10p"),
+			wantErr: true,
+		},
+		{
+			name:    "update fault",
+			old:     newExp(InjectPhaseType, InjectPhaseType, SuccessStatusType, "5m"),
+			new:     &Experiment{Spec: ExperimentSpec{Scope: PodScopeType, TargetPhase: InjectPhaseType, Experiment: &ExperimentCommon{Duration: "5m", Target: "mem", Fault: "fill"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.new.ValidateUpdate(tt.old); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/robfig/cron"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxMissedSchedules bounds the walk of missed schedule times like CronJob
+const maxMissedSchedules = 100
+
+var errTooManyMissed = fmt.Errorf("too many missed schedule times (> %d)", maxMissedSchedules)
+
+// ChaosScheduleReconciler reconciles a ChaosSchedule object
+type ChaosScheduleReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=chaosmeta.io,resources=chaosschedules,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=chaosmeta.io,resources=chaosschedules/status,verbs=get;update;patch
+
+// Reconcile creates experiments of ChaosSchedule on schedule and cleans the finished ones beyond history limits
+func (r *ChaosScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	instance, logger := &v1alpha1.ChaosSchedule{}, log.FromContext(ctx)
+	if err := r.Client.Get(ctx, req.NamespacedName, instance); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, fmt.Errorf("get instance error: %s", err.Error())
+	}
+
+	// experiments are deleted by garbage collector with owner reference, and recovered by their finalizer
+	if !instance.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	expList := &v1alpha1.ExperimentList{}
+	if err := r.Client.List(ctx, expList, client.InNamespace(instance.Namespace), client.MatchingLabels{v1alpha1.ScheduleLabelKey: instance.Name}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("list experiments of schedule error: %s", err.Error())
+	}
+
+	active, successful, failed := classifyExperiments(expList.Items)
+	if err := r.cleanHistory(ctx, successful, getHistoryLimit(instance.Spec.SuccessfulHistoryLimit, v1alpha1.DefaultSuccessfulHistoryLimit)); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.cleanHistory(ctx, failed, getHistoryLimit(instance.Spec.FailedHistoryLimit, v1alpha1.DefaultFailedHistoryLimit)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	requeueAfter, err := r.solveSchedule(ctx, instance, active, time.Now())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	logger.Info(fmt.Sprintf("schedule: %s/%s, active: %v, message: %s", instance.Namespace, instance.Name, instance.Status.Active, instance.Status.Message))
+	if err := r.Client.Status().Update(ctx, instance); err != nil {
+		return ctrl.Result{}, fmt.Errorf("update instance error: %s", err.Error())
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// solveSchedule creates the experiment if it is time to run, and returns the duration to wait for next run
+func (r *ChaosScheduleReconciler) solveSchedule(ctx context.Context, instance *v1alpha1.ChaosSchedule, active []v1alpha1.Experiment, now time.Time) (time.Duration, error) {
+	instance.Status.Active = getExperimentNames(active)
+	sched, err := cron.ParseStandard(instance.Spec.Schedule)
+	if err != nil {
+		// no need to retry until spec is updated
+		instance.Status.Message = fmt.Sprintf("parse schedule error: %s", err.Error())
+		return 0, nil
+	}
+
+	if instance.Spec.Suspend {
+		instance.Status.Message = "schedule is suspended"
+		return 0, nil
+	}
+
+	scheduleTime, next, err := getScheduleTime(sched, instance, now)
+	if err == errTooManyMissed {
+		// skip all the missed runs, so that the walk starts from now in next reconcile
+		instance.Status.LastScheduleTime = now.Format(model.TimeFormat)
+		instance.Status.Message = fmt.Sprintf("skip the missed runs: %s", err.Error())
+		return next.Sub(now), nil
+	}
+	if err != nil {
+		instance.Status.Message = fmt.Sprintf("get schedule time error: %s", err.Error())
+		return 0, nil
+	}
+
+	if scheduleTime.IsZero() {
+		return next.Sub(now), nil
+	}
+
+	instance.Status.LastScheduleTime = scheduleTime.Format(model.TimeFormat)
+	switch instance.Spec.ConcurrencyPolicy {
+	case v1alpha1.ForbidConcurrent:
+		if len(active) != 0 {
+			instance.Status.Message = fmt.Sprintf("skip the run at %s, experiments are not finished: %s", instance.Status.LastScheduleTime, strings.Join(instance.Status.Active, ", "))
+			return next.Sub(now), nil
+		}
+	case v1alpha1.ReplaceConcurrent:
+		for i := range active {
+			// experiment is recovered by its finalizer when deleted
+			if err := r.Client.Delete(ctx, &active[i]); err != nil && !errors.IsNotFound(err) {
+				return 0, fmt.Errorf("delete experiment[%s] to replace error: %s", active[i].Name, err.Error())
+			}
+		}
+		instance.Status.Active = nil
+	}
+
+	exp := newScheduledExperiment(instance, scheduleTime)
+	if err := ctrl.SetControllerReference(instance, exp, r.Scheme); err != nil {
+		return 0, fmt.Errorf("set owner reference of experiment error: %s", err.Error())
+	}
+
+	if err := r.Client.Create(ctx, exp); err != nil && !errors.IsAlreadyExists(err) {
+		// the experiment may be rejected by webhook, such as chaos policy, wait for next run
+		instance.Status.Message = fmt.Sprintf("create experiment[%s] error: %s", exp.Name, err.Error())
+		return next.Sub(now), nil
+	}
+
+	instance.Status.Active = append(instance.Status.Active, exp.Name)
+	instance.Status.LastExperiment, instance.Status.Message = exp.Name, fmt.Sprintf("create experiment[%s] success", exp.Name)
+	return next.Sub(now), nil
+}
+
+// getScheduleTime returns the latest missed schedule time which is zero if there is none, and the next schedule time.
+// errTooManyMissed is returned with the next schedule time after now if the missed ones are more than maxMissedSchedules
+func getScheduleTime(sched cron.Schedule, instance *v1alpha1.ChaosSchedule, now time.Time) (time.Time, time.Time, error) {
+	earliest := instance.ObjectMeta.CreationTimestamp.Time
+	if instance.Status.LastScheduleTime != "" {
+		last, err := time.ParseInLocation(model.TimeFormat, instance.Status.LastScheduleTime, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("parse lastScheduleTime error: %s", err.Error())
+		}
+
+		earliest = last
+	}
+
+	// only the latest one of missed runs is started
+	var scheduleTime time.Time
+	next, missed := sched.Next(earliest), 0
+	for ; !next.IsZero() && !next.After(now); next = sched.Next(next) {
+		if missed++; missed > maxMissedSchedules {
+			return time.Time{}, sched.Next(now), errTooManyMissed
+		}
+		scheduleTime = next
+	}
+
+	return scheduleTime, next, nil
+}
+
+func newScheduledExperiment(instance *v1alpha1.ChaosSchedule, scheduleTime time.Time) *v1alpha1.Experiment {
+	exp := &v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", instance.Name, scheduleTime.Unix()),
+			Namespace: instance.Namespace,
+			Labels:    map[string]string{v1alpha1.ScheduleLabelKey: instance.Name},
+		},
+		Spec: *instance.Spec.Template.DeepCopy(),
+	}
+
+	exp.Spec.TargetPhase = v1alpha1.InjectPhaseType
+	return exp
+}
+
+// classifyExperiments splits the experiments into unfinished, successful and failed ones
+func classifyExperiments(expList []v1alpha1.Experiment) (active, successful, failed []v1alpha1.Experiment) {
+	for i := range expList {
+		exp := expList[i]
+		if !isExperimentFinished(&exp) {
+			active = append(active, exp)
+		} else if !exp.ObjectMeta.DeletionTimestamp.IsZero() {
+			continue
+		} else if isExperimentSuccessful(&exp) {
+			successful = append(successful, exp)
+		} else {
+			failed = append(failed, exp)
+		}
+	}
+
+	return
+}
+
+func isExperimentFinished(exp *v1alpha1.Experiment) bool {
+	return exp.Status.Phase == v1alpha1.RecoverPhaseType && (exp.Status.Status == v1alpha1.SuccessStatusType ||
+		exp.Status.Status == v1alpha1.FailedStatusType || exp.Status.Status == v1alpha1.PartSuccessStatusType)
+}
+
+// isExperimentSuccessful means all objects are injected and recovered successfully
+func isExperimentSuccessful(exp *v1alpha1.Experiment) bool {
+	if exp.Status.Status != v1alpha1.SuccessStatusType || len(exp.Status.Detail.Inject) == 0 {
+		return false
+	}
+
+	for _, unit := range exp.Status.Detail.Inject {
+		if unit.Status != v1alpha1.SuccessStatusType {
+			return false
+		}
+	}
+
+	return true
+}
+
+// cleanHistory deletes the oldest experiments beyond the limit
+func (r *ChaosScheduleReconciler) cleanHistory(ctx context.Context, expList []v1alpha1.Experiment, limit int32) error {
+	if len(expList) <= int(limit) {
+		return nil
+	}
+
+	sort.Slice(expList, func(i, j int) bool {
+		return expList[i].ObjectMeta.CreationTimestamp.Before(&expList[j].ObjectMeta.CreationTimestamp)
+	})
+
+	for i := 0; i < len(expList)-int(limit); i++ {
+		if err := r.Client.Delete(ctx, &expList[i]); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("delete history experiment[%s] error: %s", expList[i].Name, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func getHistoryLimit(limit *int32, defaultLimit int32) int32 {
+	if limit == nil {
+		return defaultLimit
+	}
+
+	if *limit < 0 {
+		return 0
+	}
+
+	return *limit
+}
+
+func getExperimentNames(expList []v1alpha1.Experiment) []string {
+	var names []string
+	for i := range expList {
+		names = append(names, expList[i].Name)
+	}
+
+	return names
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ChaosScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ChaosSchedule{}).
+		Owns(&v1alpha1.Experiment{}).
+		Complete(r)
+}
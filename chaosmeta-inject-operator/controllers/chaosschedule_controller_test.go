@@ -0,0 +1,221 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"github.com/robfig/cron"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+	"time"
+)
+
+func newScheduleReconciler(t *testing.T, objects ...client.Object) *ChaosScheduleReconciler {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme error: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme error: %v", err)
+	}
+
+	return &ChaosScheduleReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		Scheme: scheme,
+	}
+}
+
+func newSchedule(policy v1alpha1.ConcurrencyPolicyType, lastScheduleTime time.Time) *v1alpha1.ChaosSchedule {
+	limit := int32(1)
+	return &v1alpha1.ChaosSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "chaosmeta",
+			Name:              "nightly",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-24 * time.Hour)),
+		},
+		Spec: v1alpha1.ChaosScheduleSpec{
+			Schedule:               "* * * * *",
+			ConcurrencyPolicy:      policy,
+			SuccessfulHistoryLimit: &limit,
+			Template: v1alpha1.ExperimentSpec{
+				Scope:      v1alpha1.PodScopeType,
+				Experiment: &v1alpha1.ExperimentCommon{Duration: "10m", Target: "cpu", Fault: "burn"},
+				Selector:   []v1alpha1.SelectorUnit{{Namespace: "default"}},
+			},
+		},
+		Status: v1alpha1.ChaosScheduleStatus{
+			LastScheduleTime: lastScheduleTime.Format(model.TimeFormat),
+		},
+	}
+}
+
+func newScheduledExp(name string, created time.Time, phase v1alpha1.PhaseType, status v1alpha1.StatusType) *v1alpha1.Experiment {
+	return &v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "chaosmeta",
+			Name:              name,
+			Labels:            map[string]string{v1alpha1.ScheduleLabelKey: "nightly"},
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Status: v1alpha1.ExperimentStatus{
+			Phase:  phase,
+			Status: status,
+			Detail: v1alpha1.ExperimentDetail{Inject: []v1alpha1.ExperimentDetailUnit{
+				{InjectObjectName: "pod/default/nginx-0/nginx", Status: v1alpha1.SuccessStatusType},
+			}},
+		},
+	}
+}
+
+func Test_getScheduleTime(t *testing.T) {
+	sched, err := cron.ParseStandard("*/10 * * * *")
+	assert.NoError(t, err)
+
+	var (
+		created = time.Date(2023, 11, 1, 10, 1, 0, 0, time.Local)
+		now     = time.Date(2023, 11, 1, 10, 35, 0, 0, time.Local)
+		sc      = &v1alpha1.ChaosSchedule{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+	)
+
+	scheduleTime, next, err := getScheduleTime(sched, sc, now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 11, 1, 10, 30, 0, 0, time.Local), scheduleTime)
+	assert.Equal(t, time.Date(2023, 11, 1, 10, 40, 0, 0, time.Local), next)
+
+	sc.Status.LastScheduleTime = scheduleTime.Format(model.TimeFormat)
+	scheduleTime, next, err = getScheduleTime(sched, sc, now)
+	assert.NoError(t, err)
+	assert.True(t, scheduleTime.IsZero())
+	assert.Equal(t, time.Date(2023, 11, 1, 10, 40, 0, 0, time.Local), next)
+
+	sc.Status.LastScheduleTime = "2023-11-01"
+	_, _, err = getScheduleTime(sched, sc, now)
+	assert.Error(t, err)
+
+	// 144 runs are missed in one day
+	sc.Status.LastScheduleTime = now.Add(-24 * time.Hour).Format(model.TimeFormat)
+	scheduleTime, next, err = getScheduleTime(sched, sc, now)
+	assert.Equal(t, errTooManyMissed, err)
+	assert.True(t, scheduleTime.IsZero())
+	assert.Equal(t, time.Date(2023, 11, 1, 10, 40, 0, 0, time.Local), next)
+}
+
+func TestChaosScheduleReconciler_Forbid(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		now     = time.Now()
+		sc      = newSchedule(v1alpha1.ForbidConcurrent, now.Add(-10*time.Minute))
+		history = []client.Object{
+			newScheduledExp("nightly-1", now.Add(-3*time.Hour), v1alpha1.RecoverPhaseType, v1alpha1.SuccessStatusType),
+			newScheduledExp("nightly-2", now.Add(-2*time.Hour), v1alpha1.RecoverPhaseType, v1alpha1.SuccessStatusType),
+			newScheduledExp("nightly-3", now.Add(-time.Hour), v1alpha1.RecoverPhaseType, v1alpha1.FailedStatusType),
+			newScheduledExp("nightly-4", now.Add(-time.Minute), v1alpha1.InjectPhaseType, v1alpha1.RunningStatusType),
+		}
+		r = newScheduleReconciler(t, append(history, sc)...)
+	)
+
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "chaosmeta", Name: "nightly"}})
+	assert.NoError(t, err)
+	assert.True(t, result.RequeueAfter > 0 && result.RequeueAfter <= time.Minute)
+
+	expList := &v1alpha1.ExperimentList{}
+	assert.NoError(t, r.Client.List(ctx, expList))
+	assert.Equal(t, []string{"nightly-2", "nightly-3", "nightly-4"}, getExperimentNames(expList.Items))
+
+	newSc := &v1alpha1.ChaosSchedule{}
+	assert.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "chaosmeta", Name: "nightly"}, newSc))
+	assert.Equal(t, []string{"nightly-4"}, newSc.Status.Active)
+	assert.Contains(t, newSc.Status.Message, "skip the run")
+	assert.NotEqual(t, sc.Status.LastScheduleTime, newSc.Status.LastScheduleTime)
+}
+
+func TestChaosScheduleReconciler_Replace(t *testing.T) {
+	var (
+		ctx = context.Background()
+		now = time.Now()
+		sc  = newSchedule(v1alpha1.ReplaceConcurrent, now.Add(-10*time.Minute))
+		r   = newScheduleReconciler(t, sc,
+			newScheduledExp("nightly-1", now.Add(-time.Minute), v1alpha1.InjectPhaseType, v1alpha1.SuccessStatusType))
+	)
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "chaosmeta", Name: "nightly"}})
+	assert.NoError(t, err)
+
+	expList := &v1alpha1.ExperimentList{}
+	assert.NoError(t, r.Client.List(ctx, expList))
+	assert.Equal(t, 1, len(expList.Items))
+
+	exp := expList.Items[0]
+	assert.NotEqual(t, "nightly-1", exp.Name)
+	assert.Equal(t, v1alpha1.InjectPhaseType, exp.Spec.TargetPhase)
+	assert.Equal(t, "burn", exp.Spec.Experiment.Fault)
+	assert.Equal(t, "nightly", exp.Labels[v1alpha1.ScheduleLabelKey])
+	assert.Equal(t, "nightly", exp.OwnerReferences[0].Name)
+
+	newSc := &v1alpha1.ChaosSchedule{}
+	assert.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "chaosmeta", Name: "nightly"}, newSc))
+	assert.Equal(t, []string{exp.Name}, newSc.Status.Active)
+	assert.Equal(t, exp.Name, newSc.Status.LastExperiment)
+}
+
+func TestChaosScheduleReconciler_Suspend(t *testing.T) {
+	var (
+		ctx = context.Background()
+		sc  = newSchedule(v1alpha1.AllowConcurrent, time.Now().Add(-10*time.Minute))
+	)
+	sc.Spec.Suspend = true
+	r := newScheduleReconciler(t, sc)
+
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "chaosmeta", Name: "nightly"}})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), result.RequeueAfter)
+
+	expList := &v1alpha1.ExperimentList{}
+	assert.NoError(t, r.Client.List(ctx, expList))
+	assert.Equal(t, 0, len(expList.Items))
+}
+
+func TestChaosScheduleReconciler_TooManyMissed(t *testing.T) {
+	var (
+		ctx = context.Background()
+		sc  = newSchedule(v1alpha1.AllowConcurrent, time.Now().Add(-3*time.Hour))
+	)
+	r := newScheduleReconciler(t, sc)
+
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "chaosmeta", Name: "nightly"}})
+	assert.NoError(t, err)
+	assert.True(t, result.RequeueAfter > 0 && result.RequeueAfter <= time.Minute)
+
+	expList := &v1alpha1.ExperimentList{}
+	assert.NoError(t, r.Client.List(ctx, expList))
+	assert.Equal(t, 0, len(expList.Items))
+
+	// the missed runs are skipped, the next run is started on schedule
+	assert.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "chaosmeta", Name: "nightly"}, sc))
+	last, err := time.ParseInLocation(model.TimeFormat, sc.Status.LastScheduleTime, time.Local)
+	assert.NoError(t, err)
+	assert.True(t, time.Since(last) < time.Minute)
+}
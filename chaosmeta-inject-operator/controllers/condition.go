@@ -0,0 +1,106 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// setConditions updates the conditions according to phase and status of experiment
+func setConditions(instance *v1alpha1.Experiment) {
+	phase, status, msg := instance.Status.Phase, instance.Status.Status, instance.Status.Message
+	if phase == "" {
+		return
+	}
+
+	var (
+		isFinal   = status == v1alpha1.SuccessStatusType || status == v1alpha1.PartSuccessStatusType || status == v1alpha1.FailedStatusType
+		isSuccess = status == v1alpha1.SuccessStatusType || status == v1alpha1.PartSuccessStatusType
+		total     = len(instance.Status.Detail.Inject)
+	)
+
+	// Selected
+	if total != 0 {
+		setCondition(instance, v1alpha1.SelectedConditionType, metav1.ConditionTrue, "TargetSelected", fmt.Sprintf("%d targets selected", total))
+	} else if isFinal {
+		setCondition(instance, v1alpha1.SelectedConditionType, metav1.ConditionFalse, "SelectFailed", msg)
+	}
+
+	// Injected and Recovered
+	switch phase {
+	case v1alpha1.InjectPhaseType:
+		if isSuccess {
+			setCondition(instance, v1alpha1.InjectedConditionType, metav1.ConditionTrue, "InjectSuccess", msg)
+		} else if isFinal {
+			setCondition(instance, v1alpha1.InjectedConditionType, metav1.ConditionFalse, "InjectFailed", msg)
+		} else {
+			setCondition(instance, v1alpha1.InjectedConditionType, metav1.ConditionFalse, "Injecting", msg)
+		}
+		setCondition(instance, v1alpha1.RecoveredConditionType, metav1.ConditionFalse, "NotRecovered", msg)
+	case v1alpha1.PausePhaseType, v1alpha1.RecoverPhaseType:
+		prefix := "Recover"
+		if phase == v1alpha1.PausePhaseType {
+			prefix = "Pause"
+		}
+
+		if status == v1alpha1.SuccessStatusType {
+			setCondition(instance, v1alpha1.InjectedConditionType, metav1.ConditionFalse, prefix+"Success", msg)
+			setCondition(instance, v1alpha1.RecoveredConditionType, metav1.ConditionTrue, prefix+"Success", msg)
+		} else if isFinal {
+			// some objects may still be injected
+			setCondition(instance, v1alpha1.InjectedConditionType, metav1.ConditionUnknown, prefix+"Failed", msg)
+			setCondition(instance, v1alpha1.RecoveredConditionType, metav1.ConditionFalse, prefix+"Failed", msg)
+		} else {
+			setCondition(instance, v1alpha1.InjectedConditionType, metav1.ConditionUnknown, prefix+"Running", msg)
+			setCondition(instance, v1alpha1.RecoveredConditionType, metav1.ConditionFalse, prefix+"Running", msg)
+		}
+	}
+
+	// Degraded
+	detail, failCount := getPhaseDetail(instance), 0
+	for i := range detail {
+		if detail[i].Status == v1alpha1.FailedStatusType {
+			failCount++
+		}
+	}
+
+	if failCount != 0 {
+		setCondition(instance, v1alpha1.DegradedConditionType, metav1.ConditionTrue, "TargetFailed", fmt.Sprintf("%d of %d targets failed in %s phase", failCount, len(detail), phase))
+	} else if status == v1alpha1.FailedStatusType {
+		setCondition(instance, v1alpha1.DegradedConditionType, metav1.ConditionTrue, "ExperimentFailed", msg)
+	} else {
+		setCondition(instance, v1alpha1.DegradedConditionType, metav1.ConditionFalse, "NoTargetFailed", fmt.Sprintf("no target failed in %s phase", phase))
+	}
+}
+
+func setCondition(instance *v1alpha1.Experiment, conditionType string, status metav1.ConditionStatus, reason, msg string) {
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: instance.Generation,
+		Reason:             reason,
+		Message:            msg,
+	})
+}
+
+// getPhaseDetail returns the details of current phase
+func getPhaseDetail(instance *v1alpha1.Experiment) []v1alpha1.ExperimentDetailUnit {
+	return getDetailByPhase(instance, instance.Status.Phase)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"strings"
+)
+
+// statusSnapshot records the status before processing, used to find the transitions
+type statusSnapshot struct {
+	phase  v1alpha1.PhaseType
+	status v1alpha1.StatusType
+	units  map[string]v1alpha1.StatusType
+}
+
+func newStatusSnapshot(instance *v1alpha1.Experiment) *statusSnapshot {
+	s := &statusSnapshot{
+		phase:  instance.Status.Phase,
+		status: instance.Status.Status,
+		units:  make(map[string]v1alpha1.StatusType),
+	}
+
+	for _, phase := range []v1alpha1.PhaseType{v1alpha1.InjectPhaseType, v1alpha1.PausePhaseType, v1alpha1.RecoverPhaseType} {
+		for _, unit := range getDetailByPhase(instance, phase) {
+			s.units[getUnitKey(phase, &unit)] = unit.Status
+		}
+	}
+
+	return s
+}
+
+// recordEvents emits events on the experiment and its pod or node targets for the status transitions
+func recordEvents(recorder record.EventRecorder, instance *v1alpha1.Experiment, snapshot *statusSnapshot) {
+	if recorder == nil || instance.Status.Phase == "" {
+		return
+	}
+
+	phase := instance.Status.Phase
+	if phase != snapshot.phase || instance.Status.Status != snapshot.status {
+		recorder.Event(instance, getEventType(instance.Status.Status), getEventReason(phase, instance.Status.Status), instance.Status.Message)
+	}
+
+	detail := getPhaseDetail(instance)
+	for i := range detail {
+		oldStatus, ok := snapshot.units[getUnitKey(phase, &detail[i])]
+		if (ok && oldStatus == detail[i].Status) || detail[i].Status == v1alpha1.CreatedStatusType {
+			continue
+		}
+
+		target := getEventTarget(detail[i].InjectObjectName)
+		if target == nil {
+			continue
+		}
+
+		recorder.Event(target, getEventType(detail[i].Status), getEventReason(phase, detail[i].Status),
+			fmt.Sprintf("experiment %s/%s, fault %s/%s: %s", instance.Namespace, instance.Name, instance.Spec.Experiment.Target, instance.Spec.Experiment.Fault, detail[i].Message))
+	}
+}
+
+func getDetailByPhase(instance *v1alpha1.Experiment, phase v1alpha1.PhaseType) []v1alpha1.ExperimentDetailUnit {
+	switch phase {
+	case v1alpha1.InjectPhaseType:
+		return instance.Status.Detail.Inject
+	case v1alpha1.PausePhaseType:
+		return instance.Status.Detail.Pause
+	case v1alpha1.RecoverPhaseType:
+		return instance.Status.Detail.Recover
+	default:
+		return nil
+	}
+}
+
+func getUnitKey(phase v1alpha1.PhaseType, unit *v1alpha1.ExperimentDetailUnit) string {
+	return fmt.Sprintf("%s%s%s%s%s", phase, model.ObjectNameSplit, unit.UID, model.ObjectNameSplit, unit.InjectObjectName)
+}
+
+func getEventType(status v1alpha1.StatusType) string {
+	if status == v1alpha1.FailedStatusType || status == v1alpha1.PartSuccessStatusType {
+		return corev1.EventTypeWarning
+	}
+
+	return corev1.EventTypeNormal
+}
+
+// getEventReason such as "InjectSuccess"、"RecoverFailed"
+func getEventReason(phase v1alpha1.PhaseType, status v1alpha1.StatusType) string {
+	return upperFirst(string(phase)) + upperFirst(string(status))
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// getEventTarget returns the pod or node of inject object, other kinds of object return nil
+func getEventTarget(injectObjectName string) runtime.Object {
+	tmpArr := strings.Split(injectObjectName, model.ObjectNameSplit)
+	switch {
+	case tmpArr[0] == "pod" && len(tmpArr) >= 3:
+		return &corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: tmpArr[1], Name: tmpArr[2]},
+		}
+	case tmpArr[0] == "node" && len(tmpArr) >= 2:
+		return &corev1.Node{
+			TypeMeta:   metav1.TypeMeta{Kind: "Node", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: tmpArr[1]},
+		}
+	default:
+		return nil
+	}
+}
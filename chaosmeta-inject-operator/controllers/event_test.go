@@ -0,0 +1,111 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"testing"
+)
+
+func newEventExperiment() *v1alpha1.Experiment {
+	return &v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "chaosmeta", Name: "exp"},
+		Spec: v1alpha1.ExperimentSpec{
+			Scope:      v1alpha1.PodScopeType,
+			Experiment: &v1alpha1.ExperimentCommon{Target: "cpu", Fault: "burn"},
+		},
+		Status: v1alpha1.ExperimentStatus{
+			Phase:  v1alpha1.InjectPhaseType,
+			Status: v1alpha1.RunningStatusType,
+			Detail: v1alpha1.ExperimentDetail{Inject: []v1alpha1.ExperimentDetailUnit{
+				{InjectObjectName: "pod/default/nginx-0/nginx", UID: "1", Status: v1alpha1.RunningStatusType},
+				{InjectObjectName: "pod/default/nginx-1/nginx", UID: "2", Status: v1alpha1.RunningStatusType},
+			}},
+		},
+	}
+}
+
+func Test_setConditions(t *testing.T) {
+	exp := newEventExperiment()
+	setConditions(exp)
+	assert.True(t, meta.IsStatusConditionTrue(exp.Status.Conditions, v1alpha1.SelectedConditionType))
+	assert.Equal(t, "Injecting", meta.FindStatusCondition(exp.Status.Conditions, v1alpha1.InjectedConditionType).Reason)
+	assert.True(t, meta.IsStatusConditionFalse(exp.Status.Conditions, v1alpha1.DegradedConditionType))
+
+	exp.Status.Status, exp.Status.Detail.Inject[1].Status = v1alpha1.PartSuccessStatusType, v1alpha1.FailedStatusType
+	setConditions(exp)
+	assert.True(t, meta.IsStatusConditionTrue(exp.Status.Conditions, v1alpha1.InjectedConditionType))
+	assert.True(t, meta.IsStatusConditionFalse(exp.Status.Conditions, v1alpha1.RecoveredConditionType))
+	degraded := meta.FindStatusCondition(exp.Status.Conditions, v1alpha1.DegradedConditionType)
+	assert.Equal(t, metav1.ConditionTrue, degraded.Status)
+	assert.Equal(t, "1 of 2 targets failed in inject phase", degraded.Message)
+
+	exp.Status.Phase, exp.Status.Status = v1alpha1.RecoverPhaseType, v1alpha1.SuccessStatusType
+	exp.Status.Detail.Recover = []v1alpha1.ExperimentDetailUnit{{InjectObjectName: "pod/default/nginx-0/nginx", UID: "1", Status: v1alpha1.SuccessStatusType}}
+	setConditions(exp)
+	assert.True(t, meta.IsStatusConditionTrue(exp.Status.Conditions, v1alpha1.RecoveredConditionType))
+	assert.True(t, meta.IsStatusConditionFalse(exp.Status.Conditions, v1alpha1.InjectedConditionType))
+	assert.True(t, meta.IsStatusConditionFalse(exp.Status.Conditions, v1alpha1.DegradedConditionType))
+	assert.Equal(t, 4, len(exp.Status.Conditions))
+
+	exp = &v1alpha1.Experiment{Status: v1alpha1.ExperimentStatus{Phase: v1alpha1.InjectPhaseType, Status: v1alpha1.FailedStatusType, Message: "no matching target"}}
+	setConditions(exp)
+	selected := meta.FindStatusCondition(exp.Status.Conditions, v1alpha1.SelectedConditionType)
+	assert.Equal(t, metav1.ConditionFalse, selected.Status)
+	assert.Equal(t, "no matching target", selected.Message)
+	assert.True(t, meta.IsStatusConditionTrue(exp.Status.Conditions, v1alpha1.DegradedConditionType))
+}
+
+func Test_recordEvents(t *testing.T) {
+	var (
+		recorder = record.NewFakeRecorder(10)
+		exp      = newEventExperiment()
+		snapshot = newStatusSnapshot(exp)
+	)
+
+	exp.Status.Status = v1alpha1.PartSuccessStatusType
+	exp.Status.Detail.Inject[0].Status, exp.Status.Detail.Inject[0].Message = v1alpha1.SuccessStatusType, "inject success"
+	exp.Status.Detail.Inject[1].Status, exp.Status.Detail.Inject[1].Message = v1alpha1.FailedStatusType, "inject failed"
+	recordEvents(recorder, exp, snapshot)
+
+	close(recorder.Events)
+	var events []string
+	for e := range recorder.Events {
+		events = append(events, e)
+	}
+
+	assert.Equal(t, []string{
+		"Warning InjectPartSuccess ",
+		"Normal InjectSuccess experiment chaosmeta/exp, fault cpu/burn: inject success",
+		"Warning InjectFailed experiment chaosmeta/exp, fault cpu/burn: inject failed",
+	}, events)
+}
+
+func Test_getEventTarget(t *testing.T) {
+	pod := getEventTarget("pod/default/nginx-0/nginx")
+	assert.Equal(t, "nginx-0", pod.(metav1.Object).GetName())
+	assert.Equal(t, "default", pod.(metav1.Object).GetNamespace())
+
+	node := getEventTarget("node/node-1/10.0.0.1")
+	assert.Equal(t, "node-1", node.(metav1.Object).GetName())
+
+	assert.Nil(t, getEventTarget("deployment/default/nginx"))
+}
@@ -21,18 +21,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/phasehandler"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/scopehandler"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	"math/rand"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/log"
-	"sort"
+	"strings"
 	"time"
 )
 
@@ -41,17 +42,21 @@ const defaultConcurrentReconciles = 10
 // ExperimentReconciler reconciles a Experiment object
 type ExperimentReconciler struct {
 	client.Client
+	Recorder record.EventRecorder
 	//RESTClient rest.Interface
 	//RESTConfig *rest.Config
 	//Scheme     *runtime.Scheme
 }
 
+//+kubebuilder:rbac:groups=chaosmeta.io,resources=chaospolicies,verbs=get;list;watch
 //+kubebuilder:rbac:groups=chaosmeta.io,resources=experiments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=chaosmeta.io,resources=experiments/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=chaosmeta.io,resources=experiments/finalizers,verbs=update
-//+kubebuilder:rbac:groups=core,resources=pods;pods/exec;services;namespaces;nodes,verbs=*
-//+kubebuilder:rbac:groups=apps,resources=deployments;daemonsets;replicasets;statefulsets,verbs=*
-//+kubebuilder:rbac:groups=batchs,resources=jobs,verbs=*
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=core,resources=pods;pods/ephemeralcontainers;pods/exec;services;namespaces;nodes;resourcequotas;configmaps;secrets,verbs=*
+//+kubebuilder:rbac:groups=apps,resources=deployments;deployments/scale;daemonsets;replicasets;statefulsets;statefulsets/scale,verbs=*
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=*
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=*
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -84,7 +89,7 @@ func (r *ExperimentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	if !instance.ObjectMeta.DeletionTimestamp.IsZero() {
 		if instance.Status.Status == v1alpha1.SuccessStatusType || instance.Status.Status == v1alpha1.FailedStatusType || instance.Status.Status == v1alpha1.PartSuccessStatusType {
-			if instance.Spec.TargetPhase == v1alpha1.InjectPhaseType && instance.Status.Phase == v1alpha1.InjectPhaseType {
+			if (instance.Status.Phase == v1alpha1.InjectPhaseType || instance.Status.Phase == v1alpha1.PausePhaseType) && instance.Spec.TargetPhase != v1alpha1.RecoverPhaseType {
 				instance.Spec.TargetPhase = v1alpha1.RecoverPhaseType
 				logger.Info(fmt.Sprintf("update TargetPhase of %s/%s to: %s", instance.Namespace, instance.Name, instance.Spec.TargetPhase))
 				return ctrl.Result{}, r.Update(ctx, instance)
@@ -103,11 +108,13 @@ func (r *ExperimentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 	}
 
+	snapshot := newStatusSnapshot(instance)
 	if instance.Status.Phase == "" {
 		initProcess(ctx, instance)
 	} else {
 		statusProcess(ctx, instance)
 	}
+	setConditions(instance)
 
 	status, _ = json.Marshal(instance.Status)
 	logger.Info(fmt.Sprintf("experiment: %s/%s, start to update status: %s", instance.Namespace, instance.Name, string(status)))
@@ -115,6 +122,7 @@ func (r *ExperimentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, fmt.Errorf("update instance error: %s", err.Error())
 	}
 
+	recordEvents(r.Recorder, instance, snapshot)
 	return ctrl.Result{}, nil
 }
 
@@ -135,21 +143,50 @@ func initProcess(ctx context.Context, instance *v1alpha1.Experiment) {
 		instance.Status.Status, instance.Status.Message = v1alpha1.FailedStatusType, "no matching target"
 		return
 	}
+	// process with chaos policy
+	policyList, err := selector.GetAnalyzer().GetChaosPolicyList(ctx)
+	if err != nil {
+		instance.Status.Status, instance.Status.Message = v1alpha1.FailedStatusType, fmt.Sprintf("get chaos policy error: %s", err.Error())
+		return
+	}
+	injectObjects, trimmed, err := solvePolicy(ctx, instance, injectObjects, policyList)
+	if err != nil {
+		instance.Status.Status, instance.Status.Message = v1alpha1.FailedStatusType, fmt.Sprintf("solve chaos policy error: %s", err.Error())
+		return
+	}
 	// process with range args
-	injectObjects = solveRange(injectObjects, instance.Spec.RangeMode)
+	injectObjects, reasons, err := solveRange(ctx, injectObjects, instance.Spec.RangeMode)
+	if err != nil {
+		instance.Status.Status, instance.Status.Message = v1alpha1.FailedStatusType, fmt.Sprintf("solve range mode error: %s", err.Error())
+		return
+	}
+	injectObjects, reasons, percentTrimmed, err := solvePodPercent(ctx, instance, injectObjects, reasons, policyList)
+	if err != nil {
+		instance.Status.Status, instance.Status.Message = v1alpha1.FailedStatusType, fmt.Sprintf("solve chaos policy error: %s", err.Error())
+		return
+	}
+	trimmed = append(trimmed, percentTrimmed...)
+	if len(injectObjects) == 0 && len(trimmed) != 0 {
+		instance.Status.Status, instance.Status.Message = v1alpha1.FailedStatusType, fmt.Sprintf("all targets are trimmed by chaos policy: %s", strings.Join(trimmed, "; "))
+		return
+	}
 	details := make([]v1alpha1.ExperimentDetailUnit, len(injectObjects))
 	for i, unitInjectObj := range injectObjects {
 		details[i] = v1alpha1.ExperimentDetailUnit{
 			InjectObjectName: unitInjectObj.GetObjectName(),
 			//InjectObjectInfo: string(objBytes),
-			UID:       newUid(),
+			UID:       common.NewUid(),
 			Status:    v1alpha1.CreatedStatusType,
 			Message:   "Initial experiment created",
 			StartTime: nowTime,
+			Reason:    reasons[i],
 		}
 	}
 
 	instance.Status.Message = "Initial experiment created"
+	if len(trimmed) != 0 {
+		instance.Status.Message = fmt.Sprintf("%s, %d targets trimmed by chaos policy: %s", instance.Status.Message, len(trimmed), strings.Join(trimmed, "; "))
+	}
 	instance.Status.Status, instance.Status.Detail.Inject = v1alpha1.CreatedStatusType, details
 }
 
@@ -170,43 +207,6 @@ func statusProcess(ctx context.Context, instance *v1alpha1.Experiment) {
 	}
 }
 
-func newUid() string {
-	t := time.Now()
-	timeStr := t.Format("20060102150405")
-	return fmt.Sprintf("%s%04d", timeStr, t.Nanosecond()/1000%100000%10000)
-}
-
-func solveRange(initial []model.AtomicObject, rangeMode *v1alpha1.RangeMode) []model.AtomicObject {
-	if rangeMode == nil || rangeMode.Type == v1alpha1.AllRangeType {
-		return initial
-	}
-
-	var count int
-	if rangeMode.Type == v1alpha1.CountRangeType {
-		count = rangeMode.Value
-	}
-
-	if rangeMode.Type == v1alpha1.PercentRangeType {
-		count = rangeMode.Value * len(initial) / 100
-	}
-
-	if count >= len(initial) {
-		return initial
-	}
-
-	rand.Seed(time.Now().Unix())
-	rand.Shuffle(len(initial), func(i int, j int) {
-		initial[i], initial[j] = initial[j], initial[i]
-	})
-
-	res := initial[:count]
-	sort.Slice(res, func(i, j int) bool {
-		return res[i].GetObjectName() < res[j].GetObjectName()
-	})
-
-	return res
-}
-
 // SetupWithManager sets up the controller with the Manager.
 func (r *ExperimentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Pod{}, selector.HostIPKey, func(rawObj client.Object) []string {
@@ -24,8 +24,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
 	mockscopehandler "github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/mock/scopehandler"
+	mockselector "github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/mock/selector"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/scopehandler"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"testing"
 )
@@ -99,7 +101,14 @@ func Test_solveRange(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := solveRange(tt.args.initial, tt.args.rangeMode)
+			got, reasons, err := solveRange(context.Background(), tt.args.initial, tt.args.rangeMode)
+			if err != nil {
+				t.Errorf("solveRange() error = %v", err)
+				return
+			}
+			if len(reasons) != len(got) {
+				t.Errorf("solveRange() reasons = %v, want %v", len(reasons), len(got))
+			}
 			if len(got) != tt.want {
 				t.Errorf("solveRange() = %v, want %v", len(got), tt.want)
 			}
@@ -164,9 +173,18 @@ func Test_initProcess(t *testing.T) {
 	scopeHandlerMock := mockscopehandler.NewMockScopeHandler(ctrl)
 	scopeHandlerMock.EXPECT().ConvertSelector(ctx, &exp.Spec).Return(reObject, nil)
 
-	gomonkey.ApplyFunc(scopehandler.GetScopeHandler, func(v1alpha1.ScopeType) scopehandler.ScopeHandler {
+	scopePatches := gomonkey.ApplyFunc(scopehandler.GetScopeHandler, func(v1alpha1.ScopeType) scopehandler.ScopeHandler {
 		return scopeHandlerMock
 	})
+	defer scopePatches.Reset()
+
+	analyzerMock := mockselector.NewMockIAnalyzer(ctrl)
+	analyzerMock.EXPECT().GetChaosPolicyList(ctx).Return(&v1alpha1.ChaosPolicyList{}, nil).AnyTimes()
+	analyzerMock.EXPECT().GetRangeInfo(ctx, gomock.Any(), gomock.Any()).Return(&model.RangeInfo{Ready: true}, nil).AnyTimes()
+	analyzerPatches := gomonkey.ApplyFunc(selector.GetAnalyzer, func() selector.IAnalyzer {
+		return analyzerMock
+	})
+	defer analyzerPatches.Reset()
 
 	initProcess(ctx, exp)
 	assert.Equal(t, "pod/chaosmeta/chaosmeta-0", exp.Status.Detail.Inject[0].InjectObjectName)
@@ -0,0 +1,232 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
+	"strings"
+	"time"
+)
+
+// solvePolicy rejects the experiment which violates ChaosPolicy, and trims the inject objects which are in protected
+// namespaces, have protected labels or are not allowed to be injected with the fault. Return the trimmed messages
+func solvePolicy(ctx context.Context, instance *v1alpha1.Experiment, objects []model.AtomicObject, policyList *v1alpha1.ChaosPolicyList) ([]model.AtomicObject, []string, error) {
+	if len(policyList.Items) == 0 {
+		return objects, nil, nil
+	}
+
+	if err := policyList.CheckExperiment(&instance.Spec, time.Now()); err != nil {
+		return nil, nil, err
+	}
+
+	needLabel := false
+	for _, policy := range policyList.Items {
+		if len(policy.Spec.ProtectedLabels) != 0 {
+			needLabel = true
+		}
+	}
+
+	var (
+		result  []model.AtomicObject
+		trimmed []string
+	)
+	for _, obj := range objects {
+		var labels map[string]string
+		if needLabel {
+			info, err := selector.GetAnalyzer().GetRangeInfo(ctx, obj, v1alpha1.DefaultTopologyKey)
+			if err != nil {
+				return nil, nil, fmt.Errorf("get labels of %s error: %s", obj.GetObjectName(), err.Error())
+			}
+			labels = info.Labels
+		}
+
+		reason := getPolicyTrimReason(policyList, instance.Spec.Experiment, getObjectNamespace(obj), labels)
+		if reason != "" {
+			trimmed = append(trimmed, fmt.Sprintf("%s: %s", obj.GetObjectName(), reason))
+			continue
+		}
+
+		result = append(result, obj)
+	}
+
+	return result, trimmed, nil
+}
+
+func getPolicyTrimReason(policyList *v1alpha1.ChaosPolicyList, exp *v1alpha1.ExperimentCommon, namespace string, labels map[string]string) string {
+	for _, policy := range policyList.Items {
+		if namespace != "" {
+			if policy.Spec.IsProtectedNamespace(namespace) {
+				return fmt.Sprintf("namespace %s is protected by ChaosPolicy[%s]", namespace, policy.Name)
+			}
+
+			if err := policy.Spec.CheckFault(namespace, exp.Target, exp.Fault); err != nil {
+				return fmt.Sprintf("%s by ChaosPolicy[%s]", err.Error(), policy.Name)
+			}
+		}
+
+		if label := policy.Spec.GetProtectedLabel(labels); label != "" {
+			return fmt.Sprintf("label %s is protected by ChaosPolicy[%s]", label, policy.Name)
+		}
+	}
+
+	return ""
+}
+
+// solvePodPercent trims the pods which make the hit pods of their workload exceed MaxPodPercent,
+// the pods hit by other experiments in inject phase are counted too. reasons are trimmed along with objects
+func solvePodPercent(ctx context.Context, instance *v1alpha1.Experiment, objects []model.AtomicObject, reasons []string,
+	policyList *v1alpha1.ChaosPolicyList) ([]model.AtomicObject, []string, []string, error) {
+	var (
+		maxPercent int
+		policyName string
+	)
+	for _, policy := range policyList.Items {
+		if policy.Spec.MaxPodPercent > 0 && (maxPercent == 0 || policy.Spec.MaxPodPercent < maxPercent) {
+			maxPercent, policyName = policy.Spec.MaxPodPercent, policy.Name
+		}
+	}
+
+	if maxPercent == 0 {
+		return objects, reasons, nil, nil
+	}
+
+	hitPods, err := getHitPods(ctx, instance)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var (
+		result       []model.AtomicObject
+		resultReason []string
+		trimmed      []string
+	)
+	for i, obj := range objects {
+		ns, podName, isPod := getPodKey(obj)
+		if !isPod {
+			result, resultReason = append(result, obj), append(resultReason, reasons[i])
+			continue
+		}
+
+		info, err := selector.GetAnalyzer().GetRangeInfo(ctx, obj, v1alpha1.DefaultTopologyKey)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("get owner of %s error: %s", obj.GetObjectName(), err.Error())
+		}
+
+		ownerKey, podKey := fmt.Sprintf("%s/%s", ns, info.Owner), fmt.Sprintf("%s/%s", ns, podName)
+		if info.Owner != "" && info.OwnerReplicas > 0 && !hitPods[ownerKey][podKey] {
+			allowed := info.OwnerReplicas * maxPercent / 100
+			if len(hitPods[ownerKey]) >= allowed {
+				trimmed = append(trimmed, fmt.Sprintf("%s: hit pods of %s would exceed %d%% of %d replicas limited by ChaosPolicy[%s]",
+					obj.GetObjectName(), info.Owner, maxPercent, info.OwnerReplicas, policyName))
+				continue
+			}
+
+			if hitPods[ownerKey] == nil {
+				hitPods[ownerKey] = make(map[string]bool)
+			}
+			hitPods[ownerKey][podKey] = true
+		}
+
+		result, resultReason = append(result, obj), append(resultReason, reasons[i])
+	}
+
+	return result, resultReason, trimmed, nil
+}
+
+// getHitPods return the pods grouped by "namespace/owner" which are hit by other experiments in inject phase
+func getHitPods(ctx context.Context, instance *v1alpha1.Experiment) (map[string]map[string]bool, error) {
+	analyzer := selector.GetAnalyzer()
+	expList, err := analyzer.GetExperimentListByPhase(ctx, string(v1alpha1.InjectPhaseType))
+	if err != nil {
+		return nil, err
+	}
+
+	hitPods := make(map[string]map[string]bool)
+	for _, exp := range expList.Items {
+		if (exp.Namespace == instance.Namespace && exp.Name == instance.Name) || exp.Status.Status == v1alpha1.FailedStatusType {
+			continue
+		}
+
+		for _, detail := range exp.Status.Detail.Inject {
+			if detail.Status == v1alpha1.FailedStatusType || !strings.HasPrefix(detail.InjectObjectName, "pod"+model.ObjectNameSplit) {
+				continue
+			}
+
+			ns, podName, err := model.ParsePodInfo(detail.InjectObjectName)
+			if err != nil {
+				continue
+			}
+
+			// pod may be deleted by the fault
+			info, err := analyzer.GetRangeInfo(ctx, &model.PodObject{Namespace: ns, PodName: podName}, v1alpha1.DefaultTopologyKey)
+			if err != nil || info.Owner == "" {
+				continue
+			}
+
+			ownerKey := fmt.Sprintf("%s/%s", ns, info.Owner)
+			if hitPods[ownerKey] == nil {
+				hitPods[ownerKey] = make(map[string]bool)
+			}
+			hitPods[ownerKey][fmt.Sprintf("%s/%s", ns, podName)] = true
+		}
+	}
+
+	return hitPods, nil
+}
+
+func getPodKey(obj model.AtomicObject) (string, string, bool) {
+	switch o := obj.(type) {
+	case *model.PodObject:
+		return o.Namespace, o.PodName, true
+	case *model.ContainerObject:
+		return o.Namespace, o.PodName, true
+	}
+
+	return "", "", false
+}
+
+// getObjectNamespace return "" for cluster objects such as node
+func getObjectNamespace(obj model.AtomicObject) string {
+	switch o := obj.(type) {
+	case *model.PodObject:
+		return o.Namespace
+	case *model.ContainerObject:
+		return o.Namespace
+	case *model.DeploymentObject:
+		return o.Namespace
+	case *model.StatefulSetObject:
+		return o.Namespace
+	case *model.DaemonSetObject:
+		return o.Namespace
+	case *model.JobObject:
+		return o.Namespace
+	case *model.ServiceObject:
+		return o.Namespace
+	case *model.ConfigMapObject:
+		return o.Namespace
+	case *model.SecretObject:
+		return o.Namespace
+	case *model.NamespaceObject:
+		return o.Namespace
+	}
+
+	return ""
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+)
+
+// setupPolicyTest creates deployment app/nginx with 4 pods, pod nginx-0 is hit by another running experiment
+func setupPolicyTest(t *testing.T) []model.AtomicObject {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme error: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme error: %v", err)
+	}
+
+	var (
+		replicas  = int32(4)
+		isCtrl    = true
+		objects   []client.Object
+		injectObj []model.AtomicObject
+	)
+	objects = append(objects,
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "nginx"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		},
+		&appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "nginx-5d4f",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "nginx", Controller: &isCtrl}}},
+			Spec: appsv1.ReplicaSetSpec{Replicas: &replicas},
+		},
+		&v1alpha1.Experiment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "chaosmeta", Name: "other"},
+			Status: v1alpha1.ExperimentStatus{
+				Phase:  v1alpha1.InjectPhaseType,
+				Status: v1alpha1.SuccessStatusType,
+				Detail: v1alpha1.ExperimentDetail{Inject: []v1alpha1.ExperimentDetailUnit{
+					{InjectObjectName: "pod/app/nginx-0/nginx", Status: v1alpha1.SuccessStatusType},
+				}},
+			},
+		},
+	)
+
+	for i := 0; i < 4; i++ {
+		podName := fmt.Sprintf("nginx-%d", i)
+		labels := map[string]string{"app": "nginx"}
+		if i == 3 {
+			labels["chaosmeta.io/protected"] = "true"
+		}
+		objects = append(objects, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "app",
+			Name:            podName,
+			Labels:          labels,
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "nginx-5d4f", Controller: &isCtrl}},
+		}})
+		injectObj = append(injectObj, &model.ContainerObject{Namespace: "app", PodName: podName, ContainerName: "nginx"})
+	}
+	injectObj = append(injectObj, &model.ContainerObject{Namespace: "kube-system", PodName: "coredns", ContainerName: "coredns"})
+	objects = append(objects, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "coredns"}})
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+		WithIndex(&v1alpha1.Experiment{}, selector.PhaseKey, func(rawObj client.Object) []string {
+			return []string{string(rawObj.(*v1alpha1.Experiment).Status.Phase)}
+		}).Build()
+	selector.SetupAnalyzer(cli)
+
+	return injectObj
+}
+
+func Test_solvePolicy(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		objects  = setupPolicyTest(t)
+		instance = &v1alpha1.Experiment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "chaosmeta", Name: "test"},
+			Spec: v1alpha1.ExperimentSpec{
+				Scope:      v1alpha1.PodScopeType,
+				Experiment: &v1alpha1.ExperimentCommon{Target: "cpu", Fault: "burn"},
+				Selector:   []v1alpha1.SelectorUnit{{Namespace: "app"}, {Namespace: "other"}},
+			},
+		}
+		policyList = &v1alpha1.ChaosPolicyList{Items: []v1alpha1.ChaosPolicy{{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec: v1alpha1.ChaosPolicySpec{
+				ProtectedNamespaces: []string{"kube-system"},
+				ProtectedLabels:     map[string]string{"chaosmeta.io/protected": "true"},
+				MaxPodPercent:       50,
+			},
+		}}}
+	)
+
+	got, trimmed, err := solvePolicy(ctx, instance, objects, policyList)
+	if err != nil {
+		t.Fatalf("solvePolicy() error = %v", err)
+	}
+	if len(got) != 3 || len(trimmed) != 2 {
+		t.Fatalf("solvePolicy() got %d objects and trimmed %v, want 3 objects and 2 trimmed", len(got), trimmed)
+	}
+
+	// nginx-0 is hit by other experiment, 50% of 4 replicas only allows one more pod
+	reasons := make([]string, len(got))
+	got, reasons, trimmed, err = solvePodPercent(ctx, instance, got, reasons, policyList)
+	if err != nil {
+		t.Fatalf("solvePodPercent() error = %v", err)
+	}
+	if len(got) != 2 || len(reasons) != 2 || len(trimmed) != 1 {
+		t.Fatalf("solvePodPercent() got %v and trimmed %v, want 2 objects and 1 trimmed", got, trimmed)
+	}
+	if got[0].GetObjectName() != "pod/app/nginx-0/nginx" || got[1].GetObjectName() != "pod/app/nginx-1/nginx" {
+		t.Errorf("solvePodPercent() got %s and %s", got[0].GetObjectName(), got[1].GetObjectName())
+	}
+
+	policyList.Items[0].Spec.AllowedFaults = []v1alpha1.NamespaceFaults{{Namespace: "app", Faults: []string{"pod/delete"}}}
+	if _, _, err = solvePolicy(ctx, instance, objects, policyList); err == nil {
+		t.Errorf("solvePolicy() expect error for not allowed fault")
+	}
+}
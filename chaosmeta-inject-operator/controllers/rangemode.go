@@ -0,0 +1,276 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+type rangeCandidate struct {
+	object model.AtomicObject
+	info   *model.RangeInfo
+}
+
+// solveRange picks the inject objects by rangeMode and returns the reason of each picked object.
+// Candidates are sorted by name before shuffled with seed, so the same seed always picks the same targets
+func solveRange(ctx context.Context, initial []model.AtomicObject, rangeMode *v1alpha1.RangeMode) ([]model.AtomicObject, []string, error) {
+	if rangeMode == nil {
+		rangeMode = &v1alpha1.RangeMode{Type: v1alpha1.AllRangeType}
+	}
+
+	if err := checkRangeMode(rangeMode); err != nil {
+		return nil, nil, err
+	}
+
+	candidates, err := getRangeCandidates(ctx, initial, rangeMode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	count := len(candidates)
+	if rangeMode.Type == v1alpha1.CountRangeType {
+		count = rangeMode.Value
+	} else if rangeMode.Type == v1alpha1.PercentRangeType {
+		count = rangeMode.Value * len(candidates) / 100
+	}
+
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+
+	seed := rangeMode.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].object.GetObjectName() < candidates[j].object.GetObjectName()
+	})
+	r := rand.New(rand.NewSource(seed))
+	r.Shuffle(len(candidates), func(i int, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	if rangeMode.Prefer != "" {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			if rangeMode.Prefer == v1alpha1.NewestPreferType {
+				return candidates[i].info.CreateTime.After(candidates[j].info.CreateTime)
+			}
+			return candidates[i].info.CreateTime.Before(candidates[j].info.CreateTime)
+		})
+	}
+
+	picked := pickCandidates(candidates, count, rangeMode)
+	reasons := make(map[string]string, len(picked))
+	for i, c := range picked {
+		reasons[c.object.GetObjectName()] = getRangeReason(c, rangeMode, i+1, len(candidates), seed)
+	}
+
+	sort.Slice(picked, func(i, j int) bool {
+		return picked[i].object.GetObjectName() < picked[j].object.GetObjectName()
+	})
+
+	var (
+		res       = make([]model.AtomicObject, len(picked))
+		resReason = make([]string, len(picked))
+	)
+	for i, c := range picked {
+		res[i], resReason[i] = c.object, reasons[c.object.GetObjectName()]
+	}
+
+	return res, resReason, nil
+}
+
+func checkRangeMode(rangeMode *v1alpha1.RangeMode) error {
+	switch rangeMode.Type {
+	case v1alpha1.AllRangeType, v1alpha1.CountRangeType, v1alpha1.PercentRangeType:
+	default:
+		return fmt.Errorf("not support range type: %s", rangeMode.Type)
+	}
+
+	switch rangeMode.Topology {
+	case "", v1alpha1.SpreadTopologyType, v1alpha1.ConcentrateTopologyType:
+	default:
+		return fmt.Errorf("not support topology type: %s", rangeMode.Topology)
+	}
+
+	switch rangeMode.Prefer {
+	case "", v1alpha1.NewestPreferType, v1alpha1.OldestPreferType:
+	default:
+		return fmt.Errorf("not support prefer type: %s", rangeMode.Prefer)
+	}
+
+	if rangeMode.Value < 0 || rangeMode.MaxPerNode < 0 {
+		return fmt.Errorf("value and maxPerNode of range mode must not be negative")
+	}
+
+	return nil
+}
+
+// getRangeCandidates queries the range info only when rangeMode needs, and excludes the not Ready objects if ReadyOnly
+func getRangeCandidates(ctx context.Context, initial []model.AtomicObject, rangeMode *v1alpha1.RangeMode) ([]*rangeCandidate, error) {
+	needInfo := rangeMode.ReadyOnly || rangeMode.MaxPerNode > 0 || rangeMode.Topology != "" || rangeMode.Prefer != ""
+	topologyKey := rangeMode.TopologyKey
+	if topologyKey == "" {
+		topologyKey = v1alpha1.DefaultTopologyKey
+	}
+
+	var candidates []*rangeCandidate
+	for _, obj := range initial {
+		c := &rangeCandidate{
+			object: obj,
+			info:   &model.RangeInfo{},
+		}
+
+		if needInfo {
+			info, err := selector.GetAnalyzer().GetRangeInfo(ctx, obj, topologyKey)
+			if err != nil {
+				return nil, fmt.Errorf("get range info of %s error: %s", obj.GetObjectName(), err.Error())
+			}
+
+			if rangeMode.ReadyOnly && !info.Ready {
+				continue
+			}
+			c.info = info
+		}
+
+		candidates = append(candidates, c)
+	}
+
+	return candidates, nil
+}
+
+// pickCandidates picks at most count candidates in order, skipping the ones on the node which reaches MaxPerNode
+func pickCandidates(candidates []*rangeCandidate, count int, rangeMode *v1alpha1.RangeMode) []*rangeCandidate {
+	var (
+		result    []*rangeCandidate
+		nodeCount = make(map[string]int)
+	)
+
+	tryPick := func(c *rangeCandidate) bool {
+		if rangeMode.MaxPerNode > 0 && c.info.NodeName != "" {
+			if nodeCount[c.info.NodeName] >= rangeMode.MaxPerNode {
+				return false
+			}
+			nodeCount[c.info.NodeName]++
+		}
+
+		result = append(result, c)
+		return true
+	}
+
+	if rangeMode.Topology == "" {
+		for _, c := range candidates {
+			if len(result) >= count {
+				break
+			}
+			tryPick(c)
+		}
+
+		return result
+	}
+
+	// groups keep the order of the first candidate of each topology
+	var (
+		groups     [][]*rangeCandidate
+		groupIndex = make(map[string]int)
+	)
+	for _, c := range candidates {
+		index, ok := groupIndex[c.info.Topology]
+		if !ok {
+			index = len(groups)
+			groupIndex[c.info.Topology] = index
+			groups = append(groups, nil)
+		}
+		groups[index] = append(groups[index], c)
+	}
+
+	if rangeMode.Topology == v1alpha1.ConcentrateTopologyType {
+		sort.SliceStable(groups, func(i, j int) bool {
+			return len(groups[i]) > len(groups[j])
+		})
+
+		for _, group := range groups {
+			for _, c := range group {
+				if len(result) >= count {
+					return result
+				}
+				tryPick(c)
+			}
+		}
+
+		return result
+	}
+
+	// spread: pick one from each topology in turn
+	for len(result) < count {
+		isPicked := false
+		for i := range groups {
+			if len(result) >= count {
+				break
+			}
+
+			for len(groups[i]) > 0 {
+				c := groups[i][0]
+				groups[i] = groups[i][1:]
+				if tryPick(c) {
+					isPicked = true
+					break
+				}
+			}
+		}
+
+		if !isPicked {
+			break
+		}
+	}
+
+	return result
+}
+
+func getRangeReason(c *rangeCandidate, rangeMode *v1alpha1.RangeMode, order, total int, seed int64) string {
+	reason := []string{fmt.Sprintf("range %s: picked as No.%d of %d candidates with seed %d", rangeMode.Type, order, total, seed)}
+	if rangeMode.Prefer != "" {
+		reason = append(reason, fmt.Sprintf("prefer %s created at %s", rangeMode.Prefer, c.info.CreateTime.Format(model.TimeFormat)))
+	}
+
+	if rangeMode.Topology != "" {
+		topologyKey := rangeMode.TopologyKey
+		if topologyKey == "" {
+			topologyKey = v1alpha1.DefaultTopologyKey
+		}
+		reason = append(reason, fmt.Sprintf("%s in %s=%s", rangeMode.Topology, topologyKey, c.info.Topology))
+	}
+
+	if rangeMode.MaxPerNode > 0 {
+		reason = append(reason, fmt.Sprintf("at most %d in node %s", rangeMode.MaxPerNode, c.info.NodeName))
+	}
+
+	if rangeMode.ReadyOnly {
+		reason = append(reason, "ready")
+	}
+
+	return strings.Join(reason, ", ")
+}
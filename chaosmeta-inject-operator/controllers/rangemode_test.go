@@ -0,0 +1,247 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+	"time"
+)
+
+// setupRangeTest creates 3 nodes in zone-a and 1 node in zone-b, each node runs 2 pods, pod-<node>-1 is not Ready
+func setupRangeTest() []model.AtomicObject {
+	var (
+		objects   []client.Object
+		result    []model.AtomicObject
+		startTime = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		nodeZone  = map[string]string{"node-1": "zone-a", "node-2": "zone-a", "node-3": "zone-a", "node-4": "zone-b"}
+	)
+
+	for i := 1; i <= 4; i++ {
+		nodeName := fmt.Sprintf("node-%d", i)
+		objects = append(objects, &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   nodeName,
+			Labels: map[string]string{"topology.kubernetes.io/zone": nodeZone[nodeName], v1alpha1.DefaultTopologyKey: nodeName},
+		}})
+
+		for j := 0; j < 2; j++ {
+			podName := fmt.Sprintf("pod-%s-%d", nodeName, j)
+			ready := corev1.ConditionTrue
+			if j == 1 {
+				ready = corev1.ConditionFalse
+			}
+			objects = append(objects, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         "test",
+					Name:              podName,
+					CreationTimestamp: metav1.NewTime(startTime.Add(time.Duration(i*10+j) * time.Minute)),
+				},
+				Spec:   corev1.PodSpec{NodeName: nodeName},
+				Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}}},
+			})
+			result = append(result, &model.PodObject{Namespace: "test", PodName: podName, NodeName: nodeName})
+		}
+	}
+
+	selector.SetupAnalyzer(fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).Build())
+	return result
+}
+
+func getRangeNodeCount(t *testing.T, objects []model.AtomicObject) map[string]int {
+	nodeCount := make(map[string]int)
+	for _, obj := range objects {
+		pod, ok := obj.(*model.PodObject)
+		if !ok {
+			t.Fatalf("unexpected object: %s", obj.GetObjectName())
+		}
+		nodeCount[pod.NodeName]++
+	}
+
+	return nodeCount
+}
+
+func Test_solveRangeWithSeed(t *testing.T) {
+	initial, ctx := setupRangeTest(), context.Background()
+	rangeMode := &v1alpha1.RangeMode{Type: v1alpha1.CountRangeType, Value: 3, Seed: 20230101}
+
+	first, firstReasons, err := solveRange(ctx, initial, rangeMode)
+	if err != nil {
+		t.Fatalf("solveRange() error = %v", err)
+	}
+
+	// the order of candidates should not affect the result
+	reversed := make([]model.AtomicObject, len(initial))
+	for i := range initial {
+		reversed[len(initial)-1-i] = initial[i]
+	}
+	second, secondReasons, err := solveRange(ctx, reversed, rangeMode)
+	if err != nil {
+		t.Fatalf("solveRange() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(first, second) || !reflect.DeepEqual(firstReasons, secondReasons) {
+		t.Errorf("solveRange() with same seed got %v and %v", first, second)
+	}
+}
+
+func Test_solveRangeWithTopology(t *testing.T) {
+	initial, ctx := setupRangeTest(), context.Background()
+
+	tests := []struct {
+		name      string
+		rangeMode *v1alpha1.RangeMode
+		check     func(t *testing.T, got []model.AtomicObject)
+		wantErr   bool
+	}{
+		{
+			name: "ready only",
+			rangeMode: &v1alpha1.RangeMode{
+				Type:      v1alpha1.AllRangeType,
+				ReadyOnly: true,
+			},
+			check: func(t *testing.T, got []model.AtomicObject) {
+				if len(got) != 4 {
+					t.Errorf("got %d targets, want 4", len(got))
+				}
+				for _, obj := range got {
+					if obj.(*model.PodObject).PodName[len(obj.(*model.PodObject).PodName)-1] != '0' {
+						t.Errorf("not ready target %s is picked", obj.GetObjectName())
+					}
+				}
+			},
+		},
+		{
+			name: "max per node",
+			rangeMode: &v1alpha1.RangeMode{
+				Type:       v1alpha1.PercentRangeType,
+				Value:      100,
+				MaxPerNode: 1,
+			},
+			check: func(t *testing.T, got []model.AtomicObject) {
+				nodeCount := getRangeNodeCount(t, got)
+				if len(got) != 4 || len(nodeCount) != 4 {
+					t.Errorf("got %d targets in %d nodes, want 4 in 4", len(got), len(nodeCount))
+				}
+			},
+		},
+		{
+			name: "spread zone",
+			rangeMode: &v1alpha1.RangeMode{
+				Type:        v1alpha1.CountRangeType,
+				Value:       2,
+				Topology:    v1alpha1.SpreadTopologyType,
+				TopologyKey: "topology.kubernetes.io/zone",
+			},
+			check: func(t *testing.T, got []model.AtomicObject) {
+				nodeCount := getRangeNodeCount(t, got)
+				if len(got) != 2 || nodeCount["node-4"] != 1 {
+					t.Errorf("got %v, want one target in zone-b", nodeCount)
+				}
+			},
+		},
+		{
+			name: "concentrate node",
+			rangeMode: &v1alpha1.RangeMode{
+				Type:     v1alpha1.CountRangeType,
+				Value:    2,
+				Topology: v1alpha1.ConcentrateTopologyType,
+			},
+			check: func(t *testing.T, got []model.AtomicObject) {
+				if nodeCount := getRangeNodeCount(t, got); len(nodeCount) != 1 {
+					t.Errorf("got %v, want all targets in one node", nodeCount)
+				}
+			},
+		},
+		{
+			name: "concentrate zone with max per node",
+			rangeMode: &v1alpha1.RangeMode{
+				Type:        v1alpha1.CountRangeType,
+				Value:       4,
+				Topology:    v1alpha1.ConcentrateTopologyType,
+				TopologyKey: "topology.kubernetes.io/zone",
+				MaxPerNode:  1,
+			},
+			check: func(t *testing.T, got []model.AtomicObject) {
+				nodeCount := getRangeNodeCount(t, got)
+				if len(got) != 4 || len(nodeCount) != 4 {
+					t.Errorf("got %v, want 4 targets in 4 nodes", nodeCount)
+				}
+			},
+		},
+		{
+			name: "prefer newest",
+			rangeMode: &v1alpha1.RangeMode{
+				Type:   v1alpha1.CountRangeType,
+				Value:  2,
+				Prefer: v1alpha1.NewestPreferType,
+			},
+			check: func(t *testing.T, got []model.AtomicObject) {
+				if nodeCount := getRangeNodeCount(t, got); nodeCount["node-4"] != 2 {
+					t.Errorf("got %v, want the pods in node-4", nodeCount)
+				}
+			},
+		},
+		{
+			name: "prefer oldest and ready",
+			rangeMode: &v1alpha1.RangeMode{
+				Type:      v1alpha1.CountRangeType,
+				Value:     1,
+				Prefer:    v1alpha1.OldestPreferType,
+				ReadyOnly: true,
+			},
+			check: func(t *testing.T, got []model.AtomicObject) {
+				if len(got) != 1 || got[0].(*model.PodObject).PodName != "pod-node-1-0" {
+					t.Errorf("got %v, want pod-node-1-0", got)
+				}
+			},
+		},
+		{
+			name: "unknown topology",
+			rangeMode: &v1alpha1.RangeMode{
+				Type:     v1alpha1.AllRangeType,
+				Topology: "random",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reasons, err := solveRange(ctx, initial, tt.rangeMode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("solveRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(reasons) != len(got) {
+				t.Errorf("solveRange() got %d reasons, want %d", len(reasons), len(got))
+			}
+			tt.check(t, got)
+		})
+	}
+}
@@ -29,7 +29,9 @@ import (
 
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/config"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/cloudnativeexecutor"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/metrics"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -67,10 +69,10 @@ func init() {
 }
 
 func main() {
-	//var metricsAddr string
+	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
-	//flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
+	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
@@ -84,8 +86,8 @@ func main() {
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme: scheme,
-		//MetricsBindAddress:     metricsAddr,
+		Scheme:                 scheme,
+		MetricsBindAddress:     metricsAddr,
 		Port:                   9443,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
@@ -129,9 +131,15 @@ func main() {
 	t := []injectv1alpha1.CloudTargetType{
 		injectv1alpha1.PodCloudTarget,
 		injectv1alpha1.DeploymentCloudTarget,
+		injectv1alpha1.StatefulsetCloudTarget,
+		injectv1alpha1.DaemonsetCloudTarget,
 		injectv1alpha1.NodeCloudTarget,
 		injectv1alpha1.NamespaceCloudTarget,
 		injectv1alpha1.JobCloudTarget,
+		injectv1alpha1.ServiceCloudTarget,
+		injectv1alpha1.NetworkPolicyCloudTarget,
+		injectv1alpha1.ConfigMapCloudTarget,
+		injectv1alpha1.SecretCloudTarget,
 	}
 
 	if err := restclient.SetApiServerClientMap(mgr.GetConfig(), mgr.GetScheme(), t); err != nil {
@@ -139,6 +147,15 @@ func main() {
 		os.Exit(1)
 	}
 	setupLog.Info(fmt.Sprintf("set APIServer for cloud object success: %v", t))
+	// chaosmeta-inject is deployed in the same namespace with daemon by default
+	if mainConfig.Backup.KeySecretNs == "" {
+		mainConfig.Backup.KeySecretNs = mainConfig.Executor.DaemonsetConfig.DaemonNs
+	}
+	if err := cloudnativeexecutor.SetupBackupKey(context.Background(), mainConfig.Backup.KeySecretNs, mainConfig.Backup.KeySecretName); err != nil {
+		setupLog.Error(err, "set backup key error")
+		os.Exit(1)
+	}
+	setupLog.Info("set backup key success")
 	err = initwebhook.InitCert(setupLog, ComponentInject)
 	if err != nil {
 		setupLog.Error(err, "init cert failed")
@@ -153,18 +170,32 @@ func main() {
 
 	// start watching
 	if err = (&controllers.ExperimentReconciler{
-		Client: mgr.GetClient(),
+		Client:   mgr.GetClient(),
+		Recorder: mgr.GetEventRecorderFor("chaosmeta-inject-operator"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Experiment")
 		os.Exit(1)
 	}
 
+	if err = (&controllers.ChaosScheduleReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ChaosSchedule")
+		os.Exit(1)
+	}
+
 	if err = (&injectv1alpha1.Experiment{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Experiment")
 		os.Exit(1)
 	}
 	//+kubebuilder:scaffold:builder
 
+	if err := metrics.RegisterExperimentCollector(mgr.GetClient()); err != nil {
+		setupLog.Error(err, "unable to register metrics collector")
+		os.Exit(1)
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -200,10 +231,16 @@ func autoRecoverChecker(ctx context.Context, interval int, c client.Client) {
 }
 
 func autoRecover(ctx context.Context, c client.Client) {
+	for _, phase := range []injectv1alpha1.PhaseType{injectv1alpha1.InjectPhaseType, injectv1alpha1.PausePhaseType} {
+		autoRecoverPhase(ctx, c, phase)
+	}
+}
+
+func autoRecoverPhase(ctx context.Context, c client.Client, phase injectv1alpha1.PhaseType) {
 	logger := log.FromContext(ctx)
-	expList, err := selector.GetAnalyzer().GetExperimentListByPhase(ctx, string(injectv1alpha1.InjectPhaseType))
+	expList, err := selector.GetAnalyzer().GetExperimentListByPhase(ctx, string(phase))
 	if err != nil {
-		logger.Error(err, fmt.Sprintf("get experiment list of phase[%s] error", injectv1alpha1.InjectPhaseType))
+		logger.Error(err, fmt.Sprintf("get experiment list of phase[%s] error", phase))
 		return
 	}
 
@@ -36,6 +36,96 @@ func (m *MockIAnalyzer) EXPECT() *MockIAnalyzerMockRecorder {
 	return m.recorder
 }
 
+// GetChaosPolicyList mocks base method.
+func (m *MockIAnalyzer) GetChaosPolicyList(ctx context.Context) (*v1alpha1.ChaosPolicyList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChaosPolicyList", ctx)
+	ret0, _ := ret[0].(*v1alpha1.ChaosPolicyList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChaosPolicyList indicates an expected call of GetChaosPolicyList.
+func (mr *MockIAnalyzerMockRecorder) GetChaosPolicyList(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChaosPolicyList", reflect.TypeOf((*MockIAnalyzer)(nil).GetChaosPolicyList), ctx)
+}
+
+// GetConfigMapListByLabel mocks base method.
+func (m *MockIAnalyzer) GetConfigMapListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.ConfigMapObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigMapListByLabel", ctx, namespace, label)
+	ret0, _ := ret[0].([]*model.ConfigMapObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigMapListByLabel indicates an expected call of GetConfigMapListByLabel.
+func (mr *MockIAnalyzerMockRecorder) GetConfigMapListByLabel(ctx, namespace, label interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigMapListByLabel", reflect.TypeOf((*MockIAnalyzer)(nil).GetConfigMapListByLabel), ctx, namespace, label)
+}
+
+// GetConfigMapListByName mocks base method.
+func (m *MockIAnalyzer) GetConfigMapListByName(ctx context.Context, namespace string, name []string) ([]*model.ConfigMapObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigMapListByName", ctx, namespace, name)
+	ret0, _ := ret[0].([]*model.ConfigMapObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigMapListByName indicates an expected call of GetConfigMapListByName.
+func (mr *MockIAnalyzerMockRecorder) GetConfigMapListByName(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigMapListByName", reflect.TypeOf((*MockIAnalyzer)(nil).GetConfigMapListByName), ctx, namespace, name)
+}
+
+// GetContainer mocks base method.
+func (m *MockIAnalyzer) GetContainer(ctx context.Context, ns, podName, containerName string) (*model.ContainerObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContainer", ctx, ns, podName, containerName)
+	ret0, _ := ret[0].(*model.ContainerObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContainer indicates an expected call of GetContainer.
+func (mr *MockIAnalyzerMockRecorder) GetContainer(ctx, ns, podName, containerName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainer", reflect.TypeOf((*MockIAnalyzer)(nil).GetContainer), ctx, ns, podName, containerName)
+}
+
+// GetDaemonSetListByLabel mocks base method.
+func (m *MockIAnalyzer) GetDaemonSetListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.DaemonSetObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDaemonSetListByLabel", ctx, namespace, label)
+	ret0, _ := ret[0].([]*model.DaemonSetObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDaemonSetListByLabel indicates an expected call of GetDaemonSetListByLabel.
+func (mr *MockIAnalyzerMockRecorder) GetDaemonSetListByLabel(ctx, namespace, label interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDaemonSetListByLabel", reflect.TypeOf((*MockIAnalyzer)(nil).GetDaemonSetListByLabel), ctx, namespace, label)
+}
+
+// GetDaemonSetListByName mocks base method.
+func (m *MockIAnalyzer) GetDaemonSetListByName(ctx context.Context, namespace string, name []string) ([]*model.DaemonSetObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDaemonSetListByName", ctx, namespace, name)
+	ret0, _ := ret[0].([]*model.DaemonSetObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDaemonSetListByName indicates an expected call of GetDaemonSetListByName.
+func (mr *MockIAnalyzerMockRecorder) GetDaemonSetListByName(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDaemonSetListByName", reflect.TypeOf((*MockIAnalyzer)(nil).GetDaemonSetListByName), ctx, namespace, name)
+}
+
 // GetDeploymentListByLabel mocks base method.
 func (m *MockIAnalyzer) GetDeploymentListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.DeploymentObject, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +171,66 @@ func (mr *MockIAnalyzerMockRecorder) GetExperimentListByPhase(ctx, phase interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExperimentListByPhase", reflect.TypeOf((*MockIAnalyzer)(nil).GetExperimentListByPhase), ctx, phase)
 }
 
+// GetJobListByLabel mocks base method.
+func (m *MockIAnalyzer) GetJobListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.JobObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobListByLabel", ctx, namespace, label)
+	ret0, _ := ret[0].([]*model.JobObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobListByLabel indicates an expected call of GetJobListByLabel.
+func (mr *MockIAnalyzerMockRecorder) GetJobListByLabel(ctx, namespace, label interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobListByLabel", reflect.TypeOf((*MockIAnalyzer)(nil).GetJobListByLabel), ctx, namespace, label)
+}
+
+// GetJobListByName mocks base method.
+func (m *MockIAnalyzer) GetJobListByName(ctx context.Context, namespace string, name []string) ([]*model.JobObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobListByName", ctx, namespace, name)
+	ret0, _ := ret[0].([]*model.JobObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobListByName indicates an expected call of GetJobListByName.
+func (mr *MockIAnalyzerMockRecorder) GetJobListByName(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobListByName", reflect.TypeOf((*MockIAnalyzer)(nil).GetJobListByName), ctx, namespace, name)
+}
+
+// GetNamespaceListByLabel mocks base method.
+func (m *MockIAnalyzer) GetNamespaceListByLabel(ctx context.Context, label map[string]string) ([]*model.NamespaceObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNamespaceListByLabel", ctx, label)
+	ret0, _ := ret[0].([]*model.NamespaceObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNamespaceListByLabel indicates an expected call of GetNamespaceListByLabel.
+func (mr *MockIAnalyzerMockRecorder) GetNamespaceListByLabel(ctx, label interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNamespaceListByLabel", reflect.TypeOf((*MockIAnalyzer)(nil).GetNamespaceListByLabel), ctx, label)
+}
+
+// GetNamespaceListByName mocks base method.
+func (m *MockIAnalyzer) GetNamespaceListByName(ctx context.Context, name []string) ([]*model.NamespaceObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNamespaceListByName", ctx, name)
+	ret0, _ := ret[0].([]*model.NamespaceObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNamespaceListByName indicates an expected call of GetNamespaceListByName.
+func (mr *MockIAnalyzerMockRecorder) GetNamespaceListByName(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNamespaceListByName", reflect.TypeOf((*MockIAnalyzer)(nil).GetNamespaceListByName), ctx, name)
+}
+
 // GetNodeListByLabel mocks base method.
 func (m *MockIAnalyzer) GetNodeListByLabel(ctx context.Context, label map[string]string, containerName string) ([]*model.NodeObject, error) {
 	m.ctrl.T.Helper()
@@ -126,6 +276,36 @@ func (mr *MockIAnalyzerMockRecorder) GetNodeListByNodeName(ctx, nodeName, contai
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodeListByNodeName", reflect.TypeOf((*MockIAnalyzer)(nil).GetNodeListByNodeName), ctx, nodeName, containerName)
 }
 
+// GetNodeListBySelector mocks base method.
+func (m *MockIAnalyzer) GetNodeListBySelector(ctx context.Context, unit v1alpha1.SelectorUnit, containerName string) ([]*model.NodeObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNodeListBySelector", ctx, unit, containerName)
+	ret0, _ := ret[0].([]*model.NodeObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNodeListBySelector indicates an expected call of GetNodeListBySelector.
+func (mr *MockIAnalyzerMockRecorder) GetNodeListBySelector(ctx, unit, containerName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodeListBySelector", reflect.TypeOf((*MockIAnalyzer)(nil).GetNodeListBySelector), ctx, unit, containerName)
+}
+
+// GetObjectListBySelector mocks base method.
+func (m *MockIAnalyzer) GetObjectListBySelector(ctx context.Context, target v1alpha1.CloudTargetType, unit v1alpha1.SelectorUnit) ([]model.AtomicObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetObjectListBySelector", ctx, target, unit)
+	ret0, _ := ret[0].([]model.AtomicObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObjectListBySelector indicates an expected call of GetObjectListBySelector.
+func (mr *MockIAnalyzerMockRecorder) GetObjectListBySelector(ctx, target, unit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectListBySelector", reflect.TypeOf((*MockIAnalyzer)(nil).GetObjectListBySelector), ctx, target, unit)
+}
+
 // GetPod mocks base method.
 func (m *MockIAnalyzer) GetPod(ctx context.Context, ns, podName, containerName string) (*model.PodObject, error) {
 	m.ctrl.T.Helper()
@@ -185,3 +365,123 @@ func (mr *MockIAnalyzerMockRecorder) GetPodListByPodName(ctx, namespace, podName
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPodListByPodName", reflect.TypeOf((*MockIAnalyzer)(nil).GetPodListByPodName), ctx, namespace, podName, containerName)
 }
+
+// GetPodListBySelector mocks base method.
+func (m *MockIAnalyzer) GetPodListBySelector(ctx context.Context, unit v1alpha1.SelectorUnit) ([]*model.PodObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPodListBySelector", ctx, unit)
+	ret0, _ := ret[0].([]*model.PodObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPodListBySelector indicates an expected call of GetPodListBySelector.
+func (mr *MockIAnalyzerMockRecorder) GetPodListBySelector(ctx, unit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPodListBySelector", reflect.TypeOf((*MockIAnalyzer)(nil).GetPodListBySelector), ctx, unit)
+}
+
+// GetRangeInfo mocks base method.
+func (m *MockIAnalyzer) GetRangeInfo(ctx context.Context, obj model.AtomicObject, topologyKey string) (*model.RangeInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRangeInfo", ctx, obj, topologyKey)
+	ret0, _ := ret[0].(*model.RangeInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRangeInfo indicates an expected call of GetRangeInfo.
+func (mr *MockIAnalyzerMockRecorder) GetRangeInfo(ctx, obj, topologyKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRangeInfo", reflect.TypeOf((*MockIAnalyzer)(nil).GetRangeInfo), ctx, obj, topologyKey)
+}
+
+// GetSecretListByLabel mocks base method.
+func (m *MockIAnalyzer) GetSecretListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.SecretObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecretListByLabel", ctx, namespace, label)
+	ret0, _ := ret[0].([]*model.SecretObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecretListByLabel indicates an expected call of GetSecretListByLabel.
+func (mr *MockIAnalyzerMockRecorder) GetSecretListByLabel(ctx, namespace, label interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecretListByLabel", reflect.TypeOf((*MockIAnalyzer)(nil).GetSecretListByLabel), ctx, namespace, label)
+}
+
+// GetSecretListByName mocks base method.
+func (m *MockIAnalyzer) GetSecretListByName(ctx context.Context, namespace string, name []string) ([]*model.SecretObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecretListByName", ctx, namespace, name)
+	ret0, _ := ret[0].([]*model.SecretObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecretListByName indicates an expected call of GetSecretListByName.
+func (mr *MockIAnalyzerMockRecorder) GetSecretListByName(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecretListByName", reflect.TypeOf((*MockIAnalyzer)(nil).GetSecretListByName), ctx, namespace, name)
+}
+
+// GetServiceListByLabel mocks base method.
+func (m *MockIAnalyzer) GetServiceListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.ServiceObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceListByLabel", ctx, namespace, label)
+	ret0, _ := ret[0].([]*model.ServiceObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceListByLabel indicates an expected call of GetServiceListByLabel.
+func (mr *MockIAnalyzerMockRecorder) GetServiceListByLabel(ctx, namespace, label interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceListByLabel", reflect.TypeOf((*MockIAnalyzer)(nil).GetServiceListByLabel), ctx, namespace, label)
+}
+
+// GetServiceListByName mocks base method.
+func (m *MockIAnalyzer) GetServiceListByName(ctx context.Context, namespace string, name []string) ([]*model.ServiceObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceListByName", ctx, namespace, name)
+	ret0, _ := ret[0].([]*model.ServiceObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceListByName indicates an expected call of GetServiceListByName.
+func (mr *MockIAnalyzerMockRecorder) GetServiceListByName(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceListByName", reflect.TypeOf((*MockIAnalyzer)(nil).GetServiceListByName), ctx, namespace, name)
+}
+
+// GetStatefulSetListByLabel mocks base method.
+func (m *MockIAnalyzer) GetStatefulSetListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.StatefulSetObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatefulSetListByLabel", ctx, namespace, label)
+	ret0, _ := ret[0].([]*model.StatefulSetObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatefulSetListByLabel indicates an expected call of GetStatefulSetListByLabel.
+func (mr *MockIAnalyzerMockRecorder) GetStatefulSetListByLabel(ctx, namespace, label interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatefulSetListByLabel", reflect.TypeOf((*MockIAnalyzer)(nil).GetStatefulSetListByLabel), ctx, namespace, label)
+}
+
+// GetStatefulSetListByName mocks base method.
+func (m *MockIAnalyzer) GetStatefulSetListByName(ctx context.Context, namespace string, name []string) ([]*model.StatefulSetObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatefulSetListByName", ctx, namespace, name)
+	ret0, _ := ret[0].([]*model.StatefulSetObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatefulSetListByName indicates an expected call of GetStatefulSetListByName.
+func (mr *MockIAnalyzerMockRecorder) GetStatefulSetListByName(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatefulSetListByName", reflect.TypeOf((*MockIAnalyzer)(nil).GetStatefulSetListByName), ctx, namespace, name)
+}
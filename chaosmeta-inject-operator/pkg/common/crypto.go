@@ -0,0 +1,104 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package common
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+)
+
+const BackupKeyLen = 32
+
+var backupKey []byte
+
+// SetBackupKey set the AES-256 key used to encrypt sensitive backup, such as the data of secret
+func SetBackupKey(key []byte) error {
+	if len(key) != BackupKeyLen {
+		return fmt.Errorf("length of backup key must be %d, but got %d", BackupKeyLen, len(key))
+	}
+
+	backupKey = key
+	return nil
+}
+
+func NewBackupKey() ([]byte, error) {
+	key := make([]byte, BackupKeyLen)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("generate key error: %s", err.Error())
+	}
+
+	return key, nil
+}
+
+// EncryptBackup encrypt with AES-GCM, the result is base64 of nonce and cipher text
+func EncryptBackup(plain []byte) (string, error) {
+	gcm, err := getBackupGCM()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("generate nonce error: %s", err.Error())
+	}
+
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, plain, nil)), nil
+}
+
+func DecryptBackup(encrypted string) ([]byte, error) {
+	gcm, err := getBackupGCM()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("decode base64 error: %s", err.Error())
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("encrypted data is too short")
+	}
+
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt error: %s", err.Error())
+	}
+
+	return plain, nil
+}
+
+func getBackupGCM() (cipher.AEAD, error) {
+	if backupKey == nil {
+		return nil, fmt.Errorf("backup key is not set")
+	}
+
+	block, err := aes.NewCipher(backupKey)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher error: %s", err.Error())
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create gcm error: %s", err.Error())
+	}
+
+	return gcm, nil
+}
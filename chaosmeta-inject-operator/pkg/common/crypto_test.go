@@ -0,0 +1,49 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBackupCrypto(t *testing.T) {
+	backupKey = nil
+	_, err := EncryptBackup([]byte("a"))
+	assert.NotEqual(t, nil, err)
+
+	assert.NotEqual(t, nil, SetBackupKey([]byte("short")))
+
+	key, err := NewBackupKey()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, SetBackupKey(key))
+
+	encrypted, err := EncryptBackup([]byte("password"))
+	assert.Equal(t, nil, err)
+	assert.NotContains(t, encrypted, "password")
+
+	plain, err := DecryptBackup(encrypted)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "password", string(plain))
+
+	_, err = DecryptBackup("bm90IGVuY3J5cHRlZA==")
+	assert.NotEqual(t, nil, err)
+
+	otherKey, _ := NewBackupKey()
+	_ = SetBackupKey(otherKey)
+	_, err = DecryptBackup(encrypted)
+	assert.NotEqual(t, nil, err)
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"math"
 	"strings"
 	"time"
 )
@@ -43,6 +44,36 @@ func IsTimeout(createTimeStr string, durationStr string) (bool, error) {
 	return createTime.Add(duration).Before(time.Now()), nil
 }
 
+// GetRemainingDuration returns the duration left before auto recover deadline in seconds, at least "1s"
+func GetRemainingDuration(createTimeStr string, durationStr string) (string, error) {
+	if durationStr == "" {
+		return "", nil
+	}
+
+	duration, err := v1alpha1.ConvertDuration(durationStr)
+	if err != nil {
+		return "", fmt.Errorf("get duration error: %s", err.Error())
+	}
+
+	createTime, err := time.ParseInLocation(model.TimeFormat, createTimeStr, time.Local)
+	if err != nil {
+		return "", fmt.Errorf("get createTime error: %s", err.Error())
+	}
+
+	remaining := int64(math.Ceil(time.Until(createTime.Add(duration)).Seconds()))
+	if remaining < 1 {
+		remaining = 1
+	}
+
+	return fmt.Sprintf("%ds", remaining), nil
+}
+
+func NewUid() string {
+	t := time.Now()
+	timeStr := t.Format("20060102150405")
+	return fmt.Sprintf("%s%04d", timeStr, t.Nanosecond()/1000%100000%10000)
+}
+
 func GetArgs(args []v1alpha1.ArgsUnit, keys []string) []string {
 	reList := make([]string, len(keys))
 	for i, k := range keys {
@@ -68,3 +99,8 @@ func IsNetErr(err error) bool {
 func IsNotFoundErr(err error) bool {
 	return strings.Index(err.Error(), "not found") >= 0
 }
+
+// IsAlreadyExistsErr the object created by the same experiment exists, usually because the last attempt is not recorded
+func IsAlreadyExistsErr(err error) bool {
+	return strings.Index(err.Error(), "already exists") >= 0
+}
@@ -97,6 +97,72 @@ func TestIsTimeout(t *testing.T) {
 	}
 }
 
+func TestGetRemainingDuration(t *testing.T) {
+	type args struct {
+		createTimeStr string
+		durationStr   string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "no auto recover",
+			args: args{
+				"",
+				"",
+			},
+			want: "",
+		},
+		{
+			name: "duration error",
+			args: args{
+				time.Now().Format(model.TimeFormat),
+				"5p",
+			},
+			wantErr: true,
+		},
+		{
+			name: "time format error",
+			args: args{
+				"2023-03-0319:01:50",
+				"5m",
+			},
+			wantErr: true,
+		},
+		{
+			name: "already timeout",
+			args: args{
+				time.Now().Add(-10 * time.Minute).Format(model.TimeFormat),
+				"5m",
+			},
+			want: "1s",
+		},
+		{
+			name: "remaining",
+			args: args{
+				time.Now().Add(time.Hour).Format(model.TimeFormat),
+				"1h",
+			},
+			want: "7200s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetRemainingDuration(tt.args.createTimeStr, tt.args.durationStr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetRemainingDuration() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetRemainingDuration() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetArgs(t *testing.T) {
 	type args struct {
 		args []v1alpha1.ArgsUnit
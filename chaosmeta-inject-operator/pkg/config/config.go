@@ -39,6 +39,13 @@ type MainConfig struct {
 	Worker   WorkerConfig   `json:"worker"`
 	Ticker   TickerConfig   `json:"ticker"`
 	Executor ExecutorConfig `json:"executor"`
+	Backup   BackupConfig   `json:"backup"`
+}
+
+// BackupConfig locates the secret which stores the key to encrypt sensitive backup, it will be created if not exists
+type BackupConfig struct {
+	KeySecretNs   string `json:"keySecretNs"`
+	KeySecretName string `json:"keySecretName"`
 }
 
 type WorkerConfig struct {
@@ -50,11 +57,15 @@ type TickerConfig struct {
 }
 
 type ExecutorConfig struct {
+	// Mode Optional: agent, daemonset, ephemeral. The ephemeral mode has no executor on the nodes, it only supports
+	// the network and process faults of pod scope, and node scope and pod containerkill/containerpause fail in the
+	// whole cluster
 	Mode            string                  `json:"mode"`
 	Executor        string                  `json:"executor"`
 	Version         string                  `json:"version"`
 	AgentConfig     AgentExecutorConfig     `json:"agentConfig"`
 	DaemonsetConfig DaemonsetExecutorConfig `json:"daemonsetConfig"`
+	EphemeralConfig EphemeralExecutorConfig `json:"ephemeralConfig"`
 }
 
 type AgentExecutorConfig struct {
@@ -70,3 +81,10 @@ type DaemonsetExecutorConfig struct {
 	AutoLabelNode     bool              `json:"autoLabelNode"`
 	NodeSelectorLabel map[string]string `json:"nodeSelectorLabel"`
 }
+
+// EphemeralExecutorConfig the image of ephemeral container must contain the executor in ExecPath.
+// Faults other than network and process are rejected, because they would take effect on the ephemeral container itself
+type EphemeralExecutorConfig struct {
+	Image    string `json:"image"`
+	ExecPath string `json:"execPath"`
+}
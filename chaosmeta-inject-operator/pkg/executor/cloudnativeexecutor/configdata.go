@@ -0,0 +1,434 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"strings"
+)
+
+const (
+	AddDataOp     = "add"
+	ReplaceDataOp = "replace"
+	RemoveDataOp  = "remove"
+	CorruptDataOp = "corrupt"
+
+	corruptSuffix       = "<chaosmeta-corrupted>"
+	rolloutAnnotation   = "chaosmeta.io/config-rollout"
+	rolloutPatchFmt     = `{"spec":{"template":{"metadata":{"annotations":{"%s":%s}}}}}`
+	backupKeyDataKey    = "key"
+	defaultBackupSecret = "chaosmeta-inject-backup-key"
+)
+
+// dataOp is a JSON-patch-like operation on a key of configmap or secret
+type dataOp struct {
+	Op    string `json:"op"`
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+}
+
+// configDataBackup record the original value of changed keys, nil value means the key not exists.
+// Data of secret is stored in Encrypted
+type configDataBackup struct {
+	Data      map[string]*string `json:"data,omitempty"`
+	Encrypted string             `json:"encrypted,omitempty"`
+	Workloads []string           `json:"workloads,omitempty"`
+}
+
+type workloadInfo struct {
+	target   v1alpha1.CloudTargetType
+	resource string
+}
+
+var workloadInfoMap = map[string]workloadInfo{
+	"deployment":  {target: v1alpha1.DeploymentCloudTarget, resource: "deployments"},
+	"statefulset": {target: v1alpha1.StatefulsetCloudTarget, resource: "statefulsets"},
+	"daemonset":   {target: v1alpha1.DaemonsetCloudTarget, resource: "daemonsets"},
+}
+
+// SetupBackupKey load the key to encrypt backup from secret, the secret will be created with a random key if not exists
+func SetupBackupKey(ctx context.Context, ns, name string) error {
+	if name == "" {
+		name = defaultBackupSecret
+	}
+
+	c, secret := restclient.GetApiServerClientMap(v1alpha1.SecretCloudTarget), &corev1.Secret{}
+	err := c.Get().Namespace(ns).Resource("secrets").Name(name).Do(ctx).Into(secret)
+	if err != nil {
+		if !common.IsNotFoundErr(err) {
+			return fmt.Errorf("get secret error: %s", err.Error())
+		}
+
+		key, err := common.NewBackupKey()
+		if err != nil {
+			return err
+		}
+
+		secret = &corev1.Secret{}
+		secret.Namespace, secret.Name = ns, name
+		secret.Data = map[string][]byte{backupKeyDataKey: key}
+		if err := c.Post().Namespace(ns).Resource("secrets").Body(secret).Do(ctx).Error(); err != nil {
+			// other replica may create it at the same time
+			if !strings.Contains(err.Error(), "already exists") {
+				return fmt.Errorf("create secret error: %s", err.Error())
+			}
+
+			if err := c.Get().Namespace(ns).Resource("secrets").Name(name).Do(ctx).Into(secret); err != nil {
+				return fmt.Errorf("get secret error: %s", err.Error())
+			}
+		}
+	}
+
+	return common.SetBackupKey(secret.Data[backupKeyDataKey])
+}
+
+func parseDataOps(args []v1alpha1.ArgsUnit) ([]dataOp, bool, error) {
+	reArgs := common.GetArgs(args, []string{"patch", "rollout"})
+	if reArgs[0] == "" {
+		return nil, false, fmt.Errorf("\"patch\" is empty")
+	}
+
+	var ops []dataOp
+	if err := json.Unmarshal([]byte(reArgs[0]), &ops); err != nil {
+		return nil, false, fmt.Errorf("\"patch\" is not a json list: %s", err.Error())
+	}
+
+	if len(ops) == 0 {
+		return nil, false, fmt.Errorf("\"patch\" is empty")
+	}
+
+	for _, unit := range ops {
+		if unit.Key == "" {
+			return nil, false, fmt.Errorf("key of op is empty")
+		}
+
+		if unit.Op != AddDataOp && unit.Op != ReplaceDataOp && unit.Op != RemoveDataOp && unit.Op != CorruptDataOp {
+			return nil, false, fmt.Errorf("not support op: %s, only support: %s, %s, %s, %s",
+				unit.Op, AddDataOp, ReplaceDataOp, RemoveDataOp, CorruptDataOp)
+		}
+	}
+
+	return ops, reArgs[1] == "true", nil
+}
+
+// applyDataOps apply ops in order, return the new value and original value of changed keys, nil value means not exists
+func applyDataOps(data map[string]string, ops []dataOp) (newData, oldData map[string]*string, err error) {
+	newData, oldData = make(map[string]*string), make(map[string]*string)
+	getNow := func(k string) (string, bool) {
+		if v, ok := newData[k]; ok {
+			if v == nil {
+				return "", false
+			}
+			return *v, true
+		}
+
+		v, ok := data[k]
+		return v, ok
+	}
+
+	for _, unit := range ops {
+		nowValue, isExist := getNow(unit.Key)
+		if _, ok := oldData[unit.Key]; !ok {
+			if origin, ok := data[unit.Key]; ok {
+				oldData[unit.Key] = &origin
+			} else {
+				oldData[unit.Key] = nil
+			}
+		}
+
+		switch unit.Op {
+		case AddDataOp:
+			value := unit.Value
+			newData[unit.Key] = &value
+		case ReplaceDataOp:
+			if !isExist {
+				return nil, nil, fmt.Errorf("key[%s] to replace not exists", unit.Key)
+			}
+			value := unit.Value
+			newData[unit.Key] = &value
+		case RemoveDataOp:
+			if !isExist {
+				return nil, nil, fmt.Errorf("key[%s] to remove not exists", unit.Key)
+			}
+			newData[unit.Key] = nil
+		case CorruptDataOp:
+			if !isExist {
+				return nil, nil, fmt.Errorf("key[%s] to corrupt not exists", unit.Key)
+			}
+			runes := []rune(nowValue)
+			value := string(runes[:len(runes)/2]) + corruptSuffix
+			newData[unit.Key] = &value
+		}
+	}
+
+	return newData, oldData, nil
+}
+
+// podSpecUsesObject check whether the pod spec consumes the configmap or secret by volume, env or envFrom
+func podSpecUsesObject(spec *corev1.PodSpec, target v1alpha1.CloudTargetType, name string) bool {
+	isTarget := func(cm *corev1.LocalObjectReference, secret string) bool {
+		if target == v1alpha1.ConfigMapCloudTarget {
+			return cm != nil && cm.Name == name
+		}
+		return secret != "" && secret == name
+	}
+
+	for _, unitVolume := range spec.Volumes {
+		if unitVolume.ConfigMap != nil && isTarget(&unitVolume.ConfigMap.LocalObjectReference, "") {
+			return true
+		}
+		if unitVolume.Secret != nil && isTarget(nil, unitVolume.Secret.SecretName) {
+			return true
+		}
+		if unitVolume.Projected != nil {
+			for _, unitSource := range unitVolume.Projected.Sources {
+				if unitSource.ConfigMap != nil && isTarget(&unitSource.ConfigMap.LocalObjectReference, "") {
+					return true
+				}
+				if unitSource.Secret != nil && isTarget(nil, unitSource.Secret.Name) {
+					return true
+				}
+			}
+		}
+	}
+
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, unitContainer := range containers {
+		for _, unitEnvFrom := range unitContainer.EnvFrom {
+			if unitEnvFrom.ConfigMapRef != nil && isTarget(&unitEnvFrom.ConfigMapRef.LocalObjectReference, "") {
+				return true
+			}
+			if unitEnvFrom.SecretRef != nil && isTarget(nil, unitEnvFrom.SecretRef.Name) {
+				return true
+			}
+		}
+
+		for _, unitEnv := range unitContainer.Env {
+			if unitEnv.ValueFrom == nil {
+				continue
+			}
+			if unitEnv.ValueFrom.ConfigMapKeyRef != nil && isTarget(&unitEnv.ValueFrom.ConfigMapKeyRef.LocalObjectReference, "") {
+				return true
+			}
+			if unitEnv.ValueFrom.SecretKeyRef != nil && isTarget(nil, unitEnv.ValueFrom.SecretKeyRef.Name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// getConsumerWorkloads return the workloads in namespace which consume the configmap or secret, format: deployment/name
+func getConsumerWorkloads(ctx context.Context, ns string, target v1alpha1.CloudTargetType, name string) ([]string, error) {
+	var result []string
+	deployList := &appsv1.DeploymentList{}
+	if err := restclient.GetApiServerClientMap(v1alpha1.DeploymentCloudTarget).Get().Namespace(ns).
+		Resource("deployments").Do(ctx).Into(deployList); err != nil {
+		return nil, fmt.Errorf("list deployments error: %s", err.Error())
+	}
+	for _, unit := range deployList.Items {
+		if podSpecUsesObject(&unit.Spec.Template.Spec, target, name) {
+			result = append(result, fmt.Sprintf("%s%s%s", "deployment", model.ObjectNameSplit, unit.Name))
+		}
+	}
+
+	stsList := &appsv1.StatefulSetList{}
+	if err := restclient.GetApiServerClientMap(v1alpha1.StatefulsetCloudTarget).Get().Namespace(ns).
+		Resource("statefulsets").Do(ctx).Into(stsList); err != nil {
+		return nil, fmt.Errorf("list statefulsets error: %s", err.Error())
+	}
+	for _, unit := range stsList.Items {
+		if podSpecUsesObject(&unit.Spec.Template.Spec, target, name) {
+			result = append(result, fmt.Sprintf("%s%s%s", "statefulset", model.ObjectNameSplit, unit.Name))
+		}
+	}
+
+	dsList := &appsv1.DaemonSetList{}
+	if err := restclient.GetApiServerClientMap(v1alpha1.DaemonsetCloudTarget).Get().Namespace(ns).
+		Resource("daemonsets").Do(ctx).Into(dsList); err != nil {
+		return nil, fmt.Errorf("list daemonsets error: %s", err.Error())
+	}
+	for _, unit := range dsList.Items {
+		if podSpecUsesObject(&unit.Spec.Template.Spec, target, name) {
+			result = append(result, fmt.Sprintf("%s%s%s", "daemonset", model.ObjectNameSplit, unit.Name))
+		}
+	}
+
+	return result, nil
+}
+
+// patchRollout trigger rollout by changing the annotation of pod template, null value removes the annotation
+func patchRollout(ctx context.Context, ns, workload, value string, ignoreNotFound bool) error {
+	tmpArr := strings.Split(workload, model.ObjectNameSplit)
+	if len(tmpArr) != 2 {
+		return fmt.Errorf("unexpected format of workload: %s", workload)
+	}
+
+	info, ok := workloadInfoMap[tmpArr[0]]
+	if !ok {
+		return fmt.Errorf("not support workload kind: %s", tmpArr[0])
+	}
+
+	if err := restclient.GetApiServerClientMap(info.target).Patch(types.MergePatchType).Namespace(ns).
+		Resource(info.resource).Name(tmpArr[1]).Body([]byte(fmt.Sprintf(rolloutPatchFmt, rolloutAnnotation, value))).
+		Do(ctx).Error(); err != nil && !(ignoreNotFound && common.IsNotFoundErr(err)) {
+		return fmt.Errorf("patch %s error: %s", workload, err.Error())
+	}
+
+	return nil
+}
+
+// injectConfigData change the data of configmap or secret by patchFunc, and trigger rollout of consumers if needed
+func injectConfigData(ctx context.Context, target v1alpha1.CloudTargetType, ns, name, uid string,
+	args []v1alpha1.ArgsUnit, getDataFunc func() (map[string]string, error),
+	patchFunc func(newData map[string]*string) error) (string, error) {
+	ops, isRollout, err := parseDataOps(args)
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	data, err := getDataFunc()
+	if err != nil {
+		return "", err
+	}
+
+	newData, oldData, err := applyDataOps(data, ops)
+	if err != nil {
+		return "", fmt.Errorf("apply patch error: %s", err.Error())
+	}
+
+	backup := &configDataBackup{}
+	if target == v1alpha1.SecretCloudTarget {
+		// values of secret may be binary, so encode them as []byte
+		secretData := make(map[string]*[]byte)
+		for k, v := range oldData {
+			if v != nil {
+				value := []byte(*v)
+				secretData[k] = &value
+			} else {
+				secretData[k] = nil
+			}
+		}
+
+		oldBytes, err := json.Marshal(secretData)
+		if err != nil {
+			return "", fmt.Errorf("old data to string error: %s", err.Error())
+		}
+
+		if backup.Encrypted, err = common.EncryptBackup(oldBytes); err != nil {
+			return "", fmt.Errorf("encrypt backup error: %s", err.Error())
+		}
+	} else {
+		backup.Data = oldData
+	}
+
+	getBackup := func() string {
+		backupBytes, _ := json.Marshal(backup)
+		return string(backupBytes)
+	}
+
+	if err := patchFunc(newData); err != nil {
+		return getBackup(), err
+	}
+
+	if isRollout {
+		// the failed inject is undone here, because the backup returned with an error is not kept
+		undo := func(injectErr error) (string, error) {
+			if err := undoConfigData(ctx, ns, oldData, backup.Workloads, patchFunc); err != nil {
+				return getBackup(), fmt.Errorf("%s, undo error: %s", injectErr.Error(), err.Error())
+			}
+
+			return "", injectErr
+		}
+
+		workloads, err := getConsumerWorkloads(ctx, ns, target, name)
+		if err != nil {
+			return undo(fmt.Errorf("get consumer workloads error: %s", err.Error()))
+		}
+
+		for _, unit := range workloads {
+			if err := patchRollout(ctx, ns, unit, fmt.Sprintf("\"%s\"", uid), false); err != nil {
+				return undo(err)
+			}
+			backup.Workloads = append(backup.Workloads, unit)
+		}
+	}
+
+	return getBackup(), nil
+}
+
+// undoConfigData restore the original data by patchFunc, and remove the rollout annotation from the patched workloads
+func undoConfigData(ctx context.Context, ns string, oldData map[string]*string, workloads []string,
+	patchFunc func(data map[string]*string) error) error {
+	if err := patchFunc(oldData); err != nil {
+		return err
+	}
+
+	for _, unit := range workloads {
+		if err := patchRollout(ctx, ns, unit, "null", true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recoverConfigData restore the original data by patchFunc, and remove the rollout annotation to roll out again
+func recoverConfigData(ctx context.Context, ns, backup string, patchFunc func(oldData map[string]*string) error) error {
+	if backup == "" {
+		return nil
+	}
+
+	oldInfo := &configDataBackup{}
+	if err := json.Unmarshal([]byte(backup), oldInfo); err != nil {
+		return fmt.Errorf("get backup info error: %s", err.Error())
+	}
+
+	oldData := oldInfo.Data
+	if oldInfo.Encrypted != "" {
+		plain, err := common.DecryptBackup(oldInfo.Encrypted)
+		if err != nil {
+			return fmt.Errorf("decrypt backup error: %s", err.Error())
+		}
+
+		var secretData map[string]*[]byte
+		if err := json.Unmarshal(plain, &secretData); err != nil {
+			return fmt.Errorf("get old data error: %s", err.Error())
+		}
+
+		oldData = make(map[string]*string)
+		for k, v := range secretData {
+			if v != nil {
+				value := string(*v)
+				oldData[k] = &value
+			} else {
+				oldData[k] = nil
+			}
+		}
+	}
+
+	return undoConfigData(ctx, ns, oldData, oldInfo.Workloads, patchFunc)
+}
@@ -0,0 +1,171 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	corev1 "k8s.io/api/core/v1"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func Test_applyDataOps(t *testing.T) {
+	data := map[string]string{
+		"a": "value-a",
+		"b": "value-b",
+		"c": "12345678",
+	}
+
+	newData, oldData, err := applyDataOps(data, []dataOp{
+		{Op: ReplaceDataOp, Key: "a", Value: "new-a"},
+		{Op: RemoveDataOp, Key: "b"},
+		{Op: CorruptDataOp, Key: "c"},
+		{Op: AddDataOp, Key: "d", Value: "value-d"},
+		{Op: ReplaceDataOp, Key: "d", Value: "new-d"},
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[string]*string{
+		"a": strPtr("new-a"),
+		"b": nil,
+		"c": strPtr("1234" + corruptSuffix),
+		"d": strPtr("new-d"),
+	}, newData)
+	assert.Equal(t, map[string]*string{
+		"a": strPtr("value-a"),
+		"b": strPtr("value-b"),
+		"c": strPtr("12345678"),
+		"d": nil,
+	}, oldData)
+
+	_, _, err = applyDataOps(data, []dataOp{{Op: RemoveDataOp, Key: "b"}, {Op: CorruptDataOp, Key: "b"}})
+	assert.NotEqual(t, nil, err)
+
+	_, _, err = applyDataOps(data, []dataOp{{Op: ReplaceDataOp, Key: "x", Value: "y"}})
+	assert.NotEqual(t, nil, err)
+}
+
+func Test_parseDataOps(t *testing.T) {
+	ops, isRollout, err := parseDataOps([]v1alpha1.ArgsUnit{
+		{Key: "patch", Value: `[{"op":"remove","key":"a"}]`},
+		{Key: "rollout", Value: "true"},
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, isRollout)
+	assert.Equal(t, []dataOp{{Op: RemoveDataOp, Key: "a"}}, ops)
+
+	_, _, err = parseDataOps([]v1alpha1.ArgsUnit{{Key: "patch", Value: `[{"op":"move","key":"a"}]`}})
+	assert.NotEqual(t, nil, err)
+
+	_, _, err = parseDataOps([]v1alpha1.ArgsUnit{{Key: "patch", Value: `[{"op":"remove"}]`}})
+	assert.NotEqual(t, nil, err)
+
+	_, _, err = parseDataOps(nil)
+	assert.NotEqual(t, nil, err)
+}
+
+func Test_podSpecUsesObject(t *testing.T) {
+	spec := &corev1.PodSpec{
+		Volumes: []corev1.Volume{
+			{VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "cm-volume"}}}},
+		},
+		Containers: []corev1.Container{
+			{
+				EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "secret-env"}}}},
+			},
+		},
+	}
+
+	assert.Equal(t, true, podSpecUsesObject(spec, v1alpha1.ConfigMapCloudTarget, "cm-volume"))
+	assert.Equal(t, false, podSpecUsesObject(spec, v1alpha1.SecretCloudTarget, "cm-volume"))
+	assert.Equal(t, true, podSpecUsesObject(spec, v1alpha1.SecretCloudTarget, "secret-env"))
+	assert.Equal(t, false, podSpecUsesObject(spec, v1alpha1.ConfigMapCloudTarget, "other"))
+}
+
+func Test_secretDataBackup(t *testing.T) {
+	key, _ := common.NewBackupKey()
+	assert.Equal(t, nil, common.SetBackupKey(key))
+
+	ctx, binary := context.Background(), string([]byte{0xff, 0x00, 0xfe})
+	data := map[string]string{"password": "plain-password", "cert": binary}
+	var patched map[string]*string
+	patchFunc := func(newData map[string]*string) error {
+		patched = newData
+		return nil
+	}
+
+	backup, err := injectConfigData(ctx, v1alpha1.SecretCloudTarget, "ns", "name", "uid", []v1alpha1.ArgsUnit{
+		{Key: "patch", Value: `[{"op":"replace","key":"password","value":"wrong"},{"op":"remove","key":"cert"}]`},
+	}, func() (map[string]string, error) {
+		return data, nil
+	}, patchFunc)
+	assert.Equal(t, nil, err)
+	assert.NotContains(t, backup, "plain-password")
+	assert.Equal(t, map[string]*string{"password": strPtr("wrong"), "cert": nil}, patched)
+
+	assert.Equal(t, nil, recoverConfigData(ctx, "ns", backup, patchFunc))
+	assert.Equal(t, map[string]*string{"password": strPtr("plain-password"), "cert": strPtr(binary)}, patched)
+}
+
+func Test_injectConfigData_rolloutError(t *testing.T) {
+	deploy := func(name string) string {
+		return `{"metadata":{"name":"` + name + `"},"spec":{"template":{"spec":{"containers":[{"name":"c",` +
+			`"envFrom":[{"configMapRef":{"name":"cm1"}}]}]}}}}`
+	}
+	getReqs := startFakeApiServer(t, []v1alpha1.CloudTargetType{v1alpha1.DeploymentCloudTarget,
+		v1alpha1.StatefulsetCloudTarget, v1alpha1.DaemonsetCloudTarget}, func(r fakeRequest) (int, string) {
+		switch {
+		case r.Method == http.MethodPatch && r.Path == "/apis/apps/v1/namespaces/ns/deployments/d2" && !strings.Contains(r.Body, "null"):
+			return http.StatusInternalServerError, `{"kind":"Status","apiVersion":"v1","status":"Failure","message":"internal error","code":500}`
+		case r.Path == "/apis/apps/v1/namespaces/ns/deployments":
+			return http.StatusOK, `{"kind":"DeploymentList","apiVersion":"apps/v1","items":[` + deploy("d1") + `,` + deploy("d2") + `]}`
+		case r.Method == http.MethodGet:
+			return http.StatusOK, `{"apiVersion":"apps/v1","items":[]}`
+		}
+		return http.StatusOK, `{}`
+	})
+
+	var patched map[string]*string
+	backup, err := injectConfigData(context.Background(), v1alpha1.ConfigMapCloudTarget, "ns", "cm1", "uid", []v1alpha1.ArgsUnit{
+		{Key: "patch", Value: `[{"op":"replace","key":"a","value":"new-a"}]`},
+		{Key: "rollout", Value: "true"},
+	}, func() (map[string]string, error) {
+		return map[string]string{"a": "value-a"}, nil
+	}, func(newData map[string]*string) error {
+		patched = newData
+		return nil
+	})
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, "", backup)
+	assert.Equal(t, map[string]*string{"a": strPtr("value-a")}, patched)
+
+	var undone []string
+	for _, r := range getReqs() {
+		if r.Method == http.MethodPatch && strings.Contains(r.Body, "null") {
+			undone = append(undone, r.Path)
+		}
+	}
+	assert.Equal(t, []string{"/apis/apps/v1/namespaces/ns/deployments/d1"}, undone)
+}
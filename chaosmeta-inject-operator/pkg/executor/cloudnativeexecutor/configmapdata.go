@@ -0,0 +1,88 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"time"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.ConfigMapCloudTarget, "data", &ConfigMapDataExecutor{})
+}
+
+// ConfigMapDataExecutor change, remove or corrupt the keys of configmap data by "patch" args like
+// [{"op":"replace","key":"a","value":"b"}], consumers will be rolled out when "rollout" is true
+type ConfigMapDataExecutor struct{}
+
+func (e *ConfigMapDataExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseConfigMapInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected configmap format: %s", err.Error())
+	}
+
+	return injectConfigData(ctx, v1alpha1.ConfigMapCloudTarget, ns, name, uid, args, func() (map[string]string, error) {
+		cm := &corev1.ConfigMap{}
+		if err := restclient.GetApiServerClientMap(v1alpha1.ConfigMapCloudTarget).Get().Namespace(ns).
+			Resource("configmaps").Name(name).Do(ctx).Into(cm); err != nil {
+			return nil, fmt.Errorf("get configmap error: %s", err.Error())
+		}
+
+		return cm.Data, nil
+	}, func(newData map[string]*string) error {
+		return patchConfigMapData(ctx, ns, name, newData)
+	})
+}
+
+func (e *ConfigMapDataExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseConfigMapInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected configmap format: %s", err.Error())
+	}
+
+	return recoverConfigData(ctx, ns, backup, func(oldData map[string]*string) error {
+		return patchConfigMapData(ctx, ns, name, oldData)
+	})
+}
+
+func (e *ConfigMapDataExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
+
+func patchConfigMapData(ctx context.Context, ns, name string, data map[string]*string) error {
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("data to string error: %s", err.Error())
+	}
+
+	if err := restclient.GetApiServerClientMap(v1alpha1.ConfigMapCloudTarget).Patch(types.MergePatchType).Namespace(ns).
+		Resource("configmaps").Name(name).Body([]byte(fmt.Sprintf(`{"data":%s}`, dataBytes))).Do(ctx).Error(); err != nil {
+		return fmt.Errorf("patch configmap error: %s", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	v1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"strings"
+	"time"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.DaemonsetCloudTarget, "nodeselector", &DaemonSetNodeSelectorExecutor{})
+}
+
+const (
+	defaultShrinkSelector = "chaosmeta.io/daemonset-shrink=true"
+	nodeSelectorPatchFmt  = `{"spec":{"template":{"spec":{"nodeSelector":%s}}}}`
+)
+
+// DaemonSetNodeSelectorExecutor shrink the daemonset by adding node selector of pod template, pods on the nodes
+// which not match the selector will be deleted. The default selector matches no node, so all pods will be deleted
+type DaemonSetNodeSelectorExecutor struct{}
+
+func (e *DaemonSetNodeSelectorExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseDaemonSetInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected daemonset format: %s", err.Error())
+	}
+
+	selectorStr := common.GetArgs(args, []string{"selector"})[0]
+	if selectorStr == "" {
+		selectorStr = defaultShrinkSelector
+	}
+
+	addMap := make(map[string]string)
+	for _, unit := range strings.Split(selectorStr, v1alpha1.ArgsListSplit) {
+		tmpArr := strings.Split(unit, v1alpha1.LabelListSplit)
+		if len(tmpArr) != 2 || tmpArr[0] == "" {
+			return "", fmt.Errorf("%s is error selector format, true format is key=value", unit)
+		}
+
+		addMap[tmpArr[0]] = tmpArr[1]
+	}
+
+	c, ds := restclient.GetApiServerClientMap(v1alpha1.DaemonsetCloudTarget), &v1.DaemonSet{}
+	if err := c.Get().Namespace(ns).Resource("daemonsets").Name(name).Do(ctx).Into(ds); err != nil {
+		return "", fmt.Errorf("get daemonset error: %s", err.Error())
+	}
+
+	var backupBytes []byte
+	if ds.Spec.Template.Spec.NodeSelector != nil {
+		backupBytes, err = json.Marshal(ds.Spec.Template.Spec.NodeSelector)
+		if err != nil {
+			return "", fmt.Errorf("backup to string error: %s", err.Error())
+		}
+	}
+
+	addBytes, err := json.Marshal(addMap)
+	if err != nil {
+		return "", fmt.Errorf("selector to string error: %s", err.Error())
+	}
+
+	if err := c.Patch(types.MergePatchType).Namespace(ns).Resource("daemonsets").Name(name).
+		Body([]byte(fmt.Sprintf(nodeSelectorPatchFmt, addBytes))).Do(ctx).Error(); err != nil {
+		return "", fmt.Errorf("patch daemonset error: %s", err.Error())
+	}
+
+	return string(backupBytes), nil
+}
+
+func (e *DaemonSetNodeSelectorExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseDaemonSetInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected daemonset format: %s", err.Error())
+	}
+
+	c, ds := restclient.GetApiServerClientMap(v1alpha1.DaemonsetCloudTarget), &v1.DaemonSet{}
+	if err := c.Get().Namespace(ns).Resource("daemonsets").Name(name).Do(ctx).Into(ds); err != nil {
+		return fmt.Errorf("get daemonset error: %s", err.Error())
+	}
+
+	// the keys not in backup will be set to null
+	backupBytes, err := getBackupLabels([]byte(backup), ds.Spec.Template.Spec.NodeSelector)
+	if err != nil {
+		return fmt.Errorf("get backup node selector error: %s", err.Error())
+	}
+
+	if err := c.Patch(types.MergePatchType).Namespace(ns).Resource("daemonsets").Name(name).
+		Body([]byte(fmt.Sprintf(nodeSelectorPatchFmt, backupBytes))).Do(ctx).Error(); err != nil {
+		return fmt.Errorf("patch daemonset error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (e *DaemonSetNodeSelectorExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
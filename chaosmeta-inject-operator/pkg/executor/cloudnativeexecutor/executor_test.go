@@ -19,9 +19,58 @@ package cloudnativeexecutor
 import (
 	"encoding/json"
 	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	"io"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 )
 
+// fakeRequest is a request received by the fake apiserver
+type fakeRequest struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+// startFakeApiServer points the clients of targets to a fake apiserver, handler returns the status code and body
+func startFakeApiServer(t *testing.T, targets []v1alpha1.CloudTargetType, handler func(r fakeRequest) (int, string)) func() []fakeRequest {
+	var lock sync.Mutex
+	var reqs []fakeRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := fakeRequest{Method: r.Method, Path: r.URL.Path, Body: string(body)}
+		lock.Lock()
+		reqs = append(reqs, req)
+		lock.Unlock()
+
+		code, re := handler(req)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(re))
+	}))
+	t.Cleanup(srv.Close)
+
+	if err := restclient.SetApiServerClientMap(&rest.Config{Host: srv.URL}, clientgoscheme.Scheme, targets); err != nil {
+		t.Fatalf("set apiserver client error: %s", err.Error())
+	}
+
+	return func() []fakeRequest {
+		lock.Lock()
+		defer lock.Unlock()
+		return reqs
+	}
+}
+
+func alreadyExistsStatus(name string) string {
+	return `{"kind":"Status","apiVersion":"v1","status":"Failure","message":"` + name +
+		` already exists","reason":"AlreadyExists","code":409}`
+}
+
 func Test_getPatchLabels(t *testing.T) {
 	type args struct {
 		labels []byte
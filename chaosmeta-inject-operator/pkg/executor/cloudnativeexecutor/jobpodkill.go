@@ -0,0 +1,82 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.JobCloudTarget, "podkill", &JobPodKillExecutor{})
+}
+
+// JobPodKillExecutor delete the active pods of job, which will be counted as failed by the job controller
+type JobPodKillExecutor struct{}
+
+func (e *JobPodKillExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseJobInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected job format: %s", err.Error())
+	}
+
+	job := &batchv1.Job{}
+	if err := restclient.GetApiServerClientMap(v1alpha1.JobCloudTarget).Get().Namespace(ns).Resource("jobs").
+		Name(name).Do(ctx).Into(job); err != nil {
+		return "", fmt.Errorf("get job error: %s", err.Error())
+	}
+
+	if job.Spec.Selector == nil {
+		return "", fmt.Errorf("selector of job is empty")
+	}
+
+	podClient, podList := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget), &corev1.PodList{}
+	if err := podClient.Get().Namespace(ns).Resource("pods").
+		Param("labelSelector", metav1.FormatLabelSelector(job.Spec.Selector)).Do(ctx).Into(podList); err != nil {
+		return "", fmt.Errorf("list pods of job error: %s", err.Error())
+	}
+
+	for _, unitPod := range podList.Items {
+		if unitPod.Status.Phase != corev1.PodPending && unitPod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+
+		if err := podClient.Delete().Namespace(ns).Resource("pods").Name(unitPod.Name).Do(ctx).Error(); err != nil {
+			return "", fmt.Errorf("delete pod[%s] error: %s", unitPod.Name, err.Error())
+		}
+	}
+
+	return "", nil
+}
+
+func (e *JobPodKillExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	return nil
+}
+
+func (e *JobPodKillExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"strconv"
+	"time"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.JobCloudTarget, "suspend", &JobSuspendExecutor{})
+}
+
+const suspendPatchFmt = `{"spec":{"suspend":%t}}`
+
+// JobSuspendExecutor block the completion of job by suspending it, active pods will be deleted and no new pod will be created
+type JobSuspendExecutor struct{}
+
+func (e *JobSuspendExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseJobInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected job format: %s", err.Error())
+	}
+
+	c, job := restclient.GetApiServerClientMap(v1alpha1.JobCloudTarget), &batchv1.Job{}
+	if err := c.Get().Namespace(ns).Resource("jobs").Name(name).Do(ctx).Into(job); err != nil {
+		return "", fmt.Errorf("get job error: %s", err.Error())
+	}
+
+	if job.Status.CompletionTime != nil {
+		return "", fmt.Errorf("job is already completed")
+	}
+
+	oldSuspend := job.Spec.Suspend != nil && *job.Spec.Suspend
+	if err := c.Patch(types.MergePatchType).Namespace(ns).Resource("jobs").Name(name).
+		Body([]byte(fmt.Sprintf(suspendPatchFmt, true))).Do(ctx).Error(); err != nil {
+		return "", fmt.Errorf("patch job error: %s", err.Error())
+	}
+
+	return strconv.FormatBool(oldSuspend), nil
+}
+
+func (e *JobSuspendExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseJobInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected job format: %s", err.Error())
+	}
+
+	// empty backup means inject is not executed successfully
+	if backup == "" {
+		return nil
+	}
+
+	oldSuspend, err := strconv.ParseBool(backup)
+	if err != nil {
+		return fmt.Errorf("old suspend is not a bool: %s", err.Error())
+	}
+
+	c := restclient.GetApiServerClientMap(v1alpha1.JobCloudTarget)
+	if err := c.Patch(types.MergePatchType).Namespace(ns).Resource("jobs").Name(name).
+		Body([]byte(fmt.Sprintf(suspendPatchFmt, oldSuspend))).Do(ctx).Error(); err != nil {
+		return fmt.Errorf("patch job error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (e *JobSuspendExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	"time"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.NamespaceCloudTarget, "finalizer", &NamespaceFinalizerExecutor{})
+}
+
+// NamespaceFinalizerExecutor add finalizers to namespace, the namespace will be stuck in terminating when deleted
+type NamespaceFinalizerExecutor struct{}
+
+func (e *NamespaceFinalizerExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	c, ns := restclient.GetApiServerClientMap(v1alpha1.NamespaceCloudTarget), &corev1.Namespace{}
+	if err := c.Get().Resource("namespaces").Name(injectObject).Do(ctx).Into(ns); err != nil {
+		return "", fmt.Errorf("get namespace error: %s", err.Error())
+	}
+
+	var backupBytes []byte
+	if ns.ObjectMeta.Finalizers != nil {
+		var err error
+		backupBytes, err = json.Marshal(ns.ObjectMeta.Finalizers)
+		if err != nil {
+			return "", fmt.Errorf("backup to string error: %s", err.Error())
+		}
+	}
+
+	return string(backupBytes), patchFinalizers(ctx, c, "namespaces", "", injectObject, getNewFinalizers(ctx, ns.ObjectMeta.Finalizers, args))
+}
+
+func (e *NamespaceFinalizerExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	var oldFinalizers []string
+	if backup != "" {
+		if err := json.Unmarshal([]byte(backup), &oldFinalizers); err != nil {
+			return fmt.Errorf("get old finalizers error: %s", err.Error())
+		}
+	}
+
+	c := restclient.GetApiServerClientMap(v1alpha1.NamespaceCloudTarget)
+	return patchFinalizers(ctx, c, "namespaces", "", injectObject, oldFinalizers)
+}
+
+func (e *NamespaceFinalizerExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+	"time"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.NamespaceCloudTarget, "quota", &NamespaceQuotaExecutor{})
+}
+
+const (
+	defaultQuotaHard = "pods=0"
+	quotaNameFormat  = "chaosmeta-quota-%s"
+)
+
+// NamespaceQuotaExecutor squeeze the namespace by creating a resource quota, the default quota forbids new pods
+type NamespaceQuotaExecutor struct{}
+
+func (e *NamespaceQuotaExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	hardStr := common.GetArgs(args, []string{"hard"})[0]
+	if hardStr == "" {
+		hardStr = defaultQuotaHard
+	}
+
+	hard, err := getResourceList(hardStr)
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: injectObject,
+			Name:      fmt.Sprintf(quotaNameFormat, uid),
+		},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: hard,
+		},
+	}
+
+	if err := restclient.GetApiServerClientMap(v1alpha1.NamespaceCloudTarget).Post().Namespace(injectObject).
+		Resource("resourcequotas").Body(quota).Do(ctx).Error(); err != nil && !common.IsAlreadyExistsErr(err) {
+		return "", fmt.Errorf("create resource quota error: %s", err.Error())
+	}
+
+	return quota.Name, nil
+}
+
+func (e *NamespaceQuotaExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	if backup == "" {
+		return nil
+	}
+
+	if err := restclient.GetApiServerClientMap(v1alpha1.NamespaceCloudTarget).Delete().Namespace(injectObject).
+		Resource("resourcequotas").Name(backup).Do(ctx).Error(); err != nil && !common.IsNotFoundErr(err) {
+		return fmt.Errorf("delete resource quota error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (e *NamespaceQuotaExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
+
+// getResourceList parse resource list like "pods=0,requests.cpu=1"
+func getResourceList(str string) (corev1.ResourceList, error) {
+	re := make(corev1.ResourceList)
+	for _, unit := range strings.Split(str, v1alpha1.ArgsListSplit) {
+		tmpArr := strings.Split(unit, v1alpha1.LabelListSplit)
+		if len(tmpArr) != 2 || tmpArr[0] == "" {
+			return nil, fmt.Errorf("%s is error resource format, true format is resource=quantity", unit)
+		}
+
+		quantity, err := resource.ParseQuantity(tmpArr[1])
+		if err != nil {
+			return nil, fmt.Errorf("quantity of %s is invalid: %s", tmpArr[0], err.Error())
+		}
+
+		re[corev1.ResourceName(tmpArr[0])] = quantity
+	}
+
+	return re, nil
+}
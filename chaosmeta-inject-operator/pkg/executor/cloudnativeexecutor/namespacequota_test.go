@@ -0,0 +1,54 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"net/http"
+	"testing"
+)
+
+func Test_getResourceList(t *testing.T) {
+	re, err := getResourceList("pods=0,requests.cpu=500m")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(re))
+	pods, cpu := re["pods"], re["requests.cpu"]
+	assert.Equal(t, int64(0), pods.Value())
+	assert.Equal(t, int64(500), cpu.MilliValue())
+
+	_, err = getResourceList("pods")
+	assert.NotEqual(t, nil, err)
+
+	_, err = getResourceList("pods=abc")
+	assert.NotEqual(t, nil, err)
+}
+
+func TestNamespaceQuotaExecutor_Inject(t *testing.T) {
+	getReqs := startFakeApiServer(t, []v1alpha1.CloudTargetType{v1alpha1.NamespaceCloudTarget}, func(r fakeRequest) (int, string) {
+		return http.StatusConflict, alreadyExistsStatus(`resourcequotas \"chaosmeta-quota-123\"`)
+	})
+
+	// the quota is created by the last attempt
+	backup, err := (&NamespaceQuotaExecutor{}).Inject(context.Background(), "ns1", "123", "", nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "chaosmeta-quota-123", backup)
+	reqs := getReqs()
+	assert.Equal(t, 1, len(reqs))
+	assert.Equal(t, http.MethodPost, reqs[0].Method)
+	assert.Equal(t, "/api/v1/namespaces/ns1/resourcequotas", reqs[0].Path)
+}
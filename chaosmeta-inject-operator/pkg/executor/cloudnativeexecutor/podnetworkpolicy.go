@@ -0,0 +1,184 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sort"
+	"strings"
+	"time"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.PodCloudTarget, "networkpolicy", &PodNetworkPolicyExecutor{})
+}
+
+const (
+	podIsolateKey           = "chaosmeta.io/isolate"
+	networkPolicyNameFormat = "chaosmeta-isolate-%s"
+
+	IngressDirection = "ingress"
+	EgressDirection  = "egress"
+	AllDirection     = "all"
+)
+
+// PodNetworkPolicyExecutor isolate the pod by creating a network policy which selects the pod with podIsolateKey label.
+// Traffic from/to the pods matching any label in "peer" is denied, all traffic is denied when "peer" is empty.
+// Traffic from/to the endpoints outside of cluster is denied too, because network policy is allow list
+type PodNetworkPolicyExecutor struct{}
+
+func (e *PodNetworkPolicyExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParsePodInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected pod format: %s", err.Error())
+	}
+
+	policy, err := getIsolatePolicy(ns, uid, args)
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	if err := restclient.GetApiServerClientMap(v1alpha1.NetworkPolicyCloudTarget).Post().Namespace(ns).
+		Resource("networkpolicies").Body(policy).Do(ctx).Error(); err != nil && !common.IsAlreadyExistsErr(err) {
+		return "", fmt.Errorf("create network policy error: %s", err.Error())
+	}
+
+	if err := patchLabels(ctx, restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget), "pods", ns, name,
+		[]byte(fmt.Sprintf(`{"%s":"%s"}`, podIsolateKey, uid))); err != nil {
+		return policy.Name, fmt.Errorf("label pod error: %s", err.Error())
+	}
+
+	return policy.Name, nil
+}
+
+func (e *PodNetworkPolicyExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParsePodInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected pod format: %s", err.Error())
+	}
+
+	if backup == "" {
+		return nil
+	}
+
+	if err := patchLabels(ctx, restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget), "pods", ns, name,
+		[]byte(fmt.Sprintf(`{"%s":null}`, podIsolateKey))); err != nil && !common.IsNotFoundErr(err) {
+		return fmt.Errorf("unlabel pod error: %s", err.Error())
+	}
+
+	if err := restclient.GetApiServerClientMap(v1alpha1.NetworkPolicyCloudTarget).Delete().Namespace(ns).
+		Resource("networkpolicies").Name(backup).Do(ctx).Error(); err != nil && !common.IsNotFoundErr(err) {
+		return fmt.Errorf("delete network policy error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (e *PodNetworkPolicyExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
+
+func getIsolatePolicy(ns, uid string, args []v1alpha1.ArgsUnit) (*networkingv1.NetworkPolicy, error) {
+	reArgs := common.GetArgs(args, []string{"peer", "direction"})
+	peerStr, direction := reArgs[0], reArgs[1]
+	if direction == "" {
+		direction = AllDirection
+	}
+
+	if direction != IngressDirection && direction != EgressDirection && direction != AllDirection {
+		return nil, fmt.Errorf("not support direction: %s, only support: %s, %s, %s",
+			direction, IngressDirection, EgressDirection, AllDirection)
+	}
+
+	// pods without all peer labels are allowed
+	var peers []networkingv1.NetworkPolicyPeer
+	if peerStr != "" {
+		peerMap := make(map[string][]string)
+		for _, unit := range strings.Split(peerStr, v1alpha1.ArgsListSplit) {
+			tmpArr := strings.Split(unit, v1alpha1.LabelListSplit)
+			if len(tmpArr) != 2 || tmpArr[0] == "" {
+				return nil, fmt.Errorf("%s is error peer format, true format is key=value", unit)
+			}
+
+			peerMap[tmpArr[0]] = append(peerMap[tmpArr[0]], tmpArr[1])
+		}
+
+		var keys []string
+		for k := range peerMap {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var expressions []metav1.LabelSelectorRequirement
+		for _, k := range keys {
+			expressions = append(expressions, metav1.LabelSelectorRequirement{
+				Key:      k,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   peerMap[k],
+			})
+		}
+
+		peers = []networkingv1.NetworkPolicyPeer{
+			{
+				NamespaceSelector: &metav1.LabelSelector{},
+				PodSelector: &metav1.LabelSelector{
+					MatchExpressions: expressions,
+				},
+			},
+		}
+	}
+
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      fmt.Sprintf(networkPolicyNameFormat, uid),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					podIsolateKey: uid,
+				},
+			},
+		},
+	}
+
+	if direction == IngressDirection || direction == AllDirection {
+		policy.Spec.PolicyTypes = append(policy.Spec.PolicyTypes, networkingv1.PolicyTypeIngress)
+		if peers != nil {
+			policy.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{From: peers}}
+		}
+	}
+
+	if direction == EgressDirection || direction == AllDirection {
+		policy.Spec.PolicyTypes = append(policy.Spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+		if peers != nil {
+			policy.Spec.Egress = []networkingv1.NetworkPolicyEgressRule{{To: peers}}
+		}
+	}
+
+	return policy, nil
+}
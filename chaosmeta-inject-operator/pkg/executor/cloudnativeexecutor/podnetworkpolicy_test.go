@@ -0,0 +1,74 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
+	"testing"
+)
+
+func Test_getIsolatePolicy(t *testing.T) {
+	policy, err := getIsolatePolicy("ns1", "123", nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "chaosmeta-isolate-123", policy.Name)
+	assert.Equal(t, "123", policy.Spec.PodSelector.MatchLabels[podIsolateKey])
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}, policy.Spec.PolicyTypes)
+	assert.Equal(t, 0, len(policy.Spec.Ingress))
+	assert.Equal(t, 0, len(policy.Spec.Egress))
+
+	policy, err = getIsolatePolicy("ns1", "123", []v1alpha1.ArgsUnit{
+		{Key: "peer", Value: "app=b,app=a,tier=db"},
+		{Key: "direction", Value: IngressDirection},
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, policy.Spec.PolicyTypes)
+	assert.Equal(t, 0, len(policy.Spec.Egress))
+	assert.Equal(t, []metav1.LabelSelectorRequirement{
+		{Key: "app", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"b", "a"}},
+		{Key: "tier", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"db"}},
+	}, policy.Spec.Ingress[0].From[0].PodSelector.MatchExpressions)
+
+	_, err = getIsolatePolicy("ns1", "123", []v1alpha1.ArgsUnit{{Key: "direction", Value: "other"}})
+	assert.NotEqual(t, nil, err)
+
+	_, err = getIsolatePolicy("ns1", "123", []v1alpha1.ArgsUnit{{Key: "peer", Value: "app"}})
+	assert.NotEqual(t, nil, err)
+}
+
+func TestPodNetworkPolicyExecutor_Inject(t *testing.T) {
+	getReqs := startFakeApiServer(t, []v1alpha1.CloudTargetType{v1alpha1.PodCloudTarget, v1alpha1.NetworkPolicyCloudTarget}, func(r fakeRequest) (int, string) {
+		if r.Method == http.MethodPost {
+			return http.StatusConflict, alreadyExistsStatus(`networkpolicies.networking.k8s.io \"chaosmeta-isolate-123\"`)
+		}
+
+		return http.StatusOK, `{"kind":"Pod","apiVersion":"v1","metadata":{"name":"pod1","namespace":"ns1"}}`
+	})
+
+	// the policy is created by the last attempt, the pod is labeled again
+	backup, err := (&PodNetworkPolicyExecutor{}).Inject(context.Background(), "pod/ns1/pod1", "123", "", nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "chaosmeta-isolate-123", backup)
+	reqs := getReqs()
+	assert.Equal(t, 2, len(reqs))
+	assert.Equal(t, "/apis/networking.k8s.io/v1/namespaces/ns1/networkpolicies", reqs[0].Path)
+	assert.Equal(t, http.MethodPatch, reqs[1].Method)
+	assert.Equal(t, "/api/v1/namespaces/ns1/pods/pod1", reqs[1].Path)
+}
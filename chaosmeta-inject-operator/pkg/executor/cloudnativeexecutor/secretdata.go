@@ -0,0 +1,102 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"time"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.SecretCloudTarget, "data", &SecretDataExecutor{})
+}
+
+// SecretDataExecutor is the same as ConfigMapDataExecutor, values in args are plain text, and the backup is encrypted
+type SecretDataExecutor struct{}
+
+func (e *SecretDataExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseSecretInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected secret format: %s", err.Error())
+	}
+
+	return injectConfigData(ctx, v1alpha1.SecretCloudTarget, ns, name, uid, args, func() (map[string]string, error) {
+		secret := &corev1.Secret{}
+		if err := restclient.GetApiServerClientMap(v1alpha1.SecretCloudTarget).Get().Namespace(ns).
+			Resource("secrets").Name(name).Do(ctx).Into(secret); err != nil {
+			return nil, fmt.Errorf("get secret error: %s", err.Error())
+		}
+
+		data := make(map[string]string)
+		for k, v := range secret.Data {
+			data[k] = string(v)
+		}
+
+		return data, nil
+	}, func(newData map[string]*string) error {
+		return patchSecretData(ctx, ns, name, newData)
+	})
+}
+
+func (e *SecretDataExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseSecretInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected secret format: %s", err.Error())
+	}
+
+	return recoverConfigData(ctx, ns, backup, func(oldData map[string]*string) error {
+		return patchSecretData(ctx, ns, name, oldData)
+	})
+}
+
+func (e *SecretDataExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
+
+func patchSecretData(ctx context.Context, ns, name string, data map[string]*string) error {
+	// []byte is encoded to base64 by json
+	patchData := make(map[string]interface{})
+	for k, v := range data {
+		if v == nil {
+			patchData[k] = nil
+		} else {
+			patchData[k] = []byte(*v)
+		}
+	}
+
+	dataBytes, err := json.Marshal(patchData)
+	if err != nil {
+		return fmt.Errorf("data to string error: %s", err.Error())
+	}
+
+	if err := restclient.GetApiServerClientMap(v1alpha1.SecretCloudTarget).Patch(types.MergePatchType).Namespace(ns).
+		Resource("secrets").Name(name).Body([]byte(fmt.Sprintf(`{"data":%s}`, dataBytes))).Do(ctx).Error(); err != nil {
+		return fmt.Errorf("patch secret error: %s", err.Error())
+	}
+
+	return nil
+}
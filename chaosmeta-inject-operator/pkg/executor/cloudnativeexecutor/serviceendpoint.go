@@ -0,0 +1,239 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sort"
+	"strconv"
+	"time"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.ServiceCloudTarget, "endpoint", &ServiceEndpointExecutor{})
+}
+
+const (
+	serviceEndpointKey = "chaosmeta.io/endpoint"
+	selectorPatchFmt   = `{"spec":{"selector":%s}}`
+)
+
+// ServiceEndpointExecutor remove ready endpoints from service: the remaining ready pods are labeled with
+// serviceEndpointKey and the label is appended to the selector of service, so the other pods drop out of the endpoints
+type ServiceEndpointExecutor struct{}
+
+type serviceEndpointBackup struct {
+	Selector map[string]string `json:"selector"`
+	Pods     []string          `json:"pods"`
+}
+
+func (e *ServiceEndpointExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseServiceInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected service format: %s", err.Error())
+	}
+
+	c, svc := restclient.GetApiServerClientMap(v1alpha1.ServiceCloudTarget), &corev1.Service{}
+	if err := c.Get().Namespace(ns).Resource("services").Name(name).Do(ctx).Into(svc); err != nil {
+		return "", fmt.Errorf("get service error: %s", err.Error())
+	}
+
+	if len(svc.Spec.Selector) == 0 {
+		return "", fmt.Errorf("service without selector is not supported")
+	}
+
+	readyPods, err := getReadyPodNames(ctx, ns, svc.Spec.Selector)
+	if err != nil {
+		return "", fmt.Errorf("get ready pods of service error: %s", err.Error())
+	}
+
+	removeCount, err := getRemoveCount(args, len(readyPods))
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	backup := &serviceEndpointBackup{
+		Selector: svc.Spec.Selector,
+		Pods:     readyPods[removeCount:],
+	}
+	backupBytes, err := json.Marshal(backup)
+	if err != nil {
+		return "", fmt.Errorf("backup to string error: %s", err.Error())
+	}
+
+	// label the remaining pods first, so that they will not drop out after the selector is changed
+	podClient := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget)
+	for i, unitPod := range backup.Pods {
+		if err := patchLabels(ctx, podClient, "pods", ns, unitPod,
+			[]byte(fmt.Sprintf(`{"%s":"%s"}`, serviceEndpointKey, uid))); err != nil {
+			return undoServiceEndpoint(ctx, ns, backup.Pods[:i], string(backupBytes), fmt.Errorf("label pod[%s] error: %s", unitPod, err.Error()))
+		}
+	}
+
+	if err := c.Patch(types.MergePatchType).Namespace(ns).Resource("services").Name(name).
+		Body([]byte(fmt.Sprintf(selectorPatchFmt, fmt.Sprintf(`{"%s":"%s"}`, serviceEndpointKey, uid)))).Do(ctx).Error(); err != nil {
+		return undoServiceEndpoint(ctx, ns, backup.Pods, string(backupBytes), fmt.Errorf("patch service error: %s", err.Error()))
+	}
+
+	return string(backupBytes), nil
+}
+
+// undoServiceEndpoint removes the label from the pods labeled before the inject fails. the backup is kept only if
+// the undo fails, so that the recover can clean up the remaining labels
+func undoServiceEndpoint(ctx context.Context, ns string, labeledPods []string, backup string, injectErr error) (string, error) {
+	if err := unlabelPods(ctx, ns, labeledPods); err != nil {
+		return backup, fmt.Errorf("%s, undo error: %s", injectErr.Error(), err.Error())
+	}
+
+	return "", injectErr
+}
+
+// unlabelPods removes serviceEndpointKey from the pods, the deleted pods are skipped
+func unlabelPods(ctx context.Context, ns string, pods []string) error {
+	podClient := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget)
+	for _, unitPod := range pods {
+		if err := patchLabels(ctx, podClient, "pods", ns, unitPod,
+			[]byte(fmt.Sprintf(`{"%s":null}`, serviceEndpointKey))); err != nil && !common.IsNotFoundErr(err) {
+			return fmt.Errorf("unlabel pod[%s] error: %s", unitPod, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (e *ServiceEndpointExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseServiceInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected service format: %s", err.Error())
+	}
+
+	if backup == "" {
+		return nil
+	}
+
+	oldInfo := &serviceEndpointBackup{}
+	if err := json.Unmarshal([]byte(backup), oldInfo); err != nil {
+		return fmt.Errorf("get backup info error: %s", err.Error())
+	}
+
+	if err := recoverServiceSelector(ctx, ns, name, oldInfo.Selector); err != nil {
+		return err
+	}
+
+	return unlabelPods(ctx, ns, oldInfo.Pods)
+}
+
+func (e *ServiceEndpointExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
+
+// getReadyPodNames return the names of ready pods matching selector, sorted by name
+func getReadyPodNames(ctx context.Context, ns string, selector map[string]string) ([]string, error) {
+	podList := &corev1.PodList{}
+	if err := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget).Get().Namespace(ns).Resource("pods").
+		Param("labelSelector", labels.SelectorFromSet(selector).String()).Do(ctx).Into(podList); err != nil {
+		return nil, fmt.Errorf("list pods error: %s", err.Error())
+	}
+
+	var result []string
+	for _, unitPod := range podList.Items {
+		if unitPod.DeletionTimestamp != nil {
+			continue
+		}
+
+		for _, unitCondition := range unitPod.Status.Conditions {
+			if unitCondition.Type == corev1.PodReady && unitCondition.Status == corev1.ConditionTrue {
+				result = append(result, unitPod.Name)
+				break
+			}
+		}
+	}
+
+	sort.Strings(result)
+	return result, nil
+}
+
+// getRemoveCount get the count of endpoints to remove by "count" or "percent"
+func getRemoveCount(args []v1alpha1.ArgsUnit, total int) (int, error) {
+	reArgs := common.GetArgs(args, []string{"count", "percent"})
+	var count int
+	if reArgs[0] != "" {
+		var err error
+		count, err = strconv.Atoi(reArgs[0])
+		if err != nil || count <= 0 {
+			return 0, fmt.Errorf("\"count\" is not a positive num: %s", reArgs[0])
+		}
+	} else if reArgs[1] != "" {
+		percent, err := strconv.Atoi(reArgs[1])
+		if err != nil || percent <= 0 || percent > 100 {
+			return 0, fmt.Errorf("\"percent\" must be in (0, 100]: %s", reArgs[1])
+		}
+		count = total * percent / 100
+		if count == 0 {
+			count = 1
+		}
+	} else {
+		return 0, fmt.Errorf("\"count\" or \"percent\" must be provided")
+	}
+
+	if count > total {
+		count = total
+	}
+
+	return count, nil
+}
+
+// recoverServiceSelector restore the selector of service, the keys not in oldSelector will be removed
+func recoverServiceSelector(ctx context.Context, ns, name string, oldSelector map[string]string) error {
+	c, svc := restclient.GetApiServerClientMap(v1alpha1.ServiceCloudTarget), &corev1.Service{}
+	if err := c.Get().Namespace(ns).Resource("services").Name(name).Do(ctx).Into(svc); err != nil {
+		return fmt.Errorf("get service error: %s", err.Error())
+	}
+
+	var oldBytes []byte
+	if oldSelector != nil {
+		var err error
+		oldBytes, err = json.Marshal(oldSelector)
+		if err != nil {
+			return fmt.Errorf("old selector to string error: %s", err.Error())
+		}
+	}
+
+	patchBytes, err := getBackupLabels(oldBytes, svc.Spec.Selector)
+	if err != nil {
+		return fmt.Errorf("get backup selector error: %s", err.Error())
+	}
+
+	if err := c.Patch(types.MergePatchType).Namespace(ns).Resource("services").Name(name).
+		Body([]byte(fmt.Sprintf(selectorPatchFmt, patchBytes))).Do(ctx).Error(); err != nil {
+		return fmt.Errorf("patch service error: %s", err.Error())
+	}
+
+	return nil
+}
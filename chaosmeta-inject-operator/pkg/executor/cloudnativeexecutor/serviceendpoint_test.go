@@ -0,0 +1,126 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func Test_getRemoveCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []v1alpha1.ArgsUnit
+		total   int
+		want    int
+		wantErr bool
+	}{
+		{name: "count", args: []v1alpha1.ArgsUnit{{Key: "count", Value: "2"}}, total: 5, want: 2},
+		{name: "count larger than total", args: []v1alpha1.ArgsUnit{{Key: "count", Value: "9"}}, total: 5, want: 5},
+		{name: "count first", args: []v1alpha1.ArgsUnit{{Key: "count", Value: "1"}, {Key: "percent", Value: "100"}}, total: 5, want: 1},
+		{name: "percent", args: []v1alpha1.ArgsUnit{{Key: "percent", Value: "40"}}, total: 5, want: 2},
+		{name: "percent at least one", args: []v1alpha1.ArgsUnit{{Key: "percent", Value: "10"}}, total: 5, want: 1},
+		{name: "no ready pod", args: []v1alpha1.ArgsUnit{{Key: "percent", Value: "10"}}, total: 0, want: 0},
+		{name: "zero count", args: []v1alpha1.ArgsUnit{{Key: "count", Value: "0"}}, total: 5, wantErr: true},
+		{name: "invalid count", args: []v1alpha1.ArgsUnit{{Key: "count", Value: "a"}}, total: 5, wantErr: true},
+		{name: "percent out of range", args: []v1alpha1.ArgsUnit{{Key: "percent", Value: "101"}}, total: 5, wantErr: true},
+		{name: "empty", total: 5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getRemoveCount(tt.args, tt.total)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+// startFakeService serves a service with the selector, the patch requests are returned
+func startFakeService(t *testing.T, selector string) func() []fakeRequest {
+	getReqs := startFakeApiServer(t, []v1alpha1.CloudTargetType{v1alpha1.ServiceCloudTarget}, func(r fakeRequest) (int, string) {
+		return http.StatusOK, `{"kind":"Service","apiVersion":"v1","metadata":{"name":"svc1","namespace":"ns1"},"spec":{"selector":` + selector + `}}`
+	})
+
+	return func() []fakeRequest {
+		var patches []fakeRequest
+		for _, r := range getReqs() {
+			if r.Method == http.MethodPatch {
+				patches = append(patches, r)
+			}
+		}
+		return patches
+	}
+}
+
+func Test_recoverServiceSelector(t *testing.T) {
+	getPatches := startFakeService(t, `{"app":"a","chaosmeta.io/endpoint":"123"}`)
+	err := recoverServiceSelector(context.Background(), "ns1", "svc1", map[string]string{"app": "a", "tier": "web"})
+	assert.Equal(t, nil, err)
+	patches := getPatches()
+	assert.Equal(t, 1, len(patches))
+	assert.Equal(t, "/api/v1/namespaces/ns1/services/svc1", patches[0].Path)
+	assert.JSONEq(t, `{"spec":{"selector":{"app":"a","tier":"web","chaosmeta.io/endpoint":null}}}`, patches[0].Body)
+}
+
+func TestServiceEndpointExecutor_Inject_undo(t *testing.T) {
+	readyPod := func(name string) string {
+		return `{"metadata":{"name":"` + name + `"},"status":{"conditions":[{"type":"Ready","status":"True"}]}}`
+	}
+	failStatus := `{"kind":"Status","apiVersion":"v1","status":"Failure","message":"internal error","code":500}`
+
+	tests := []struct {
+		name        string
+		failPath    string
+		wantUnlabel []string
+	}{
+		{name: "label pod error", failPath: "/api/v1/namespaces/ns1/pods/p3", wantUnlabel: []string{"p2"}},
+		{name: "patch service error", failPath: "/api/v1/namespaces/ns1/services/svc1", wantUnlabel: []string{"p2", "p3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getReqs := startFakeApiServer(t, []v1alpha1.CloudTargetType{v1alpha1.ServiceCloudTarget, v1alpha1.PodCloudTarget}, func(r fakeRequest) (int, string) {
+				if r.Method == http.MethodPatch && r.Path == tt.failPath && !strings.Contains(r.Body, "null") {
+					return http.StatusInternalServerError, failStatus
+				}
+
+				if r.Path == "/api/v1/namespaces/ns1/pods" {
+					return http.StatusOK, `{"kind":"PodList","apiVersion":"v1","items":[` + readyPod("p1") + `,` + readyPod("p2") + `,` + readyPod("p3") + `]}`
+				}
+
+				return http.StatusOK, `{"kind":"Service","apiVersion":"v1","metadata":{"name":"svc1","namespace":"ns1"},"spec":{"selector":{"app":"a"}}}`
+			})
+
+			backup, err := (&ServiceEndpointExecutor{}).Inject(context.Background(), "service/ns1/svc1", "123", "",
+				[]v1alpha1.ArgsUnit{{Key: "count", Value: "1"}})
+			assert.NotEqual(t, nil, err)
+			assert.Equal(t, "", backup)
+
+			var unlabeled []string
+			for _, r := range getReqs() {
+				if r.Method == http.MethodPatch && strings.Contains(r.Body, `"chaosmeta.io/endpoint":null`) {
+					unlabeled = append(unlabeled, strings.TrimPrefix(r.Path, "/api/v1/namespaces/ns1/pods/"))
+				}
+			}
+			assert.Equal(t, tt.wantUnlabel, unlabeled)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.ServiceCloudTarget, "port", &ServicePortExecutor{})
+}
+
+const portsPatchFmt = `{"spec":{"ports":%s}}`
+
+// ServicePortExecutor change the target port of service ports, "port" args is like "80=8080,http=9090",
+// the key is the port number or name of service port and the value is the new target port
+type ServicePortExecutor struct{}
+
+func (e *ServicePortExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseServiceInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected service format: %s", err.Error())
+	}
+
+	c, svc := restclient.GetApiServerClientMap(v1alpha1.ServiceCloudTarget), &corev1.Service{}
+	if err := c.Get().Namespace(ns).Resource("services").Name(name).Do(ctx).Into(svc); err != nil {
+		return "", fmt.Errorf("get service error: %s", err.Error())
+	}
+
+	backupBytes, err := json.Marshal(svc.Spec.Ports)
+	if err != nil {
+		return "", fmt.Errorf("backup to string error: %s", err.Error())
+	}
+
+	newPorts, err := getNewServicePorts(svc.Spec.Ports, common.GetArgs(args, []string{"port"})[0])
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	newBytes, err := json.Marshal(newPorts)
+	if err != nil {
+		return "", fmt.Errorf("ports to string error: %s", err.Error())
+	}
+
+	// merge patch replaces the whole list
+	if err := c.Patch(types.MergePatchType).Namespace(ns).Resource("services").Name(name).
+		Body([]byte(fmt.Sprintf(portsPatchFmt, newBytes))).Do(ctx).Error(); err != nil {
+		return "", fmt.Errorf("patch service error: %s", err.Error())
+	}
+
+	return string(backupBytes), nil
+}
+
+func (e *ServicePortExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseServiceInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected service format: %s", err.Error())
+	}
+
+	if backup == "" {
+		return nil
+	}
+
+	c := restclient.GetApiServerClientMap(v1alpha1.ServiceCloudTarget)
+	if err := c.Patch(types.MergePatchType).Namespace(ns).Resource("services").Name(name).
+		Body([]byte(fmt.Sprintf(portsPatchFmt, backup))).Do(ctx).Error(); err != nil {
+		return fmt.Errorf("patch service error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (e *ServicePortExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
+
+func getNewServicePorts(oldPorts []corev1.ServicePort, portStr string) ([]corev1.ServicePort, error) {
+	if portStr == "" {
+		return nil, fmt.Errorf("\"port\" is empty")
+	}
+
+	newPorts := make([]corev1.ServicePort, len(oldPorts))
+	copy(newPorts, oldPorts)
+	for _, unit := range strings.Split(portStr, v1alpha1.ArgsListSplit) {
+		tmpArr := strings.Split(unit, v1alpha1.LabelListSplit)
+		if len(tmpArr) != 2 || tmpArr[0] == "" || tmpArr[1] == "" {
+			return nil, fmt.Errorf("%s is error port format, true format is port=targetPort", unit)
+		}
+
+		var isFound bool
+		for i := range newPorts {
+			if newPorts[i].Name == tmpArr[0] || strconv.Itoa(int(newPorts[i].Port)) == tmpArr[0] {
+				newPorts[i].TargetPort = intstr.Parse(tmpArr[1])
+				isFound = true
+			}
+		}
+
+		if !isFound {
+			return nil, fmt.Errorf("port[%s] not found in service", tmpArr[0])
+		}
+	}
+
+	return newPorts, nil
+}
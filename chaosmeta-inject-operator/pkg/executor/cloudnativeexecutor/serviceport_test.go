@@ -0,0 +1,43 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"testing"
+)
+
+func Test_getNewServicePorts(t *testing.T) {
+	oldPorts := []corev1.ServicePort{
+		{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)},
+		{Name: "grpc", Port: 9000, TargetPort: intstr.FromString("grpc")},
+	}
+
+	newPorts, err := getNewServicePorts(oldPorts, "http=9999,9000=8000")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, intstr.FromInt(9999), newPorts[0].TargetPort)
+	assert.Equal(t, intstr.FromInt(8000), newPorts[1].TargetPort)
+	// old ports should not be changed
+	assert.Equal(t, intstr.FromInt(8080), oldPorts[0].TargetPort)
+
+	_, err = getNewServicePorts(oldPorts, "443=8443")
+	assert.NotEqual(t, nil, err)
+
+	_, err = getNewServicePorts(oldPorts, "")
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"strings"
+	"time"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.ServiceCloudTarget, "selector", &ServiceSelectorExecutor{})
+}
+
+// ServiceSelectorExecutor replace the selector of service with the provided one
+type ServiceSelectorExecutor struct{}
+
+func (e *ServiceSelectorExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseServiceInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected service format: %s", err.Error())
+	}
+
+	selectorStr := common.GetArgs(args, []string{"selector"})[0]
+	if selectorStr == "" {
+		return "", fmt.Errorf("\"selector\" is empty")
+	}
+
+	newSelector := make(map[string]string)
+	for _, unit := range strings.Split(selectorStr, v1alpha1.ArgsListSplit) {
+		tmpArr := strings.Split(unit, v1alpha1.LabelListSplit)
+		if len(tmpArr) != 2 || tmpArr[0] == "" {
+			return "", fmt.Errorf("%s is error selector format, true format is key=value", unit)
+		}
+
+		newSelector[tmpArr[0]] = tmpArr[1]
+	}
+
+	c, svc := restclient.GetApiServerClientMap(v1alpha1.ServiceCloudTarget), &corev1.Service{}
+	if err := c.Get().Namespace(ns).Resource("services").Name(name).Do(ctx).Into(svc); err != nil {
+		return "", fmt.Errorf("get service error: %s", err.Error())
+	}
+
+	// a service without selector is backed up as "{}", so that recover removes the whole selector,
+	// empty backup means nothing is changed
+	oldSelector := svc.Spec.Selector
+	if oldSelector == nil {
+		oldSelector = make(map[string]string)
+	}
+
+	backupBytes, err := json.Marshal(oldSelector)
+	if err != nil {
+		return "", fmt.Errorf("backup to string error: %s", err.Error())
+	}
+
+	newBytes, err := json.Marshal(newSelector)
+	if err != nil {
+		return "", fmt.Errorf("selector to string error: %s", err.Error())
+	}
+
+	// the old keys not in new selector will be set to null
+	patchBytes, err := getBackupLabels(newBytes, svc.Spec.Selector)
+	if err != nil {
+		return "", fmt.Errorf("get new selector error: %s", err.Error())
+	}
+
+	if err := c.Patch(types.MergePatchType).Namespace(ns).Resource("services").Name(name).
+		Body([]byte(fmt.Sprintf(selectorPatchFmt, patchBytes))).Do(ctx).Error(); err != nil {
+		return "", fmt.Errorf("patch service error: %s", err.Error())
+	}
+
+	return string(backupBytes), nil
+}
+
+func (e *ServiceSelectorExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseServiceInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected service format: %s", err.Error())
+	}
+
+	if backup == "" {
+		return nil
+	}
+
+	oldSelector := make(map[string]string)
+	if err := json.Unmarshal([]byte(backup), &oldSelector); err != nil {
+		return fmt.Errorf("get old selector error: %s", err.Error())
+	}
+
+	return recoverServiceSelector(ctx, ns, name, oldSelector)
+}
+
+func (e *ServiceSelectorExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
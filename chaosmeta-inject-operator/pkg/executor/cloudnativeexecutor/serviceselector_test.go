@@ -0,0 +1,51 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"testing"
+)
+
+func TestServiceSelectorExecutor_Recover(t *testing.T) {
+	e := &ServiceSelectorExecutor{}
+
+	// inject fails before the service is changed
+	getPatches := startFakeService(t, `{"app":"b"}`)
+	assert.Equal(t, nil, e.Recover(context.Background(), "service/ns1/svc1", "123", ""))
+	assert.Equal(t, 0, len(getPatches()))
+
+	getPatches = startFakeService(t, `{"app":"b","tier":"db"}`)
+	assert.Equal(t, nil, e.Recover(context.Background(), "service/ns1/svc1", "123", `{"app":"a"}`))
+	assert.Equal(t, 1, len(getPatches()))
+	assert.JSONEq(t, `{"spec":{"selector":{"app":"a","tier":null}}}`, getPatches()[0].Body)
+}
+
+func TestServiceSelectorExecutor_InjectWithoutSelector(t *testing.T) {
+	e := &ServiceSelectorExecutor{}
+	getPatches := startFakeService(t, `null`)
+	backup, err := e.Inject(context.Background(), "service/ns1/svc1", "123", "", []v1alpha1.ArgsUnit{{Key: "selector", Value: "app=b"}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "{}", backup)
+	assert.JSONEq(t, `{"spec":{"selector":{"app":"b"}}}`, getPatches()[0].Body)
+
+	// the whole selector is removed in recover
+	getPatches = startFakeService(t, `{"app":"b"}`)
+	assert.Equal(t, nil, e.Recover(context.Background(), "service/ns1/svc1", "123", backup))
+	assert.JSONEq(t, `{"spec":{"selector":{"app":null}}}`, getPatches()[0].Body)
+}
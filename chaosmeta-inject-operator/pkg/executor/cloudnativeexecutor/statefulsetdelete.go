@@ -0,0 +1,112 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	v1 "k8s.io/api/apps/v1"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.StatefulsetCloudTarget, "delete", &StatefulSetDeleteExecutor{})
+}
+
+// StatefulSetDeleteExecutor delete the pods of statefulset by ordinal, the controller will recreate them with the same name
+type StatefulSetDeleteExecutor struct{}
+
+func (e *StatefulSetDeleteExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseStatefulSetInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected statefulset format: %s", err.Error())
+	}
+
+	c, sts := restclient.GetApiServerClientMap(v1alpha1.StatefulsetCloudTarget), &v1.StatefulSet{}
+	if err := c.Get().Namespace(ns).Resource("statefulsets").Name(name).Do(ctx).Into(sts); err != nil {
+		return "", fmt.Errorf("get statefulset error: %s", err.Error())
+	}
+
+	var replicas = 1
+	if sts.Spec.Replicas != nil {
+		replicas = int(*sts.Spec.Replicas)
+	}
+
+	ordinalList, err := getOrdinalList(common.GetArgs(args, []string{"ordinal"})[0], replicas)
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	podClient := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget)
+	for _, ordinal := range ordinalList {
+		podName := fmt.Sprintf(batchNameFormat, name, ordinal)
+		if err := podClient.Delete().Namespace(ns).Resource("pods").Name(podName).Do(ctx).Error(); err != nil {
+			return "", fmt.Errorf("delete pod[%s] error: %s", podName, err.Error())
+		}
+	}
+
+	return "", nil
+}
+
+func (e *StatefulSetDeleteExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	return nil
+}
+
+func (e *StatefulSetDeleteExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
+
+// getOrdinalList parse ordinal list like "0,2", return all ordinals of replicas when ordinalStr is empty
+func getOrdinalList(ordinalStr string, replicas int) ([]int, error) {
+	var result []int
+	if ordinalStr == "" {
+		for i := 0; i < replicas; i++ {
+			result = append(result, i)
+		}
+
+		return result, nil
+	}
+
+	isExist := make(map[int]bool)
+	for _, unit := range strings.Split(ordinalStr, v1alpha1.ArgsListSplit) {
+		ordinal, err := strconv.Atoi(strings.TrimSpace(unit))
+		if err != nil {
+			return nil, fmt.Errorf("ordinal[%s] is not a num: %s", unit, err.Error())
+		}
+
+		if ordinal < 0 || ordinal >= replicas {
+			return nil, fmt.Errorf("ordinal[%d] is out of range [0, %d)", ordinal, replicas)
+		}
+
+		if isExist[ordinal] {
+			continue
+		}
+		isExist[ordinal] = true
+		result = append(result, ordinal)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_getOrdinalList(t *testing.T) {
+	tests := []struct {
+		name       string
+		ordinalStr string
+		replicas   int
+		want       []int
+		wantErr    bool
+	}{
+		{
+			name:       "all",
+			ordinalStr: "",
+			replicas:   3,
+			want:       []int{0, 1, 2},
+		},
+		{
+			name:       "normal",
+			ordinalStr: "2,0,2",
+			replicas:   3,
+			want:       []int{2, 0},
+		},
+		{
+			name:       "out_of_range",
+			ordinalStr: "3",
+			replicas:   3,
+			wantErr:    true,
+		},
+		{
+			name:       "not_num",
+			ordinalStr: "a",
+			replicas:   3,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getOrdinalList(tt.ordinalStr, tt.replicas)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	v1 "k8s.io/api/apps/v1"
+	"time"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.StatefulsetCloudTarget, "finalizer", &StatefulSetFinalizerExecutor{})
+}
+
+type StatefulSetFinalizerExecutor struct{}
+
+func (e *StatefulSetFinalizerExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseStatefulSetInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected statefulset format: %s", err.Error())
+	}
+
+	c, sts := restclient.GetApiServerClientMap(v1alpha1.StatefulsetCloudTarget), &v1.StatefulSet{}
+	if err := c.Get().Namespace(ns).Resource("statefulsets").Name(name).Do(ctx).Into(sts); err != nil {
+		return "", fmt.Errorf("get statefulset error: %s", err.Error())
+	}
+
+	var backupBytes []byte
+	if sts.ObjectMeta.Finalizers != nil {
+		backupBytes, err = json.Marshal(sts.ObjectMeta.Finalizers)
+		if err != nil {
+			return "", fmt.Errorf("backup to string error: %s", err.Error())
+		}
+	}
+
+	return string(backupBytes), patchFinalizers(ctx, c, "statefulsets", ns, name, getNewFinalizers(ctx, sts.ObjectMeta.Finalizers, args))
+}
+
+func (e *StatefulSetFinalizerExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseStatefulSetInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected statefulset format: %s", err.Error())
+	}
+
+	var oldFinalizers []string
+	if backup != "" {
+		if err := json.Unmarshal([]byte(backup), &oldFinalizers); err != nil {
+			return fmt.Errorf("get old finalizers error: %s", err.Error())
+		}
+	}
+
+	c := restclient.GetApiServerClientMap(v1alpha1.StatefulsetCloudTarget)
+	return patchFinalizers(ctx, c, "statefulsets", ns, name, oldFinalizers)
+}
+
+func (e *StatefulSetFinalizerExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	v1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"strconv"
+	"time"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.StatefulsetCloudTarget, "partition", &StatefulSetPartitionExecutor{})
+}
+
+const partitionPatchFmt = `{"spec":{"updateStrategy":{"rollingUpdate":{"partition":%s}}}}`
+
+// StatefulSetPartitionExecutor stall the rolling update by raising the partition of statefulset,
+// pods with ordinal lower than partition will not be updated. Partition defaults to replicas
+type StatefulSetPartitionExecutor struct{}
+
+func (e *StatefulSetPartitionExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseStatefulSetInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected statefulset format: %s", err.Error())
+	}
+
+	c, sts := restclient.GetApiServerClientMap(v1alpha1.StatefulsetCloudTarget), &v1.StatefulSet{}
+	if err := c.Get().Namespace(ns).Resource("statefulsets").Name(name).Do(ctx).Into(sts); err != nil {
+		return "", fmt.Errorf("get statefulset error: %s", err.Error())
+	}
+
+	if sts.Spec.UpdateStrategy.Type == v1.OnDeleteStatefulSetStrategyType {
+		return "", fmt.Errorf("update strategy of statefulset is %s, partition only works with %s",
+			v1.OnDeleteStatefulSetStrategyType, v1.RollingUpdateStatefulSetStrategyType)
+	}
+
+	var partition = 1
+	if sts.Spec.Replicas != nil {
+		partition = int(*sts.Spec.Replicas)
+	}
+
+	if partitionStr := common.GetArgs(args, []string{"partition"})[0]; partitionStr != "" {
+		partition, err = strconv.Atoi(partitionStr)
+		if err != nil || partition < 0 {
+			return "", fmt.Errorf("\"partition\" is not a non-negative num: %s", partitionStr)
+		}
+	}
+
+	// empty backup means partition is not set
+	var backup string
+	if sts.Spec.UpdateStrategy.RollingUpdate != nil && sts.Spec.UpdateStrategy.RollingUpdate.Partition != nil {
+		backup = strconv.Itoa(int(*sts.Spec.UpdateStrategy.RollingUpdate.Partition))
+	}
+
+	if err := c.Patch(types.MergePatchType).Namespace(ns).Resource("statefulsets").Name(name).
+		Body([]byte(fmt.Sprintf(partitionPatchFmt, strconv.Itoa(partition)))).Do(ctx).Error(); err != nil {
+		return "", fmt.Errorf("patch statefulset error: %s", err.Error())
+	}
+
+	return backup, nil
+}
+
+func (e *StatefulSetPartitionExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseStatefulSetInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected statefulset format: %s", err.Error())
+	}
+
+	var oldPartition = "null"
+	if backup != "" {
+		if _, err := strconv.Atoi(backup); err != nil {
+			return fmt.Errorf("old partition is not a num: %s", err.Error())
+		}
+		oldPartition = backup
+	}
+
+	c := restclient.GetApiServerClientMap(v1alpha1.StatefulsetCloudTarget)
+	if err := c.Patch(types.MergePatchType).Namespace(ns).Resource("statefulsets").Name(name).
+		Body([]byte(fmt.Sprintf(partitionPatchFmt, oldPartition))).Do(ctx).Error(); err != nil {
+		return fmt.Errorf("patch statefulset error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (e *StatefulSetPartitionExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
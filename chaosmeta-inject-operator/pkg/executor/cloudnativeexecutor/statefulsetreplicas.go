@@ -0,0 +1,94 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	v1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"strconv"
+	"time"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.StatefulsetCloudTarget, "replicas", &StatefulSetReplicasExecutor{})
+}
+
+type StatefulSetReplicasExecutor struct{}
+
+func (e *StatefulSetReplicasExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseStatefulSetInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected statefulset format: %s", err.Error())
+	}
+
+	replicasArgs, err := ParseReplicasArgs(args)
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	c := restclient.GetApiServerClientMap(v1alpha1.StatefulsetCloudTarget)
+	sts := &v1.StatefulSet{}
+	if err := c.Get().Namespace(ns).Resource("statefulsets").Name(name).Do(ctx).Into(sts); err != nil {
+		return "", fmt.Errorf("get statefulset error: %s", err.Error())
+	}
+
+	oldCount := int(*sts.Spec.Replicas)
+	var count = replicasArgs.getAbsoluteCount(oldCount)
+	if oldCount == count {
+		return strconv.Itoa(oldCount), nil
+	}
+
+	if err := c.Patch(types.MergePatchType).Namespace(ns).Resource("statefulsets").Name(name).
+		Body([]byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, count))).SubResource("scale").Do(ctx).Error(); err != nil {
+		return "", fmt.Errorf("patch statefulset error: %s", err.Error())
+	}
+
+	return strconv.Itoa(oldCount), nil
+}
+
+func (e *StatefulSetReplicasExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseStatefulSetInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected statefulset format: %s", err.Error())
+	}
+
+	oldCount, err := strconv.Atoi(backup)
+	if err != nil {
+		return fmt.Errorf("old replicas is not a num: %s", err.Error())
+	}
+
+	c := restclient.GetApiServerClientMap(v1alpha1.StatefulsetCloudTarget)
+	if err := c.Patch(types.MergePatchType).Namespace(ns).Resource("statefulsets").Name(name).
+		Body([]byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, oldCount))).SubResource("scale").Do(ctx).Error(); err != nil {
+		return fmt.Errorf("patch statefulset error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (e *StatefulSetReplicasExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
@@ -0,0 +1,319 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ephemeralexecutor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor/base"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strings"
+	"time"
+)
+
+const (
+	containerNamePrefix = "chaosmeta-"
+	// exitFile is created in the ephemeral container to stop it after the experiment is recovered
+	exitFile = "/tmp/chaosmeta-exit"
+
+	runningWaitTimeout  = 60 * time.Second
+	runningWaitInterval = time.Second
+)
+
+// supportTargets the ephemeral container only shares the process namespace of the target container and the network
+// namespace of the pod, faults of other targets would take effect on the ephemeral container itself
+var supportTargets = map[string]bool{
+	"network": true,
+	"process": true,
+}
+
+// EphemeralRemoteExecutor runs chaosmetad in a privileged ephemeral container attached to the target pod,
+// so that pod scope experiments can be executed without installing chaosmetad on the nodes.
+// Only the targets in supportTargets are supported. There is no executor on the nodes, so node scope experiments
+// and the faults executed on the node of the pod, such as pod containerkill and containerpause, are not supported.
+// Ephemeral containers can not be removed from a pod, so the container is stopped after the experiment is recovered
+// and remains in the pod spec until the pod is recreated.
+type EphemeralRemoteExecutor struct {
+	RESTConfig *rest.Config
+	Schema     *runtime.Scheme
+
+	Image    string
+	ExecPath string
+	Executor string
+	Version  string
+}
+
+func (r *EphemeralRemoteExecutor) CheckAlive(ctx context.Context, injectObject string) error {
+	return getNodeNotSupportErr(injectObject)
+}
+
+// Init install agent
+func (r *EphemeralRemoteExecutor) Init(ctx context.Context, target string) error {
+	return nil
+}
+
+func (r *EphemeralRemoteExecutor) Inject(ctx context.Context, injectObject string, target, fault, uid, timeout, cID, cRuntime string, args []v1alpha1.ArgsUnit) error {
+	return getNodeNotSupportErr(injectObject)
+}
+
+func (r *EphemeralRemoteExecutor) Recover(ctx context.Context, injectObject string, uid string) error {
+	return getNodeNotSupportErr(injectObject)
+}
+
+func (r *EphemeralRemoteExecutor) Query(ctx context.Context, injectObject string, uid string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return nil, getNodeNotSupportErr(injectObject)
+}
+
+// InjectPod attaches an ephemeral container to the pod of the target container and injects in it,
+// the name of the ephemeral container is returned as backup
+func (r *EphemeralRemoteExecutor) InjectPod(ctx context.Context, container *model.ContainerObject, target, fault, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	if !supportTargets[target] {
+		return "", fmt.Errorf("ephemeral executor only supports target network and process, not support target: %s", target)
+	}
+
+	cName := getContainerName(uid)
+	if err := r.attachContainer(ctx, container, cName); err != nil {
+		return "", fmt.Errorf("attach ephemeral container[%s] to pod[%s/%s] error: %s", cName, container.Namespace, container.PodName, err.Error())
+	}
+
+	executeCmd := getInjectCmd(r.getExecutor(), target, fault, uid, timeout, args)
+	if _, err := r.kubeExec(ctx, container.Namespace, container.PodName, cName, executeCmd); err != nil {
+		return "", fmt.Errorf("kubectl exec error: %s", err.Error())
+	}
+
+	return cName, nil
+}
+
+func (r *EphemeralRemoteExecutor) RecoverPod(ctx context.Context, container *model.ContainerObject, uid, backup string) error {
+	executeCmd := fmt.Sprintf("%s recover %s", r.getExecutor(), uid)
+	if _, err := r.kubeExec(ctx, container.Namespace, container.PodName, getBackupContainerName(uid, backup), executeCmd); err != nil {
+		return fmt.Errorf("kubectl exec error: %s", err.Error())
+	}
+
+	return nil
+}
+
+// QueryPod queries the experiment in the ephemeral container, and stops the container when it is recovered successfully
+func (r *EphemeralRemoteExecutor) QueryPod(ctx context.Context, container *model.ContainerObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	cName := getBackupContainerName(uid, backup)
+	executeCmd := fmt.Sprintf("%s query -u %s --format json", r.getExecutor(), uid)
+	stdout, err := r.kubeExec(ctx, container.Namespace, container.PodName, cName, executeCmd)
+	if err != nil {
+		return nil, fmt.Errorf("kubectl exec error: %s", err.Error())
+	}
+
+	var res base.QueryResponseData
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, fmt.Errorf("query output [%s] is not json format: %s", string(stdout), err.Error())
+	}
+
+	if res.Total != 1 {
+		return nil, fmt.Errorf("query output expect 1 but get: %d", res.Total)
+	}
+
+	info := &model.SubExpInfo{
+		UID:        uid,
+		CreateTime: res.Experiments[0].CreateTime,
+		UpdateTime: res.Experiments[0].UpdateTime,
+		Message:    res.Experiments[0].Error_,
+		Status:     base.ConvertStatus(res.Experiments[0].Status, phase),
+	}
+
+	if phase == v1alpha1.RecoverPhaseType && info.Status == v1alpha1.SuccessStatusType {
+		if _, err := r.kubeExec(ctx, container.Namespace, container.PodName, cName, fmt.Sprintf("touch %s", exitFile)); err != nil {
+			log.FromContext(ctx).Error(err, fmt.Sprintf("stop ephemeral container[%s] of pod[%s/%s] error", cName, container.Namespace, container.PodName))
+		}
+	}
+
+	return info, nil
+}
+
+// attachContainer adds the ephemeral container to the pod if not exists, and waits for it to be running
+func (r *EphemeralRemoteExecutor) attachContainer(ctx context.Context, container *model.ContainerObject, cName string) error {
+	c, pod := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget), &corev1.Pod{}
+	if err := c.Get().Namespace(container.Namespace).Resource("pods").Name(container.PodName).Do(ctx).Into(pod); err != nil {
+		return fmt.Errorf("get pod error: %s", err.Error())
+	}
+
+	if !isContainerExist(pod, cName) {
+		patchBytes, err := json.Marshal(map[string]interface{}{
+			"spec": map[string]interface{}{
+				"ephemeralContainers": []corev1.EphemeralContainer{r.newEphemeralContainer(cName, container.ContainerName)},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("convert patch data error: %s", err.Error())
+		}
+
+		if err := c.Patch(types.StrategicMergePatchType).Namespace(container.Namespace).Resource("pods").Name(container.PodName).
+			SubResource("ephemeralcontainers").Body(patchBytes).Do(ctx).Error(); err != nil {
+			return fmt.Errorf("patch ephemeral container error: %s", err.Error())
+		}
+	}
+
+	deadline := time.Now().Add(runningWaitTimeout)
+	for {
+		if err := c.Get().Namespace(container.Namespace).Resource("pods").Name(container.PodName).Do(ctx).Into(pod); err != nil {
+			return fmt.Errorf("get pod error: %s", err.Error())
+		}
+
+		running, err := isContainerRunning(pod, cName)
+		if err != nil {
+			return err
+		}
+
+		if running {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("wait for ephemeral container running timeout")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(runningWaitInterval):
+		}
+	}
+}
+
+func (r *EphemeralRemoteExecutor) newEphemeralContainer(cName, targetContainer string) corev1.EphemeralContainer {
+	privileged := true
+	return corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:            cName,
+			Image:           r.Image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         []string{"/bin/sh", "-c", fmt.Sprintf("while [ ! -f %s ]; do sleep 1; done", exitFile)},
+			SecurityContext: &corev1.SecurityContext{
+				Privileged: &privileged,
+			},
+		},
+		TargetContainerName: targetContainer,
+	}
+}
+
+func (r *EphemeralRemoteExecutor) getExecutor() string {
+	return fmt.Sprintf("%s/%s-%s/%s", r.ExecPath, r.Executor, r.Version, r.Executor)
+}
+
+func (r *EphemeralRemoteExecutor) kubeExec(ctx context.Context, ns, podName, cName, cmd string) ([]byte, error) {
+	logger := log.FromContext(ctx)
+	logger.Info(fmt.Sprintf("%s/%s/%s,exec: %s", ns, podName, cName, cmd))
+
+	execReq := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget).Post().
+		Namespace(ns).Resource("pods").Name(podName).SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: cName,
+			Command:   []string{"/bin/sh", "-c", cmd},
+			Stdin:     false,
+			Stdout:    true,
+			Stderr:    true,
+		}, runtime.NewParameterCodec(r.Schema))
+
+	exec, err := remotecommand.NewSPDYExecutor(r.RESTConfig, "POST", execReq.URL())
+	if err != nil {
+		return nil, fmt.Errorf("create remote cmd executor error: %s", err.Error())
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := exec.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}); err != nil {
+		return nil, fmt.Errorf("exec remote cmd error: %s %s %s", err.Error(), stdout.String(), stderr.String())
+	}
+
+	if stderr.String() != "" {
+		return stdout.Bytes(), fmt.Errorf("exec remote cmd get error message: %s", stderr.String())
+	}
+
+	return stdout.Bytes(), nil
+}
+
+func getNodeNotSupportErr(injectObject string) error {
+	return fmt.Errorf("ephemeral executor can not execute on node, node scope and pod containerkill/containerpause are not supported, inject object: %s", injectObject)
+}
+
+func getInjectCmd(executor, target, fault, uid, timeout string, args []v1alpha1.ArgsUnit) string {
+	executeCmd := fmt.Sprintf("%s inject %s %s --uid %s", executor, target, fault, uid)
+	for _, unitArgs := range args {
+		if unitArgs.Key == v1alpha1.ContainerKey {
+			continue
+		}
+
+		unitArgs.Key = strings.ReplaceAll(unitArgs.Key, "_", "-")
+		executeCmd = fmt.Sprintf("%s --%s=%s", executeCmd, unitArgs.Key, unitArgs.Value)
+	}
+
+	if timeout != "" {
+		executeCmd = fmt.Sprintf("%s --timeout %s", executeCmd, timeout)
+	}
+
+	return executeCmd
+}
+
+// getContainerName one ephemeral container for each sub experiment, so that retrying the injection is idempotent
+func getContainerName(uid string) string {
+	return containerNamePrefix + uid
+}
+
+// getBackupContainerName the backup is empty if the injection did not return successfully
+func getBackupContainerName(uid, backup string) string {
+	if backup != "" {
+		return backup
+	}
+
+	return getContainerName(uid)
+}
+
+func isContainerExist(pod *corev1.Pod, cName string) bool {
+	for _, unitC := range pod.Spec.EphemeralContainers {
+		if unitC.Name == cName {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isContainerRunning(pod *corev1.Pod, cName string) (bool, error) {
+	for _, unitStatus := range pod.Status.EphemeralContainerStatuses {
+		if unitStatus.Name != cName {
+			continue
+		}
+
+		if unitStatus.State.Terminated != nil {
+			return false, fmt.Errorf("ephemeral container is terminated: %s", unitStatus.State.Terminated.Reason)
+		}
+
+		return unitStatus.State.Running != nil, nil
+	}
+
+	return false, nil
+}
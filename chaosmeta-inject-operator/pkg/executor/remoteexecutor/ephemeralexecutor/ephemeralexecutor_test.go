@@ -0,0 +1,95 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ephemeralexecutor
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	corev1 "k8s.io/api/core/v1"
+	"testing"
+)
+
+func TestEphemeralRemoteExecutor_InjectPod_notSupportTarget(t *testing.T) {
+	r := &EphemeralRemoteExecutor{Image: "chaosmeta-daemon:v0.5.1"}
+	container := &model.ContainerObject{Namespace: "ns1", PodName: "pod1", ContainerName: "nginx"}
+	for _, target := range []string{"cpu", "mem", "disk", "file"} {
+		_, err := r.InjectPod(context.Background(), container, target, "fill", "123", "", nil)
+		assert.ErrorContains(t, err, "not support target: "+target)
+	}
+}
+
+func TestEphemeralRemoteExecutor_Inject(t *testing.T) {
+	r := &EphemeralRemoteExecutor{}
+	assert.ErrorContains(t, r.Inject(context.Background(), "10.0.0.1", "container", "kill", "123", "", "c1", "docker", nil),
+		"containerkill/containerpause are not supported")
+}
+
+func Test_getInjectCmd(t *testing.T) {
+	args := []v1alpha1.ArgsUnit{
+		{Key: v1alpha1.ContainerKey, Value: "nginx"},
+		{Key: "percent", Value: "80"},
+		{Key: "core_count", Value: "2"},
+	}
+
+	assert.Equal(t, "/opt/chaosmeta/chaosmetad-0.5.1/chaosmetad inject cpu burn --uid 123 --percent=80 --core-count=2 --timeout 10m",
+		getInjectCmd("/opt/chaosmeta/chaosmetad-0.5.1/chaosmetad", "cpu", "burn", "123", "10m", args))
+	assert.Equal(t, "chaosmetad inject network delay --uid 123",
+		getInjectCmd("chaosmetad", "network", "delay", "123", "", nil))
+}
+
+func Test_getBackupContainerName(t *testing.T) {
+	assert.Equal(t, "chaosmeta-123", getBackupContainerName("123", ""))
+	assert.Equal(t, "chaosmeta-456", getBackupContainerName("123", "chaosmeta-456"))
+}
+
+func Test_newEphemeralContainer(t *testing.T) {
+	r := &EphemeralRemoteExecutor{Image: "chaosmeta-daemon:v0.5.1"}
+	c := r.newEphemeralContainer("chaosmeta-123", "nginx")
+	assert.Equal(t, "chaosmeta-123", c.Name)
+	assert.Equal(t, "nginx", c.TargetContainerName)
+	assert.Equal(t, "chaosmeta-daemon:v0.5.1", c.Image)
+	assert.True(t, *c.SecurityContext.Privileged)
+}
+
+func Test_isContainerRunning(t *testing.T) {
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			EphemeralContainerStatuses: []corev1.ContainerStatus{
+				{Name: "chaosmeta-1", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}}},
+				{Name: "chaosmeta-2", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				{Name: "chaosmeta-3", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}}},
+			},
+		},
+	}
+
+	running, err := isContainerRunning(pod, "chaosmeta-1")
+	assert.NoError(t, err)
+	assert.False(t, running)
+
+	running, err = isContainerRunning(pod, "chaosmeta-2")
+	assert.NoError(t, err)
+	assert.True(t, running)
+
+	_, err = isContainerRunning(pod, "chaosmeta-3")
+	assert.Error(t, err)
+
+	running, err = isContainerRunning(pod, "chaosmeta-4")
+	assert.NoError(t, err)
+	assert.False(t, running)
+}
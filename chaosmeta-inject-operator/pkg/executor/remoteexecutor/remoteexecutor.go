@@ -23,7 +23,9 @@ import (
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/config"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor/agentexecutor"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor/daemonsetexecutor"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor/ephemeralexecutor"
 	httpclient "github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/http"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/metrics"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
@@ -35,6 +37,7 @@ type RemoteModeType string
 const (
 	AgentRemoteMode     RemoteModeType = "agent"
 	DaemonsetRemoteMode RemoteModeType = "daemonset"
+	EphemeralRemoteMode RemoteModeType = "ephemeral"
 )
 
 type RemoteExecutor interface {
@@ -48,12 +51,27 @@ type RemoteExecutor interface {
 	//SyncStatus(ctx context.Context, exp *v1alpha1.ExperimentStatus)
 }
 
-var globalRemoteExecutor RemoteExecutor
+// PodRemoteExecutor executes experiments inside the target pod instead of on its node,
+// the returned backup of InjectPod must be provided to recover and query
+type PodRemoteExecutor interface {
+	InjectPod(ctx context.Context, container *model.ContainerObject, target, fault, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error)
+	RecoverPod(ctx context.Context, container *model.ContainerObject, uid, backup string) error
+	QueryPod(ctx context.Context, container *model.ContainerObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error)
+}
+
+var (
+	globalRemoteExecutor    RemoteExecutor
+	globalPodRemoteExecutor PodRemoteExecutor
+)
 
 func SetGlobalRemoteExecutor(config *config.ExecutorConfig, restConfig *rest.Config, schema *runtime.Scheme) error {
+	var (
+		executor    RemoteExecutor
+		podExecutor PodRemoteExecutor
+	)
 	switch RemoteModeType(config.Mode) {
 	case AgentRemoteMode:
-		globalRemoteExecutor = &agentexecutor.AgentRemoteExecutor{
+		executor = &agentexecutor.AgentRemoteExecutor{
 			Client: &httpclient.HTTPClient{
 				Client: &http.Client{},
 			},
@@ -61,7 +79,7 @@ func SetGlobalRemoteExecutor(config *config.ExecutorConfig, restConfig *rest.Con
 			ServicePort: config.AgentConfig.AgentPort,
 		}
 	case DaemonsetRemoteMode:
-		globalRemoteExecutor = &daemonsetexecutor.DaemonsetRemoteExecutor{
+		executor = &daemonsetexecutor.DaemonsetRemoteExecutor{
 			//ApiServer:  apiServer,
 			RESTConfig: restConfig,
 			Schema:     schema,
@@ -76,13 +94,93 @@ func SetGlobalRemoteExecutor(config *config.ExecutorConfig, restConfig *rest.Con
 			//AutoLabelNode:     config.DaemonsetConfig.AutoLabelNode,
 			//NodeSelectorLabel: config.DaemonsetConfig.NodeSelectorLabel,
 		}
+	case EphemeralRemoteMode:
+		ephemeralExecutor := &ephemeralexecutor.EphemeralRemoteExecutor{
+			RESTConfig: restConfig,
+			Schema:     schema,
+
+			Image:    config.EphemeralConfig.Image,
+			ExecPath: config.EphemeralConfig.ExecPath,
+			Executor: config.Executor,
+			Version:  config.Version,
+		}
+		executor, podExecutor = ephemeralExecutor, ephemeralExecutor
 	default:
 		return fmt.Errorf("not support remote executor: %s", config.Mode)
 	}
 
+	globalRemoteExecutor = &metricRemoteExecutor{RemoteExecutor: executor, mode: RemoteModeType(config.Mode)}
+	globalPodRemoteExecutor = nil
+	if podExecutor != nil {
+		globalPodRemoteExecutor = &metricPodRemoteExecutor{PodRemoteExecutor: podExecutor, mode: RemoteModeType(config.Mode)}
+	}
 	return nil
 }
 
 func GetRemoteExecutor() RemoteExecutor {
 	return globalRemoteExecutor
 }
+
+// GetPodRemoteExecutor returns nil if the remote executor can not execute experiments inside the pod
+func GetPodRemoteExecutor() PodRemoteExecutor {
+	return globalPodRemoteExecutor
+}
+
+// metricRemoteExecutor counts the errors of calling remote executor by mode
+type metricRemoteExecutor struct {
+	RemoteExecutor
+	mode RemoteModeType
+}
+
+func (e *metricRemoteExecutor) CheckAlive(ctx context.Context, injectObject string) error {
+	return e.count("checkAlive", e.RemoteExecutor.CheckAlive(ctx, injectObject))
+}
+
+func (e *metricRemoteExecutor) Init(ctx context.Context, target string) error {
+	return e.count("init", e.RemoteExecutor.Init(ctx, target))
+}
+
+func (e *metricRemoteExecutor) Inject(ctx context.Context, injectObject string, target, fault, uid, timeout, cID, cRuntime string, args []v1alpha1.ArgsUnit) error {
+	return e.count("inject", e.RemoteExecutor.Inject(ctx, injectObject, target, fault, uid, timeout, cID, cRuntime, args))
+}
+
+func (e *metricRemoteExecutor) Recover(ctx context.Context, injectObject string, uid string) error {
+	return e.count("recover", e.RemoteExecutor.Recover(ctx, injectObject, uid))
+}
+
+func (e *metricRemoteExecutor) Query(ctx context.Context, injectObject string, uid string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	info, err := e.RemoteExecutor.Query(ctx, injectObject, uid, phase)
+	return info, e.count("query", err)
+}
+
+func (e *metricRemoteExecutor) count(operation string, err error) error {
+	return countError(e.mode, operation, err)
+}
+
+// metricPodRemoteExecutor counts the errors of calling pod remote executor by mode
+type metricPodRemoteExecutor struct {
+	PodRemoteExecutor
+	mode RemoteModeType
+}
+
+func (e *metricPodRemoteExecutor) InjectPod(ctx context.Context, container *model.ContainerObject, target, fault, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	backup, err := e.PodRemoteExecutor.InjectPod(ctx, container, target, fault, uid, timeout, args)
+	return backup, countError(e.mode, "inject", err)
+}
+
+func (e *metricPodRemoteExecutor) RecoverPod(ctx context.Context, container *model.ContainerObject, uid, backup string) error {
+	return countError(e.mode, "recover", e.PodRemoteExecutor.RecoverPod(ctx, container, uid, backup))
+}
+
+func (e *metricPodRemoteExecutor) QueryPod(ctx context.Context, container *model.ContainerObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	info, err := e.PodRemoteExecutor.QueryPod(ctx, container, uid, backup, phase)
+	return info, countError(e.mode, "query", err)
+}
+
+func countError(mode RemoteModeType, operation string, err error) error {
+	if err != nil {
+		metrics.ExecutorErrors.WithLabelValues(string(mode), operation).Inc()
+	}
+
+	return err
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"time"
+)
+
+const (
+	namespace        = "chaosmeta"
+	subsystem        = "inject"
+	collectorTimeout = 10 * time.Second
+)
+
+var (
+	// InjectLatency is the time cost of injecting one target
+	InjectLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "target_inject_duration_seconds",
+		Help:      "Time cost of injecting fault to one target.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"scope", "target", "fault"})
+
+	// ExecutorErrors is the count of errors when calling the remote agent
+	ExecutorErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "executor_errors_total",
+		Help:      "Count of errors when calling the remote executor.",
+	}, []string{"executor", "operation"})
+
+	experimentDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystem, "experiments"),
+		"Count of experiments by phase and status.",
+		[]string{"phase", "status"}, nil,
+	)
+)
+
+func init() {
+	crmetrics.Registry.MustRegister(InjectLatency, ExecutorErrors)
+}
+
+// ObserveInject records the time cost of injecting one target from start
+func ObserveInject(scope v1alpha1.ScopeType, expArgs *v1alpha1.ExperimentCommon, start time.Time) {
+	InjectLatency.WithLabelValues(string(scope), expArgs.Target, expArgs.Fault).Observe(time.Since(start).Seconds())
+}
+
+// experimentCollector counts the experiments in cache when scraped
+type experimentCollector struct {
+	reader client.Reader
+}
+
+// RegisterExperimentCollector registers the collector of experiments by phase and status
+func RegisterExperimentCollector(reader client.Reader) error {
+	return crmetrics.Registry.Register(&experimentCollector{reader: reader})
+}
+
+func (c *experimentCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- experimentDesc
+}
+
+func (c *experimentCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectorTimeout)
+	defer cancel()
+
+	expList := &v1alpha1.ExperimentList{}
+	if err := c.reader.List(ctx, expList); err != nil {
+		log.FromContext(ctx).Error(err, "list experiments for metrics error")
+		return
+	}
+
+	for key, count := range countExperiments(expList.Items) {
+		ch <- prometheus.MustNewConstMetric(experimentDesc, prometheus.GaugeValue, float64(count), string(key.phase), string(key.status))
+	}
+}
+
+type experimentKey struct {
+	phase  v1alpha1.PhaseType
+	status v1alpha1.StatusType
+}
+
+func countExperiments(expList []v1alpha1.Experiment) map[experimentKey]int {
+	counts := make(map[experimentKey]int)
+	for i := range expList {
+		counts[experimentKey{phase: expList[i].Status.Phase, status: expList[i].Status.Status}]++
+	}
+
+	return counts
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"strings"
+	"testing"
+)
+
+func newExperiment(name string, phase v1alpha1.PhaseType, status v1alpha1.StatusType) *v1alpha1.Experiment {
+	return &v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "chaosmeta", Name: name},
+		Status:     v1alpha1.ExperimentStatus{Phase: phase, Status: status},
+	}
+}
+
+func TestExperimentCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme error: %v", err)
+	}
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newExperiment("exp-1", v1alpha1.InjectPhaseType, v1alpha1.RunningStatusType),
+		newExperiment("exp-2", v1alpha1.InjectPhaseType, v1alpha1.RunningStatusType),
+		newExperiment("exp-3", v1alpha1.RecoverPhaseType, v1alpha1.SuccessStatusType),
+	).Build()
+
+	expected := `
+# HELP chaosmeta_inject_experiments Count of experiments by phase and status.
+# TYPE chaosmeta_inject_experiments gauge
+chaosmeta_inject_experiments{phase="inject",status="running"} 2
+chaosmeta_inject_experiments{phase="recover",status="success"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(&experimentCollector{reader: cli}, strings.NewReader(expected)))
+}
+
+func TestObserveInject(t *testing.T) {
+	expArgs := &v1alpha1.ExperimentCommon{Target: "cpu", Fault: "burn"}
+	ObserveInject(v1alpha1.PodScopeType, expArgs, metav1.Now().Time)
+	assert.Equal(t, 1, testutil.CollectAndCount(InjectLatency, "chaosmeta_inject_target_inject_duration_seconds"))
+}
@@ -0,0 +1,31 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import "time"
+
+// RangeInfo is the attributes of inject object used by RangeMode and ChaosPolicy
+type RangeInfo struct {
+	NodeName   string
+	Topology   string
+	Ready      bool
+	CreateTime time.Time
+	Labels     map[string]string
+	// Owner is the top workload of pod like "Deployment/nginx", OwnerReplicas is 0 when unknown
+	Owner         string
+	OwnerReplicas int
+}
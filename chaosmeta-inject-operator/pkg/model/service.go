@@ -0,0 +1,41 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ServiceObject struct {
+	Namespace   string
+	ServiceName string
+}
+
+func (d *ServiceObject) GetObjectName() string {
+	return fmt.Sprintf("%s%s%s%s%s", "service", ObjectNameSplit, d.Namespace, ObjectNameSplit, d.ServiceName)
+}
+
+func ParseServiceInfo(str string) (namespace, name string, err error) {
+	tmpArr := strings.Split(str, ObjectNameSplit)
+	if len(tmpArr) == 3 {
+		namespace, name = tmpArr[1], tmpArr[2]
+	} else {
+		err = fmt.Errorf("unexpected format of service string: %s", str)
+	}
+
+	return
+}
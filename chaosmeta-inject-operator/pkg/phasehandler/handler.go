@@ -40,7 +40,7 @@ func GetHandler(phase v1alpha1.PhaseType) PhaseHandler {
 	switch phase {
 	case v1alpha1.InjectPhaseType:
 		return globalInjectHandler
-	case v1alpha1.RecoverPhaseType:
+	case v1alpha1.RecoverPhaseType, v1alpha1.PausePhaseType:
 		return globalRecoverHandler
 	default:
 		return nil
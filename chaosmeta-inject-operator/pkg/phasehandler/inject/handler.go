@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/metrics"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/scopehandler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -115,7 +116,15 @@ func solveCreated(ctx context.Context, wg *sync.WaitGroup, exp *v1alpha1.Experim
 		return
 	}
 
-	backup, err := scopeHandler.ExecuteInject(ctx, commonObject, targetSubExp[i].UID, exp.Spec.Experiment)
+	expArgs, err := getInjectArgs(exp)
+	if err != nil {
+		targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.FailedStatusType, fmt.Sprintf("get inject args error: %s", err.Error())
+		return
+	}
+
+	start := time.Now()
+	backup, err := scopeHandler.ExecuteInject(ctx, commonObject, targetSubExp[i].UID, expArgs)
+	metrics.ObserveInject(exp.Spec.Scope, expArgs, start)
 	if err != nil {
 		if common.IsKeyUniqueErr(err) {
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.RunningStatusType, "experiment start success"
@@ -260,29 +269,54 @@ func (h *InjectPhaseHandler) SolveFailed(ctx context.Context, exp *v1alpha1.Expe
 }
 
 func solveFinalStatus(ctx context.Context, exp *v1alpha1.Experiment) {
-	if exp.Spec.TargetPhase == exp.Status.Phase || exp.Spec.TargetPhase != v1alpha1.RecoverPhaseType {
-		return
+	switch exp.Spec.TargetPhase {
+	case v1alpha1.RecoverPhaseType:
+		exp.Status.Detail.Recover = startNextPhase(exp, v1alpha1.RecoverPhaseType, "start to recover")
+	case v1alpha1.PausePhaseType:
+		exp.Status.Detail.Pause = startNextPhase(exp, v1alpha1.PausePhaseType, "start to pause")
 	}
+}
 
+// startNextPhase moves experiment to the phase of recover type and returns the details for it
+func startNextPhase(exp *v1alpha1.Experiment, phase v1alpha1.PhaseType, msg string) []v1alpha1.ExperimentDetailUnit {
 	injectDetail := exp.Status.Detail.Inject
 	nowTime := time.Now().Format(model.TimeFormat)
-	exp.Status.Phase, exp.Status.UpdateTime = v1alpha1.RecoverPhaseType, nowTime
-
-	if len(injectDetail) != 0 {
-		recoverDetail := make([]v1alpha1.ExperimentDetailUnit, len(injectDetail))
-		for i := range injectDetail {
-			recoverDetail[i] = v1alpha1.ExperimentDetailUnit{
-				InjectObjectName: injectDetail[i].InjectObjectName,
-				UID:              injectDetail[i].UID,
-				Status:           v1alpha1.CreatedStatusType,
-				Message:          "start to recover",
-				StartTime:        nowTime,
-				Backup:           injectDetail[i].Backup,
-			}
-		}
+	exp.Status.Phase, exp.Status.UpdateTime = phase, nowTime
 
-		exp.Status.Status, exp.Status.Detail.Recover = v1alpha1.CreatedStatusType, recoverDetail
-	} else {
+	if len(injectDetail) == 0 {
 		exp.Status.Status = v1alpha1.SuccessStatusType
+		return nil
+	}
+
+	detail := make([]v1alpha1.ExperimentDetailUnit, len(injectDetail))
+	for i := range injectDetail {
+		detail[i] = v1alpha1.ExperimentDetailUnit{
+			InjectObjectName: injectDetail[i].InjectObjectName,
+			UID:              injectDetail[i].UID,
+			Status:           v1alpha1.CreatedStatusType,
+			Message:          msg,
+			StartTime:        nowTime,
+			Backup:           injectDetail[i].Backup,
+			Reason:           injectDetail[i].Reason,
+		}
+	}
+
+	exp.Status.Status = v1alpha1.CreatedStatusType
+	return detail
+}
+
+// getInjectArgs returns the args used to inject, the resumed experiment only uses the remaining duration
+func getInjectArgs(exp *v1alpha1.Experiment) (*v1alpha1.ExperimentCommon, error) {
+	if len(exp.Status.Detail.Pause) == 0 {
+		return exp.Spec.Experiment, nil
 	}
+
+	remaining, err := common.GetRemainingDuration(exp.Status.CreateTime, exp.Spec.Experiment.Duration)
+	if err != nil {
+		return nil, err
+	}
+
+	expArgs := exp.Spec.Experiment.DeepCopy()
+	expArgs.Duration = remaining
+	return expArgs, nil
 }
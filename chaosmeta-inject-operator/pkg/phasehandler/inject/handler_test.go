@@ -552,3 +552,80 @@ func Test_solveFinalStatus(t *testing.T) {
 	assert.Equal(t, v1alpha1.RecoverPhaseType, exp.Status.Phase)
 	assert.Equal(t, len(exp.Status.Detail.Inject), len(exp.Status.Detail.Recover))
 }
+
+func Test_solveFinalStatus_Pause(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		nowTime = time.Now().Format(model.TimeFormat)
+		exp     = &v1alpha1.Experiment{
+			Spec: v1alpha1.ExperimentSpec{
+				Scope: v1alpha1.PodScopeType,
+				Experiment: &v1alpha1.ExperimentCommon{
+					Duration: "2m",
+					Target:   "cpu",
+					Fault:    "burn",
+				},
+				TargetPhase: v1alpha1.PausePhaseType,
+			},
+			Status: v1alpha1.ExperimentStatus{
+				Phase:      v1alpha1.InjectPhaseType,
+				Status:     v1alpha1.PartSuccessStatusType,
+				CreateTime: nowTime,
+				UpdateTime: nowTime,
+				Detail: v1alpha1.ExperimentDetail{
+					Inject: []v1alpha1.ExperimentDetailUnit{
+						{
+							InjectObjectName: "pod/chaosmeta/chaosmeta-1",
+							UID:              "fwaf1",
+							Status:           v1alpha1.SuccessStatusType,
+							Backup:           "backup1",
+						},
+						{
+							InjectObjectName: "pod/chaosmeta/chaosmeta-2",
+							UID:              "fwaf2",
+							Status:           v1alpha1.FailedStatusType,
+						},
+					},
+				},
+			},
+		}
+	)
+
+	solveFinalStatus(ctx, exp)
+
+	assert.Equal(t, v1alpha1.CreatedStatusType, exp.Status.Status)
+	assert.Equal(t, v1alpha1.PausePhaseType, exp.Status.Phase)
+	assert.Equal(t, 2, len(exp.Status.Detail.Pause))
+	assert.Equal(t, 0, len(exp.Status.Detail.Recover))
+	assert.Equal(t, "fwaf1", exp.Status.Detail.Pause[0].UID)
+	assert.Equal(t, "backup1", exp.Status.Detail.Pause[0].Backup)
+	assert.Equal(t, v1alpha1.CreatedStatusType, exp.Status.Detail.Pause[1].Status)
+}
+
+func Test_getInjectArgs(t *testing.T) {
+	exp := &v1alpha1.Experiment{
+		Spec: v1alpha1.ExperimentSpec{
+			Experiment: &v1alpha1.ExperimentCommon{
+				Duration: "10m",
+				Target:   "cpu",
+				Fault:    "burn",
+			},
+		},
+		Status: v1alpha1.ExperimentStatus{
+			CreateTime: time.Now().Add(-4 * time.Minute).Format(model.TimeFormat),
+		},
+	}
+
+	expArgs, err := getInjectArgs(exp)
+	assert.NoError(t, err)
+	assert.Equal(t, "10m", expArgs.Duration)
+
+	exp.Status.Detail.Pause = []v1alpha1.ExperimentDetailUnit{{InjectObjectName: "pod/chaosmeta/chaosmeta-1"}}
+	expArgs, err = getInjectArgs(exp)
+	assert.NoError(t, err)
+	remaining, err := v1alpha1.ConvertDuration(expArgs.Duration)
+	assert.NoError(t, err)
+	assert.True(t, remaining > 5*time.Minute && remaining <= 6*time.Minute+time.Second)
+	assert.Equal(t, "10m", exp.Spec.Experiment.Duration)
+	assert.Equal(t, "cpu", expArgs.Target)
+}
@@ -43,7 +43,7 @@ func (h *RecoverPhaseHandler) SolveCreated(ctx context.Context, exp *v1alpha1.Ex
 	}
 
 	var (
-		targetSubExp = exp.Status.Detail.Recover
+		targetSubExp = getTargetDetail(exp)
 		wg           = sync.WaitGroup{}
 	)
 
@@ -95,7 +95,7 @@ func (h *RecoverPhaseHandler) SolveRunning(ctx context.Context, exp *v1alpha1.Ex
 	}
 
 	var (
-		targetSubExp = exp.Status.Detail.Recover
+		targetSubExp = getTargetDetail(exp)
 		wg           = sync.WaitGroup{}
 	)
 
@@ -138,21 +138,115 @@ func (h *RecoverPhaseHandler) SolveRunning(ctx context.Context, exp *v1alpha1.Ex
 
 func (h *RecoverPhaseHandler) SolveSuccess(ctx context.Context, exp *v1alpha1.Experiment) {
 	log.FromContext(ctx).Info(fmt.Sprintf("experiment: %s/%s, SolveSuccess start", exp.Namespace, exp.Name))
+	solveFinalStatus(ctx, exp)
 }
 
 func (h *RecoverPhaseHandler) SolvePartSuccess(ctx context.Context, exp *v1alpha1.Experiment) {
 	log.FromContext(ctx).Info(fmt.Sprintf("experiment: %s/%s, SolvePartSuccess start", exp.Namespace, exp.Name))
+	solveFinalStatus(ctx, exp)
 }
 
 func (h *RecoverPhaseHandler) SolveFailed(ctx context.Context, exp *v1alpha1.Experiment) {
 	log.FromContext(ctx).Info(fmt.Sprintf("experiment: %s/%s, SolveFailed start", exp.Namespace, exp.Name))
+	solveFinalStatus(ctx, exp)
+}
+
+// getTargetDetail returns the details of current phase, the pause phase is solved the same as recover phase
+func getTargetDetail(exp *v1alpha1.Experiment) []v1alpha1.ExperimentDetailUnit {
+	if exp.Status.Phase == v1alpha1.PausePhaseType {
+		return exp.Status.Detail.Pause
+	}
+
+	return exp.Status.Detail.Recover
+}
+
+func solveFinalStatus(ctx context.Context, exp *v1alpha1.Experiment) {
+	if exp.Status.Phase != v1alpha1.PausePhaseType {
+		return
+	}
+
+	switch exp.Spec.TargetPhase {
+	case v1alpha1.ResumePhaseType:
+		solveResume(ctx, exp)
+	case v1alpha1.RecoverPhaseType:
+		solvePauseRecover(ctx, exp)
+	}
+}
+
+// solveResume injects the paused objects again with new sub-uid, objects failed to pause are kept with the old sub-uid
+func solveResume(ctx context.Context, exp *v1alpha1.Experiment) {
+	var (
+		pauseDetail  = exp.Status.Detail.Pause
+		nowTime      = time.Now().Format(model.TimeFormat)
+		injectDetail = make([]v1alpha1.ExperimentDetailUnit, len(pauseDetail))
+		createdCount int
+	)
+
+	for i := range pauseDetail {
+		injectDetail[i] = v1alpha1.ExperimentDetailUnit{
+			InjectObjectName: pauseDetail[i].InjectObjectName,
+			StartTime:        nowTime,
+			Reason:           pauseDetail[i].Reason,
+		}
+
+		if pauseDetail[i].Status == v1alpha1.SuccessStatusType {
+			injectDetail[i].UID, injectDetail[i].Status, injectDetail[i].Message = common.NewUid(), v1alpha1.CreatedStatusType, "start to resume"
+			createdCount++
+		} else {
+			injectDetail[i].UID, injectDetail[i].Backup = pauseDetail[i].UID, pauseDetail[i].Backup
+			injectDetail[i].Status, injectDetail[i].Message = v1alpha1.FailedStatusType, fmt.Sprintf("pause failed, not resumed: %s", pauseDetail[i].Message)
+		}
+	}
+
+	log.FromContext(ctx).Info(fmt.Sprintf("experiment: %s/%s, resume: totalCount[%d], createdCount[%d]", exp.Namespace, exp.Name, len(pauseDetail), createdCount))
+	exp.Status.Phase, exp.Status.UpdateTime, exp.Status.Detail.Inject = v1alpha1.InjectPhaseType, nowTime, injectDetail
+	if createdCount > 0 {
+		exp.Status.Status, exp.Status.Message = v1alpha1.CreatedStatusType, "start to resume"
+	} else if len(pauseDetail) == 0 {
+		exp.Status.Status, exp.Status.Message = v1alpha1.SuccessStatusType, "no object to resume"
+	} else {
+		exp.Status.Status, exp.Status.Message = v1alpha1.FailedStatusType, "all objects failed to pause, no object to resume"
+	}
+}
+
+// solvePauseRecover recovers the objects which are not paused successfully
+func solvePauseRecover(ctx context.Context, exp *v1alpha1.Experiment) {
+	var (
+		pauseDetail   = exp.Status.Detail.Pause
+		nowTime       = time.Now().Format(model.TimeFormat)
+		recoverDetail []v1alpha1.ExperimentDetailUnit
+	)
+
+	for i := range pauseDetail {
+		if pauseDetail[i].Status == v1alpha1.SuccessStatusType {
+			continue
+		}
+
+		recoverDetail = append(recoverDetail, v1alpha1.ExperimentDetailUnit{
+			InjectObjectName: pauseDetail[i].InjectObjectName,
+			UID:              pauseDetail[i].UID,
+			Status:           v1alpha1.CreatedStatusType,
+			Message:          "start to recover",
+			StartTime:        nowTime,
+			Backup:           pauseDetail[i].Backup,
+			Reason:           pauseDetail[i].Reason,
+		})
+	}
+
+	log.FromContext(ctx).Info(fmt.Sprintf("experiment: %s/%s, recover from pause: totalCount[%d], recoverCount[%d]", exp.Namespace, exp.Name, len(pauseDetail), len(recoverDetail)))
+	exp.Status.Phase, exp.Status.UpdateTime, exp.Status.Detail.Recover = v1alpha1.RecoverPhaseType, nowTime, recoverDetail
+	if len(recoverDetail) > 0 {
+		exp.Status.Status, exp.Status.Message = v1alpha1.CreatedStatusType, "start to recover"
+	} else {
+		exp.Status.Status, exp.Status.Message = v1alpha1.SuccessStatusType, "all objects are recovered when pause"
+	}
 }
 
 func solveCreated(ctx context.Context, wg *sync.WaitGroup, exp *v1alpha1.Experiment, i int, isTimeout bool) {
 	var (
 		logger       = log.FromContext(ctx)
 		scopeHandler = scopehandler.GetScopeHandler(exp.Spec.Scope)
-		targetSubExp = exp.Status.Detail.Recover
+		targetSubExp = getTargetDetail(exp)
 		commonObject model.AtomicObject
 		err          error
 	)
@@ -201,7 +295,7 @@ func solveCreated(ctx context.Context, wg *sync.WaitGroup, exp *v1alpha1.Experim
 func solveRunning(ctx context.Context, wg *sync.WaitGroup, exp *v1alpha1.Experiment, i int, isTimeout bool) {
 	var (
 		scopeHandler = scopehandler.GetScopeHandler(exp.Spec.Scope)
-		targetSubExp = exp.Status.Detail.Recover
+		targetSubExp = getTargetDetail(exp)
 		commonObject model.AtomicObject
 		err          error
 		logger       = log.FromContext(ctx)
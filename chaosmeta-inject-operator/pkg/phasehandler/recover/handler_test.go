@@ -253,3 +253,82 @@ func TestRecoverPhaseHandler_SolveRunning(t *testing.T) {
 	assert.Equal(t, v1alpha1.FailedStatusType, exp.Status.Detail.Recover[0].Status)
 	assert.Equal(t, v1alpha1.FailedStatusType, exp.Status.Detail.Recover[1].Status)
 }
+
+func newPausedExperiment(targetPhase v1alpha1.PhaseType) *v1alpha1.Experiment {
+	nowTime := time.Now().Format(model.TimeFormat)
+	return &v1alpha1.Experiment{
+		Spec: v1alpha1.ExperimentSpec{
+			Scope: v1alpha1.PodScopeType,
+			Experiment: &v1alpha1.ExperimentCommon{
+				Duration: "2m",
+				Target:   "cpu",
+				Fault:    "burn",
+			},
+			TargetPhase: targetPhase,
+		},
+		Status: v1alpha1.ExperimentStatus{
+			Phase:      v1alpha1.PausePhaseType,
+			Status:     v1alpha1.PartSuccessStatusType,
+			CreateTime: nowTime,
+			UpdateTime: nowTime,
+			Detail: v1alpha1.ExperimentDetail{
+				Pause: []v1alpha1.ExperimentDetailUnit{
+					{
+						InjectObjectName: "pod/chaosmeta/chaosmeta-1",
+						UID:              "fwaf1",
+						Status:           v1alpha1.SuccessStatusType,
+						Reason:           "reason1",
+					},
+					{
+						InjectObjectName: "pod/chaosmeta/chaosmeta-2",
+						UID:              "fwaf2",
+						Status:           v1alpha1.FailedStatusType,
+						Backup:           "backup2",
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_solveFinalStatus_Resume(t *testing.T) {
+	ctx, exp := context.Background(), newPausedExperiment(v1alpha1.PausePhaseType)
+
+	solveFinalStatus(ctx, exp)
+	assert.Equal(t, v1alpha1.PausePhaseType, exp.Status.Phase)
+	assert.Equal(t, v1alpha1.PartSuccessStatusType, exp.Status.Status)
+
+	exp.Spec.TargetPhase = v1alpha1.ResumePhaseType
+	solveFinalStatus(ctx, exp)
+
+	inject := exp.Status.Detail.Inject
+	assert.Equal(t, v1alpha1.InjectPhaseType, exp.Status.Phase)
+	assert.Equal(t, v1alpha1.CreatedStatusType, exp.Status.Status)
+	assert.Equal(t, 2, len(inject))
+	assert.Equal(t, v1alpha1.CreatedStatusType, inject[0].Status)
+	assert.NotEqual(t, "fwaf1", inject[0].UID)
+	assert.Equal(t, "reason1", inject[0].Reason)
+	assert.Equal(t, v1alpha1.FailedStatusType, inject[1].Status)
+	assert.Equal(t, "fwaf2", inject[1].UID)
+	assert.Equal(t, "backup2", inject[1].Backup)
+}
+
+func Test_solveFinalStatus_PauseRecover(t *testing.T) {
+	ctx, exp := context.Background(), newPausedExperiment(v1alpha1.RecoverPhaseType)
+
+	solveFinalStatus(ctx, exp)
+
+	recoverDetail := exp.Status.Detail.Recover
+	assert.Equal(t, v1alpha1.RecoverPhaseType, exp.Status.Phase)
+	assert.Equal(t, v1alpha1.CreatedStatusType, exp.Status.Status)
+	assert.Equal(t, 1, len(recoverDetail))
+	assert.Equal(t, "fwaf2", recoverDetail[0].UID)
+	assert.Equal(t, "backup2", recoverDetail[0].Backup)
+
+	exp = newPausedExperiment(v1alpha1.RecoverPhaseType)
+	exp.Status.Detail.Pause = exp.Status.Detail.Pause[:1]
+	solveFinalStatus(ctx, exp)
+	assert.Equal(t, v1alpha1.RecoverPhaseType, exp.Status.Phase)
+	assert.Equal(t, v1alpha1.SuccessStatusType, exp.Status.Status)
+	assert.Equal(t, 0, len(exp.Status.Detail.Recover))
+}
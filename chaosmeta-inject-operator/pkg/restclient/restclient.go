@@ -53,12 +53,24 @@ func newClient(target v1alpha1.CloudTargetType, c *rest.Config, s *runtime.Schem
 		e, err = newRESTClientForGVK("", "v1", "Pod", c, s)
 	case v1alpha1.DeploymentCloudTarget:
 		e, err = newRESTClientForGVK("apps", "v1", "Deployment", c, s)
+	case v1alpha1.StatefulsetCloudTarget:
+		e, err = newRESTClientForGVK("apps", "v1", "StatefulSet", c, s)
+	case v1alpha1.DaemonsetCloudTarget:
+		e, err = newRESTClientForGVK("apps", "v1", "DaemonSet", c, s)
 	case v1alpha1.NodeCloudTarget:
 		e, err = newRESTClientForGVK("", "v1", "Node", c, s)
 	case v1alpha1.NamespaceCloudTarget:
 		e, err = newRESTClientForGVK("", "v1", "Namespace", c, s)
 	case v1alpha1.JobCloudTarget:
 		e, err = newRESTClientForGVK("batch", "v1", "Job", c, s)
+	case v1alpha1.ServiceCloudTarget:
+		e, err = newRESTClientForGVK("", "v1", "Service", c, s)
+	case v1alpha1.ConfigMapCloudTarget:
+		e, err = newRESTClientForGVK("", "v1", "ConfigMap", c, s)
+	case v1alpha1.SecretCloudTarget:
+		e, err = newRESTClientForGVK("", "v1", "Secret", c, s)
+	case v1alpha1.NetworkPolicyCloudTarget:
+		e, err = newRESTClientForGVK("networking.k8s.io", "v1", "NetworkPolicy", c, s)
 	default:
 		err = fmt.Errorf("not support target: %s", target)
 	}
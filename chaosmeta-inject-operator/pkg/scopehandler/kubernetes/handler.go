@@ -43,6 +43,20 @@ func (k KubernetesScopeHandler) ConvertSelector(ctx context.Context, spec *v1alp
 		return pod.GetGlobalPodHandler().ConvertSelector(ctx, spec)
 	case v1alpha1.DeploymentCloudTarget:
 		return convertDeploy(ctx, spec)
+	case v1alpha1.StatefulsetCloudTarget:
+		return convertNamespacedObject(ctx, spec, getStatefulSetObjectFromSelector)
+	case v1alpha1.DaemonsetCloudTarget:
+		return convertNamespacedObject(ctx, spec, getDaemonSetObjectFromSelector)
+	case v1alpha1.JobCloudTarget:
+		return convertNamespacedObject(ctx, spec, getJobObjectFromSelector)
+	case v1alpha1.ServiceCloudTarget:
+		return convertNamespacedObject(ctx, spec, getServiceObjectFromSelector)
+	case v1alpha1.ConfigMapCloudTarget:
+		return convertNamespacedObject(ctx, spec, getConfigMapObjectFromSelector)
+	case v1alpha1.SecretCloudTarget:
+		return convertNamespacedObject(ctx, spec, getSecretObjectFromSelector)
+	case v1alpha1.NamespaceCloudTarget:
+		return convertNamespace(ctx, spec)
 	case v1alpha1.NodeCloudTarget:
 		return node.GetGlobalNodeHandler().ConvertSelector(ctx, spec)
 	case v1alpha1.ClusterCloudTarget:
@@ -66,9 +80,69 @@ func (k KubernetesScopeHandler) GetInjectObject(ctx context.Context, exp *v1alph
 			Namespace:      ns,
 			DeploymentName: name,
 		}, nil
+	case v1alpha1.StatefulsetCloudTarget:
+		ns, name, err := model.ParseStatefulSetInfo(objectName)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected statefulset object name: %s", objectName)
+		}
+
+		return &model.StatefulSetObject{
+			Namespace:       ns,
+			StatefulSetName: name,
+		}, nil
+	case v1alpha1.DaemonsetCloudTarget:
+		ns, name, err := model.ParseDaemonSetInfo(objectName)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected daemonset object name: %s", objectName)
+		}
+
+		return &model.DaemonSetObject{
+			Namespace:     ns,
+			DaemonSetName: name,
+		}, nil
+	case v1alpha1.JobCloudTarget:
+		ns, name, err := model.ParseJobInfo(objectName)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected job object name: %s", objectName)
+		}
+
+		return &model.JobObject{
+			Namespace: ns,
+			JobName:   name,
+		}, nil
+	case v1alpha1.ServiceCloudTarget:
+		ns, name, err := model.ParseServiceInfo(objectName)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected service object name: %s", objectName)
+		}
+
+		return &model.ServiceObject{
+			Namespace:   ns,
+			ServiceName: name,
+		}, nil
+	case v1alpha1.ConfigMapCloudTarget:
+		ns, name, err := model.ParseConfigMapInfo(objectName)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected configmap object name: %s", objectName)
+		}
+
+		return &model.ConfigMapObject{
+			Namespace:     ns,
+			ConfigMapName: name,
+		}, nil
+	case v1alpha1.SecretCloudTarget:
+		ns, name, err := model.ParseSecretInfo(objectName)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected secret object name: %s", objectName)
+		}
+
+		return &model.SecretObject{
+			Namespace:  ns,
+			SecretName: name,
+		}, nil
 	case v1alpha1.NodeCloudTarget:
 		return node.GetGlobalNodeHandler().GetInjectObject(ctx, exp, objectName)
-	case v1alpha1.ClusterCloudTarget:
+	case v1alpha1.ClusterCloudTarget, v1alpha1.NamespaceCloudTarget:
 		return &model.NamespaceObject{
 			Namespace: objectName,
 		}, nil
@@ -110,6 +184,12 @@ func convertCluster(ctx context.Context, spec *v1alpha1.ExperimentSpec) ([]model
 }
 
 func convertDeploy(ctx context.Context, spec *v1alpha1.ExperimentSpec) ([]model.AtomicObject, error) {
+	return convertNamespacedObject(ctx, spec, getDeployObjectFromSelector)
+}
+
+// convertNamespacedObject converts selectors of namespaced workload targets, each selector must provide namespace
+func convertNamespacedObject(ctx context.Context, spec *v1alpha1.ExperimentSpec,
+	getFunc func(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) ([]model.AtomicObject, error)) ([]model.AtomicObject, error) {
 	var (
 		result  []model.AtomicObject
 		isExist = make(map[string]bool)
@@ -117,10 +197,18 @@ func convertDeploy(ctx context.Context, spec *v1alpha1.ExperimentSpec) ([]model.
 
 	for _, unitSelector := range spec.Selector {
 		if unitSelector.Namespace == "" {
-			return nil, fmt.Errorf("selector of scope deployment must provide namespace")
+			return nil, fmt.Errorf("selector of scope %s must provide namespace", spec.Experiment.Target)
 		}
 
-		resultUnitSelector, err := getDeployObjectFromSelector(ctx, unitSelector)
+		var (
+			resultUnitSelector []model.AtomicObject
+			err                error
+		)
+		if selector.IsExtendedSelector(unitSelector) {
+			resultUnitSelector, err = selector.GetAnalyzer().GetObjectListBySelector(ctx, v1alpha1.CloudTargetType(spec.Experiment.Target), unitSelector)
+		} else {
+			resultUnitSelector, err = getFunc(ctx, unitSelector)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -161,3 +249,191 @@ func getDeployObjectFromSelector(ctx context.Context, selectorUnit v1alpha1.Sele
 
 	return result, err
 }
+
+func getStatefulSetObjectFromSelector(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) ([]model.AtomicObject, error) {
+	var err error
+	analyzer := selector.GetAnalyzer()
+	var reList []*model.StatefulSetObject
+	if len(selectorUnit.Name) != 0 {
+		reList, err = analyzer.GetStatefulSetListByName(ctx, selectorUnit.Namespace, selectorUnit.Name)
+		if err != nil {
+			return nil, fmt.Errorf("get statefulset info by name list error: %s", err.Error())
+		}
+	} else {
+		reList, err = analyzer.GetStatefulSetListByLabel(ctx, selectorUnit.Namespace, selectorUnit.Label)
+		if err != nil {
+			return nil, fmt.Errorf("get statefulset info by label error: %s", err.Error())
+		}
+	}
+
+	var result = make([]model.AtomicObject, len(reList))
+	for i := range reList {
+		result[i] = reList[i]
+	}
+
+	return result, err
+}
+
+func getDaemonSetObjectFromSelector(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) ([]model.AtomicObject, error) {
+	var err error
+	analyzer := selector.GetAnalyzer()
+	var reList []*model.DaemonSetObject
+	if len(selectorUnit.Name) != 0 {
+		reList, err = analyzer.GetDaemonSetListByName(ctx, selectorUnit.Namespace, selectorUnit.Name)
+		if err != nil {
+			return nil, fmt.Errorf("get daemonset info by name list error: %s", err.Error())
+		}
+	} else {
+		reList, err = analyzer.GetDaemonSetListByLabel(ctx, selectorUnit.Namespace, selectorUnit.Label)
+		if err != nil {
+			return nil, fmt.Errorf("get daemonset info by label error: %s", err.Error())
+		}
+	}
+
+	var result = make([]model.AtomicObject, len(reList))
+	for i := range reList {
+		result[i] = reList[i]
+	}
+
+	return result, err
+}
+
+func getJobObjectFromSelector(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) ([]model.AtomicObject, error) {
+	var err error
+	analyzer := selector.GetAnalyzer()
+	var reList []*model.JobObject
+	if len(selectorUnit.Name) != 0 {
+		reList, err = analyzer.GetJobListByName(ctx, selectorUnit.Namespace, selectorUnit.Name)
+		if err != nil {
+			return nil, fmt.Errorf("get job info by name list error: %s", err.Error())
+		}
+	} else {
+		reList, err = analyzer.GetJobListByLabel(ctx, selectorUnit.Namespace, selectorUnit.Label)
+		if err != nil {
+			return nil, fmt.Errorf("get job info by label error: %s", err.Error())
+		}
+	}
+
+	var result = make([]model.AtomicObject, len(reList))
+	for i := range reList {
+		result[i] = reList[i]
+	}
+
+	return result, err
+}
+
+func getServiceObjectFromSelector(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) ([]model.AtomicObject, error) {
+	var err error
+	analyzer := selector.GetAnalyzer()
+	var reList []*model.ServiceObject
+	if len(selectorUnit.Name) != 0 {
+		reList, err = analyzer.GetServiceListByName(ctx, selectorUnit.Namespace, selectorUnit.Name)
+		if err != nil {
+			return nil, fmt.Errorf("get service info by name list error: %s", err.Error())
+		}
+	} else {
+		reList, err = analyzer.GetServiceListByLabel(ctx, selectorUnit.Namespace, selectorUnit.Label)
+		if err != nil {
+			return nil, fmt.Errorf("get service info by label error: %s", err.Error())
+		}
+	}
+
+	var result = make([]model.AtomicObject, len(reList))
+	for i := range reList {
+		result[i] = reList[i]
+	}
+
+	return result, err
+}
+
+func getConfigMapObjectFromSelector(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) ([]model.AtomicObject, error) {
+	var err error
+	analyzer := selector.GetAnalyzer()
+	var reList []*model.ConfigMapObject
+	if len(selectorUnit.Name) != 0 {
+		reList, err = analyzer.GetConfigMapListByName(ctx, selectorUnit.Namespace, selectorUnit.Name)
+		if err != nil {
+			return nil, fmt.Errorf("get configmap info by name list error: %s", err.Error())
+		}
+	} else {
+		reList, err = analyzer.GetConfigMapListByLabel(ctx, selectorUnit.Namespace, selectorUnit.Label)
+		if err != nil {
+			return nil, fmt.Errorf("get configmap info by label error: %s", err.Error())
+		}
+	}
+
+	var result = make([]model.AtomicObject, len(reList))
+	for i := range reList {
+		result[i] = reList[i]
+	}
+
+	return result, err
+}
+
+func getSecretObjectFromSelector(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) ([]model.AtomicObject, error) {
+	var err error
+	analyzer := selector.GetAnalyzer()
+	var reList []*model.SecretObject
+	if len(selectorUnit.Name) != 0 {
+		reList, err = analyzer.GetSecretListByName(ctx, selectorUnit.Namespace, selectorUnit.Name)
+		if err != nil {
+			return nil, fmt.Errorf("get secret info by name list error: %s", err.Error())
+		}
+	} else {
+		reList, err = analyzer.GetSecretListByLabel(ctx, selectorUnit.Namespace, selectorUnit.Label)
+		if err != nil {
+			return nil, fmt.Errorf("get secret info by label error: %s", err.Error())
+		}
+	}
+
+	var result = make([]model.AtomicObject, len(reList))
+	for i := range reList {
+		result[i] = reList[i]
+	}
+
+	return result, err
+}
+
+// convertNamespace selects namespaces by name, label or extended selector, "namespace" of selector is ignored
+func convertNamespace(ctx context.Context, spec *v1alpha1.ExperimentSpec) ([]model.AtomicObject, error) {
+	var (
+		result   []model.AtomicObject
+		isExist  = make(map[string]bool)
+		analyzer = selector.GetAnalyzer()
+	)
+
+	for _, unitSelector := range spec.Selector {
+		var (
+			reList []*model.NamespaceObject
+			err    error
+		)
+
+		if selector.IsExtendedSelector(unitSelector) {
+			var objList []model.AtomicObject
+			objList, err = analyzer.GetObjectListBySelector(ctx, v1alpha1.NamespaceCloudTarget, unitSelector)
+			for _, obj := range objList {
+				reList = append(reList, obj.(*model.NamespaceObject))
+			}
+		} else if len(unitSelector.Name) != 0 {
+			reList, err = analyzer.GetNamespaceListByName(ctx, unitSelector.Name)
+		} else if len(unitSelector.Label) != 0 {
+			reList, err = analyzer.GetNamespaceListByLabel(ctx, unitSelector.Label)
+		} else {
+			return nil, fmt.Errorf("selector of scope namespace must provide name or label")
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("get namespace info error: %s", err.Error())
+		}
+
+		for _, unitObj := range reList {
+			if isExist[unitObj.GetObjectName()] {
+				continue
+			}
+			isExist[unitObj.GetObjectName()] = true
+			result = append(result, unitObj)
+		}
+	}
+
+	return result, nil
+}
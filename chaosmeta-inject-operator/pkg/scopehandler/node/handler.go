@@ -124,12 +124,14 @@ func (h *NodeScopeHandler) GetInjectObject(ctx context.Context, exp *v1alpha1.Ex
 	return nodeInfo, nil
 }
 
-// getInjectObjectList IP > nodeName > label
+// getInjectObjectList IP > nodeName > label, all conditions are "AND" when extended selector is used
 func getNodeObjectList(ctx context.Context, selectorUnit v1alpha1.SelectorUnit, containerName string) ([]model.AtomicObject, error) {
 	var err error
 	analyzer := selector.GetAnalyzer()
 	var nodeList []*model.NodeObject
-	if len(selectorUnit.IP) > 0 {
+	if selector.IsExtendedSelector(selectorUnit) {
+		nodeList, err = analyzer.GetNodeListBySelector(ctx, selectorUnit, containerName)
+	} else if len(selectorUnit.IP) > 0 {
 		nodeList, err = analyzer.GetNodeListByNodeIP(ctx, selectorUnit.IP, containerName)
 	} else if len(selectorUnit.Name) > 0 {
 		nodeList, err = analyzer.GetNodeListByNodeName(ctx, selectorUnit.Name, containerName)
@@ -83,6 +83,11 @@ func (h *PodScopeHandler) CheckAlive(ctx context.Context, injectObject model.Ato
 		return fmt.Errorf("inject object change to pod error")
 	}
 
+	// the ephemeral container is attached when injecting, nothing to check in advance
+	if remoteexecutor.GetPodRemoteExecutor() != nil {
+		return nil
+	}
+
 	return remoteexecutor.GetRemoteExecutor().CheckAlive(ctx, pod.NodeIP)
 }
 
@@ -92,6 +97,10 @@ func (h *PodScopeHandler) QueryExperiment(ctx context.Context, injectObject mode
 		return nil, fmt.Errorf("inject object change to container error")
 	}
 
+	if podExecutor := remoteexecutor.GetPodRemoteExecutor(); podExecutor != nil {
+		return podExecutor.QueryPod(ctx, container, UID, backup, phase)
+	}
+
 	return remoteexecutor.GetRemoteExecutor().Query(ctx, container.NodeIP, UID, phase)
 
 }
@@ -102,6 +111,10 @@ func (h *PodScopeHandler) ExecuteInject(ctx context.Context, injectObject model.
 		return "", fmt.Errorf("inject object change to pod error")
 	}
 
+	if podExecutor := remoteexecutor.GetPodRemoteExecutor(); podExecutor != nil {
+		return podExecutor.InjectPod(ctx, c, expArgs.Target, expArgs.Fault, UID, expArgs.Duration, expArgs.Args)
+	}
+
 	err := remoteexecutor.GetRemoteExecutor().Inject(ctx, c.NodeIP, expArgs.Target, expArgs.Fault, UID, expArgs.Duration, c.ContainerId, c.ContainerRuntime, expArgs.Args)
 	if err != nil {
 		return "", err
@@ -116,6 +129,10 @@ func (h *PodScopeHandler) ExecuteRecover(ctx context.Context, injectObject model
 		return fmt.Errorf("inject object change to pod error")
 	}
 
+	if podExecutor := remoteexecutor.GetPodRemoteExecutor(); podExecutor != nil {
+		return podExecutor.RecoverPod(ctx, container, UID, backup)
+	}
+
 	return remoteexecutor.GetRemoteExecutor().Recover(ctx, container.NodeIP, UID)
 }
 
@@ -123,7 +140,12 @@ func getPodObjectList(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) (
 	var err error
 	analyzer := selector.GetAnalyzer()
 	var podList []*model.PodObject
-	if len(selectorUnit.Name) != 0 {
+	if selector.IsExtendedSelector(selectorUnit) {
+		podList, err = analyzer.GetPodListBySelector(ctx, selectorUnit)
+		if err != nil {
+			return nil, fmt.Errorf("get pod info by selector error: %s", err.Error())
+		}
+	} else if len(selectorUnit.Name) != 0 {
 		podList, err = analyzer.GetPodListByPodName(ctx, selectorUnit.Namespace, selectorUnit.Name, selectorUnit.SubName)
 		if err != nil {
 			return nil, fmt.Errorf("get pod info by podname list error: %s", err.Error())
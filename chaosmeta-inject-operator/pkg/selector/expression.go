@@ -0,0 +1,382 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selector
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+)
+
+const (
+	replicaSetKind = "ReplicaSet"
+	jobKind        = "Job"
+)
+
+// IsExtendedSelector return true if the selector unit use matchExpressions, annotation, field or owner
+func IsExtendedSelector(unit v1alpha1.SelectorUnit) bool {
+	return len(unit.MatchExpressions) != 0 || len(unit.Annotation) != 0 || unit.Field != "" || unit.Owner != nil
+}
+
+// unitMatcher is the parsed SelectorUnit, all conditions are "AND"
+type unitMatcher struct {
+	names      map[string]bool
+	label      labels.Selector
+	annotation map[string]string
+	field      fields.Selector
+	owner      *v1alpha1.OwnerSelector
+}
+
+func newUnitMatcher(unit v1alpha1.SelectorUnit) (*unitMatcher, error) {
+	labelSelector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
+		MatchLabels:      unit.Label,
+		MatchExpressions: unit.MatchExpressions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("parse label selector error: %s", err.Error())
+	}
+
+	fieldSelector, err := fields.ParseSelector(unit.Field)
+	if err != nil {
+		return nil, fmt.Errorf("parse field selector[%s] error: %s", unit.Field, err.Error())
+	}
+
+	if unit.Owner != nil && (unit.Owner.Kind == "" || unit.Owner.Name == "") {
+		return nil, fmt.Errorf("owner selector must provide kind and name")
+	}
+
+	m := &unitMatcher{
+		label:      labelSelector,
+		annotation: unit.Annotation,
+		field:      fieldSelector,
+		owner:      unit.Owner,
+	}
+
+	if len(unit.Name) != 0 {
+		m.names = make(map[string]bool)
+		for _, unitName := range unit.Name {
+			m.names[unitName] = true
+		}
+	}
+
+	return m, nil
+}
+
+// matchStatic checks all the conditions except owner, which need to query apiserver
+func (m *unitMatcher) matchStatic(obj client.Object) (bool, error) {
+	if m.names != nil && !m.names[obj.GetName()] {
+		return false, nil
+	}
+
+	if !m.label.Matches(labels.Set(obj.GetLabels())) {
+		return false, nil
+	}
+
+	objAnnotations := obj.GetAnnotations()
+	for k, v := range m.annotation {
+		if realV, ok := objAnnotations[k]; !ok || realV != v {
+			return false, nil
+		}
+	}
+
+	return matchFields(obj, m.field)
+}
+
+// matchFields supports any field path of the object, values are compared as string and absent field means ""
+func matchFields(obj client.Object, fieldSelector fields.Selector) (bool, error) {
+	if fieldSelector.Empty() {
+		return true, nil
+	}
+
+	objMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return false, fmt.Errorf("convert %s to unstructured error: %s", obj.GetName(), err.Error())
+	}
+
+	for _, r := range fieldSelector.Requirements() {
+		value, found, err := unstructured.NestedFieldNoCopy(objMap, strings.Split(strings.TrimPrefix(r.Field, "."), ".")...)
+		if err != nil {
+			return false, fmt.Errorf("get field[%s] of %s error: %s", r.Field, obj.GetName(), err.Error())
+		}
+
+		var realV string
+		if found && value != nil {
+			realV = fmt.Sprint(value)
+		}
+
+		switch r.Operator {
+		case selection.Equals, selection.DoubleEquals:
+			if realV != r.Value {
+				return false, nil
+			}
+		case selection.NotEquals:
+			if realV == r.Value {
+				return false, nil
+			}
+		default:
+			return false, fmt.Errorf("not support field operator: %s", r.Operator)
+		}
+	}
+
+	return true, nil
+}
+
+// isOwnedBy checks the owner references recursively through ReplicaSet and Job,
+// so that pods can be selected by Deployment and CronJob
+func (a *Analyzer) isOwnedBy(ctx context.Context, obj client.Object, owner *v1alpha1.OwnerSelector) (bool, error) {
+	for _, ref := range obj.GetOwnerReferences() {
+		if strings.EqualFold(ref.Kind, owner.Kind) && ref.Name == owner.Name {
+			return true, nil
+		}
+
+		var parent client.Object
+		switch ref.Kind {
+		case replicaSetKind:
+			parent = &appsv1.ReplicaSet{}
+		case jobKind:
+			parent = &batchv1.Job{}
+		default:
+			continue
+		}
+
+		if err := a.ApiServer.Get(ctx, client.ObjectKey{Namespace: obj.GetNamespace(), Name: ref.Name}, parent); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return false, fmt.Errorf("get %s[%s] error: %s", ref.Kind, ref.Name, err.Error())
+		}
+
+		isOwned, err := a.isOwnedBy(ctx, parent, owner)
+		if err != nil {
+			return false, err
+		}
+
+		if isOwned {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// listBySelector list the objects in namespace which match the selector unit, namespace is ignored when empty
+func (a *Analyzer) listBySelector(ctx context.Context, list client.ObjectList, namespace string, unit v1alpha1.SelectorUnit) ([]client.Object, error) {
+	m, err := newUnitMatcher(unit)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []client.ListOption{
+		client.MatchingLabelsSelector{Selector: m.label},
+	}
+	if namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+
+	if err := a.ApiServer.List(ctx, list, opts...); err != nil {
+		return nil, fmt.Errorf("list object error: %s", err.Error())
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, fmt.Errorf("extract list error: %s", err.Error())
+	}
+
+	var result []client.Object
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object type: %T", item)
+		}
+
+		isMatch, err := m.matchStatic(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		if isMatch && m.owner != nil {
+			isMatch, err = a.isOwnedBy(ctx, obj, m.owner)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if isMatch {
+			result = append(result, obj)
+		}
+	}
+
+	return result, nil
+}
+
+func (a *Analyzer) GetPodListBySelector(ctx context.Context, unit v1alpha1.SelectorUnit) ([]*model.PodObject, error) {
+	objList, err := a.listBySelector(ctx, &corev1.PodList{}, unit.Namespace, unit)
+	if err != nil {
+		return nil, fmt.Errorf("list pod info by selector error: %s", err.Error())
+	}
+
+	var result []*model.PodObject
+	for _, obj := range objList {
+		unitPod := obj.(*corev1.Pod)
+		podInfo := &model.PodObject{
+			PodName:   unitPod.Name,
+			PodUID:    string(unitPod.UID),
+			PodIP:     unitPod.Status.PodIP,
+			Namespace: unitPod.Namespace,
+			NodeName:  unitPod.Spec.NodeName,
+			NodeIP:    unitPod.Status.HostIP,
+		}
+
+		if unit.SubName != "" {
+			containers, err := GetTargetContainers(unit.SubName, unitPod.Status.ContainerStatuses)
+			if err != nil {
+				return nil, fmt.Errorf("get target container[%s] in pod[%s] error: %s", unit.SubName, unitPod.Name, err.Error())
+			}
+			podInfo.Containers = containers
+		}
+
+		result = append(result, podInfo)
+	}
+
+	return result, nil
+}
+
+// GetNodeListBySelector filter nodes by InternalIP additionally when ip of selector unit is provided
+func (a *Analyzer) GetNodeListBySelector(ctx context.Context, unit v1alpha1.SelectorUnit, containerName string) ([]*model.NodeObject, error) {
+	objList, err := a.listBySelector(ctx, &corev1.NodeList{}, "", unit)
+	if err != nil {
+		return nil, fmt.Errorf("list node by selector error: %s", err.Error())
+	}
+
+	ipMap := make(map[string]bool)
+	for _, unitIP := range unit.IP {
+		ipMap[unitIP] = true
+	}
+
+	var result []*model.NodeObject
+	for _, obj := range objList {
+		unitNode := obj.(*corev1.Node)
+		nodeInfo := &model.NodeObject{
+			NodeName: unitNode.Name,
+		}
+
+		for _, unitAddress := range unitNode.Status.Addresses {
+			if unitAddress.Type == "InternalIP" {
+				nodeInfo.NodeInternalIP = unitAddress.Address
+			} else if unitAddress.Type == "Hostname" {
+				nodeInfo.HostName = unitAddress.Address
+			}
+		}
+
+		if len(ipMap) != 0 && !ipMap[nodeInfo.NodeInternalIP] {
+			continue
+		}
+
+		if containerName != "" {
+			r, id, err := model.ParseContainerID(containerName)
+			if err != nil {
+				return nil, fmt.Errorf("parse container info error: %s", err.Error())
+			}
+
+			nodeInfo.ContainerRuntime, nodeInfo.ContainerID = r, id
+		}
+
+		result = append(result, nodeInfo)
+	}
+
+	return result, nil
+}
+
+// GetObjectListBySelector supports the namespaced workload targets and namespace
+func (a *Analyzer) GetObjectListBySelector(ctx context.Context, target v1alpha1.CloudTargetType, unit v1alpha1.SelectorUnit) ([]model.AtomicObject, error) {
+	var (
+		list      client.ObjectList
+		namespace = unit.Namespace
+		newObject func(ns, name string) model.AtomicObject
+	)
+
+	switch target {
+	case v1alpha1.DeploymentCloudTarget:
+		list = &appsv1.DeploymentList{}
+		newObject = func(ns, name string) model.AtomicObject {
+			return &model.DeploymentObject{Namespace: ns, DeploymentName: name}
+		}
+	case v1alpha1.StatefulsetCloudTarget:
+		list = &appsv1.StatefulSetList{}
+		newObject = func(ns, name string) model.AtomicObject {
+			return &model.StatefulSetObject{Namespace: ns, StatefulSetName: name}
+		}
+	case v1alpha1.DaemonsetCloudTarget:
+		list = &appsv1.DaemonSetList{}
+		newObject = func(ns, name string) model.AtomicObject {
+			return &model.DaemonSetObject{Namespace: ns, DaemonSetName: name}
+		}
+	case v1alpha1.JobCloudTarget:
+		list = &batchv1.JobList{}
+		newObject = func(ns, name string) model.AtomicObject {
+			return &model.JobObject{Namespace: ns, JobName: name}
+		}
+	case v1alpha1.ServiceCloudTarget:
+		list = &corev1.ServiceList{}
+		newObject = func(ns, name string) model.AtomicObject {
+			return &model.ServiceObject{Namespace: ns, ServiceName: name}
+		}
+	case v1alpha1.ConfigMapCloudTarget:
+		list = &corev1.ConfigMapList{}
+		newObject = func(ns, name string) model.AtomicObject {
+			return &model.ConfigMapObject{Namespace: ns, ConfigMapName: name}
+		}
+	case v1alpha1.SecretCloudTarget:
+		list = &corev1.SecretList{}
+		newObject = func(ns, name string) model.AtomicObject {
+			return &model.SecretObject{Namespace: ns, SecretName: name}
+		}
+	case v1alpha1.NamespaceCloudTarget:
+		list, namespace = &corev1.NamespaceList{}, ""
+		newObject = func(ns, name string) model.AtomicObject {
+			return &model.NamespaceObject{Namespace: name}
+		}
+	default:
+		return nil, fmt.Errorf("target %s not support extended selector", target)
+	}
+
+	objList, err := a.listBySelector(ctx, list, namespace, unit)
+	if err != nil {
+		return nil, fmt.Errorf("list %s by selector error: %s", target, err.Error())
+	}
+
+	var result = make([]model.AtomicObject, len(objList))
+	for i, obj := range objList {
+		result[i] = newObject(obj.GetNamespace(), obj.GetName())
+	}
+
+	return result, nil
+}
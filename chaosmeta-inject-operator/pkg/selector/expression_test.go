@@ -0,0 +1,211 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selector
+
+import (
+	"context"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sort"
+	"testing"
+)
+
+func newTestPod(name, nodeName string, phase corev1.PodPhase, label, annotation map[string]string, owner string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "test",
+			Name:        name,
+			Labels:      label,
+			Annotations: annotation,
+		},
+		Spec:   corev1.PodSpec{NodeName: nodeName},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+
+	if owner != "" {
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: owner}}
+	}
+
+	return pod
+}
+
+func TestAnalyzer_GetPodListBySelector(t *testing.T) {
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "test",
+			Name:            "nginx-5d4f",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "nginx"}},
+		},
+	}
+
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		rs,
+		newTestPod("nginx-1", "node-1", corev1.PodRunning, map[string]string{"app": "nginx", "env": "prod"}, map[string]string{"team": "a"}, "nginx-5d4f"),
+		newTestPod("nginx-2", "node-2", corev1.PodPending, map[string]string{"app": "nginx", "env": "test"}, nil, "nginx-5d4f"),
+		newTestPod("redis-1", "node-1", corev1.PodRunning, map[string]string{"app": "redis"}, map[string]string{"team": "a"}, ""),
+	).Build()
+	a := &Analyzer{ApiServer: cli}
+
+	tests := []struct {
+		name    string
+		unit    v1alpha1.SelectorUnit
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "expression in",
+			unit: v1alpha1.SelectorUnit{
+				Namespace: "test",
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"nginx", "redis"}},
+					{Key: "env", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"test"}},
+				},
+			},
+			want: []string{"nginx-1", "redis-1"},
+		},
+		{
+			name: "expression does not exist and label",
+			unit: v1alpha1.SelectorUnit{
+				Namespace: "test",
+				Label:     map[string]string{"app": "redis"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "env", Operator: metav1.LabelSelectorOpDoesNotExist},
+				},
+			},
+			want: []string{"redis-1"},
+		},
+		{
+			name: "annotation",
+			unit: v1alpha1.SelectorUnit{
+				Namespace:  "test",
+				Annotation: map[string]string{"team": "a"},
+			},
+			want: []string{"nginx-1", "redis-1"},
+		},
+		{
+			name: "field",
+			unit: v1alpha1.SelectorUnit{
+				Namespace: "test",
+				Field:     "spec.nodeName=node-1,status.phase!=Pending",
+			},
+			want: []string{"nginx-1", "redis-1"},
+		},
+		{
+			name: "owner deployment",
+			unit: v1alpha1.SelectorUnit{
+				Namespace: "test",
+				Owner:     &v1alpha1.OwnerSelector{Kind: "deployment", Name: "nginx"},
+			},
+			want: []string{"nginx-1", "nginx-2"},
+		},
+		{
+			name: "owner and field",
+			unit: v1alpha1.SelectorUnit{
+				Namespace: "test",
+				Field:     "status.phase=Pending",
+				Owner:     &v1alpha1.OwnerSelector{Kind: "ReplicaSet", Name: "nginx-5d4f"},
+			},
+			want: []string{"nginx-2"},
+		},
+		{
+			name: "invalid expression",
+			unit: v1alpha1.SelectorUnit{
+				Namespace: "test",
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "app", Operator: metav1.LabelSelectorOpIn},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "owner without name",
+			unit: v1alpha1.SelectorUnit{
+				Namespace: "test",
+				Owner:     &v1alpha1.OwnerSelector{Kind: "Deployment"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !IsExtendedSelector(tt.unit) {
+				t.Errorf("IsExtendedSelector() = false, want true")
+			}
+
+			got, err := a.GetPodListBySelector(context.Background(), tt.unit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPodListBySelector() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			var gotNames []string
+			for _, pod := range got {
+				gotNames = append(gotNames, pod.PodName)
+			}
+			sort.Strings(gotNames)
+			if !reflect.DeepEqual(gotNames, tt.want) {
+				t.Errorf("GetPodListBySelector() got = %v, want %v", gotNames, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_GetObjectListBySelector(t *testing.T) {
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod-a", Labels: map[string]string{"env": "prod"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dev-a"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "prod-a", Name: "nginx", Labels: map[string]string{"tier": "web"}}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "prod-a", Name: "mysql", Labels: map[string]string{"tier": "db"}}},
+	).Build()
+	a := &Analyzer{ApiServer: cli}
+
+	got, err := a.GetObjectListBySelector(context.Background(), v1alpha1.NamespaceCloudTarget, v1alpha1.SelectorUnit{
+		Namespace: "ignored",
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "env", Operator: metav1.LabelSelectorOpExists},
+		},
+	})
+	if err != nil {
+		t.Fatalf("GetObjectListBySelector() error = %v", err)
+	}
+	if len(got) != 1 || got[0].GetObjectName() != "prod-a" {
+		t.Errorf("GetObjectListBySelector() got = %v, want [prod-a]", got)
+	}
+
+	got, err = a.GetObjectListBySelector(context.Background(), v1alpha1.DeploymentCloudTarget, v1alpha1.SelectorUnit{
+		Namespace: "prod-a",
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "tier", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"db"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("GetObjectListBySelector() error = %v", err)
+	}
+	if len(got) != 1 || got[0].GetObjectName() != (&model.DeploymentObject{Namespace: "prod-a", DeploymentName: "nginx"}).GetObjectName() {
+		t.Errorf("GetObjectListBySelector() got = %v, want nginx", got)
+	}
+
+	if _, err = a.GetObjectListBySelector(context.Background(), v1alpha1.ClusterCloudTarget, v1alpha1.SelectorUnit{Field: "a=b"}); err == nil {
+		t.Errorf("GetObjectListBySelector() expect error for cluster target")
+	}
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selector
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetRangeInfo returns the node, topology, readiness and creation time of inject object.
+// Topology is the value of topologyKey label in the node, objects not running in node are always Ready
+func (a *Analyzer) GetRangeInfo(ctx context.Context, obj model.AtomicObject, topologyKey string) (*model.RangeInfo, error) {
+	switch o := obj.(type) {
+	case *model.PodObject:
+		return a.getPodRangeInfo(ctx, o.Namespace, o.PodName, topologyKey)
+	case *model.ContainerObject:
+		return a.getPodRangeInfo(ctx, o.Namespace, o.PodName, topologyKey)
+	case *model.NodeObject:
+		node := &corev1.Node{}
+		if err := a.ApiServer.Get(ctx, client.ObjectKey{Name: o.NodeName}, node); err != nil {
+			return nil, fmt.Errorf("get node error: %s", err.Error())
+		}
+
+		return &model.RangeInfo{
+			NodeName:   node.Name,
+			Topology:   node.Labels[topologyKey],
+			Ready:      isNodeReady(node),
+			CreateTime: node.CreationTimestamp.Time,
+			Labels:     node.Labels,
+		}, nil
+	}
+
+	var (
+		realObj client.Object
+		key     client.ObjectKey
+	)
+	switch o := obj.(type) {
+	case *model.DeploymentObject:
+		realObj, key = &appsv1.Deployment{}, client.ObjectKey{Namespace: o.Namespace, Name: o.DeploymentName}
+	case *model.StatefulSetObject:
+		realObj, key = &appsv1.StatefulSet{}, client.ObjectKey{Namespace: o.Namespace, Name: o.StatefulSetName}
+	case *model.DaemonSetObject:
+		realObj, key = &appsv1.DaemonSet{}, client.ObjectKey{Namespace: o.Namespace, Name: o.DaemonSetName}
+	case *model.JobObject:
+		realObj, key = &batchv1.Job{}, client.ObjectKey{Namespace: o.Namespace, Name: o.JobName}
+	case *model.ServiceObject:
+		realObj, key = &corev1.Service{}, client.ObjectKey{Namespace: o.Namespace, Name: o.ServiceName}
+	case *model.ConfigMapObject:
+		realObj, key = &corev1.ConfigMap{}, client.ObjectKey{Namespace: o.Namespace, Name: o.ConfigMapName}
+	case *model.SecretObject:
+		realObj, key = &corev1.Secret{}, client.ObjectKey{Namespace: o.Namespace, Name: o.SecretName}
+	case *model.NamespaceObject:
+		realObj, key = &corev1.Namespace{}, client.ObjectKey{Name: o.Namespace}
+	default:
+		return nil, fmt.Errorf("not support range info of object: %s", obj.GetObjectName())
+	}
+
+	if err := a.ApiServer.Get(ctx, key, realObj); err != nil {
+		return nil, fmt.Errorf("get %s error: %s", obj.GetObjectName(), err.Error())
+	}
+
+	return &model.RangeInfo{
+		Ready:      true,
+		CreateTime: realObj.GetCreationTimestamp().Time,
+		Labels:     realObj.GetLabels(),
+	}, nil
+}
+
+func (a *Analyzer) getPodRangeInfo(ctx context.Context, namespace, podName, topologyKey string) (*model.RangeInfo, error) {
+	pod := &corev1.Pod{}
+	if err := a.ApiServer.Get(ctx, client.ObjectKey{Namespace: namespace, Name: podName}, pod); err != nil {
+		return nil, fmt.Errorf("get pod error: %s", err.Error())
+	}
+
+	info := &model.RangeInfo{
+		NodeName:   pod.Spec.NodeName,
+		Ready:      isPodReady(pod),
+		CreateTime: pod.CreationTimestamp.Time,
+		Labels:     pod.Labels,
+	}
+
+	var err error
+	info.Owner, info.OwnerReplicas, err = a.getPodOwner(ctx, pod)
+	if err != nil {
+		return nil, fmt.Errorf("get owner of pod[%s] error: %s", podName, err.Error())
+	}
+
+	if info.NodeName != "" {
+		node := &corev1.Node{}
+		if err := a.ApiServer.Get(ctx, client.ObjectKey{Name: info.NodeName}, node); err != nil {
+			return nil, fmt.Errorf("get node of pod[%s] error: %s", podName, err.Error())
+		}
+		info.Topology = node.Labels[topologyKey]
+	}
+
+	return info, nil
+}
+
+// getPodOwner return the top workload of pod through ReplicaSet, and the desired replicas of the workload
+func (a *Analyzer) getPodOwner(ctx context.Context, pod *corev1.Pod) (string, int, error) {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return "", 0, nil
+	}
+
+	var (
+		ownerKind, ownerName = ref.Kind, ref.Name
+		replicas             int
+		key                  = client.ObjectKey{Namespace: pod.Namespace, Name: ref.Name}
+		err                  error
+	)
+
+	switch ref.Kind {
+	case replicaSetKind:
+		rs := &appsv1.ReplicaSet{}
+		if err = a.ApiServer.Get(ctx, key, rs); err != nil {
+			break
+		}
+
+		replicas = getReplicas(rs.Spec.Replicas)
+		if rsRef := metav1.GetControllerOf(rs); rsRef != nil && rsRef.Kind == "Deployment" {
+			deploy := &appsv1.Deployment{}
+			ownerKind, ownerName = rsRef.Kind, rsRef.Name
+			if err = a.ApiServer.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: rsRef.Name}, deploy); err == nil {
+				replicas = getReplicas(deploy.Spec.Replicas)
+			}
+		}
+	case "StatefulSet":
+		sts := &appsv1.StatefulSet{}
+		if err = a.ApiServer.Get(ctx, key, sts); err == nil {
+			replicas = getReplicas(sts.Spec.Replicas)
+		}
+	case "DaemonSet":
+		ds := &appsv1.DaemonSet{}
+		if err = a.ApiServer.Get(ctx, key, ds); err == nil {
+			replicas = int(ds.Status.DesiredNumberScheduled)
+		}
+	case jobKind:
+		job := &batchv1.Job{}
+		if err = a.ApiServer.Get(ctx, key, job); err == nil {
+			replicas = getReplicas(job.Spec.Parallelism)
+		}
+	}
+
+	if err != nil && !errors.IsNotFound(err) {
+		return "", 0, err
+	}
+
+	return fmt.Sprintf("%s%s%s", ownerKind, model.ObjectNameSplit, ownerName), replicas, nil
+}
+
+// getReplicas return 1 when replicas is not set, which is the default of workloads
+func getReplicas(replicas *int32) int {
+	if replicas == nil {
+		return 1
+	}
+
+	return int(*replicas)
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
@@ -22,7 +22,10 @@ import (
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"regexp"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strings"
@@ -50,6 +53,7 @@ func GetAnalyzer() IAnalyzer {
 
 type IAnalyzer interface {
 	GetExperimentListByPhase(ctx context.Context, phase string) (*v1alpha1.ExperimentList, error)
+	GetChaosPolicyList(ctx context.Context) (*v1alpha1.ChaosPolicyList, error)
 	GetContainer(ctx context.Context, ns, podName, containerName string) (*model.ContainerObject, error)
 
 	GetPod(ctx context.Context, ns, podName, containerName string) (*model.PodObject, error)
@@ -63,6 +67,33 @@ type IAnalyzer interface {
 
 	GetDeploymentListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.DeploymentObject, error)
 	GetDeploymentListByName(ctx context.Context, namespace string, name []string) ([]*model.DeploymentObject, error)
+
+	GetStatefulSetListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.StatefulSetObject, error)
+	GetStatefulSetListByName(ctx context.Context, namespace string, name []string) ([]*model.StatefulSetObject, error)
+
+	GetDaemonSetListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.DaemonSetObject, error)
+	GetDaemonSetListByName(ctx context.Context, namespace string, name []string) ([]*model.DaemonSetObject, error)
+
+	GetJobListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.JobObject, error)
+	GetJobListByName(ctx context.Context, namespace string, name []string) ([]*model.JobObject, error)
+
+	GetServiceListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.ServiceObject, error)
+	GetServiceListByName(ctx context.Context, namespace string, name []string) ([]*model.ServiceObject, error)
+
+	GetConfigMapListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.ConfigMapObject, error)
+	GetConfigMapListByName(ctx context.Context, namespace string, name []string) ([]*model.ConfigMapObject, error)
+
+	GetSecretListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.SecretObject, error)
+	GetSecretListByName(ctx context.Context, namespace string, name []string) ([]*model.SecretObject, error)
+
+	GetNamespaceListByLabel(ctx context.Context, label map[string]string) ([]*model.NamespaceObject, error)
+	GetNamespaceListByName(ctx context.Context, name []string) ([]*model.NamespaceObject, error)
+
+	GetPodListBySelector(ctx context.Context, unit v1alpha1.SelectorUnit) ([]*model.PodObject, error)
+	GetNodeListBySelector(ctx context.Context, unit v1alpha1.SelectorUnit, containerName string) ([]*model.NodeObject, error)
+	GetObjectListBySelector(ctx context.Context, target v1alpha1.CloudTargetType, unit v1alpha1.SelectorUnit) ([]model.AtomicObject, error)
+
+	GetRangeInfo(ctx context.Context, obj model.AtomicObject, topologyKey string) (*model.RangeInfo, error)
 }
 
 type Analyzer struct {
@@ -87,6 +118,20 @@ func (a *Analyzer) GetExperimentListByPhase(ctx context.Context, phase string) (
 	return expList, nil
 }
 
+func (a *Analyzer) GetChaosPolicyList(ctx context.Context) (*v1alpha1.ChaosPolicyList, error) {
+	policyList := &v1alpha1.ChaosPolicyList{}
+	if err := a.ApiServer.List(ctx, policyList); err != nil {
+		// the ChaosPolicy CRD is optional, there is no policy if it is not installed
+		if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+			return policyList, nil
+		}
+
+		return nil, fmt.Errorf("list chaos policy error: %s", err.Error())
+	}
+
+	return policyList, nil
+}
+
 func (a *Analyzer) GetPodListByLabelInNode(ctx context.Context, namespace string, label map[string]string, nodeIP string) ([]*model.PodObject, error) {
 	opts := []client.ListOption{
 		client.InNamespace(namespace),
@@ -527,3 +572,361 @@ func (a *Analyzer) GetDeploymentListByName(ctx context.Context, namespace string
 
 	return result, nil
 }
+
+func (a *Analyzer) GetStatefulSetListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.StatefulSetObject, error) {
+	opts := []client.ListOption{
+		client.InNamespace(namespace),
+		client.MatchingLabels(label),
+	}
+
+	stsList := &appsv1.StatefulSetList{}
+	if err := a.ApiServer.List(ctx, stsList, opts...); err != nil {
+		return nil, fmt.Errorf("list statefulset info error: %s", err.Error())
+	}
+
+	var result = make([]*model.StatefulSetObject, len(stsList.Items))
+	for i, unitStatefulSet := range stsList.Items {
+		result[i] = &model.StatefulSetObject{
+			StatefulSetName: unitStatefulSet.Name,
+			Namespace:       unitStatefulSet.Namespace,
+		}
+	}
+
+	return result, nil
+}
+
+func (a *Analyzer) GetStatefulSetListByName(ctx context.Context, namespace string, name []string) ([]*model.StatefulSetObject, error) {
+	opts := []client.ListOption{
+		client.InNamespace(namespace),
+	}
+
+	stsList := &appsv1.StatefulSetList{}
+	if err := a.ApiServer.List(ctx, stsList, opts...); err != nil {
+		return nil, fmt.Errorf("list statefulset info error: %s", err.Error())
+	}
+
+	stsNameMap := make(map[string]bool)
+	for _, unitN := range name {
+		stsNameMap[unitN] = true
+	}
+
+	var result []*model.StatefulSetObject
+	for _, unitStatefulSet := range stsList.Items {
+		if !stsNameMap[unitStatefulSet.Name] {
+			continue
+		}
+
+		result = append(result, &model.StatefulSetObject{
+			StatefulSetName: unitStatefulSet.Name,
+			Namespace:       unitStatefulSet.Namespace,
+		})
+	}
+
+	return result, nil
+}
+
+func (a *Analyzer) GetDaemonSetListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.DaemonSetObject, error) {
+	opts := []client.ListOption{
+		client.InNamespace(namespace),
+		client.MatchingLabels(label),
+	}
+
+	dsList := &appsv1.DaemonSetList{}
+	if err := a.ApiServer.List(ctx, dsList, opts...); err != nil {
+		return nil, fmt.Errorf("list daemonset info error: %s", err.Error())
+	}
+
+	var result = make([]*model.DaemonSetObject, len(dsList.Items))
+	for i, unitDaemonSet := range dsList.Items {
+		result[i] = &model.DaemonSetObject{
+			DaemonSetName: unitDaemonSet.Name,
+			Namespace:     unitDaemonSet.Namespace,
+		}
+	}
+
+	return result, nil
+}
+
+func (a *Analyzer) GetDaemonSetListByName(ctx context.Context, namespace string, name []string) ([]*model.DaemonSetObject, error) {
+	opts := []client.ListOption{
+		client.InNamespace(namespace),
+	}
+
+	dsList := &appsv1.DaemonSetList{}
+	if err := a.ApiServer.List(ctx, dsList, opts...); err != nil {
+		return nil, fmt.Errorf("list daemonset info error: %s", err.Error())
+	}
+
+	dsNameMap := make(map[string]bool)
+	for _, unitN := range name {
+		dsNameMap[unitN] = true
+	}
+
+	var result []*model.DaemonSetObject
+	for _, unitDaemonSet := range dsList.Items {
+		if !dsNameMap[unitDaemonSet.Name] {
+			continue
+		}
+
+		result = append(result, &model.DaemonSetObject{
+			DaemonSetName: unitDaemonSet.Name,
+			Namespace:     unitDaemonSet.Namespace,
+		})
+	}
+
+	return result, nil
+}
+
+func (a *Analyzer) GetJobListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.JobObject, error) {
+	opts := []client.ListOption{
+		client.InNamespace(namespace),
+		client.MatchingLabels(label),
+	}
+
+	jobList := &batchv1.JobList{}
+	if err := a.ApiServer.List(ctx, jobList, opts...); err != nil {
+		return nil, fmt.Errorf("list job info error: %s", err.Error())
+	}
+
+	var result = make([]*model.JobObject, len(jobList.Items))
+	for i, unitJob := range jobList.Items {
+		result[i] = &model.JobObject{
+			JobName:   unitJob.Name,
+			Namespace: unitJob.Namespace,
+		}
+	}
+
+	return result, nil
+}
+
+func (a *Analyzer) GetJobListByName(ctx context.Context, namespace string, name []string) ([]*model.JobObject, error) {
+	opts := []client.ListOption{
+		client.InNamespace(namespace),
+	}
+
+	jobList := &batchv1.JobList{}
+	if err := a.ApiServer.List(ctx, jobList, opts...); err != nil {
+		return nil, fmt.Errorf("list job info error: %s", err.Error())
+	}
+
+	jobNameMap := make(map[string]bool)
+	for _, unitN := range name {
+		jobNameMap[unitN] = true
+	}
+
+	var result []*model.JobObject
+	for _, unitJob := range jobList.Items {
+		if !jobNameMap[unitJob.Name] {
+			continue
+		}
+
+		result = append(result, &model.JobObject{
+			JobName:   unitJob.Name,
+			Namespace: unitJob.Namespace,
+		})
+	}
+
+	return result, nil
+}
+
+func (a *Analyzer) GetServiceListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.ServiceObject, error) {
+	opts := []client.ListOption{
+		client.InNamespace(namespace),
+		client.MatchingLabels(label),
+	}
+
+	svcList := &corev1.ServiceList{}
+	if err := a.ApiServer.List(ctx, svcList, opts...); err != nil {
+		return nil, fmt.Errorf("list service info error: %s", err.Error())
+	}
+
+	var result = make([]*model.ServiceObject, len(svcList.Items))
+	for i, unitService := range svcList.Items {
+		result[i] = &model.ServiceObject{
+			ServiceName: unitService.Name,
+			Namespace:   unitService.Namespace,
+		}
+	}
+
+	return result, nil
+}
+
+func (a *Analyzer) GetServiceListByName(ctx context.Context, namespace string, name []string) ([]*model.ServiceObject, error) {
+	opts := []client.ListOption{
+		client.InNamespace(namespace),
+	}
+
+	svcList := &corev1.ServiceList{}
+	if err := a.ApiServer.List(ctx, svcList, opts...); err != nil {
+		return nil, fmt.Errorf("list service info error: %s", err.Error())
+	}
+
+	svcNameMap := make(map[string]bool)
+	for _, unitN := range name {
+		svcNameMap[unitN] = true
+	}
+
+	var result []*model.ServiceObject
+	for _, unitService := range svcList.Items {
+		if !svcNameMap[unitService.Name] {
+			continue
+		}
+
+		result = append(result, &model.ServiceObject{
+			ServiceName: unitService.Name,
+			Namespace:   unitService.Namespace,
+		})
+	}
+
+	return result, nil
+}
+
+func (a *Analyzer) GetConfigMapListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.ConfigMapObject, error) {
+	opts := []client.ListOption{
+		client.InNamespace(namespace),
+		client.MatchingLabels(label),
+	}
+
+	cmList := &corev1.ConfigMapList{}
+	if err := a.ApiServer.List(ctx, cmList, opts...); err != nil {
+		return nil, fmt.Errorf("list configmap info error: %s", err.Error())
+	}
+
+	var result = make([]*model.ConfigMapObject, len(cmList.Items))
+	for i, unitConfigMap := range cmList.Items {
+		result[i] = &model.ConfigMapObject{
+			ConfigMapName: unitConfigMap.Name,
+			Namespace:     unitConfigMap.Namespace,
+		}
+	}
+
+	return result, nil
+}
+
+func (a *Analyzer) GetConfigMapListByName(ctx context.Context, namespace string, name []string) ([]*model.ConfigMapObject, error) {
+	opts := []client.ListOption{
+		client.InNamespace(namespace),
+	}
+
+	cmList := &corev1.ConfigMapList{}
+	if err := a.ApiServer.List(ctx, cmList, opts...); err != nil {
+		return nil, fmt.Errorf("list configmap info error: %s", err.Error())
+	}
+
+	cmNameMap := make(map[string]bool)
+	for _, unitN := range name {
+		cmNameMap[unitN] = true
+	}
+
+	var result []*model.ConfigMapObject
+	for _, unitConfigMap := range cmList.Items {
+		if !cmNameMap[unitConfigMap.Name] {
+			continue
+		}
+
+		result = append(result, &model.ConfigMapObject{
+			ConfigMapName: unitConfigMap.Name,
+			Namespace:     unitConfigMap.Namespace,
+		})
+	}
+
+	return result, nil
+}
+
+func (a *Analyzer) GetSecretListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.SecretObject, error) {
+	opts := []client.ListOption{
+		client.InNamespace(namespace),
+		client.MatchingLabels(label),
+	}
+
+	secretList := &corev1.SecretList{}
+	if err := a.ApiServer.List(ctx, secretList, opts...); err != nil {
+		return nil, fmt.Errorf("list secret info error: %s", err.Error())
+	}
+
+	var result = make([]*model.SecretObject, len(secretList.Items))
+	for i, unitSecret := range secretList.Items {
+		result[i] = &model.SecretObject{
+			SecretName: unitSecret.Name,
+			Namespace:  unitSecret.Namespace,
+		}
+	}
+
+	return result, nil
+}
+
+func (a *Analyzer) GetSecretListByName(ctx context.Context, namespace string, name []string) ([]*model.SecretObject, error) {
+	opts := []client.ListOption{
+		client.InNamespace(namespace),
+	}
+
+	secretList := &corev1.SecretList{}
+	if err := a.ApiServer.List(ctx, secretList, opts...); err != nil {
+		return nil, fmt.Errorf("list secret info error: %s", err.Error())
+	}
+
+	secretNameMap := make(map[string]bool)
+	for _, unitN := range name {
+		secretNameMap[unitN] = true
+	}
+
+	var result []*model.SecretObject
+	for _, unitSecret := range secretList.Items {
+		if !secretNameMap[unitSecret.Name] {
+			continue
+		}
+
+		result = append(result, &model.SecretObject{
+			SecretName: unitSecret.Name,
+			Namespace:  unitSecret.Namespace,
+		})
+	}
+
+	return result, nil
+}
+
+// GetNamespaceListByLabel return all namespace when label is empty map or nil
+func (a *Analyzer) GetNamespaceListByLabel(ctx context.Context, label map[string]string) ([]*model.NamespaceObject, error) {
+	opts := []client.ListOption{
+		client.MatchingLabels(label),
+	}
+
+	nsList := &corev1.NamespaceList{}
+	if err := a.ApiServer.List(ctx, nsList, opts...); err != nil {
+		return nil, fmt.Errorf("list namespace info error: %s", err.Error())
+	}
+
+	var result = make([]*model.NamespaceObject, len(nsList.Items))
+	for i, unitNs := range nsList.Items {
+		result[i] = &model.NamespaceObject{
+			Namespace: unitNs.Name,
+		}
+	}
+
+	return result, nil
+}
+
+func (a *Analyzer) GetNamespaceListByName(ctx context.Context, name []string) ([]*model.NamespaceObject, error) {
+	nsList := &corev1.NamespaceList{}
+	if err := a.ApiServer.List(ctx, nsList, []client.ListOption{}...); err != nil {
+		return nil, fmt.Errorf("list namespace info error: %s", err.Error())
+	}
+
+	nsNameMap := make(map[string]bool)
+	for _, unitN := range name {
+		nsNameMap[unitN] = true
+	}
+
+	var result []*model.NamespaceObject
+	for _, unitNs := range nsList.Items {
+		if !nsNameMap[unitNs.Name] {
+			continue
+		}
+
+		result = append(result, &model.NamespaceObject{
+			Namespace: unitNs.Name,
+		})
+	}
+
+	return result, nil
+}
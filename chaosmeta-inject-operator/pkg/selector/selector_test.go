@@ -17,8 +17,13 @@
 package selector
 
 import (
+	"context"
+	"errors"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"testing"
 )
 
@@ -115,3 +120,36 @@ func TestGetTargetContainer(t *testing.T) {
 		})
 	}
 }
+
+// listErrClient returns err for all list requests
+type listErrClient struct {
+	client.Client
+	err error
+}
+
+func (c *listErrClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return c.err
+}
+
+func TestAnalyzer_GetChaosPolicyList(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr bool
+	}{
+		{name: "crd not installed", err: &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "chaosmeta.io", Kind: "ChaosPolicy"}}},
+		{name: "other error", err: errors.New("connection refused"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Analyzer{ApiServer: &listErrClient{err: tt.err}}
+			got, err := a.GetChaosPolicyList(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetChaosPolicyList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(got.Items) != 0 {
+				t.Errorf("GetChaosPolicyList() = %v, want no policy", got.Items)
+			}
+		})
+	}
+}
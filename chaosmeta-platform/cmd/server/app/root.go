@@ -18,6 +18,7 @@ package app
 
 import (
 	"chaosmeta-platform/config"
+	"chaosmeta-platform/pkg/service/agent"
 	"chaosmeta-platform/pkg/service/experiment"
 	"chaosmeta-platform/pkg/service/inject"
 	"chaosmeta-platform/pkg/service/namespace"
@@ -67,6 +68,7 @@ func initConfig() {
 		log.Panic(err)
 	}
 	experiment.Init()
+	agent.Init()
 	//if err := clientset.Init(); err != nil {
 	//	log.Panic(err)
 	//}
@@ -61,6 +61,8 @@ type Config struct {
 		Level string `yaml:"level"`
 	} `yaml:"log"`
 	RunMode RunMode `yaml:"runmode"`
+	// AgentToken authenticates the requests from chaosmetad agents, the agent apis are rejected if it is empty
+	AgentToken string `yaml:"agentToken"`
 }
 
 func InitConfigWithFilePath(filePath string) error {
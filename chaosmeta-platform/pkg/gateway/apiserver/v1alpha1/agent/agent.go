@@ -0,0 +1,207 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent
+
+import (
+	"chaosmeta-platform/pkg/gateway/apiserver/v1alpha1"
+	"chaosmeta-platform/pkg/service/agent"
+	"chaosmeta-platform/pkg/service/user"
+	"context"
+	"encoding/json"
+	"errors"
+	beego "github.com/beego/beego/v2/server/web"
+)
+
+type AgentController struct {
+	v1alpha1.BeegoOutputController
+	beego.Controller
+}
+
+func (c *AgentController) Register() {
+	var requestBody AgentInfoRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &requestBody); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	info, err := toAgentInfo(requestBody)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	if info.IP == "" {
+		info.IP = c.Ctx.Input.IP()
+	}
+
+	agentService := &agent.AgentService{}
+	id, err := agentService.Register(context.Background(), info)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, RegisterAgentResponse{ID: id})
+}
+
+func (c *AgentController) Heartbeat() {
+	id, err := c.GetInt(":id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	var requestBody AgentInfoRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &requestBody); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	info, err := toAgentInfo(requestBody)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	agentService := &agent.AgentService{}
+	if err := agentService.Heartbeat(context.Background(), id, info); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.SuccessNoData(&c.Controller)
+}
+
+func (c *AgentController) PullTasks() {
+	id, err := c.GetInt(":id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	limit, _ := c.GetInt("limit", 10)
+
+	agentService := &agent.AgentService{}
+	tasks, err := agentService.PullTasks(context.Background(), id, limit)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	res := PullAgentTasksResponse{Tasks: make([]AgentTaskData, len(tasks))}
+	for i, task := range tasks {
+		res.Tasks[i] = AgentTaskData{
+			ID:      task.ID,
+			Type:    task.Type,
+			Uid:     task.Uid,
+			Content: task.Content,
+		}
+	}
+	c.Success(&c.Controller, res)
+}
+
+// CreateTask adds an inject or recover task for the agent in pull mode, only admin is allowed
+func (c *AgentController) CreateTask() {
+	userName := c.Ctx.Input.GetData("userName").(string)
+	userService := &user.UserService{}
+	if !userService.IsAdmin(context.Background(), userName) {
+		c.Error(&c.Controller, errors.New("not admin"))
+		return
+	}
+
+	id, err := c.GetInt(":id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	var requestBody CreateAgentTaskRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &requestBody); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	var content string
+	if requestBody.Content != nil {
+		contentBytes, err := json.Marshal(requestBody.Content)
+		if err != nil {
+			c.Error(&c.Controller, err)
+			return
+		}
+		content = string(contentBytes)
+	}
+
+	agentService := &agent.AgentService{}
+	taskId, err := agentService.CreateTask(context.Background(), id, requestBody.Type, requestBody.Uid, content)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, CreateAgentTaskResponse{ID: taskId})
+}
+
+func (c *AgentController) ReportTaskResult() {
+	id, err := c.GetInt(":id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	taskId, err := c.GetInt(":task_id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	var requestBody ReportAgentTaskRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &requestBody); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	agentService := &agent.AgentService{}
+	if err := agentService.ReportTaskResult(context.Background(), id, taskId, requestBody.Success, requestBody.Uid, requestBody.Message); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.SuccessNoData(&c.Controller)
+}
+
+func toAgentInfo(req AgentInfoRequest) (agent.AgentInfo, error) {
+	info := agent.AgentInfo{
+		Hostname:          req.Hostname,
+		IP:                req.IP,
+		Port:              req.Port,
+		Version:           req.Version,
+		Mode:              req.Mode,
+		ContainerRuntimes: req.ContainerRuntimes,
+	}
+
+	if req.Faults != nil {
+		faults, err := json.Marshal(req.Faults)
+		if err != nil {
+			return info, err
+		}
+		info.Faults = string(faults)
+	}
+
+	if req.Experiments != nil {
+		experiments, err := json.Marshal(req.Experiments)
+		if err != nil {
+			return info, err
+		}
+		info.Experiments = string(experiments)
+	}
+
+	return info, nil
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent
+
+type AgentInfoRequest struct {
+	Hostname          string   `json:"hostname"`
+	IP                string   `json:"ip"`
+	Port              int      `json:"port"`
+	Version           string   `json:"version"`
+	Mode              string   `json:"mode"`
+	ContainerRuntimes []string `json:"containerRuntimes"`
+	// Faults is the fault catalog of agent, format: {"target": ["fault"]}
+	Faults map[string][]string `json:"faults"`
+	// Experiments is the summary of the experiments on agent
+	Experiments interface{} `json:"experiments"`
+}
+
+type RegisterAgentResponse struct {
+	ID int `json:"id"`
+}
+
+type AgentTaskData struct {
+	ID      int    `json:"id"`
+	Type    string `json:"type"`
+	Uid     string `json:"uid"`
+	Content string `json:"content"`
+}
+
+type PullAgentTasksResponse struct {
+	Tasks []AgentTaskData `json:"tasks"`
+}
+
+type CreateAgentTaskRequest struct {
+	Type string `json:"type"`
+	Uid  string `json:"uid"`
+	// Content is the inject args of chaosmetad for inject task
+	Content interface{} `json:"content"`
+}
+
+type CreateAgentTaskResponse struct {
+	ID int64 `json:"id"`
+}
+
+type ReportAgentTaskRequest struct {
+	Success bool   `json:"success"`
+	Uid     string `json:"uid"`
+	Message string `json:"message"`
+}
@@ -20,24 +20,42 @@ import (
 	models "chaosmeta-platform/pkg/models/common"
 	"context"
 	"errors"
+	"github.com/beego/beego/v2/client/orm"
+	"time"
 )
 
 type Agent struct {
-	ID               int    `json:"id" orm:"pk;auto;column(id)"`
-	AgentType        string `json:"agentType" orm:"column(agent_type);size(32);index"`
-	ClusterID        int    `json:"clusterId" orm:"column(cluster_id);index"`
-	ContainerRuntime string `json:"containerRuntime" orm:"column(container_runtime);size(32);default(docker)"`
-	Version          string `json:"version" orm:"column(version);size(32);index"`
-	NodeName         string `json:"nodeName" orm:"column(node_name);size(255);"`
-	Hostname         string `json:"hostname" orm:"column(host_name);size(255);index"`
-	IP               string `json:"ip" orm:"column(ip);size(32);index"`
-	Status           string `json:"status"  orm:"column(status);size(32);index"`
-	AppID            int    `json:"appId" orm:"column(app_id);index"`
-	SelectLabel      string `json:"selectLabel" orm:"column(select_label);size(1024);"`
-	SelectNamespace  string `json:"selectNamespace" orm:"column(select_namespace);size(255);"`
+	ID               int       `json:"id" orm:"pk;auto;column(id)"`
+	AgentType        string    `json:"agentType" orm:"column(agent_type);size(32);index"`
+	ClusterID        int       `json:"clusterId" orm:"column(cluster_id);index"`
+	ContainerRuntime string    `json:"containerRuntime" orm:"column(container_runtime);size(32);default(docker)"`
+	Version          string    `json:"version" orm:"column(version);size(32);index"`
+	NodeName         string    `json:"nodeName" orm:"column(node_name);size(255);"`
+	Hostname         string    `json:"hostname" orm:"column(host_name);size(255);index"`
+	IP               string    `json:"ip" orm:"column(ip);size(32);index"`
+	Status           string    `json:"status"  orm:"column(status);size(32);index"`
+	AppID            int       `json:"appId" orm:"column(app_id);index"`
+	SelectLabel      string    `json:"selectLabel" orm:"column(select_label);size(1024);"`
+	SelectNamespace  string    `json:"selectNamespace" orm:"column(select_namespace);size(255);"`
+	Port             int       `json:"port" orm:"column(port)"`
+	Mode             string    `json:"mode" orm:"column(mode);size(32);default(push)"`
+	Faults           string    `json:"faults" orm:"column(faults);type(text);null"`
+	Experiments      string    `json:"experiments" orm:"column(experiments);type(text);null"`
+	HeartbeatTime    time.Time `json:"heartbeatTime" orm:"column(heartbeat_time);type(datetime);null"`
 	models.BaseTimeModel
 }
 
+const (
+	AgentTypeChaosmetad = "chaosmetad"
+
+	AgentStatusOnline  = "online"
+	AgentStatusOffline = "offline"
+
+	// AgentModePush means the platform calls the http service of agent, AgentModePull means the agent pulls tasks from the platform
+	AgentModePush = "push"
+	AgentModePull = "pull"
+)
+
 func (a *Agent) TableName() string {
 	return "agent"
 }
@@ -127,3 +145,28 @@ func QueryAgents(ctx context.Context, hostname, ip, selectLabel, version, status
 	_, err = agentQuery.GetOamQuerySeter().All(agents)
 	return totalCount, *agents, err
 }
+
+// UpdateOfflineAgents marks the online agents without heartbeat since the deadline as offline
+func UpdateOfflineAgents(ctx context.Context, deadline time.Time) (int64, error) {
+	a := Agent{}
+	return models.GetORM().QueryTable(a.TableName()).
+		Filter("status", AgentStatusOnline).
+		Filter("heartbeat_time__lt", deadline).
+		Update(orm.Params{"status": AgentStatusOffline})
+}
+
+// ListAgentIDsByStatus returns the ids of the agents in the status
+func ListAgentIDsByStatus(ctx context.Context, status string) ([]int, error) {
+	a, agents := Agent{}, new([]Agent)
+	_, err := models.GetORM().QueryTable(a.TableName()).
+		Filter("status", status).
+		All(agents, "id")
+	if err != nil && !errors.Is(err, orm.ErrNoRows) {
+		return nil, err
+	}
+	ids := make([]int, 0, len(*agents))
+	for _, agentGet := range *agents {
+		ids = append(ids, agentGet.ID)
+	}
+	return ids, nil
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent
+
+import (
+	models "chaosmeta-platform/pkg/models/common"
+	"context"
+	"errors"
+	"github.com/beego/beego/v2/client/orm"
+	"time"
+)
+
+// AgentTask is an inject or recover task for the agent in pull mode
+type AgentTask struct {
+	ID      int    `json:"id" orm:"pk;auto;column(id)"`
+	AgentID int    `json:"agentId" orm:"column(agent_id);index"`
+	Type    string `json:"type" orm:"column(type);size(32)"`
+	Uid     string `json:"uid" orm:"column(uid);size(64);index"`
+	Content string `json:"content" orm:"column(content);type(text);null"`
+	Status  string `json:"status" orm:"column(status);size(32);index"`
+	Message string `json:"message" orm:"column(message);type(text);null"`
+	models.BaseTimeModel
+}
+
+const (
+	AgentTaskTypeInject  = "inject"
+	AgentTaskTypeRecover = "recover"
+
+	AgentTaskStatusPending    = "pending"
+	AgentTaskStatusDispatched = "dispatched"
+	AgentTaskStatusSuccess    = "success"
+	AgentTaskStatusFailed     = "failed"
+)
+
+func (a *AgentTask) TableName() string {
+	return "agent_task"
+}
+
+func InsertAgentTask(ctx context.Context, task *AgentTask) (int64, error) {
+	if task == nil {
+		return 0, errors.New("agent task is nil")
+	}
+	return models.GetORM().Insert(task)
+}
+
+func UpdateAgentTask(ctx context.Context, task *AgentTask, cols ...string) (int64, error) {
+	if task == nil {
+		return 0, errors.New("agent task is nil")
+	}
+	return models.GetORM().Update(task, cols...)
+}
+
+func GetAgentTaskById(ctx context.Context, task *AgentTask) error {
+	if task == nil {
+		return errors.New("agent task is nil")
+	}
+	return models.GetORM().Read(task)
+}
+
+func ListPendingAgentTasks(ctx context.Context, agentID, limit int) ([]AgentTask, error) {
+	a, tasks := AgentTask{}, new([]AgentTask)
+	_, err := models.GetORM().QueryTable(a.TableName()).
+		Filter("agent_id", agentID).
+		Filter("status", AgentTaskStatusPending).
+		OrderBy("id").
+		Limit(limit).
+		All(tasks)
+	if err != nil && !errors.Is(err, orm.ErrNoRows) {
+		return nil, err
+	}
+	return *tasks, nil
+}
+
+// FailExpiredAgentTasks marks the dispatched tasks failed which have no result since the deadline
+func FailExpiredAgentTasks(ctx context.Context, deadline time.Time, message string) (int64, error) {
+	a := AgentTask{}
+	return models.GetORM().QueryTable(a.TableName()).
+		Filter("status", AgentTaskStatusDispatched).
+		Filter("update_time__lt", deadline).
+		Update(orm.Params{"status": AgentTaskStatusFailed, "message": message, "update_time": time.Now()})
+}
+
+// FailAgentsDispatchedTasks marks the dispatched tasks of the agents failed
+func FailAgentsDispatchedTasks(ctx context.Context, agentIDs []int, message string) (int64, error) {
+	if len(agentIDs) == 0 {
+		return 0, nil
+	}
+	a := AgentTask{}
+	return models.GetORM().QueryTable(a.TableName()).
+		Filter("status", AgentTaskStatusDispatched).
+		Filter("agent_id__in", agentIDs).
+		Update(orm.Params{"status": AgentTaskStatusFailed, "message": message, "update_time": time.Now()})
+}
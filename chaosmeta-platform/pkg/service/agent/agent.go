@@ -0,0 +1,239 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent
+
+import (
+	"chaosmeta-platform/pkg/models/agent"
+	"chaosmeta-platform/util/log"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/robfig/cron"
+	"strings"
+	"time"
+)
+
+const (
+	// an agent is offline when there is no heartbeat during offlineTimeout
+	offlineTimeout = 3 * time.Minute
+	// a dispatched task fails when the agent reports no result during dispatchTimeout
+	dispatchTimeout = 10 * time.Minute
+	maxPullLimit    = 100
+)
+
+func Init() {
+	go startOfflineRoutine(context.Background())
+}
+
+func startOfflineRoutine(ctx context.Context) {
+	localCron := cron.New()
+	if err := localCron.AddFunc("@every 30s", func() {
+		num, err := agent.UpdateOfflineAgents(ctx, time.Now().Add(-offlineTimeout))
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		if num > 0 {
+			log.Infof("%d agents become offline", num)
+		}
+
+		failStaleTasks(ctx)
+	}); err != nil {
+		log.Error(err)
+		return
+	}
+
+	localCron.Start()
+}
+
+// failStaleTasks fails the dispatched tasks of the offline agents and the ones without result after dispatchTimeout.
+// They are not re-queued, because an agent may have executed the task before it died
+func failStaleTasks(ctx context.Context) {
+	offlineIDs, err := agent.ListAgentIDsByStatus(ctx, agent.AgentStatusOffline)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	num, err := agent.FailAgentsDispatchedTasks(ctx, offlineIDs, "agent is offline before reporting the result")
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if num > 0 {
+		log.Infof("%d dispatched tasks of offline agents failed", num)
+	}
+
+	num, err = agent.FailExpiredAgentTasks(ctx, time.Now().Add(-dispatchTimeout), fmt.Sprintf("no result is reported in %s after dispatched", dispatchTimeout))
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if num > 0 {
+		log.Infof("%d dispatched tasks expired", num)
+	}
+}
+
+type AgentService struct{}
+
+type AgentInfo struct {
+	Hostname          string
+	IP                string
+	Port              int
+	Version           string
+	Mode              string
+	ContainerRuntimes []string
+	Faults            string
+	Experiments       string
+}
+
+// Register creates the agent by hostname or updates the existed one, and returns the id of agent
+func (a *AgentService) Register(ctx context.Context, info AgentInfo) (int, error) {
+	if info.Hostname == "" {
+		return 0, errors.New("hostname is empty")
+	}
+
+	if info.Mode != agent.AgentModePush && info.Mode != agent.AgentModePull {
+		return 0, fmt.Errorf("mode[%s] is not supported", info.Mode)
+	}
+
+	agentGet := agent.Agent{Hostname: info.Hostname}
+	isExisted := agent.GetAgentByHostname(ctx, &agentGet) == nil
+	setAgentInfo(&agentGet, info)
+	agentGet.AgentType = agent.AgentTypeChaosmetad
+
+	if isExisted {
+		_, err := agent.UpdateAgent(ctx, &agentGet)
+		return agentGet.ID, err
+	}
+
+	id, err := agent.InsertAgent(ctx, &agentGet)
+	return int(id), err
+}
+
+func (a *AgentService) Heartbeat(ctx context.Context, id int, info AgentInfo) error {
+	agentGet := agent.Agent{ID: id}
+	if err := agent.GetAgentById(ctx, &agentGet); err != nil {
+		return fmt.Errorf("get agent[%d] error: %s", id, err.Error())
+	}
+
+	if info.Mode == "" {
+		info.Mode = agentGet.Mode
+	}
+	setAgentInfo(&agentGet, info)
+	_, err := agent.UpdateAgent(ctx, &agentGet)
+	return err
+}
+
+func setAgentInfo(agentGet *agent.Agent, info AgentInfo) {
+	if info.Hostname != "" {
+		agentGet.Hostname = info.Hostname
+	}
+	if info.IP != "" {
+		agentGet.IP = info.IP
+	}
+	if info.Port != 0 {
+		agentGet.Port = info.Port
+	}
+	if info.Version != "" {
+		agentGet.Version = info.Version
+	}
+
+	agentGet.Mode = info.Mode
+	agentGet.ContainerRuntime = strings.Join(info.ContainerRuntimes, ",")
+	agentGet.Faults = info.Faults
+	agentGet.Experiments = info.Experiments
+	agentGet.Status = agent.AgentStatusOnline
+	agentGet.HeartbeatTime = time.Now()
+}
+
+// CreateTask adds a task for the agent in pull mode, content is the inject args for inject task
+func (a *AgentService) CreateTask(ctx context.Context, agentID int, taskType, uid, content string) (int64, error) {
+	if taskType != agent.AgentTaskTypeInject && taskType != agent.AgentTaskTypeRecover {
+		return 0, fmt.Errorf("task type[%s] is not supported", taskType)
+	}
+
+	if taskType == agent.AgentTaskTypeRecover && uid == "" {
+		return 0, errors.New("uid is empty for recover task")
+	}
+
+	if taskType == agent.AgentTaskTypeInject && content == "" {
+		return 0, errors.New("content is empty for inject task")
+	}
+
+	agentGet := agent.Agent{ID: agentID}
+	if err := agent.GetAgentById(ctx, &agentGet); err != nil {
+		return 0, fmt.Errorf("get agent[%d] error: %s", agentID, err.Error())
+	}
+
+	// the tasks of agent in push mode are never pulled
+	if agentGet.Mode != agent.AgentModePull {
+		return 0, fmt.Errorf("agent[%d] is not in pull mode", agentID)
+	}
+
+	return agent.InsertAgentTask(ctx, &agent.AgentTask{
+		AgentID: agentID,
+		Type:    taskType,
+		Uid:     uid,
+		Content: content,
+		Status:  agent.AgentTaskStatusPending,
+	})
+}
+
+// PullTasks returns the pending tasks of the agent and marks them dispatched.
+// A dispatched task fails if the agent goes offline or reports no result in dispatchTimeout
+func (a *AgentService) PullTasks(ctx context.Context, agentID, limit int) ([]agent.AgentTask, error) {
+	if limit <= 0 || limit > maxPullLimit {
+		limit = maxPullLimit
+	}
+
+	tasks, err := agent.ListPendingAgentTasks(ctx, agentID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range tasks {
+		tasks[i].Status = agent.AgentTaskStatusDispatched
+		if _, err := agent.UpdateAgentTask(ctx, &tasks[i], "status", "update_time"); err != nil {
+			return nil, fmt.Errorf("update status of task[%d] error: %s", tasks[i].ID, err.Error())
+		}
+	}
+
+	return tasks, nil
+}
+
+func (a *AgentService) ReportTaskResult(ctx context.Context, agentID, taskID int, success bool, uid, message string) error {
+	task := agent.AgentTask{ID: taskID}
+	if err := agent.GetAgentTaskById(ctx, &task); err != nil {
+		return fmt.Errorf("get task[%d] error: %s", taskID, err.Error())
+	}
+
+	if task.AgentID != agentID {
+		return fmt.Errorf("task[%d] does not belong to agent[%d]", taskID, agentID)
+	}
+
+	task.Status, task.Message = agent.AgentTaskStatusFailed, message
+	if success {
+		task.Status = agent.AgentTaskStatusSuccess
+	}
+	if uid != "" {
+		task.Uid = uid
+	}
+
+	_, err := agent.UpdateAgentTask(ctx, &task, "status", "message", "uid", "update_time")
+	return err
+}
@@ -59,6 +59,10 @@ type PhaseType string
 const (
 	InjectPhaseType  PhaseType = "inject"
 	RecoverPhaseType PhaseType = "recover"
+	// PausePhaseType recovers all inject objects temporarily and keeps the selection
+	PausePhaseType PhaseType = "pause"
+	// ResumePhaseType injects the paused objects again with new sub-uid
+	ResumePhaseType PhaseType = "resume"
 )
 
 type StatusType string
@@ -79,8 +83,22 @@ type ExperimentStatus struct {
 	Detail     ExperimentDetail `json:"detail"`
 	CreateTime string           `json:"createTime"`
 	UpdateTime string           `json:"updateTime"`
+	// Conditions support types: Selected, Injected, Recovered, Degraded
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// condition types of Experiment
+const (
+	// SelectedConditionType means the inject objects are selected
+	SelectedConditionType = "Selected"
+	// InjectedConditionType means the fault is active on the inject objects
+	InjectedConditionType = "Injected"
+	// RecoveredConditionType means the inject objects are recovered, include paused
+	RecoveredConditionType = "Recovered"
+	// DegradedConditionType means some inject objects failed in current phase
+	DegradedConditionType = "Degraded"
+)
+
 type ExperimentInjectStruct struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -103,10 +121,36 @@ const (
 	CountRangeType   RangeType = "count"
 )
 
+type TopologyType string
+
+const (
+	SpreadTopologyType      TopologyType = "spread"
+	ConcentrateTopologyType TopologyType = "concentrate"
+)
+
+type PreferType string
+
+const (
+	NewestPreferType PreferType = "newest"
+	OldestPreferType PreferType = "oldest"
+)
+
 type RangeMode struct {
 	// Type Optional: all、percent、count
 	Type  RangeType `json:"type"`
 	Value int       `json:"value,omitempty"`
+	// Seed makes the selection reproducible, the same candidates and seed always pick the same targets. Random when 0
+	Seed int64 `json:"seed,omitempty"`
+	// Topology Optional: spread、concentrate. Spread the targets evenly across the values of TopologyKey, or concentrate them in as few values as possible
+	Topology TopologyType `json:"topology,omitempty"`
+	// TopologyKey is the label key of node, such as "topology.kubernetes.io/zone", default "kubernetes.io/hostname"
+	TopologyKey string `json:"topologyKey,omitempty"`
+	// MaxPerNode limits the number of targets on the same node, 0 means no limit
+	MaxPerNode int `json:"maxPerNode,omitempty"`
+	// ReadyOnly excludes the pods and nodes which are not Ready
+	ReadyOnly bool `json:"readyOnly,omitempty"`
+	// Prefer Optional: newest、oldest. Pick the targets by creation time before random
+	Prefer PreferType `json:"prefer,omitempty"`
 }
 
 type SelectorUnit struct {
@@ -115,10 +159,27 @@ type SelectorUnit struct {
 	IP        []string          `json:"ip,omitempty"`
 	Label     map[string]string `json:"label,omitempty"`
 	SubName   string            `json:"subName,omitempty"`
+	// MatchExpressions is ANDed with Label, operator support In、NotIn、Exists、DoesNotExist
+	MatchExpressions []metav1.LabelSelectorRequirement `json:"matchExpressions,omitempty"`
+	// Annotation select the objects whose annotations contain all the key-value pairs
+	Annotation map[string]string `json:"annotation,omitempty"`
+	// Field is a field selector like "spec.nodeName=node-1,status.phase!=Running", operator support =、==、!=
+	Field string `json:"field,omitempty"`
+	// Owner select the objects controlled by the owner, directly or through ReplicaSet、Job
+	Owner *OwnerSelector `json:"owner,omitempty"`
+}
+
+type OwnerSelector struct {
+	// Kind Optional: Deployment、StatefulSet、DaemonSet、ReplicaSet、Job、CronJob
+	Kind string `json:"kind"`
+	Name string `json:"name"`
 }
 
 type ExperimentCommon struct {
-	// Duration support "h", "m", "s"
+	// Duration support "h", "m", "s". It can be updated to move the auto recover deadline when experiment is in
+	// "pause" phase, or in "inject" phase of "kubernetes" scope. Updates in "inject" phase of "node" and "pod" scope
+	// are rejected, because chaosmetad recovers them with the timeout given at inject time, pause the experiment
+	// first and the new duration takes effect when it is resumed
 	Duration string     `json:"duration,omitempty"`
 	Target   string     `json:"target"`
 	Fault    string     `json:"fault"`
@@ -141,6 +202,7 @@ type ArgsUnit struct {
 type ExperimentDetail struct {
 	Inject  []ExperimentDetailUnit `json:"inject,omitempty"`
 	Recover []ExperimentDetailUnit `json:"recover,omitempty"`
+	Pause   []ExperimentDetailUnit `json:"pause,omitempty"`
 }
 
 type ExperimentDetailUnit struct {
@@ -151,6 +213,8 @@ type ExperimentDetailUnit struct {
 	StartTime        string     `json:"startTime,omitempty"`
 	UpdateTime       string     `json:"updateTime,omitempty"`
 	Backup           string     `json:"backup,omitempty"`
+	// Reason records why the inject object is picked by RangeMode
+	Reason string `json:"reason,omitempty"`
 }
 
 type CloudTargetType string
@@ -165,4 +229,8 @@ const (
 	DaemonsetCloudTarget                CloudTargetType = "daemonset"
 	NamespaceCloudTarget                CloudTargetType = "namespace"
 	JobCloudTarget                      CloudTargetType = "job"
+	ServiceCloudTarget                  CloudTargetType = "service"
+	NetworkPolicyCloudTarget            CloudTargetType = "networkpolicy"
+	ConfigMapCloudTarget                CloudTargetType = "configmap"
+	SecretCloudTarget                   CloudTargetType = "secret"
 )
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package routers
+
+import (
+	"chaosmeta-platform/config"
+	"chaosmeta-platform/pkg/gateway/apiserver/v1alpha1/agent"
+	"chaosmeta-platform/util/errors"
+	"chaosmeta-platform/util/log"
+	"crypto/subtle"
+	"fmt"
+	beego "github.com/beego/beego/v2/server/web"
+	beecontext "github.com/beego/beego/v2/server/web/context"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+type agentRequest struct {
+	method string
+	path   *regexp.Regexp
+}
+
+// agentRequests are sent by chaosmetad and authenticated by the agent token, the other agent apis are for users
+var agentRequests = []agentRequest{
+	{method: http.MethodPost, path: regexp.MustCompile(fmt.Sprintf("^%s$", NewWebServicePath("agents/register")))},
+	{method: http.MethodPost, path: regexp.MustCompile(fmt.Sprintf("^%s$", NewWebServicePath("agents/[0-9]+/heartbeat")))},
+	{method: http.MethodGet, path: regexp.MustCompile(fmt.Sprintf("^%s$", NewWebServicePath("agents/[0-9]+/tasks")))},
+	{method: http.MethodPost, path: regexp.MustCompile(fmt.Sprintf("^%s$", NewWebServicePath("agents/[0-9]+/tasks/[0-9]+/result")))},
+}
+
+func agentInit() {
+	beego.Router(NewWebServicePath("agents/register"), &agent.AgentController{}, "post:Register")
+	beego.Router(NewWebServicePath("agents/:id/heartbeat"), &agent.AgentController{}, "post:Heartbeat")
+	beego.Router(NewWebServicePath("agents/:id/tasks"), &agent.AgentController{}, "get:PullTasks;post:CreateTask")
+	beego.Router(NewWebServicePath("agents/:id/tasks/:task_id/result"), &agent.AgentController{}, "post:ReportTaskResult")
+}
+
+func isAgentRequest(method, path string) bool {
+	for _, req := range agentRequests {
+		if req.method == method && req.path.MatchString(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func CheckAgentTokenMiddleware(ctx *beecontext.Context) {
+	if err := checkAgentToken(ctx.Input.Header("Authorization"), config.DefaultRunOptIns.AgentToken); err != nil {
+		log.Error(err)
+		ctx.Output.JSON(errors.ErrUnauthorized().WithMessage(err.Error()), false, false)
+	}
+}
+
+func checkAgentToken(authorization, agentToken string) error {
+	if agentToken == "" {
+		return fmt.Errorf("agent token is not configured")
+	}
+
+	token := strings.TrimPrefix(authorization, "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(agentToken)) != 1 {
+		return fmt.Errorf("invalid agent token")
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package routers
+
+import (
+	"net/http"
+	"testing"
+)
+
+func Test_isAgentRequest(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   bool
+	}{
+		{method: http.MethodPost, path: "/chaosmeta/api/v1/agents/register", want: true},
+		{method: http.MethodPost, path: "/chaosmeta/api/v1/agents/12/heartbeat", want: true},
+		{method: http.MethodGet, path: "/chaosmeta/api/v1/agents/12/tasks", want: true},
+		{method: http.MethodPost, path: "/chaosmeta/api/v1/agents/12/tasks/3/result", want: true},
+		// creating task is for users
+		{method: http.MethodPost, path: "/chaosmeta/api/v1/agents/12/tasks", want: false},
+		{method: http.MethodGet, path: "/chaosmeta/api/v1/agents/register", want: false},
+		{method: http.MethodPost, path: "/chaosmeta/api/v1/agents/register/other", want: false},
+		{method: http.MethodGet, path: "/chaosmeta/api/v1/users", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+tt.path, func(t *testing.T) {
+			if got := isAgentRequest(tt.method, tt.path); got != tt.want {
+				t.Errorf("isAgentRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkAgentToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		agentToken    string
+		wantErr       bool
+	}{
+		{name: "valid", authorization: "Bearer agent-token", agentToken: "agent-token", wantErr: false},
+		{name: "invalid", authorization: "Bearer user-token", agentToken: "agent-token", wantErr: true},
+		{name: "empty", authorization: "", agentToken: "agent-token", wantErr: true},
+		{name: "not configured", authorization: "Bearer ", agentToken: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkAgentToken(tt.authorization, tt.agentToken); (err != nil) != tt.wantErr {
+				t.Errorf("checkAgentToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
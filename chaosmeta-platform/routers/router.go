@@ -37,6 +37,7 @@ func routerInit() {
 	injectInit()
 	experimentInit()
 	experimentInstanceInit()
+	agentInit()
 }
 
 func Init() {
@@ -46,6 +47,12 @@ func Init() {
 }
 
 func CheckTokenMiddleware(ctx *beecontext.Context) {
+	// agents use a long-lived token instead of the user token which expires in minutes
+	if isAgentRequest(ctx.Input.Method(), ctx.Input.URL()) {
+		CheckAgentTokenMiddleware(ctx)
+		return
+	}
+
 	token := ctx.Input.Header("Authorization")
 	if token == "" {
 		log.Error("token is empty")
@@ -0,0 +1,49 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/query"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+)
+
+// NewEventsCommand eventsCmd represents the events command
+func NewEventsCommand() *cobra.Command {
+	var (
+		uid           string
+		offset, limit uint
+		format        string
+	)
+
+	eventsCmd := &cobra.Command{
+		Use:   "events",
+		Short: "experiment lifecycle events query command",
+		Run: func(cmd *cobra.Command, args []string) {
+			query.PrintEventsByUid(utils.GetCtxWithTraceId(context.Background(), utils.TraceId), uid, offset, limit, format)
+		},
+	}
+
+	eventsCmd.Flags().StringVarP(&uid, "uid", "u", "", "query events of experiment by uid, eg: chaosmetad events -u [uid]")
+	eventsCmd.Flags().UintVarP(&offset, "offset", "o", 0, "query event records with offset, eg: chaosmetad events -u [uid] -o 5")
+	eventsCmd.Flags().UintVarP(&limit, "limit", "l", 0, "query event records with limit, 0 means no limit, eg: chaosmetad events -u [uid] -l 5")
+	eventsCmd.Flags().StringVar(&format, "format", query.TableFormat, fmt.Sprintf("data show format, support: %s(default), %s", query.TableFormat, query.JsonFormat))
+
+	return eventsCmd
+}
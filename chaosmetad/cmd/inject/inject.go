@@ -17,6 +17,7 @@
 package inject
 
 import (
+	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/container"
@@ -25,9 +26,11 @@ import (
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/diskio"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/dns"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/file"
+	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/grpc"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/jvm"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/kernel"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/mem"
+	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/middleware"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/network"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/process"
 )
@@ -49,6 +52,12 @@ func NewInjectCommand() *cobra.Command {
 	injectCmd.PersistentFlags().StringVar(&args.ContainerId, "container-id", "", "if attack a container of local host, need to provide the container id of target container")
 
 	injectCmd.PersistentFlags().StringVar(&args.Uid, "uid", "", "if not provide, it will automatically generate an uid")
+
+	injectCmd.PersistentFlags().StringVar(&args.Ramp.Interval, "ramp-interval", "", "if provided, the fault intensity will be re-tuned every interval from \"ramp-start\" to \"ramp-end\", support unit: \"s、m、h\"(default s)")
+	injectCmd.PersistentFlags().IntVar(&args.Ramp.Start, "ramp-start", 0, "fault intensity at the beginning of ramp, eg: 10 means cpu burn 10% or delay 10ms")
+	injectCmd.PersistentFlags().IntVar(&args.Ramp.End, "ramp-end", 0, "fault intensity at the end of ramp")
+	injectCmd.PersistentFlags().IntVar(&args.Ramp.Step, "ramp-step", 0, fmt.Sprintf("intensity changed every interval, only used in ramp shape \"%s\"", injector.RampShapeStep))
+	injectCmd.PersistentFlags().StringVar(&args.Ramp.Shape, "ramp-shape", "", fmt.Sprintf("ramp shape, support: %s（default）、%s. %s needs \"timeout\"", injector.RampShapeLinear, injector.RampShapeStep, injector.RampShapeLinear))
 	//var args = make([]string, 2)
 	//injectCmd.PersistentFlags().StringVarP(&args[0], "timeout", "t", "", "experiment's duration（default 0, means need to stop manually）")
 	//injectCmd.PersistentFlags().StringVar(&args[1], "creator", "", "experiment's creator（default the cmd exec user）")
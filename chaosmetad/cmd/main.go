@@ -17,33 +17,59 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/events"
 	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/inject"
 	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/query"
+	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/ramp"
 	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/recover"
 	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/server"
+	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/transfer"
+	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/update"
 	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/version"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
-	"os"
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   utils.RootName,
 	Short: fmt.Sprintf("a command line client to create %s experiment", utils.RootName),
+	// the errors are printed by errutil with the output format
+	SilenceErrors: true,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if err := errutil.CheckOutput(utils.Output); err != nil {
+			utils.Output = utils.OutputTable
+			errutil.SolveErr(context.Background(), errutil.BadArgsErr, err.Error())
+		}
+
+		if err := storage.ExportConfig(); err != nil {
+			errutil.SolveErr(context.Background(), errutil.InternalErr, fmt.Sprintf("export storage config error: %s", err.Error()))
+		}
+	},
 }
 
 func initRootCmd() {
 	rootCmd.PersistentFlags().StringVar(&log.Level, "log-level", "info", "value support: debug, info, warn, error")
 	rootCmd.PersistentFlags().StringVar(&log.Path, "log-path", "", "log file's path, eg: /tmp/chaosmetad.log")
 	rootCmd.PersistentFlags().StringVar(&utils.TraceId, "trace-id", "", "trace id")
+	rootCmd.PersistentFlags().StringVar(&utils.Output, "output", utils.OutputTable, fmt.Sprintf("result format, support: %s(default), %s, %s. the result of %s and %s is printed to stdout with uid, status, code and message, the logs are printed to stderr", utils.OutputTable, utils.OutputJson, utils.OutputYaml, utils.OutputJson, utils.OutputYaml))
+	rootCmd.PersistentFlags().StringVar(&storage.Path, "db-path", "", fmt.Sprintf("db file's path, env: %s, default: [run path]/chaosmetad.dat", storage.EnvDBPath))
+	rootCmd.PersistentFlags().StringVar(&storage.RetentionAge, "retention-age", "", fmt.Sprintf("finished experiments older than it are deleted, \"0\" means no limit, env: %s, default: %s", storage.EnvRetentionAge, storage.DefaultRetentionAge))
+	rootCmd.PersistentFlags().IntVar(&storage.RetentionCount, "retention-count", storage.NoRetentionCount, fmt.Sprintf("max count of finished experiments to keep, 0 means no limit, env: %s, default: %d", storage.EnvRetentionCount, storage.DefaultRetentionCount))
 
 	rootCmd.AddCommand(inject.NewInjectCommand())
 	rootCmd.AddCommand(query.NewQueryCommand())
 	rootCmd.AddCommand(recover.NewRecoverCommand())
+	rootCmd.AddCommand(events.NewEventsCommand())
+	rootCmd.AddCommand(ramp.NewRampCommand())
+	rootCmd.AddCommand(update.NewUpdateCommand())
+	rootCmd.AddCommand(transfer.NewExportCommand())
+	rootCmd.AddCommand(transfer.NewImportCommand())
 	rootCmd.AddCommand(server.NewServerCommand())
 	rootCmd.AddCommand(version.NewVersionCommand())
 }
@@ -51,8 +77,11 @@ func initRootCmd() {
 func main() {
 	initRootCmd()
 
-	err := rootCmd.Execute()
-	if err != nil {
-		os.Exit(errutil.InternalErr)
+	if err := rootCmd.Execute(); err != nil {
+		if errutil.CheckOutput(utils.Output) != nil {
+			utils.Output = utils.OutputTable
+		}
+
+		errutil.SolveErr(context.Background(), errutil.BadArgsErr, err.Error())
 	}
 }
@@ -26,6 +26,7 @@ import (
 )
 
 func NewRecoverCommand() *cobra.Command {
+	var auto bool
 	recoverCmd := &cobra.Command{
 		Use:   "recover",
 		Short: "experiment recover command",
@@ -36,10 +37,18 @@ func NewRecoverCommand() *cobra.Command {
 				errutil.SolveErr(ctx, errutil.BadArgsErr, fmt.Sprintf("please add target experiment's uid, eg: recover [uid]"))
 			}
 
+			if auto {
+				ctx = utils.GetCtxWithActor(ctx, utils.ActorAutoRecover)
+			}
+
 			code, msg := injector.ProcessRecover(ctx, args[0])
-			errutil.SolveErr(ctx, code, msg)
+			errutil.SolveErrWithUid(ctx, args[0], code, msg)
 		},
 	}
 
+	// only used by the delay recover process started after inject
+	recoverCmd.Flags().BoolVar(&auto, "auto", false, "recover triggered by timeout")
+	_ = recoverCmd.Flags().MarkHidden("auto")
+
 	return recoverCmd
 }
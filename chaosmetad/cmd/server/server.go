@@ -19,7 +19,9 @@ package server
 import (
 	"context"
 	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/agent"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
@@ -27,11 +29,15 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
+const pruneInterval = time.Hour
+
 func watchSignal(ctx context.Context) {
 	logger := log.GetLogger(ctx)
 	c := make(chan os.Signal)
@@ -50,17 +56,38 @@ func watchSignal(ctx context.Context) {
 	}
 }
 
+func pruneExperiments(ctx context.Context) {
+	logger := log.GetLogger(ctx)
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		if count, err := storage.Prune(); err != nil {
+			logger.Warnf("prune finished experiments error: %s", err.Error())
+		} else if count > 0 {
+			logger.Infof("prune %d finished experiments", count)
+		}
+
+		<-ticker.C
+	}
+}
+
 // NewServerCommand serverCmd represents the server command
 func NewServerCommand() *cobra.Command {
 	var addr, port string
 	//var cert, key string
 	var isPprof bool
+	var agentCfg = &agent.Config{}
 	cmd := &cobra.Command{
 		Use:   "server",
 		Short: "start up daemon service",
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx := utils.GetCtxWithTraceId(context.Background(), "system")
 			go watchSignal(ctx)
+			go pruneExperiments(ctx)
+			if agentCfg.Addr != "" {
+				startAgent(ctx, agentCfg, port)
+			}
 
 			//if cert != "" && key != "" {
 			//	startHTTPSServer(addr, port, isPprof, cert, key)
@@ -73,6 +100,12 @@ func NewServerCommand() *cobra.Command {
 	cmd.Flags().StringVarP(&addr, "addr", "a", "0.0.0.0", "service bind addr")
 	cmd.Flags().StringVarP(&port, "port", "p", "29595", "service bind port")
 	cmd.Flags().BoolVar(&isPprof, "enable-pprof", true, "if open pprof service")
+	cmd.Flags().StringVar(&agentCfg.Addr, "platform-addr", "", "register to the platform and send heartbeats if set, eg: http://127.0.0.1:8082")
+	cmd.Flags().StringVar(&agentCfg.Token, "platform-token", "", "agent token configured in the platform as \"agentToken\"")
+	cmd.Flags().StringVar(&agentCfg.AdvertiseIP, "advertise-ip", "", "ip reported to the platform, default: the source ip seen by the platform")
+	cmd.Flags().DurationVar(&agentCfg.HeartbeatInterval, "heartbeat-interval", 30*time.Second, "interval of heartbeat to the platform")
+	cmd.Flags().BoolVar(&agentCfg.Pull, "pull", false, "pull inject and recover tasks from the platform, for the hosts the platform can not access")
+	cmd.Flags().DurationVar(&agentCfg.PullInterval, "pull-interval", 5*time.Second, "interval of pulling tasks from the platform")
 	//cmd.Flags().StringVarP(&cert, "cert", "c", "", "path to certificate file")
 	//cmd.Flags().StringVarP(&key, "key", "k", "", "path to private key file")
 	// HTTPS
@@ -82,6 +115,21 @@ func NewServerCommand() *cobra.Command {
 	return cmd
 }
 
+func startAgent(ctx context.Context, cfg *agent.Config, port string) {
+	portInt, err := strconv.Atoi(port)
+	if err != nil {
+		errutil.SolveErr(ctx, errutil.BadArgsErr, fmt.Sprintf("\"port\" is not a valid number: %s", err.Error()))
+	}
+	cfg.Port = portInt
+
+	a, err := agent.NewAgent(cfg)
+	if err != nil {
+		errutil.SolveErr(ctx, errutil.BadArgsErr, fmt.Sprintf("create agent error: %s", err.Error()))
+	}
+
+	go a.Start(ctx)
+}
+
 func startHTTPService(ctx context.Context, addr string, port string, isPprof bool) {
 	logger := log.GetLogger(ctx)
 	logger.Infof("HTTP Service Listen on %s:%s, pprof: %t", addr, port, isPprof)
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"io"
+	"os"
+)
+
+// NewExportCommand exportCmd represents the export command
+func NewExportCommand() *cobra.Command {
+	var file string
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "export experiments and events of db",
+		Long:  "export experiments and events of db to a json file, which can be imported on another host, usage: export -f [file]",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := utils.GetCtxWithTraceId(context.Background(), utils.TraceId)
+			var (
+				w   io.Writer = os.Stdout
+				f   *os.File
+				err error
+			)
+			if file != "" {
+				f, err = os.OpenFile(file, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+				if err != nil {
+					errutil.SolveErr(ctx, errutil.InternalErr, fmt.Sprintf("open file[%s] error: %s", file, err.Error()))
+				}
+				w = f
+			}
+
+			count, err := storage.Export(w)
+			if err != nil {
+				errutil.SolveErr(ctx, errutil.DBErr, fmt.Sprintf("export error: %s", err.Error()))
+			}
+
+			if file != "" {
+				// close before the result, which exits the process
+				if err := f.Close(); err != nil {
+					errutil.SolveErr(ctx, errutil.InternalErr, fmt.Sprintf("close file[%s] error: %s", file, err.Error()))
+				}
+
+				msg := fmt.Sprintf("export %d experiments to %s", count, file)
+				log.GetLogger(ctx).Info(msg)
+				errutil.SolveResult(ctx, &errutil.Result{Code: errutil.NoErr, Message: msg, Data: map[string]interface{}{"file": file, "count": count}})
+			}
+		},
+	}
+
+	exportCmd.Flags().StringVarP(&file, "file", "f", "", "file to export to, default: stdout")
+
+	return exportCmd
+}
+
+// NewImportCommand importCmd represents the import command
+func NewImportCommand() *cobra.Command {
+	var file string
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "import experiments and events to db",
+		Long:  "import experiments and events exported by another host, the experiments with existed uid are skipped and the experiments not finished are imported as error, usage: import -f [file]",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := utils.GetCtxWithTraceId(context.Background(), utils.TraceId)
+			if file == "" {
+				errutil.SolveErr(ctx, errutil.BadArgsErr, "\"file\" is empty, eg: import -f [file]")
+			}
+
+			f, err := os.Open(file)
+			if err != nil {
+				errutil.SolveErr(ctx, errutil.BadArgsErr, fmt.Sprintf("open file[%s] error: %s", file, err.Error()))
+			}
+			defer f.Close()
+
+			imported, skipped, err := storage.Import(f)
+			if err != nil {
+				errutil.SolveErr(ctx, errutil.DBErr, fmt.Sprintf("import error: %s", err.Error()))
+			}
+
+			msg := fmt.Sprintf("import %d experiments, skip %d existed experiments", imported, skipped)
+			log.GetLogger(ctx).Info(msg)
+			errutil.SolveResult(ctx, &errutil.Result{Code: errutil.NoErr, Message: msg, Data: map[string]interface{}{"imported": imported, "skipped": skipped}})
+		},
+	}
+
+	importCmd.Flags().StringVarP(&file, "file", "f", "", "file to import from")
+
+	return importCmd
+}
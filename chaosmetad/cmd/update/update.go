@@ -0,0 +1,51 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package update
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+)
+
+func NewUpdateCommand() *cobra.Command {
+	var argsStr string
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "experiment update command",
+		Long:  "update the args of a running experiment, only the args supported by the fault can be changed, such as method and condition args of jvm method faults, usage: update [uid] --args '{\"percent\":50}'",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := utils.GetCtxWithTraceId(context.Background(), utils.TraceId)
+			if len(args) != 1 {
+				errutil.SolveErr(ctx, errutil.BadArgsErr, fmt.Sprintf("please add target experiment's uid, eg: update [uid] --args '{\"percent\":50}'"))
+			}
+
+			if argsStr == "" {
+				errutil.SolveErr(ctx, errutil.BadArgsErr, "please provide new args by \"args\"")
+			}
+
+			code, msg := injector.ProcessUpdate(ctx, args[0], argsStr)
+			errutil.SolveErrWithUid(ctx, args[0], code, msg)
+		},
+	}
+
+	updateCmd.Flags().StringVarP(&argsStr, "args", "a", "", "new args of the experiment in json format, the args not provided keep unchanged, eg: '{\"condition\":\"args[0]==\\\"userA\\\"\"}'")
+	return updateCmd
+}
@@ -0,0 +1,263 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/version"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	apiPrefix    = "/chaosmeta/api/v1"
+	registerPath = "/agents/register"
+	// the arg is agent id
+	heartbeatPath = "/agents/%d/heartbeat"
+	// the args are agent id, limit
+	pullPath = "/agents/%d/tasks?limit=%d"
+	// the args are agent id, task id
+	resultPath = "/agents/%d/tasks/%d/result"
+
+	requestTimeout  = 10 * time.Second
+	pullLimit       = 10
+	maxActiveReport = 100
+)
+
+type Config struct {
+	// Addr is the address of platform, eg: http://127.0.0.1:8082
+	Addr              string
+	Token             string
+	AdvertiseIP       string
+	Port              int
+	HeartbeatInterval time.Duration
+	Pull              bool
+	PullInterval      time.Duration
+}
+
+type Agent struct {
+	cfg    *Config
+	client *http.Client
+	// id is set by register and read by pull, 0 means not registered
+	id int64
+}
+
+func NewAgent(cfg *Config) (*Agent, error) {
+	if !strings.HasPrefix(cfg.Addr, "http://") && !strings.HasPrefix(cfg.Addr, "https://") {
+		return nil, fmt.Errorf("platform addr[%s] must start with http:// or https://", cfg.Addr)
+	}
+
+	if cfg.HeartbeatInterval <= 0 {
+		return nil, fmt.Errorf("heartbeat interval must larger than 0")
+	}
+
+	if cfg.Pull && cfg.PullInterval <= 0 {
+		return nil, fmt.Errorf("pull interval must larger than 0")
+	}
+
+	return &Agent{
+		cfg:    cfg,
+		client: &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+// Start registers to the platform, and then keeps sending heartbeats and pulling tasks in pull mode.
+// The agent registers again when a heartbeat fails, so a restart of platform is tolerated
+func (a *Agent) Start(ctx context.Context) {
+	logger := log.GetLogger(ctx)
+	if a.cfg.Pull {
+		go a.startPull(ctx)
+	}
+
+	ticker := time.NewTicker(a.cfg.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		if a.getId() == 0 {
+			if err := a.register(ctx); err != nil {
+				logger.Warnf("register to platform[%s] error: %s", a.cfg.Addr, err.Error())
+			} else {
+				logger.Infof("register to platform[%s] success, agent id: %d", a.cfg.Addr, a.getId())
+			}
+		} else if err := a.heartbeat(ctx); err != nil {
+			logger.Warnf("send heartbeat to platform[%s] error: %s, register again", a.cfg.Addr, err.Error())
+			atomic.StoreInt64(&a.id, 0)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *Agent) register(ctx context.Context) error {
+	info, err := a.getAgentInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data registerData
+	if err := a.doRequest(ctx, http.MethodPost, registerPath, info, &data); err != nil {
+		return err
+	}
+
+	if data.ID <= 0 {
+		return fmt.Errorf("unexpected agent id: %d", data.ID)
+	}
+	atomic.StoreInt64(&a.id, int64(data.ID))
+
+	return nil
+}
+
+func (a *Agent) getId() int64 {
+	return atomic.LoadInt64(&a.id)
+}
+
+func (a *Agent) heartbeat(ctx context.Context) error {
+	info, err := a.getAgentInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	return a.doRequest(ctx, http.MethodPost, fmt.Sprintf(heartbeatPath, a.getId()), info, nil)
+}
+
+func (a *Agent) getAgentInfo(ctx context.Context) (*AgentInfo, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("get hostname error: %s", err.Error())
+	}
+
+	summary, err := getExperimentSummary()
+	if err != nil {
+		return nil, fmt.Errorf("get experiment summary error: %s", err.Error())
+	}
+
+	mode := ModePush
+	if a.cfg.Pull {
+		mode = ModePull
+	}
+
+	return &AgentInfo{
+		Hostname:          hostname,
+		IP:                a.cfg.AdvertiseIP,
+		Port:              a.cfg.Port,
+		Version:           version.GetVersion().Version,
+		Mode:              mode,
+		ContainerRuntimes: crclient.GetAvailableRuntimes(),
+		Faults:            getFaultCatalog(),
+		Experiments:       summary,
+	}, nil
+}
+
+func getFaultCatalog() map[string][]string {
+	catalog := make(map[string][]string)
+	for _, target := range injector.GetTargets() {
+		catalog[target] = injector.GetFaultsByTarget(target)
+	}
+
+	return catalog
+}
+
+func getExperimentSummary() (*ExperimentSummary, error) {
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return nil, err
+	}
+
+	statusCount, err := db.CountByStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	exps, _, err := db.QueryByOption("", utils.StatusSuccess, "", "", "", "", "", 0, maxActiveReport)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &ExperimentSummary{
+		StatusCount: statusCount,
+		Active:      make([]*ActiveExperiment, len(exps)),
+	}
+	for i, exp := range exps {
+		summary.Active[i] = &ActiveExperiment{
+			Uid:         exp.Uid,
+			Target:      exp.Target,
+			Fault:       exp.Fault,
+			ContainerId: exp.ContainerId,
+			Timeout:     exp.Timeout,
+			CreateTime:  exp.CreateTime,
+		}
+	}
+
+	return summary, nil
+}
+
+// doRequest sends the request to platform, and decodes the data of response into data if it is not nil
+func (a *Agent) doRequest(ctx context.Context, method, path string, body, data interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("request body convert to string error: %s", err.Error())
+		}
+		reqBody = bytes.NewReader(bodyBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(a.cfg.Addr, "/")+apiPrefix+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("create request error: %s", err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.cfg.Token)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request error: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected http status: %s", resp.Status)
+	}
+
+	res := &platformResponse{Data: data}
+	if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
+		return fmt.Errorf("decode response error: %s", err.Error())
+	}
+
+	if res.Code != platformSuccessCode {
+		return fmt.Errorf("code: %d, message: %s", res.Code, res.Message)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "chaosmetad-agent")
+	if err != nil {
+		panic(err)
+	}
+
+	storage.Path = filepath.Join(dir, "chaosmetad.dat")
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestAgent_RegisterAndHeartbeat(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer agent-token" {
+			_ = json.NewEncoder(w).Encode(platformResponse{Code: 401, Message: "invalid agent token"})
+			return
+		}
+
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		_ = json.NewEncoder(w).Encode(platformResponse{Code: platformSuccessCode, Data: registerData{ID: 7}})
+	}))
+	defer server.Close()
+
+	a, err := NewAgent(&Config{Addr: server.URL, Token: "agent-token", HeartbeatInterval: time.Second})
+	if err != nil {
+		t.Fatalf("NewAgent() error = %v", err)
+	}
+
+	ctx := context.Background()
+	if err := a.register(ctx); err != nil {
+		t.Fatalf("register() error = %v", err)
+	}
+
+	if a.getId() != 7 {
+		t.Fatalf("getId() = %d, want 7", a.getId())
+	}
+
+	if err := a.heartbeat(ctx); err != nil {
+		t.Fatalf("heartbeat() error = %v", err)
+	}
+
+	want := []string{"POST /chaosmeta/api/v1/agents/register", "POST /chaosmeta/api/v1/agents/7/heartbeat"}
+	if len(paths) != len(want) || paths[0] != want[0] || paths[1] != want[1] {
+		t.Errorf("requests = %v, want %v", paths, want)
+	}
+
+	a.cfg.Token = "user-token"
+	if err := a.heartbeat(ctx); err == nil {
+		t.Errorf("heartbeat() with invalid token, want error")
+	}
+}
+
+func TestNewAgent(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		wantErr bool
+	}{
+		{name: "valid", cfg: &Config{Addr: "http://127.0.0.1:8082", HeartbeatInterval: time.Second}, wantErr: false},
+		{name: "no scheme", cfg: &Config{Addr: "127.0.0.1:8082", HeartbeatInterval: time.Second}, wantErr: true},
+		{name: "no heartbeat interval", cfg: &Config{Addr: "http://127.0.0.1:8082"}, wantErr: true},
+		{name: "no pull interval", cfg: &Config{Addr: "http://127.0.0.1:8082", HeartbeatInterval: time.Second, Pull: true}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAgent(tt.cfg); (err != nil) != tt.wantErr {
+				t.Errorf("NewAgent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAgent_execTask(t *testing.T) {
+	a := &Agent{cfg: &Config{}}
+	if res := a.execTask(context.Background(), &Task{ID: 1, Type: "unknown"}); res.Success {
+		t.Errorf("execTask() with unknown type, want failed")
+	}
+
+	if res := a.execTask(context.Background(), &Task{ID: 2, Type: TaskTypeInject, Content: "{"}); res.Success {
+		t.Errorf("execTask() with invalid content, want failed")
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/handler"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/model"
+	"net/http"
+	"time"
+)
+
+const platformActor = "platform"
+
+func (a *Agent) startPull(ctx context.Context) {
+	logger := log.GetLogger(ctx)
+	ticker := time.NewTicker(a.cfg.PullInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// not registered yet
+		agentId := a.getId()
+		if agentId == 0 {
+			continue
+		}
+
+		var data pullData
+		if err := a.doRequest(ctx, http.MethodGet, fmt.Sprintf(pullPath, agentId, pullLimit), nil, &data); err != nil {
+			logger.Warnf("pull tasks from platform[%s] error: %s", a.cfg.Addr, err.Error())
+			continue
+		}
+
+		for _, task := range data.Tasks {
+			result := a.execTask(ctx, task)
+			if err := a.doRequest(ctx, http.MethodPost, fmt.Sprintf(resultPath, agentId, task.ID), result, nil); err != nil {
+				logger.Warnf("report result of task[%d] error: %s", task.ID, err.Error())
+			}
+		}
+	}
+}
+
+func (a *Agent) execTask(ctx context.Context, task *Task) *taskResult {
+	logger := log.GetLogger(ctx)
+	ctx = utils.GetCtxWithActor(utils.GetCtxWithTraceId(ctx, fmt.Sprintf("task-%d", task.ID)), platformActor)
+	logger.Infof("exec %s task[%d] from platform", task.Type, task.ID)
+
+	switch task.Type {
+	case TaskTypeInject:
+		injectReq := &model.InjectRequest{}
+		if err := json.Unmarshal([]byte(task.Content), injectReq); err != nil {
+			return &taskResult{Message: fmt.Sprintf("inject args format error: %s", err.Error())}
+		}
+
+		if injectReq.TraceId != "" {
+			ctx = utils.GetCtxWithTraceId(ctx, injectReq.TraceId)
+		}
+		injectRes := handler.ProcessInjectRequest(ctx, injectReq, platformActor)
+		result := &taskResult{Success: injectRes.Code == errutil.NoErr, Message: injectRes.Message}
+		if injectRes.Data != nil {
+			result.Uid = injectRes.Data.Experiment.Uid
+		}
+		return result
+	case TaskTypeRecover:
+		code, msg := injector.ProcessRecover(ctx, task.Uid)
+		return &taskResult{Success: code == errutil.NoErr, Uid: task.Uid, Message: msg}
+	default:
+		return &taskResult{Message: fmt.Sprintf("not support task type: %s", task.Type)}
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent
+
+const (
+	ModePush = "push"
+	ModePull = "pull"
+
+	TaskTypeInject  = "inject"
+	TaskTypeRecover = "recover"
+
+	platformSuccessCode = 200
+)
+
+// platformResponse is the common response format of platform
+type platformResponse struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data"`
+}
+
+type AgentInfo struct {
+	Hostname          string              `json:"hostname"`
+	IP                string              `json:"ip,omitempty"`
+	Port              int                 `json:"port"`
+	Version           string              `json:"version"`
+	Mode              string              `json:"mode"`
+	ContainerRuntimes []string            `json:"containerRuntimes"`
+	Faults            map[string][]string `json:"faults"`
+	Experiments       *ExperimentSummary  `json:"experiments"`
+}
+
+type ExperimentSummary struct {
+	StatusCount map[string]int64    `json:"statusCount"`
+	Active      []*ActiveExperiment `json:"active"`
+}
+
+type ActiveExperiment struct {
+	Uid         string `json:"uid"`
+	Target      string `json:"target"`
+	Fault       string `json:"fault"`
+	ContainerId string `json:"containerId,omitempty"`
+	Timeout     string `json:"timeout,omitempty"`
+	CreateTime  string `json:"createTime"`
+}
+
+type registerData struct {
+	ID int `json:"id"`
+}
+
+type Task struct {
+	ID   int    `json:"id"`
+	Type string `json:"type"`
+	Uid  string `json:"uid"`
+	// Content is the json of inject request for inject task
+	Content string `json:"content"`
+}
+
+type pullData struct {
+	Tasks []*Task `json:"tasks"`
+}
+
+type taskResult struct {
+	Success bool   `json:"success"`
+	Uid     string `json:"uid"`
+	Message string `json:"message"`
+}
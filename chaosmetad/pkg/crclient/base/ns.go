@@ -0,0 +1,212 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package base
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+const (
+	loopbackInterface = "lo"
+	rootPath          = "/"
+)
+
+// the clients can not depend on cmdexec, because cmdexec gets the container's pid by the clients
+func execInNs(ctx context.Context, pid int, nsFlag string, args ...string) (string, error) {
+	cmdArgs := append([]string{"-t", strconv.Itoa(pid), nsFlag, "--"}, args...)
+	cmdStr := fmt.Sprintf("nsenter %s", strings.Join(cmdArgs, " "))
+	log.GetLogger(ctx).Debugf("run cmd: %s", cmdStr)
+	utils.RecordCmd(ctx, cmdStr)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "nsenter", cmdArgs...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("error: %s, stderr: %s", err.Error(), stderr.String())
+	}
+
+	return stdout.String(), nil
+}
+
+func getInterfaceList(ctx context.Context, pid int) ([]string, error) {
+	output, err := execInNs(ctx, pid, "-n", "ip", "-o", "link", "show")
+	if err != nil {
+		return nil, fmt.Errorf("list links error: %s", err.Error())
+	}
+
+	// format: "2: eth0@if5: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1500 ..."
+	var ifaceList []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		iface := strings.Split(strings.TrimSuffix(fields[1], ":"), "@")[0]
+		if iface != loopbackInterface {
+			ifaceList = append(ifaceList, iface)
+		}
+	}
+
+	return ifaceList, nil
+}
+
+// DisconnectNetnsByPid sets all the links except loopback down in the network namespace of pid
+func DisconnectNetnsByPid(ctx context.Context, pid int) ([]NetworkEndpoint, error) {
+	hostNs, err := os.Readlink("/proc/1/ns/net")
+	if err != nil {
+		return nil, fmt.Errorf("get network namespace of host error: %s", err.Error())
+	}
+
+	targetNs, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/net", pid))
+	if err != nil {
+		return nil, fmt.Errorf("get network namespace of process[%d] error: %s", pid, err.Error())
+	}
+
+	if hostNs == targetNs {
+		return nil, fmt.Errorf("container in host network is not supported")
+	}
+
+	ifaceList, err := getInterfaceList(ctx, pid)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ifaceList) == 0 {
+		return nil, fmt.Errorf("no interface to disconnect")
+	}
+
+	var endpoints []NetworkEndpoint
+	for _, iface := range ifaceList {
+		// routes of the link are deleted after it is down, so save them first
+		output, err := execInNs(ctx, pid, "-n", "ip", "route", "show", "dev", iface)
+		if err != nil {
+			return endpoints, fmt.Errorf("get routes of interface[%s] error: %s", iface, err.Error())
+		}
+
+		var routes []string
+		for _, route := range strings.Split(strings.TrimSpace(output), "\n") {
+			if route != "" {
+				routes = append(routes, route)
+			}
+		}
+
+		if _, err := execInNs(ctx, pid, "-n", "ip", "link", "set", "dev", iface, "down"); err != nil {
+			return endpoints, fmt.Errorf("set interface[%s] down error: %s", iface, err.Error())
+		}
+
+		endpoints = append(endpoints, NetworkEndpoint{Interface: iface, Routes: routes})
+	}
+
+	return endpoints, nil
+}
+
+// ConnectNetnsByPid sets the links up and restores their routes in the network namespace of pid
+func ConnectNetnsByPid(ctx context.Context, pid int, endpoints []NetworkEndpoint) error {
+	var errMsg string
+	for _, endpoint := range endpoints {
+		if endpoint.Interface == "" {
+			continue
+		}
+
+		if _, err := execInNs(ctx, pid, "-n", "ip", "link", "set", "dev", endpoint.Interface, "up"); err != nil {
+			errMsg = fmt.Sprintf("%s. set interface[%s] up error: %s", errMsg, endpoint.Interface, err.Error())
+			continue
+		}
+
+		for _, route := range endpoint.Routes {
+			args := append([]string{"ip", "route", "replace"}, strings.Fields(route)...)
+			args = append(args, "dev", endpoint.Interface)
+			if _, err := execInNs(ctx, pid, "-n", args...); err != nil {
+				errMsg = fmt.Sprintf("%s. restore route[%s] of interface[%s] error: %s", errMsg, route, endpoint.Interface, err.Error())
+			}
+		}
+	}
+
+	if errMsg != "" {
+		return errors.New(errMsg)
+	}
+
+	return nil
+}
+
+// RemountByPid remounts path in the mount namespace of pid, the path not root is regarded as a bind mounted volume
+func RemountByPid(ctx context.Context, pid int, path string, readOnly bool) error {
+	opt := "remount,rw"
+	if readOnly {
+		opt = "remount,ro"
+	}
+
+	if path != rootPath {
+		opt = fmt.Sprintf("%s,bind", opt)
+	}
+
+	if _, err := execInNs(ctx, pid, "-m", "mount", "-o", opt, path); err != nil {
+		return fmt.Errorf("remount %s with %s error: %s", path, opt, err.Error())
+	}
+
+	return nil
+}
+
+// IsReadOnlyByPid checks whether path is mounted read-only in the mount namespace of pid
+func IsReadOnlyByPid(pid int, path string) (bool, error) {
+	mountInfo, err := os.ReadFile(fmt.Sprintf("/proc/%d/mountinfo", pid))
+	if err != nil {
+		return false, fmt.Errorf("read mountinfo of process[%d] error: %s", pid, err.Error())
+	}
+
+	return parseMountReadOnly(string(mountInfo), path)
+}
+
+// parseMountReadOnly the last mount of path takes effect,
+// format: "36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue"
+func parseMountReadOnly(mountInfo, path string) (bool, error) {
+	var (
+		found    bool
+		readOnly bool
+	)
+
+	for _, line := range strings.Split(mountInfo, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 6 || fields[4] != path {
+			continue
+		}
+
+		found, readOnly = true, false
+		for _, opt := range strings.Split(fields[5], ",") {
+			if opt == "ro" {
+				readOnly = true
+				break
+			}
+		}
+	}
+
+	if !found {
+		return false, fmt.Errorf("%s is not a mount point", path)
+	}
+
+	return readOnly, nil
+}
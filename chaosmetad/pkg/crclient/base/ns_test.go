@@ -0,0 +1,51 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package base
+
+import "testing"
+
+func Test_parseMountReadOnly(t *testing.T) {
+	mountInfo := `1290 1200 0:120 / / rw,relatime master:400 - overlay overlay rw,lowerdir=/l1,upperdir=/u1,workdir=/w1
+1291 1290 0:121 / /proc rw,nosuid,nodev,noexec,relatime - proc proc rw
+1300 1290 8:1 /var/lib/kubelet/pods/p1/volumes/v1 /data ro,relatime - ext4 /dev/sda1 rw
+1301 1290 8:1 /var/lib/kubelet/pods/p1/volumes/v2 /logs rw,relatime - ext4 /dev/sda1 rw
+1302 1301 8:1 /var/lib/kubelet/pods/p1/volumes/v2 /logs ro,relatime - ext4 /dev/sda1 rw
+`
+	tests := []struct {
+		name    string
+		path    string
+		want    bool
+		wantErr bool
+	}{
+		{name: "rootfs rw", path: "/", want: false},
+		{name: "volume ro", path: "/data", want: true},
+		{name: "last mount takes effect", path: "/logs", want: true},
+		{name: "not mount point", path: "/tmp", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMountReadOnly(mountInfo, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseMountReadOnly() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("parseMountReadOnly() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -20,3 +20,19 @@ type SimpleProcess struct {
 	Pid  int
 	Cmd  string
 }
+
+// NetworkEndpoint is a network connection detached from the container, it is used to reattach the container on recover
+type NetworkEndpoint struct {
+	// Network is the runtime network which the container is detached from
+	Network   string `json:"network,omitempty"`
+	IPAddress string `json:"ip_address,omitempty"`
+	// Interface is the link set down in the container's network namespace when there is no runtime network to detach
+	Interface string   `json:"interface,omitempty"`
+	Routes    []string `json:"routes,omitempty"`
+}
+
+// MemoryLimit is the memory limit of the container in bytes, the value not larger than 0 means no limit
+type MemoryLimit struct {
+	Limit int64 `json:"limit"`
+	Swap  int64 `json:"swap"`
+}
@@ -43,6 +43,11 @@ type Client interface {
 	CpFile(ctx context.Context, containerID, src, dst string) error
 	Exec(ctx context.Context, containerID, cmd string) (string, error)
 	GetAllPidList(ctx context.Context, containerID string) ([]base.SimpleProcess, error)
+	DisconnectNetworkById(ctx context.Context, containerID string) ([]base.NetworkEndpoint, error)
+	ConnectNetworkById(ctx context.Context, containerID string, endpoints []base.NetworkEndpoint) error
+	RemountById(ctx context.Context, containerID, path string, readOnly bool) error
+	GetMemoryLimitById(ctx context.Context, containerID string) (*base.MemoryLimit, error)
+	UpdateMemoryLimitById(ctx context.Context, containerID string, limit *base.MemoryLimit) error
 }
 
 func GetClient(ctx context.Context, cr string) (Client, error) {
@@ -59,3 +64,21 @@ func GetClient(ctx context.Context, cr string) (Client, error) {
 		return nil, fmt.Errorf("not support container runtime: %s", cr)
 	}
 }
+
+// GetAvailableRuntimes returns the container runtimes found on the host
+func GetAvailableRuntimes() []string {
+	var runtimes []string
+	if docker.IsAvailable() {
+		runtimes = append(runtimes, CrDocker)
+	}
+
+	if containerd.IsAvailable() {
+		runtimes = append(runtimes, CrContainerd)
+	}
+
+	if pouch.IsAvailable() {
+		runtimes = append(runtimes, CrPouch)
+	}
+
+	return runtimes
+}
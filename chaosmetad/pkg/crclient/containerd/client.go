@@ -51,6 +51,12 @@ var (
 	mutex          sync.Mutex
 )
 
+// IsAvailable checks whether the socket of the runtime exists on the host
+func IsAvailable() bool {
+	_, err := os.Stat(defaultSocket)
+	return err == nil
+}
+
 func GetClient(ctx context.Context) (d *Client, err error) {
 	defer func() {
 		if e := recover(); e != any(nil) {
@@ -310,3 +316,81 @@ func (d *Client) CpFile(ctx context.Context, containerID, src, dst string) error
 
 	return nil
 }
+
+// DisconnectNetworkById containerd has no network of its own, the network is created by CNI, so set the links of the container down
+func (d *Client) DisconnectNetworkById(ctx context.Context, containerID string) ([]base.NetworkEndpoint, error) {
+	pid, err := d.GetPidById(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return base.DisconnectNetnsByPid(ctx, pid)
+}
+
+func (d *Client) ConnectNetworkById(ctx context.Context, containerID string, endpoints []base.NetworkEndpoint) error {
+	pid, err := d.GetPidById(ctx, containerID)
+	if err != nil {
+		return err
+	}
+
+	return base.ConnectNetnsByPid(ctx, pid, endpoints)
+}
+
+func (d *Client) RemountById(ctx context.Context, containerID, path string, readOnly bool) error {
+	pid, err := d.GetPidById(ctx, containerID)
+	if err != nil {
+		return err
+	}
+
+	return base.RemountByPid(ctx, pid, path, readOnly)
+}
+
+func (d *Client) GetMemoryLimitById(ctx context.Context, containerID string) (*base.MemoryLimit, error) {
+	container, err := d.client.LoadContainer(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("load container error: %s", err.Error())
+	}
+
+	spec, err := container.Spec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get spec of container error: %s", err.Error())
+	}
+
+	limit := &base.MemoryLimit{}
+	if spec.Linux != nil && spec.Linux.Resources != nil && spec.Linux.Resources.Memory != nil {
+		memory := spec.Linux.Resources.Memory
+		if memory.Limit != nil {
+			limit.Limit = *memory.Limit
+		}
+
+		if memory.Swap != nil {
+			limit.Swap = *memory.Swap
+		}
+	}
+
+	return limit, nil
+}
+
+func (d *Client) UpdateMemoryLimitById(ctx context.Context, containerID string, limit *base.MemoryLimit) error {
+	task, err := d.getContainerTask(ctx, containerID)
+	if err != nil {
+		return fmt.Errorf("get task of container error: %s", err.Error())
+	}
+
+	// -1 means no limit for runc
+	memLimit, swapLimit := limit.Limit, limit.Swap
+	if memLimit <= 0 {
+		memLimit = -1
+	}
+
+	if swapLimit <= 0 {
+		swapLimit = -1
+	}
+
+	return task.Update(ctx, containerd.WithResources(&specs.LinuxResources{
+		Memory: &specs.LinuxMemory{
+			Limit: &memLimit,
+			Swap:  &swapLimit,
+		},
+	}))
+}
@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
 	dockerClient "github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/system"
@@ -30,6 +32,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -38,6 +41,8 @@ const (
 	defaultSocket     = "unix:///var/run/docker.sock"
 	dockerVersionKey  = "DOCKER_API_VERSION"
 	defaultAPIVersion = "1.24"
+
+	defaultBridgeNetwork = "bridge"
 )
 
 type Client struct {
@@ -49,6 +54,12 @@ var (
 	mutex          sync.Mutex
 )
 
+// IsAvailable checks whether the socket of the runtime exists on the host
+func IsAvailable() bool {
+	_, err := os.Stat(strings.TrimPrefix(defaultSocket, "unix://"))
+	return err == nil
+}
+
 func GetClient(ctx context.Context) (d *Client, err error) {
 	defer func() {
 		if e := recover(); e != any(nil) {
@@ -254,3 +265,94 @@ func (d *Client) CpFile(ctx context.Context, containerID, src, dst string) error
 		AllowOverwriteDirWithFile: true,
 	})
 }
+
+func (d *Client) DisconnectNetworkById(ctx context.Context, containerID string) ([]base.NetworkEndpoint, error) {
+	info, err := d.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("get meta data of container[%s] error: %s", containerID, err.Error())
+	}
+
+	if info.HostConfig.NetworkMode.IsHost() || info.HostConfig.NetworkMode.IsNone() {
+		return nil, fmt.Errorf("not support network mode: %s", info.HostConfig.NetworkMode)
+	}
+
+	// the container shares the network of another container, such as the sandbox container of pod
+	if info.HostConfig.NetworkMode.IsContainer() || len(info.NetworkSettings.Networks) == 0 {
+		return base.DisconnectNetnsByPid(ctx, info.State.Pid)
+	}
+
+	var endpoints []base.NetworkEndpoint
+	for name, setting := range info.NetworkSettings.Networks {
+		if err := d.client.NetworkDisconnect(ctx, name, containerID, true); err != nil {
+			return endpoints, fmt.Errorf("disconnect network[%s] error: %s", name, err.Error())
+		}
+
+		endpoints = append(endpoints, base.NetworkEndpoint{Network: name, IPAddress: setting.IPAddress})
+	}
+
+	return endpoints, nil
+}
+
+func (d *Client) ConnectNetworkById(ctx context.Context, containerID string, endpoints []base.NetworkEndpoint) error {
+	var nsEndpoints []base.NetworkEndpoint
+	for _, endpoint := range endpoints {
+		if endpoint.Network == "" {
+			nsEndpoints = append(nsEndpoints, endpoint)
+			continue
+		}
+
+		setting := &network.EndpointSettings{}
+		// user specified ip is only supported in user defined network
+		if endpoint.IPAddress != "" && endpoint.Network != defaultBridgeNetwork {
+			setting.IPAMConfig = &network.EndpointIPAMConfig{IPv4Address: endpoint.IPAddress}
+		}
+
+		if err := d.client.NetworkConnect(ctx, endpoint.Network, containerID, setting); err != nil {
+			return fmt.Errorf("connect network[%s] error: %s", endpoint.Network, err.Error())
+		}
+	}
+
+	if len(nsEndpoints) == 0 {
+		return nil
+	}
+
+	pid, err := d.GetPidById(ctx, containerID)
+	if err != nil {
+		return err
+	}
+
+	return base.ConnectNetnsByPid(ctx, pid, nsEndpoints)
+}
+
+func (d *Client) RemountById(ctx context.Context, containerID, path string, readOnly bool) error {
+	pid, err := d.GetPidById(ctx, containerID)
+	if err != nil {
+		return err
+	}
+
+	return base.RemountByPid(ctx, pid, path, readOnly)
+}
+
+func (d *Client) GetMemoryLimitById(ctx context.Context, containerID string) (*base.MemoryLimit, error) {
+	info, err := d.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("get meta data of container[%s] error: %s", containerID, err.Error())
+	}
+
+	return &base.MemoryLimit{Limit: info.HostConfig.Memory, Swap: info.HostConfig.MemorySwap}, nil
+}
+
+func (d *Client) UpdateMemoryLimitById(ctx context.Context, containerID string, limit *base.MemoryLimit) error {
+	if limit.Limit <= 0 {
+		return fmt.Errorf("docker not support removing the memory limit of container")
+	}
+
+	_, err := d.client.ContainerUpdate(ctx, containerID, container.UpdateConfig{
+		Resources: container.Resources{
+			Memory:     limit.Limit,
+			MemorySwap: limit.Swap,
+		},
+	})
+
+	return err
+}
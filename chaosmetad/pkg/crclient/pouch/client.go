@@ -30,11 +30,17 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 )
 
 const (
 	defaultSocket = "unix:///var/run/pouchd.sock"
+
+	networkModeHost            = "host"
+	networkModeNone            = "none"
+	networkModeBridge          = "bridge"
+	networkModeContainerPrefix = "container:"
 )
 
 type Client struct {
@@ -46,6 +52,12 @@ var (
 	mutex          sync.Mutex
 )
 
+// IsAvailable checks whether the socket of the runtime exists on the host
+func IsAvailable() bool {
+	_, err := os.Stat(strings.TrimPrefix(defaultSocket, "unix://"))
+	return err == nil
+}
+
 func GetClient(ctx context.Context) (d *Client, err error) {
 	defer func() {
 		if e := recover(); e != any(nil) {
@@ -241,3 +253,96 @@ func (d *Client) CpFile(ctx context.Context, containerID, src, dst string) error
 
 	return d.client.CopyToContainer(ctx, containerID, resolvedDstPath, content)
 }
+
+func (d *Client) DisconnectNetworkById(ctx context.Context, containerID string) ([]base.NetworkEndpoint, error) {
+	info, err := d.client.ContainerGet(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("get meta data of container[%s] error: %s", containerID, err.Error())
+	}
+
+	networkMode := info.HostConfig.NetworkMode
+	if networkMode == networkModeHost || networkMode == networkModeNone {
+		return nil, fmt.Errorf("not support network mode: %s", networkMode)
+	}
+
+	// the container shares the network of another container, such as the sandbox container of pod
+	if strings.HasPrefix(networkMode, networkModeContainerPrefix) || info.NetworkSettings == nil || len(info.NetworkSettings.Networks) == 0 {
+		return base.DisconnectNetnsByPid(ctx, int(info.State.Pid))
+	}
+
+	var endpoints []base.NetworkEndpoint
+	for name, setting := range info.NetworkSettings.Networks {
+		if err := d.client.NetworkDisconnect(ctx, name, containerID, true); err != nil {
+			return endpoints, fmt.Errorf("disconnect network[%s] error: %s", name, err.Error())
+		}
+
+		endpoints = append(endpoints, base.NetworkEndpoint{Network: name, IPAddress: setting.IPAddress})
+	}
+
+	return endpoints, nil
+}
+
+func (d *Client) ConnectNetworkById(ctx context.Context, containerID string, endpoints []base.NetworkEndpoint) error {
+	var nsEndpoints []base.NetworkEndpoint
+	for _, endpoint := range endpoints {
+		if endpoint.Network == "" {
+			nsEndpoints = append(nsEndpoints, endpoint)
+			continue
+		}
+
+		setting := &types.EndpointSettings{}
+		// user specified ip is only supported in user defined network
+		if endpoint.IPAddress != "" && endpoint.Network != networkModeBridge {
+			setting.IPAMConfig = &types.EndpointIPAMConfig{IPV4Address: endpoint.IPAddress}
+		}
+
+		if err := d.client.NetworkConnect(ctx, endpoint.Network, &types.NetworkConnect{
+			Container:      containerID,
+			EndpointConfig: setting,
+		}); err != nil {
+			return fmt.Errorf("connect network[%s] error: %s", endpoint.Network, err.Error())
+		}
+	}
+
+	if len(nsEndpoints) == 0 {
+		return nil
+	}
+
+	pid, err := d.GetPidById(ctx, containerID)
+	if err != nil {
+		return err
+	}
+
+	return base.ConnectNetnsByPid(ctx, pid, nsEndpoints)
+}
+
+func (d *Client) RemountById(ctx context.Context, containerID, path string, readOnly bool) error {
+	pid, err := d.GetPidById(ctx, containerID)
+	if err != nil {
+		return err
+	}
+
+	return base.RemountByPid(ctx, pid, path, readOnly)
+}
+
+func (d *Client) GetMemoryLimitById(ctx context.Context, containerID string) (*base.MemoryLimit, error) {
+	info, err := d.client.ContainerGet(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("get meta data of container[%s] error: %s", containerID, err.Error())
+	}
+
+	return &base.MemoryLimit{Limit: info.HostConfig.Memory, Swap: info.HostConfig.MemorySwap}, nil
+}
+
+func (d *Client) UpdateMemoryLimitById(ctx context.Context, containerID string, limit *base.MemoryLimit) error {
+	if limit.Limit <= 0 {
+		return fmt.Errorf("pouch not support removing the memory limit of container")
+	}
+
+	return d.client.ContainerUpdate(ctx, containerID, &types.UpdateConfig{
+		Resources: types.Resources{
+			Memory:     limit.Limit,
+			MemorySwap: limit.Swap,
+		},
+	})
+}
@@ -24,5 +24,10 @@ const (
 	FaultContainerPause   = "pause"
 	FaultContainerRm      = "rm"
 
+	FaultContainerNetDisconnect = "netdisconnect"
+	FaultContainerRofs          = "rofs"
+	FaultContainerMemLimit      = "memlimit"
+
 	DefaultWaitTime = 10
+	DefaultRofsPath = "/"
 )
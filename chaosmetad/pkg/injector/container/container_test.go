@@ -0,0 +1,83 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package container
+
+import (
+	"context"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"testing"
+)
+
+func newBaseInjector(cr, cId string) injector.BaseInjector {
+	return injector.BaseInjector{Info: injector.BaseInfo{ContainerRuntime: cr, ContainerId: cId}}
+}
+
+func TestNetDisconnectInjector_Validator(t *testing.T) {
+	i := &NetDisconnectInjector{BaseInjector: newBaseInjector("", "")}
+	if err := i.Validator(context.Background()); err == nil {
+		t.Errorf("Validator() without container, want error")
+	}
+}
+
+func TestRofsInjector_Validator(t *testing.T) {
+	i := &RofsInjector{BaseInjector: newBaseInjector("", "")}
+	i.SetDefault()
+	if i.Args.Path != DefaultRofsPath {
+		t.Errorf("SetDefault() path = %s, want %s", i.Args.Path, DefaultRofsPath)
+	}
+
+	if err := i.Validator(context.Background()); err == nil {
+		t.Errorf("Validator() without container, want error")
+	}
+
+	i = &RofsInjector{BaseInjector: newBaseInjector("docker", "c1"), Args: RofsArgs{Path: "data"}}
+	if err := i.Validator(context.Background()); err == nil {
+		t.Errorf("Validator() with relative path, want error")
+	}
+}
+
+func TestMemLimitInjector_Validator(t *testing.T) {
+	tests := []struct {
+		name string
+		args MemLimitArgs
+	}{
+		{name: "empty", args: MemLimitArgs{}},
+		{name: "invalid limit", args: MemLimitArgs{Limit: "10XB"}},
+		{name: "percent too large", args: MemLimitArgs{Percent: 100}},
+		{name: "negative percent", args: MemLimitArgs{Percent: -10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &MemLimitInjector{BaseInjector: newBaseInjector("docker", "c1"), Args: tt.args}
+			if err := i.Validator(context.Background()); err == nil {
+				t.Errorf("Validator() with args %+v, want error", tt.args)
+			}
+		})
+	}
+}
+
+func TestMemLimitInjector_getNewLimit(t *testing.T) {
+	i := &MemLimitInjector{Args: MemLimitArgs{Percent: 25}}
+	if got, _ := i.getNewLimit(1024 * 1024 * 1024); got != 256*1024*1024 {
+		t.Errorf("getNewLimit() by percent = %d, want %d", got, 256*1024*1024)
+	}
+
+	i = &MemLimitInjector{Args: MemLimitArgs{Limit: "100MB", Percent: 25}}
+	if got, _ := i.getNewLimit(1024 * 1024 * 1024); got != 100*1024*1024 {
+		t.Errorf("getNewLimit() by limit = %d, want %d", got, 100*1024*1024)
+	}
+}
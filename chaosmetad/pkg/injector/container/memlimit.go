@@ -0,0 +1,140 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient/base"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+)
+
+func init() {
+	injector.Register(TargetContainer, FaultContainerMemLimit, func() injector.IInjector { return &MemLimitInjector{} })
+}
+
+type MemLimitInjector struct {
+	injector.BaseInjector
+	Args    MemLimitArgs
+	Runtime MemLimitRuntime
+}
+
+type MemLimitArgs struct {
+	Limit   string `json:"limit,omitempty"`
+	Percent int    `json:"percent,omitempty"`
+}
+
+type MemLimitRuntime struct {
+	OldLimit base.MemoryLimit `json:"old_limit"`
+}
+
+func (i *MemLimitInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *MemLimitInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *MemLimitInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&i.Args.Limit, "limit", "l", "", "new memory limit of the container, support unit: B/KB/MB/GB/TB（default B）")
+	cmd.Flags().IntVarP(&i.Args.Percent, "percent", "p", 0, "new memory limit as a percentage of the current limit, range: (0,100). if \"limit\" provided, \"percent\" will be ignored")
+}
+
+func (i *MemLimitInjector) Validator(ctx context.Context) error {
+	if i.Info.ContainerRuntime == "" || i.Info.ContainerId == "" {
+		return fmt.Errorf("please provide container runtime and id")
+	}
+
+	if i.Args.Limit == "" && i.Args.Percent == 0 {
+		return fmt.Errorf("must provide \"limit\" or \"percent\"")
+	}
+
+	if i.Args.Limit != "" {
+		if _, err := utils.GetBytes(i.Args.Limit); err != nil {
+			return fmt.Errorf("\"limit\" is invalid: %s", err.Error())
+		}
+	} else if i.Args.Percent <= 0 || i.Args.Percent >= 100 {
+		return fmt.Errorf("\"percent\" must in (0,100)")
+	}
+
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	client, err := crclient.GetClient(ctx, i.Info.ContainerRuntime)
+	if err != nil {
+		return fmt.Errorf("get %s client error: %s", i.Info.ContainerRuntime, err.Error())
+	}
+
+	oldLimit, err := client.GetMemoryLimitById(ctx, i.Info.ContainerId)
+	if err != nil {
+		return fmt.Errorf("get memory limit of container error: %s", err.Error())
+	}
+
+	if oldLimit.Limit <= 0 {
+		return fmt.Errorf("container has no memory limit, can not be restored after the limit is lowered")
+	}
+
+	newLimit, _ := i.getNewLimit(oldLimit.Limit)
+	if newLimit <= 0 || newLimit >= oldLimit.Limit {
+		return fmt.Errorf("new memory limit[%d] must be larger than 0 and less than the current limit[%d]", newLimit, oldLimit.Limit)
+	}
+
+	return nil
+}
+
+func (i *MemLimitInjector) getNewLimit(oldLimit int64) (int64, error) {
+	if i.Args.Limit != "" {
+		return utils.GetBytes(i.Args.Limit)
+	}
+
+	return oldLimit * int64(i.Args.Percent) / 100, nil
+}
+
+func (i *MemLimitInjector) Inject(ctx context.Context) error {
+	client, err := crclient.GetClient(ctx, i.Info.ContainerRuntime)
+	if err != nil {
+		return fmt.Errorf("get %s client error: %s", i.Info.ContainerRuntime, err.Error())
+	}
+
+	oldLimit, err := client.GetMemoryLimitById(ctx, i.Info.ContainerId)
+	if err != nil {
+		return fmt.Errorf("get memory limit of container error: %s", err.Error())
+	}
+	i.Runtime.OldLimit = *oldLimit
+
+	newLimit, _ := i.getNewLimit(oldLimit.Limit)
+	// swap is limited to the same value, so that the container is killed by OOM instead of swapping
+	return client.UpdateMemoryLimitById(ctx, i.Info.ContainerId, &base.MemoryLimit{Limit: newLimit, Swap: newLimit})
+}
+
+func (i *MemLimitInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	client, err := crclient.GetClient(ctx, i.Info.ContainerRuntime)
+	if err != nil {
+		return fmt.Errorf("get %s client error: %s", i.Info.ContainerRuntime, err.Error())
+	}
+
+	return client.UpdateMemoryLimitById(ctx, i.Info.ContainerId, &i.Runtime.OldLimit)
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient/base"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+)
+
+func init() {
+	injector.Register(TargetContainer, FaultContainerNetDisconnect, func() injector.IInjector { return &NetDisconnectInjector{} })
+}
+
+type NetDisconnectInjector struct {
+	injector.BaseInjector
+	Args    NetDisconnectArgs
+	Runtime NetDisconnectRuntime
+}
+
+type NetDisconnectArgs struct {
+}
+
+type NetDisconnectRuntime struct {
+	Endpoints []base.NetworkEndpoint `json:"endpoints"`
+}
+
+func (i *NetDisconnectInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *NetDisconnectInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *NetDisconnectInjector) SetOption(cmd *cobra.Command) {
+}
+
+func (i *NetDisconnectInjector) Validator(ctx context.Context) error {
+	if i.Info.ContainerRuntime == "" || i.Info.ContainerId == "" {
+		return fmt.Errorf("please provide container runtime and id")
+	}
+
+	return i.BaseInjector.Validator(ctx)
+}
+
+func (i *NetDisconnectInjector) Inject(ctx context.Context) error {
+	client, err := crclient.GetClient(ctx, i.Info.ContainerRuntime)
+	if err != nil {
+		return fmt.Errorf("get %s client error: %s", i.Info.ContainerRuntime, err.Error())
+	}
+
+	i.Runtime.Endpoints, err = client.DisconnectNetworkById(ctx, i.Info.ContainerId)
+	if err != nil {
+		// undo the endpoints which have been disconnected
+		if len(i.Runtime.Endpoints) > 0 {
+			if rErr := client.ConnectNetworkById(ctx, i.Info.ContainerId, i.Runtime.Endpoints); rErr != nil {
+				log.GetLogger(ctx).Warnf("undo error: %s", rErr.Error())
+			}
+		}
+
+		return fmt.Errorf("disconnect network error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (i *NetDisconnectInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	client, err := crclient.GetClient(ctx, i.Info.ContainerRuntime)
+	if err != nil {
+		return fmt.Errorf("get %s client error: %s", i.Info.ContainerRuntime, err.Error())
+	}
+
+	return client.ConnectNetworkById(ctx, i.Info.ContainerId, i.Runtime.Endpoints)
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient/base"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"path/filepath"
+)
+
+func init() {
+	injector.Register(TargetContainer, FaultContainerRofs, func() injector.IInjector { return &RofsInjector{} })
+}
+
+type RofsInjector struct {
+	injector.BaseInjector
+	Args    RofsArgs
+	Runtime RofsRuntime
+}
+
+type RofsArgs struct {
+	Path string `json:"path"`
+}
+
+type RofsRuntime struct {
+	// ReadOnly is the mount flag before injection, which is restored when recovering
+	ReadOnly bool `json:"read_only"`
+}
+
+func (i *RofsInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *RofsInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *RofsInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Path == "" {
+		i.Args.Path = DefaultRofsPath
+	}
+}
+
+func (i *RofsInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&i.Args.Path, "path", "p", "", fmt.Sprintf("the mount point in container to remount read-only, the rootfs or a volume (default %s)", DefaultRofsPath))
+}
+
+func (i *RofsInjector) Validator(ctx context.Context) error {
+	if i.Info.ContainerRuntime == "" || i.Info.ContainerId == "" {
+		return fmt.Errorf("please provide container runtime and id")
+	}
+
+	if !filepath.IsAbs(i.Args.Path) {
+		return fmt.Errorf("\"path\" must be an absolute path")
+	}
+
+	i.Args.Path = filepath.Clean(i.Args.Path)
+	return i.BaseInjector.Validator(ctx)
+}
+
+func (i *RofsInjector) Inject(ctx context.Context) error {
+	client, err := crclient.GetClient(ctx, i.Info.ContainerRuntime)
+	if err != nil {
+		return fmt.Errorf("get %s client error: %s", i.Info.ContainerRuntime, err.Error())
+	}
+
+	pid, err := client.GetPidById(ctx, i.Info.ContainerId)
+	if err != nil {
+		return fmt.Errorf("get pid of container error: %s", err.Error())
+	}
+
+	i.Runtime.ReadOnly, err = base.IsReadOnlyByPid(pid, i.Args.Path)
+	if err != nil {
+		return fmt.Errorf("get mount flag of %s error: %s", i.Args.Path, err.Error())
+	}
+
+	return client.RemountById(ctx, i.Info.ContainerId, i.Args.Path, true)
+}
+
+func (i *RofsInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	client, err := crclient.GetClient(ctx, i.Info.ContainerRuntime)
+	if err != nil {
+		return fmt.Errorf("get %s client error: %s", i.Info.ContainerRuntime, err.Error())
+	}
+
+	return client.RemountById(ctx, i.Info.ContainerId, i.Args.Path, i.Runtime.ReadOnly)
+}
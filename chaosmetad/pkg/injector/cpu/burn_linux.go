@@ -82,38 +82,13 @@ func (i *BurnInjector) Validator(ctx context.Context) error {
 		return fmt.Errorf("\"percent\"[%d] must be in (0,100]", i.Args.Percent)
 	}
 
-	cpuList, err := getAllCpuList(ctx, i.Info.ContainerRuntime, i.Info.ContainerId)
+	cpuList, err := cgroup.GetAvailableCpuList(ctx, i.Info.ContainerRuntime, i.Info.ContainerId)
 	if err != nil {
 		return fmt.Errorf("get all available cpu list error: %s", err.Error())
 	}
 
-	if i.Args.List != "" {
-		targetList, err := utils.GetNumArrByList(i.Args.List)
-		if err != nil {
-			return fmt.Errorf("\"list\"[%s] is not valid: %s", i.Args.List, err.Error())
-		}
-
-		for _, core := range targetList {
-			var exist bool
-			for _, availCore := range cpuList {
-				if availCore == core {
-					exist = true
-					break
-				}
-			}
-
-			if !exist {
-				return fmt.Errorf("\"core\"[%d] is not available", core)
-			}
-		}
-	} else {
-		if i.Args.Count == 0 || i.Args.Count > len(cpuList) {
-			i.Args.Count = len(cpuList)
-		}
-
-		if i.Args.Count < 0 {
-			return fmt.Errorf("\"count\"[%d] can not less than 0", i.Args.Count)
-		}
+	if _, err := utils.GetTargetCoreList(i.Args.List, i.Args.Count, cpuList); err != nil {
+		return err
 	}
 
 	if !cmdexec.SupportCmd("taskset") {
@@ -126,13 +101,14 @@ func (i *BurnInjector) Validator(ctx context.Context) error {
 func (i *BurnInjector) Inject(ctx context.Context) error {
 	logger := log.GetLogger(ctx)
 
-	var coreList []int
-	cpuList, _ := getAllCpuList(ctx, i.Info.ContainerRuntime, i.Info.ContainerId)
+	cpuList, err := cgroup.GetAvailableCpuList(ctx, i.Info.ContainerRuntime, i.Info.ContainerId)
+	if err != nil {
+		return fmt.Errorf("get all available cpu list error: %s", err.Error())
+	}
 
-	if i.Args.List != "" {
-		coreList, _ = utils.GetNumArrByList(i.Args.List)
-	} else {
-		coreList = utils.GetNumArrByCount(i.Args.Count, cpuList)
+	coreList, err := utils.GetTargetCoreList(i.Args.List, i.Args.Count, cpuList)
+	if err != nil {
+		return err
 	}
 
 	logger.Debugf("burn core list: %v", coreList)
@@ -161,47 +137,35 @@ func (i *BurnInjector) Inject(ctx context.Context) error {
 	return nil
 }
 
-func (i *BurnInjector) Recover(ctx context.Context) error {
-	if i.BaseInjector.Recover(ctx) == nil {
-		return nil
+func (i *BurnInjector) CheckRampValue(value int) error {
+	if value <= 0 || value > 100 {
+		return fmt.Errorf("percent[%d] must be in (0,100]", value)
 	}
 
-	return process.CheckExistAndKillByKey(ctx, fmt.Sprintf("%s %s", CpuBurnKey, i.Info.Uid))
+	return nil
 }
 
-//func (i *BurnInjector) DelayRecover(ctx context.Context, timeout int64) error {
-//	return nil
-//}
+func (i *BurnInjector) SetRampValue(value int) {
+	i.Args.Percent = value
+}
 
-func getAllCpuList(ctx context.Context, cr, cId string) (cpuList []int, err error) {
-	var cpusetPath = "/"
-	if cr != "" {
-		cpusetPath, err = cgroup.GetContainerCgroupPath(ctx, cr, cId, cgroup.CPUSET)
-		if err != nil {
-			return nil, fmt.Errorf("get cgroup[%s] path of container[%s] error: %s", cgroup.CPUSET, cId, err.Error())
-		}
+// Retune restarts the burn workers with the new percent
+func (i *BurnInjector) Retune(ctx context.Context) error {
+	if err := process.CheckExistAndKillByKey(ctx, fmt.Sprintf("%s %s", CpuBurnKey, i.Info.Uid)); err != nil {
+		return fmt.Errorf("stop old burn workers error: %s", err.Error())
 	}
 
-	return getCpuList(ctx, cpusetPath)
+	return i.Inject(ctx)
 }
 
-func getCpuList(ctx context.Context, path string) ([]int, error) {
-	//cpusetFile := fmt.Sprintf("%s/%s%s/%s", containercgroup.RootCgroupPath, cgroup.CPUSET, path, cgroup.CpusetCoreFile)
-	//reByte, err := os.ReadFile(cpusetFile)
-	//if err != nil {
-	//	return nil, fmt.Errorf("read cpu list info from file[%s] error: %s", cpusetFile, err.Error())
-	//}
-	//
-	//cpuListStr := string(reByte)
-	cpuListStr, err := cgroup.ReadCgroupFileStr(ctx, path, cgroup.CPUSET, cgroup.CpusetCoreFile)
-	if err != nil {
-		return nil, fmt.Errorf("read cpu list string error: %s", err.Error())
-	}
-
-	cpuList, err := utils.GetNumArrByList(cpuListStr)
-	if err != nil {
-		return nil, fmt.Errorf("format cpu list string error: %s", err.Error())
+func (i *BurnInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
 	}
 
-	return cpuList, nil
+	return process.CheckExistAndKillByKey(ctx, fmt.Sprintf("%s %s", CpuBurnKey, i.Info.Uid))
 }
+
+//func (i *BurnInjector) DelayRecover(ctx context.Context, timeout int64) error {
+//	return nil
+//}
@@ -24,4 +24,11 @@ const (
 
 	FaultCpuLoad = "load"
 	CpuLoadKey   = "chaosmeta_cpuload"
+
+	FaultCpuOffline = "offline"
+	CpuSysPath      = "/sys/devices/system/cpu"
+	CpuOnlineFile   = "online"
+
+	FaultCpuContention     = "contention"
+	DefaultContentionShare = 1024
 )
@@ -0,0 +1,234 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cpu
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cgroup"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/containercgroup"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+)
+
+func init() {
+	injector.Register(TargetCpu, FaultCpuContention, func() injector.IInjector { return &ContentionInjector{} })
+}
+
+// ContentionInjector runs full-speed workers in the cpu cgroup of the target, so that the target has to wait for
+// the cpu instead of only seeing its usage rise. The share of each worker is a scheduler weight, 1024 equals a task
+// with nice 0, it is converted to the nearest nice value.
+type ContentionInjector struct {
+	injector.BaseInjector
+	Args    ContentionArgs
+	Runtime ContentionRuntime
+}
+
+type ContentionArgs struct {
+	Pid   int    `json:"pid,omitempty"`
+	Share int    `json:"share,omitempty"`
+	Count int    `json:"count,omitempty"`
+	List  string `json:"list,omitempty"`
+}
+
+type ContentionRuntime struct {
+}
+
+// weightList is the "sched_prio_to_weight" of the kernel, index 0 is nice -20
+var weightList = []int{
+	88761, 71755, 56483, 46273, 36291,
+	29154, 23254, 18705, 14949, 11916,
+	9548, 7620, 6100, 4904, 3906,
+	3121, 2501, 1991, 1586, 1277,
+	1024, 820, 655, 526, 423,
+	335, 272, 215, 172, 137,
+	110, 87, 70, 56, 45,
+	36, 29, 23, 18, 15,
+}
+
+func (i *ContentionInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *ContentionInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *ContentionInjector) getCmdExecutor() *cmdexec.CmdExecutor {
+	return &cmdexec.CmdExecutor{
+		ContainerId:      i.Info.ContainerId,
+		ContainerRuntime: i.Info.ContainerRuntime,
+		ContainerNs:      []string{namespace.PID},
+	}
+}
+
+func (i *ContentionInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Share == 0 {
+		i.Args.Share = DefaultContentionShare
+	}
+}
+
+func (i *ContentionInjector) SetOption(cmd *cobra.Command) {
+	// i.BaseInjector.SetOption(cmd)
+
+	cmd.Flags().IntVarP(&i.Args.Pid, "pid", "p", 0, "the workers join the cpu cgroup of the process. if attack a container, the workers join the container's cgroup")
+	cmd.Flags().IntVarP(&i.Args.Share, "share", "s", 0, fmt.Sprintf("scheduler weight of each worker, %d equals a task with nice 0, in [%d,%d]（default %d）", DefaultContentionShare, weightList[len(weightList)-1], weightList[0], DefaultContentionShare))
+	cmd.Flags().StringVarP(&i.Args.List, "list", "l", "", "core number list to run the workers, start from 0, eg: \"0-2,6\" means \"0,1,2,6\" core")
+	cmd.Flags().IntVarP(&i.Args.Count, "count", "c", 0, "core count to run the workers（default 0, means all available core）. if provide args \"list\", \"count\" will be ignored.")
+}
+
+// Validator list > count
+func (i *ContentionInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Share < weightList[len(weightList)-1] || i.Args.Share > weightList[0] {
+		return fmt.Errorf("\"share\"[%d] must be in [%d,%d]", i.Args.Share, weightList[len(weightList)-1], weightList[0])
+	}
+
+	if i.Info.ContainerRuntime == "" {
+		if i.Args.Pid <= 0 {
+			return fmt.Errorf("must provide \"pid\" or attack a container")
+		}
+
+		exist, err := process.ExistPid(ctx, i.Args.Pid)
+		if err != nil {
+			return fmt.Errorf("check pid[%d] exist error: %s", i.Args.Pid, err.Error())
+		}
+
+		if !exist {
+			return fmt.Errorf("pid[%d] is not exist", i.Args.Pid)
+		}
+	}
+
+	cpuList, err := i.getCpuList(ctx)
+	if err != nil {
+		return fmt.Errorf("get all available cpu list error: %s", err.Error())
+	}
+
+	if _, err := utils.GetTargetCoreList(i.Args.List, i.Args.Count, cpuList); err != nil {
+		return err
+	}
+
+	if !cmdexec.SupportCmd("taskset") {
+		return fmt.Errorf("not support cmd \"taskset\"")
+	}
+
+	return nil
+}
+
+// getCpuList returns the cpuset of the target, the workers out of it do not compete with the target
+func (i *ContentionInjector) getCpuList(ctx context.Context) ([]int, error) {
+	if i.Info.ContainerRuntime != "" {
+		return cgroup.GetAvailableCpuList(ctx, i.Info.ContainerRuntime, i.Info.ContainerId)
+	}
+
+	cpusetPath, err := cgroup.GetPidCgroupPath(ctx, i.Args.Pid, cgroup.CPUSET)
+	if err != nil {
+		return nil, fmt.Errorf("get cpuset cgroup of process[%d] error: %s", i.Args.Pid, err.Error())
+	}
+
+	return cgroup.GetCpusetCpuList(ctx, cpusetPath)
+}
+
+func (i *ContentionInjector) Inject(ctx context.Context) error {
+	logger := log.GetLogger(ctx)
+
+	cpuList, err := i.getCpuList(ctx)
+	if err != nil {
+		return fmt.Errorf("get all available cpu list error: %s", err.Error())
+	}
+
+	coreList, err := utils.GetTargetCoreList(i.Args.List, i.Args.Count, cpuList)
+	if err != nil {
+		return err
+	}
+
+	nice := getNiceByWeight(i.Args.Share)
+	logger.Debugf("contention core list: %v, nice: %d", coreList, nice)
+
+	var timeout int64
+	if i.Info.Timeout != "" {
+		timeout, _ = utils.GetTimeSecond(i.Info.Timeout)
+	}
+
+	// the container exec process joins the cgroups of the container itself, a host process is moved by the shell before exec
+	var procsFile string
+	if i.Info.ContainerRuntime == "" {
+		cgroupPath, err := cgroup.GetPidCgroupPath(ctx, i.Args.Pid, cgroup.CPU)
+		if err != nil {
+			return fmt.Errorf("get cpu cgroup of process[%d] error: %s", i.Args.Pid, err.Error())
+		}
+
+		procsFile = fmt.Sprintf("%s/%s%s/%s", containercgroup.RootCgroupPath, cgroup.GetSubSys(cgroup.CPU), cgroupPath, cgroup.CgroupProcsFile)
+	}
+
+	e := i.getCmdExecutor()
+	for _, core := range coreList {
+		// percent 100 makes the burn worker run at full speed without adjusting
+		cmd := fmt.Sprintf("taskset -c %d nice -n %d %s %s %d 100 0 %d", core, nice, utils.GetToolPath(CpuBurnKey), i.Info.Uid, core, timeout)
+		var err error
+		if procsFile != "" {
+			_, err = cmdexec.StartBashCmdAndWaitPid(ctx, fmt.Sprintf("echo $$ > %s && exec %s", procsFile, cmd), 0)
+		} else {
+			err = e.StartCmdAndWait(ctx, cmd)
+		}
+
+		if err != nil {
+			if err := i.Recover(ctx); err != nil {
+				logger.Warnf("undo error: %s", err.Error())
+			}
+
+			return fmt.Errorf("start contention worker on core[%d] error: %s", core, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (i *ContentionInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return process.CheckExistAndKillByKey(ctx, fmt.Sprintf("%s %s", CpuBurnKey, i.Info.Uid))
+}
+
+// getNiceByWeight returns the nice value whose weight is the nearest to the given one
+func getNiceByWeight(weight int) int {
+	var index, minDiff = 0, -1
+	for j, w := range weightList {
+		diff := w - weight
+		if diff < 0 {
+			diff = -diff
+		}
+
+		if minDiff < 0 || diff < minDiff {
+			index, minDiff = j, diff
+		}
+	}
+
+	return index - 20
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cpu
+
+import (
+	"context"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeCpuset(t *testing.T, root, path, cpus string) {
+	dir := filepath.Join(root, path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("mkdir %s error: %s", dir, err.Error())
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "cpuset.cpus"), []byte(cpus+"\n"), 0644); err != nil {
+		t.Fatalf("write cpuset of %s error: %s", dir, err.Error())
+	}
+}
+
+func readCpuset(t *testing.T, root, path string) string {
+	reByte, err := os.ReadFile(filepath.Join(root, path, "cpuset.cpus"))
+	if err != nil {
+		t.Fatalf("read cpuset of %s error: %s", path, err.Error())
+	}
+
+	return strings.TrimSpace(string(reByte))
+}
+
+func TestOfflineCpusets(t *testing.T) {
+	root := t.TempDir()
+	writeCpuset(t, root, "", "0-3")
+	writeCpuset(t, root, "/kubepods", "0-3")
+	writeCpuset(t, root, "/kubepods/pod1", "2-3")
+	writeCpuset(t, root, "/kubepods/pod2", "0-1")
+	writeCpuset(t, root, "/empty", "")
+
+	cpusets, err := getCpusetsByCores(root, []int{3})
+	if err != nil {
+		t.Fatalf("getCpusetsByCores() error: %s", err.Error())
+	}
+
+	want := map[string]string{"/kubepods": "0-3", "/kubepods/pod1": "2-3"}
+	if !reflect.DeepEqual(cpusets, want) {
+		t.Fatalf("getCpusetsByCores() = %v, want %v", cpusets, want)
+	}
+
+	// what the kernel does when core 3 is offline
+	writeCpuset(t, root, "/kubepods", "0-2")
+	writeCpuset(t, root, "/kubepods/pod1", "2")
+	if err := restoreCpusets(root, cpusets); err != nil {
+		t.Fatalf("restoreCpusets() error: %s", err.Error())
+	}
+
+	for path, cpus := range want {
+		if got := readCpuset(t, root, path); got != cpus {
+			t.Errorf("cpuset of %s = %s, want %s", path, got, cpus)
+		}
+	}
+
+	if got := readCpuset(t, root, "/kubepods/pod2"); got != "0-1" {
+		t.Errorf("cpuset of not affected cgroup is changed: %s", got)
+	}
+
+	// the pod is deleted before recover
+	if err := os.RemoveAll(filepath.Join(root, "/kubepods/pod1")); err != nil {
+		t.Fatalf("remove cgroup error: %s", err.Error())
+	}
+
+	if err := restoreCpusets(root, cpusets); err != nil {
+		t.Errorf("restoreCpusets() with removed cgroup error: %s", err.Error())
+	}
+}
+
+func TestOfflineInjector_Validator(t *testing.T) {
+	i := &OfflineInjector{BaseInjector: injector.BaseInjector{Info: injector.BaseInfo{Uid: "test-uid", ContainerId: "c1"}}}
+	if err := i.Validator(context.Background()); err == nil || !strings.Contains(err.Error(), "not support in container") {
+		t.Errorf("Validator() in container, error = %v", err)
+	}
+}
+
+func TestContentionInjector_Validator(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    ContentionArgs
+		wantErr string
+	}{
+		{name: "share too small", args: ContentionArgs{Share: 1, Pid: 1}, wantErr: "\"share\""},
+		{name: "share too large", args: ContentionArgs{Share: 100000, Pid: 1}, wantErr: "\"share\""},
+		{name: "no target", args: ContentionArgs{}, wantErr: "must provide"},
+		{name: "pid not exist", args: ContentionArgs{Pid: 1 << 30}, wantErr: "not exist"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &ContentionInjector{BaseInjector: injector.BaseInjector{Info: injector.BaseInfo{Uid: "test-uid"}}, Args: tt.args}
+			i.SetDefault()
+			err := i.Validator(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validator() error = %v, want contains %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetNiceByWeight(t *testing.T) {
+	tests := []struct {
+		weight int
+		want   int
+	}{
+		{weight: 1024, want: 0},
+		{weight: 88761, want: -20},
+		{weight: 15, want: 19},
+		{weight: 1000, want: 0},
+		{weight: 2000, want: -3},
+	}
+	for _, tt := range tests {
+		if got := getNiceByWeight(tt.weight); got != tt.want {
+			t.Errorf("getNiceByWeight(%d) = %d, want %d", tt.weight, got, tt.want)
+		}
+	}
+}
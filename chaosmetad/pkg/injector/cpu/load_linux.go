@@ -23,6 +23,7 @@ import (
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cgroup"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
@@ -73,7 +74,7 @@ func (i *LoadInjector) Validator(ctx context.Context) error {
 		return err
 	}
 
-	cpuList, err := getAllCpuList(ctx, i.Info.ContainerRuntime, i.Info.ContainerId)
+	cpuList, err := cgroup.GetAvailableCpuList(ctx, i.Info.ContainerRuntime, i.Info.ContainerId)
 	if err != nil {
 		return fmt.Errorf("get all available cpu list error: %s", err.Error())
 	}
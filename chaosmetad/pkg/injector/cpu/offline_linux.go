@@ -0,0 +1,303 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cpu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cgroup"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/containercgroup"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func init() {
+	injector.Register(TargetCpu, FaultCpuOffline, func() injector.IInjector { return &OfflineInjector{} })
+}
+
+// OfflineInjector hot-unplugs cores of the host through sysfs, the cores which do not support hotplug(usually core 0) can not be the target.
+// on cgroup v1, the kernel removes the offline cores from "cpuset.cpus" of every cpuset and does not add them back when
+// they are online again, so the affected cpusets are saved before inject and restored in recover
+type OfflineInjector struct {
+	injector.BaseInjector
+	Args    OfflineArgs
+	Runtime OfflineRuntime
+}
+
+type OfflineArgs struct {
+	Count int    `json:"count,omitempty"`
+	List  string `json:"list,omitempty"`
+}
+
+type OfflineRuntime struct {
+	CoreList []int `json:"core_list,omitempty"`
+	// Cpusets is "cpuset.cpus" of the cpuset cgroups containing the target cores, key is the path relative to the hierarchy root
+	Cpusets map[string]string `json:"cpusets,omitempty"`
+}
+
+func (i *OfflineInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *OfflineInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *OfflineInjector) SetOption(cmd *cobra.Command) {
+	// i.BaseInjector.SetOption(cmd)
+
+	cmd.Flags().StringVarP(&i.Args.List, "list", "l", "", "core number list to offline, eg: \"1-2,6\" means \"1,2,6\" core")
+	cmd.Flags().IntVarP(&i.Args.Count, "count", "c", 0, "count of cores to offline, the cores with larger number are chosen first. if provide args \"list\", \"count\" will be ignored.")
+}
+
+// Validator list > count
+func (i *OfflineInjector) Validator(ctx context.Context) error {
+	if i.Info.ContainerId != "" || i.Info.ContainerRuntime != "" {
+		return fmt.Errorf("fault \"%s\" not support in container", FaultCpuOffline)
+	}
+
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	onlineList, err := getOnlineCpuList()
+	if err != nil {
+		return fmt.Errorf("get online cpu list error: %s", err.Error())
+	}
+
+	pluggableList := getPluggableCpuList(onlineList)
+	if i.Args.List != "" {
+		targetList, err := utils.GetNumArrByList(i.Args.List)
+		if err != nil {
+			return fmt.Errorf("\"list\"[%s] is not valid: %s", i.Args.List, err.Error())
+		}
+
+		for _, core := range targetList {
+			var exist bool
+			for _, pluggableCore := range pluggableList {
+				if pluggableCore == core {
+					exist = true
+					break
+				}
+			}
+
+			if !exist {
+				return fmt.Errorf("\"core\"[%d] is not online or not support hotplug", core)
+			}
+		}
+
+		if len(targetList) >= len(onlineList) {
+			return fmt.Errorf("can not offline all online cores")
+		}
+	} else {
+		if i.Args.Count <= 0 {
+			return fmt.Errorf("must provide \"list\" or \"count\"")
+		}
+
+		if i.Args.Count >= len(onlineList) {
+			return fmt.Errorf("\"count\"[%d] must be less than online core count[%d]", i.Args.Count, len(onlineList))
+		}
+
+		if i.Args.Count > len(pluggableList) {
+			return fmt.Errorf("\"count\"[%d] is larger than the count of cores support hotplug[%d]", i.Args.Count, len(pluggableList))
+		}
+	}
+
+	return nil
+}
+
+func (i *OfflineInjector) Inject(ctx context.Context) error {
+	logger := log.GetLogger(ctx)
+
+	var coreList []int
+	if i.Args.List != "" {
+		coreList, _ = utils.GetNumArrByList(i.Args.List)
+	} else {
+		onlineList, _ := getOnlineCpuList()
+		pluggableList := getPluggableCpuList(onlineList)
+		sort.Sort(sort.Reverse(sort.IntSlice(pluggableList)))
+		coreList = utils.GetNumArrByCount(i.Args.Count, pluggableList)
+	}
+
+	logger.Debugf("offline core list: %v", coreList)
+
+	if !cgroup.IsCgroupV2() {
+		cpusets, err := getCpusetsByCores(getCpusetRoot(), coreList)
+		if err != nil {
+			return fmt.Errorf("save cpusets of target cores error: %s", err.Error())
+		}
+
+		i.Runtime.Cpusets = cpusets
+	}
+
+	for _, core := range coreList {
+		if err := setCpuOnline(core, false); err != nil {
+			if err := i.Recover(ctx); err != nil {
+				logger.Warnf("undo error: %s", err.Error())
+			}
+
+			return fmt.Errorf("offline core[%d] error: %s", core, err.Error())
+		}
+
+		i.Runtime.CoreList = append(i.Runtime.CoreList, core)
+	}
+
+	return nil
+}
+
+func (i *OfflineInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	var errMsg string
+	for _, core := range i.Runtime.CoreList {
+		if err := setCpuOnline(core, true); err != nil {
+			errMsg += fmt.Sprintf("online core[%d] error: %s. ", core, err.Error())
+		}
+	}
+
+	// the cores must be online before they are written back to the cpusets
+	if err := restoreCpusets(getCpusetRoot(), i.Runtime.Cpusets); err != nil {
+		errMsg += fmt.Sprintf("restore cpusets error: %s. ", err.Error())
+	}
+
+	if errMsg != "" {
+		return errors.New(errMsg)
+	}
+
+	return nil
+}
+
+func getOnlineCpuList() ([]int, error) {
+	reByte, err := os.ReadFile(fmt.Sprintf("%s/%s", CpuSysPath, CpuOnlineFile))
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.GetNumArrByList(strings.TrimSpace(string(reByte)))
+}
+
+// getPluggableCpuList the core which supports hotplug has an "online" file
+func getPluggableCpuList(onlineList []int) []int {
+	var re []int
+	for _, core := range onlineList {
+		if _, err := os.Stat(getCpuOnlineFile(core)); err == nil {
+			re = append(re, core)
+		}
+	}
+
+	return re
+}
+
+func getCpuOnlineFile(core int) string {
+	return fmt.Sprintf("%s/cpu%d/%s", CpuSysPath, core, CpuOnlineFile)
+}
+
+func setCpuOnline(core int, online bool) error {
+	var value = "0"
+	if online {
+		value = "1"
+	}
+
+	return os.WriteFile(getCpuOnlineFile(core), []byte(value), 0644)
+}
+
+func getCpusetRoot() string {
+	return fmt.Sprintf("%s/%s", containercgroup.RootCgroupPath, cgroup.CPUSET)
+}
+
+// getCpusetsByCores returns "cpuset.cpus" of the cgroups under root which contain any of the cores, the root cgroup is
+// excluded because the kernel maintains it
+func getCpusetsByCores(root string, coreList []int) (map[string]string, error) {
+	var coreMap = make(map[int]bool)
+	for _, core := range coreList {
+		coreMap[core] = true
+	}
+
+	cpusets := make(map[string]string)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() || path == root {
+			return nil
+		}
+
+		reByte, err := os.ReadFile(filepath.Join(path, cgroup.CpusetCoreFile))
+		if err != nil {
+			return fmt.Errorf("read cpuset of %s error: %s", path, err.Error())
+		}
+
+		cpusStr := strings.TrimSpace(string(reByte))
+		if cpusStr == "" {
+			return nil
+		}
+
+		cpuList, err := utils.GetNumArrByList(cpusStr)
+		if err != nil {
+			return fmt.Errorf("cpuset[%s] of %s is invalid: %s", cpusStr, path, err.Error())
+		}
+
+		for _, core := range cpuList {
+			if coreMap[core] {
+				cpusets[strings.TrimPrefix(path, root)] = cpusStr
+				break
+			}
+		}
+
+		return nil
+	})
+
+	return cpusets, err
+}
+
+// restoreCpusets writes back "cpuset.cpus", the parents are written before the children because a child can not have
+// the cores its parent does not have. the cgroups removed after inject are ignored
+func restoreCpusets(root string, cpusets map[string]string) error {
+	var pathList []string
+	for path := range cpusets {
+		pathList = append(pathList, path)
+	}
+
+	sort.Slice(pathList, func(i, j int) bool {
+		return strings.Count(pathList[i], "/") < strings.Count(pathList[j], "/")
+	})
+
+	var errMsg string
+	for _, path := range pathList {
+		cpusetFile := filepath.Join(root, path, cgroup.CpusetCoreFile)
+		if err := os.WriteFile(cpusetFile, []byte(cpusets[path]), 0644); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errMsg += fmt.Sprintf("write %s to %s error: %s. ", cpusets[path], cpusetFile, err.Error())
+		}
+	}
+
+	if errMsg != "" {
+		return errors.New(errMsg)
+	}
+
+	return nil
+}
@@ -37,5 +37,11 @@ const (
 
 	TmpCgroup = "/user.slice"
 
+	FaultDiskIODelay = "delay"
+	FaultDiskIOError = "error"
+	DMNamePrefix     = "chaosmeta-"
+	DMOriginSuffix   = "-origin"
+	DefaultDown      = "1s"
+
 	DiskIOExec = "chaosmeta_diskio"
 )
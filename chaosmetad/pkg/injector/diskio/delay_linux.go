@@ -0,0 +1,113 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diskio
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/dm"
+	"time"
+)
+
+func init() {
+	injector.Register(TargetDiskIO, FaultDiskIODelay, func() injector.IInjector { return &DelayInjector{} })
+}
+
+// DelayInjector delays every I/O of the device by dm-delay
+type DelayInjector struct {
+	injector.BaseInjector
+	Args    DelayArgs
+	Runtime DMRuntime
+}
+
+type DelayArgs struct {
+	DMArgs
+	ReadLatency  string `json:"read_latency,omitempty"`
+	WriteLatency string `json:"write_latency,omitempty"`
+}
+
+func (i *DelayInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *DelayInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *DelayInjector) SetOption(cmd *cobra.Command) {
+	// i.BaseInjector.SetOption(cmd)
+	setDMOption(cmd, &i.Args.DMArgs)
+	cmd.Flags().StringVarP(&i.Args.ReadLatency, "read-latency", "r", "", "latency of every read I/O, support unit: \"s、ms\", eg: 100ms")
+	cmd.Flags().StringVarP(&i.Args.WriteLatency, "write-latency", "w", "", "latency of every write I/O, support unit: \"s、ms\", eg: 100ms")
+}
+
+func (i *DelayInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.ReadLatency == "" && i.Args.WriteLatency == "" {
+		return fmt.Errorf("must provide \"read-latency\" or \"write-latency\"")
+	}
+
+	if _, err := getLatencyMs(i.Args.ReadLatency); err != nil {
+		return fmt.Errorf("\"read-latency\" is invalid: %s", err.Error())
+	}
+
+	if _, err := getLatencyMs(i.Args.WriteLatency); err != nil {
+		return fmt.Errorf("\"write-latency\" is invalid: %s", err.Error())
+	}
+
+	return i.Args.DMArgs.validator(ctx, &i.Info, dm.TargetDelay)
+}
+
+// getLatencyMs returns 0 if latency is empty, dm-delay only supports millisecond
+func getLatencyMs(latency string) (int64, error) {
+	if latency == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(latency)
+	if err != nil {
+		return 0, err
+	}
+
+	if d < time.Millisecond {
+		return 0, fmt.Errorf("must not less than 1ms")
+	}
+
+	return d.Milliseconds(), nil
+}
+
+func (i *DelayInjector) Inject(ctx context.Context) error {
+	readMs, _ := getLatencyMs(i.Args.ReadLatency)
+	writeMs, _ := getLatencyMs(i.Args.WriteLatency)
+
+	return injectDM(ctx, i.Info.Uid, &i.Args.DMArgs, &i.Runtime, func(sectors int64, dev string) string {
+		return dm.DelayTable(sectors, dev, readMs, writeMs)
+	}, nil)
+}
+
+func (i *DelayInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return recoverDM(ctx, &i.Runtime)
+}
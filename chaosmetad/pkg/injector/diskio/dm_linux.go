@@ -0,0 +1,214 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diskio
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/dm"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
+)
+
+// DMArgs selects the device to place the device-mapper target over:
+// 1. a device-mapper device(or the one a path located in), its table is replaced in place, so the running I/O is affected;
+// 2. other block device(eg: loop device), which is not in use, a new device "/dev/mapper/chaosmeta-[uid]" is created over it.
+type DMArgs struct {
+	Dev  string `json:"dev,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+// DMRuntime persists the mapping before inject, recover restores it
+type DMRuntime struct {
+	Dev         string `json:"dev,omitempty"`
+	Name        string `json:"name,omitempty"`
+	OriginName  string `json:"origin_name,omitempty"`
+	OriginTable string `json:"origin_table,omitempty"`
+}
+
+func setDMOption(cmd *cobra.Command, args *DMArgs) {
+	cmd.Flags().StringVarP(&args.Dev, "dev", "d", "", "target block device, eg: \"/dev/mapper/vg-data\"、\"/dev/loop0\". device-mapper device is changed in place, other device must not be in use and a new device \"/dev/mapper/chaosmeta-[uid]\" is created over it")
+	cmd.Flags().StringVar(&args.Path, "path", "", "target path, the device it is located in must be a device-mapper device, eg: a lvm volume")
+}
+
+func (a *DMArgs) validator(ctx context.Context, info *injector.BaseInfo, target string) error {
+	if info.ContainerId != "" || info.ContainerRuntime != "" {
+		return fmt.Errorf("fault \"%s\" not support in container", info.Fault)
+	}
+
+	if !cmdexec.SupportCmd("dmsetup") || !cmdexec.SupportCmd("blockdev") {
+		return fmt.Errorf("not support cmd \"dmsetup\" or \"blockdev\"")
+	}
+
+	if !dm.SupportTarget(ctx, target) {
+		return fmt.Errorf("device-mapper target \"%s\" is not supported by kernel", target)
+	}
+
+	if _, _, err := a.getTarget(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getTarget returns the target block device and its device-mapper name, the name is empty if it is not a device-mapper device
+func (a *DMArgs) getTarget(ctx context.Context) (dev, name string, err error) {
+	if (a.Dev == "") == (a.Path == "") {
+		return "", "", fmt.Errorf("must provide one of \"dev\" and \"path\"")
+	}
+
+	dev = a.Dev
+	if a.Path != "" {
+		if err := filesys.CheckDirLocal(a.Path); err != nil {
+			return "", "", fmt.Errorf("\"path\"[%s] check error: %s", a.Path, err.Error())
+		}
+
+		if dev, err = dm.GetPathDev(ctx, a.Path); err != nil {
+			return "", "", fmt.Errorf("get device of \"path\"[%s] error: %s", a.Path, err.Error())
+		}
+	}
+
+	isExist, err := filesys.ExistPathLocal(dev)
+	if err != nil {
+		return "", "", fmt.Errorf("check device[%s] exist error: %s", dev, err.Error())
+	}
+
+	if !isExist {
+		return "", "", fmt.Errorf("device[%s] is not exist", dev)
+	}
+
+	if name, err = dm.GetName(ctx, dev); err != nil {
+		return "", "", fmt.Errorf("get device-mapper name of %s error: %s", dev, err.Error())
+	}
+
+	if name != "" {
+		return dev, name, nil
+	}
+
+	if a.Path != "" {
+		return "", "", fmt.Errorf("device[%s] of \"path\" is not a device-mapper device, can not be changed in place", dev)
+	}
+
+	inUse, err := dm.IsInUse(ctx, dev)
+	if err != nil {
+		return "", "", fmt.Errorf("check device[%s] in use error: %s", dev, err.Error())
+	}
+
+	if inUse {
+		return "", "", fmt.Errorf("device[%s] is mounted or held by other device", dev)
+	}
+
+	return dev, "", nil
+}
+
+// injectDM places the table created by "getTable" over the target, "afterLoad" is called when the table is active
+func injectDM(ctx context.Context, uid string, args *DMArgs, runtime *DMRuntime,
+	getTable func(sectors int64, dev string) string, afterLoad func(name string) error) error {
+	logger := log.GetLogger(ctx)
+	dev, name, err := args.getTarget(ctx)
+	if err != nil {
+		return err
+	}
+
+	sectors, err := dm.GetDevSectors(ctx, dev)
+	if err != nil {
+		return fmt.Errorf("get sectors of %s error: %s", dev, err.Error())
+	}
+
+	runtime.Dev = dev
+	if name == "" {
+		runtime.Name = DMNamePrefix + uid
+		if err := dm.Create(ctx, runtime.Name, getTable(sectors, dev)); err != nil {
+			return fmt.Errorf("create device %s over %s error: %s", runtime.Name, dev, err.Error())
+		}
+	} else {
+		// the origin device keeps the original mapping, the target device is re-mapped over it
+		runtime.Name, runtime.OriginName = name, DMNamePrefix+uid+DMOriginSuffix
+		if runtime.OriginTable, err = dm.GetTable(ctx, name); err != nil {
+			return fmt.Errorf("get table of %s error: %s", name, err.Error())
+		}
+
+		if err := dm.Create(ctx, runtime.OriginName, runtime.OriginTable); err != nil {
+			return fmt.Errorf("create origin device %s error: %s", runtime.OriginName, err.Error())
+		}
+
+		if err := dm.Reload(ctx, name, getTable(sectors, fmt.Sprintf("%s/%s", dm.MapperDir, runtime.OriginName))); err != nil {
+			if rErr := recoverDM(ctx, runtime); rErr != nil {
+				logger.Warnf("undo error: %s", rErr.Error())
+			}
+
+			return fmt.Errorf("reload table of %s error: %s", name, err.Error())
+		}
+	}
+
+	if afterLoad != nil {
+		if err := afterLoad(runtime.Name); err != nil {
+			if rErr := recoverDM(ctx, runtime); rErr != nil {
+				logger.Warnf("undo error: %s", rErr.Error())
+			}
+
+			return err
+		}
+	}
+
+	logger.Infof("device-mapper fault is placed on %s/%s", dm.MapperDir, runtime.Name)
+	return nil
+}
+
+// recoverDM restores the original table of the target, or removes the device created by inject
+func recoverDM(ctx context.Context, runtime *DMRuntime) error {
+	if runtime.Name == "" {
+		return nil
+	}
+
+	isExist, err := dm.Exist(ctx, runtime.Name)
+	if err != nil {
+		return fmt.Errorf("check device %s exist error: %s", runtime.Name, err.Error())
+	}
+
+	if runtime.OriginName == "" {
+		if isExist {
+			if err := dm.Remove(ctx, runtime.Name); err != nil {
+				return fmt.Errorf("remove device %s error: %s", runtime.Name, err.Error())
+			}
+		}
+
+		return nil
+	}
+
+	if isExist {
+		if err := dm.Reload(ctx, runtime.Name, runtime.OriginTable); err != nil {
+			return fmt.Errorf("restore table of %s error: %s", runtime.Name, err.Error())
+		}
+	}
+
+	isExist, err = dm.Exist(ctx, runtime.OriginName)
+	if err != nil {
+		return fmt.Errorf("check device %s exist error: %s", runtime.OriginName, err.Error())
+	}
+
+	if isExist {
+		if err := dm.Remove(ctx, runtime.OriginName); err != nil {
+			return fmt.Errorf("remove origin device %s error: %s", runtime.OriginName, err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diskio
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/dm"
+)
+
+func init() {
+	injector.Register(TargetDiskIO, FaultDiskIOError, func() injector.IInjector { return &ErrorInjector{} })
+}
+
+// ErrorInjector returns I/O errors by dm-flakey in the error windows, or by dm-dust on the bad sectors
+type ErrorInjector struct {
+	injector.BaseInjector
+	Args    ErrorArgs
+	Runtime DMRuntime
+}
+
+type ErrorArgs struct {
+	DMArgs
+	Mode       string `json:"mode,omitempty"`
+	Up         string `json:"up,omitempty"`
+	Down       string `json:"down,omitempty"`
+	BadSectors string `json:"bad_sectors,omitempty"`
+}
+
+func (i *ErrorInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *ErrorInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *ErrorInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Mode == "" {
+		i.Args.Mode = ModeAll
+	}
+
+	if i.Args.BadSectors == "" {
+		if i.Args.Up == "" {
+			i.Args.Up = "0"
+		}
+
+		if i.Args.Down == "" {
+			i.Args.Down = DefaultDown
+		}
+	}
+}
+
+func (i *ErrorInjector) SetOption(cmd *cobra.Command) {
+	// i.BaseInjector.SetOption(cmd)
+	setDMOption(cmd, &i.Args.DMArgs)
+	cmd.Flags().StringVarP(&i.Args.Mode, "mode", "m", "", fmt.Sprintf("target IO mode to fail, support: %s、%s、%s（default %s）. \"%s\" only support %s",
+		ModeAll, ModeRead, ModeWrite, ModeAll, "bad-sectors", ModeRead))
+	cmd.Flags().StringVarP(&i.Args.Up, "up", "u", "", "the device works normally for \"up\" time in every cycle, \"0\" means always fail, support unit: \"s、m、h\"(default s)（default 0）")
+	cmd.Flags().StringVar(&i.Args.Down, "down", "", fmt.Sprintf("the I/O fails for \"down\" time in every cycle, support unit: \"s、m、h\"(default s)（default %s）", DefaultDown))
+	cmd.Flags().StringVarP(&i.Args.BadSectors, "bad-sectors", "b", "", fmt.Sprintf("only read the bad sectors fails, the sector is 512 bytes, eg: \"100,2048-2055\", at most %d sectors. a sector is good again after written. if provided, \"up\" and \"down\" are ignored", dm.MaxSectorCount))
+}
+
+func (i *ErrorInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Mode != ModeRead && i.Args.Mode != ModeWrite && i.Args.Mode != ModeAll {
+		return fmt.Errorf("\"mode\" is not support: %s", i.Args.Mode)
+	}
+
+	target := dm.TargetFlakey
+	if i.Args.BadSectors != "" {
+		target = dm.TargetDust
+		if i.Args.Mode == ModeWrite {
+			return fmt.Errorf("\"bad-sectors\" only support mode \"%s\"", ModeRead)
+		}
+
+		if _, err := dm.GetSectorList(i.Args.BadSectors); err != nil {
+			return fmt.Errorf("\"bad-sectors\" is invalid: %s", err.Error())
+		}
+	} else {
+		up, err := utils.GetTimeSecond(i.Args.Up)
+		if err != nil {
+			return fmt.Errorf("\"up\" is invalid: %s", err.Error())
+		}
+
+		if up < 0 {
+			return fmt.Errorf("\"up\" can not less than 0")
+		}
+
+		down, err := utils.GetTimeSecond(i.Args.Down)
+		if err != nil {
+			return fmt.Errorf("\"down\" is invalid: %s", err.Error())
+		}
+
+		if down <= 0 {
+			return fmt.Errorf("\"down\" must larger than 0")
+		}
+	}
+
+	return i.Args.DMArgs.validator(ctx, &i.Info, target)
+}
+
+func (i *ErrorInjector) Inject(ctx context.Context) error {
+	if i.Args.BadSectors != "" {
+		sectorList, _ := dm.GetSectorList(i.Args.BadSectors)
+		return injectDM(ctx, i.Info.Uid, &i.Args.DMArgs, &i.Runtime, dm.DustTable, func(name string) error {
+			if err := dm.AddBadSectors(ctx, name, sectorList); err != nil {
+				return fmt.Errorf("add bad sectors error: %s", err.Error())
+			}
+
+			if err := dm.Message(ctx, name, "enable"); err != nil {
+				return fmt.Errorf("enable bad sectors error: %s", err.Error())
+			}
+
+			return nil
+		})
+	}
+
+	up, _ := utils.GetTimeSecond(i.Args.Up)
+	down, _ := utils.GetTimeSecond(i.Args.Down)
+	return injectDM(ctx, i.Info.Uid, &i.Args.DMArgs, &i.Runtime, func(sectors int64, dev string) string {
+		return dm.FlakeyTable(sectors, dev, up, down, i.Args.Mode)
+	}, nil)
+}
+
+func (i *ErrorInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return recoverDM(ctx, &i.Runtime)
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diskio
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/dm"
+	"strings"
+	"testing"
+)
+
+func TestErrorInjector_Validator(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    ErrorArgs
+		wantErr string
+	}{
+		{name: "invalid mode", args: ErrorArgs{Mode: "rw"}, wantErr: "\"mode\""},
+		{name: "bad sectors with write", args: ErrorArgs{Mode: ModeWrite, BadSectors: "1"}, wantErr: "only support mode"},
+		{name: "too many bad sectors", args: ErrorArgs{Mode: ModeRead, BadSectors: fmt.Sprintf("0-%d", dm.MaxSectorCount)}, wantErr: "\"bad-sectors\""},
+		{name: "negative up", args: ErrorArgs{Mode: ModeAll, Up: "-1", Down: "1"}, wantErr: "\"up\""},
+		{name: "zero down", args: ErrorArgs{Mode: ModeAll, Up: "0", Down: "0"}, wantErr: "\"down\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &ErrorInjector{BaseInjector: injector.BaseInjector{Info: injector.BaseInfo{Uid: "test-uid"}}, Args: tt.args}
+			err := i.Validator(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validator() error = %v, want contains %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestErrorInjector_SetDefault(t *testing.T) {
+	i := &ErrorInjector{}
+	i.SetDefault()
+	if i.Args.Mode != ModeAll || i.Args.Up != "0" || i.Args.Down != DefaultDown {
+		t.Errorf("SetDefault() args = %+v", i.Args)
+	}
+
+	i = &ErrorInjector{Args: ErrorArgs{BadSectors: "1"}}
+	i.SetDefault()
+	if i.Args.Up != "" || i.Args.Down != "" {
+		t.Errorf("SetDefault() with bad sectors should not set up and down, args = %+v", i.Args)
+	}
+}
+
+func TestDMArgs_getTarget(t *testing.T) {
+	for _, args := range []DMArgs{{}, {Dev: "/dev/loop0", Path: "/data"}} {
+		if _, _, err := args.getTarget(context.Background()); err == nil {
+			t.Errorf("getTarget() with %+v, want error", args)
+		}
+	}
+
+	if _, _, err := (&DMArgs{Dev: "/dev/chaosmeta-not-exist"}).getTarget(context.Background()); err == nil || !strings.Contains(err.Error(), "not exist") {
+		t.Errorf("getTarget() with not exist device, error = %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/user"
+)
+
+const cliActorPrefix = "cli:"
+
+func getActor(ctx context.Context) string {
+	if actor := utils.GetActor(ctx); actor != "" {
+		return actor
+	}
+
+	return cliActorPrefix + user.GetUser()
+}
+
+// recordEvent appends an event to the journal of the experiment with the commands executed since the last event.
+// The journal is only for post-mortems, so an error of it does not stop the process
+func recordEvent(ctx context.Context, uid, eventType, msg string) {
+	logger := log.GetLogger(ctx)
+	db, err := storage.GetEventStore()
+	if err != nil {
+		logger.Warnf("record event[%s] for experiment[%s] error: connect db error: %s", eventType, uid, err.Error())
+		return
+	}
+
+	var cmdStr string
+	if cmds := utils.PopRecordedCmds(ctx); len(cmds) > 0 {
+		cmdBytes, err := json.Marshal(cmds)
+		if err != nil {
+			logger.Warnf("commands convert to string error: %s", err.Error())
+		} else {
+			cmdStr = string(cmdBytes)
+		}
+	}
+
+	if err := db.Insert(&storage.Event{
+		Uid:      uid,
+		Type:     eventType,
+		Actor:    getActor(ctx),
+		TraceId:  utils.GetTraceId(ctx),
+		Message:  msg,
+		Commands: cmdStr,
+	}); err != nil {
+		logger.Warnf("record event[%s] for experiment[%s] error: %s", eventType, uid, err.Error())
+	}
+}
+
+// recordErrEvent records an errored event and returns the input code and msg
+func recordErrEvent(ctx context.Context, uid string, code int, msg string) (int, string) {
+	recordEvent(ctx, uid, utils.EventErrored, fmt.Sprintf("code: %d, %s", code, msg))
+	return code, msg
+}
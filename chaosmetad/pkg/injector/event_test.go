@@ -0,0 +1,59 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"context"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"strings"
+	"testing"
+)
+
+func Test_recordEvent(t *testing.T) {
+	ctx := utils.GetCtxWithCmdRecorder(utils.GetCtxWithTraceId(context.Background(), "trace-event"))
+	utils.RecordCmd(ctx, "echo inject")
+	recordEvent(ctx, "event-uid", utils.EventInjected, "")
+
+	// the commands are recorded by the first event only
+	autoCtx := utils.GetCtxWithActor(ctx, utils.ActorAutoRecover)
+	if code, msg := recordErrEvent(autoCtx, "event-uid", errutil.RecoverErr, "recover failed"); code != errutil.RecoverErr || msg != "recover failed" {
+		t.Errorf("recordErrEvent() = %d, %s, want the input code and msg", code, msg)
+	}
+
+	db, err := storage.GetEventStore()
+	if err != nil {
+		t.Fatalf("GetEventStore() error = %v", err)
+	}
+
+	events, total, err := db.QueryByUid("event-uid", 0, 0)
+	if err != nil || total != 2 {
+		t.Fatalf("QueryByUid() = %d, %v, want 2 events", total, err)
+	}
+
+	injected, errored := events[0], events[1]
+	if injected.Type != utils.EventInjected || !strings.HasPrefix(injected.Actor, cliActorPrefix) ||
+		injected.TraceId != "trace-event" || injected.Commands != "[\"echo inject\"]" {
+		t.Errorf("injected event = %+v, want cli actor, trace id and commands", injected)
+	}
+
+	if errored.Type != utils.EventErrored || errored.Actor != utils.ActorAutoRecover ||
+		errored.TraceId != "trace-event" || errored.Commands != "" || !strings.Contains(errored.Message, "recover failed") {
+		t.Errorf("errored event = %+v, want auto recover actor and error message without commands", errored)
+	}
+}
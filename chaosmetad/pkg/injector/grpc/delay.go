@@ -0,0 +1,104 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/grpcproxy"
+	"time"
+)
+
+func init() {
+	injector.Register(TargetGRPC, FaultDelay, func() injector.IInjector { return &DelayInjector{} })
+}
+
+type DelayInjector struct {
+	injector.BaseInjector
+	Args    DelayArgs
+	Runtime ProxyRuntime
+}
+
+type DelayArgs struct {
+	ProxyArgs
+	Latency string `json:"latency,omitempty"`
+}
+
+func (i *DelayInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *DelayInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *DelayInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+	i.Args.ProxyArgs.setDefault()
+}
+
+func (i *DelayInjector) SetOption(cmd *cobra.Command) {
+	// i.BaseInjector.SetOption(cmd)
+	setProxyOption(cmd, &i.Args.ProxyArgs)
+	cmd.Flags().StringVarP(&i.Args.Latency, "latency", "l", "", "delay time before the call is forwarded, support unit: \"s、ms\", eg: 500ms")
+}
+
+func (i *DelayInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if _, err := i.getDelay(); err != nil {
+		return fmt.Errorf("\"latency\" is invalid: %s", err.Error())
+	}
+
+	return i.Args.ProxyArgs.validator(ctx, i.Info.ContainerRuntime, i.Info.ContainerId)
+}
+
+func (i *DelayInjector) getDelay() (int64, error) {
+	if i.Args.Latency == "" {
+		return 0, fmt.Errorf("must provide")
+	}
+
+	d, err := time.ParseDuration(i.Args.Latency)
+	if err != nil {
+		return 0, err
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf("must larger than 0")
+	}
+
+	return d.Milliseconds(), nil
+}
+
+func (i *DelayInjector) Inject(ctx context.Context) error {
+	rule := i.Args.ProxyArgs.getRule(grpcproxy.ActionDelay)
+	rule.Delay, _ = i.getDelay()
+
+	return injectProxy(ctx, &i.Info, &i.Args.ProxyArgs, rule, &i.Runtime)
+}
+
+func (i *DelayInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return recoverProxy(ctx, &i.Info, &i.Runtime)
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/grpcproxy"
+)
+
+func init() {
+	injector.Register(TargetGRPC, FaultDrop, func() injector.IInjector { return &DropInjector{} })
+}
+
+// DropInjector resets the streams of the matched calls
+type DropInjector struct {
+	injector.BaseInjector
+	Args    ProxyArgs
+	Runtime ProxyRuntime
+}
+
+func (i *DropInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *DropInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *DropInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+	i.Args.setDefault()
+}
+
+func (i *DropInjector) SetOption(cmd *cobra.Command) {
+	// i.BaseInjector.SetOption(cmd)
+	setProxyOption(cmd, &i.Args)
+}
+
+func (i *DropInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	return i.Args.validator(ctx, i.Info.ContainerRuntime, i.Info.ContainerId)
+}
+
+func (i *DropInjector) Inject(ctx context.Context) error {
+	return injectProxy(ctx, &i.Info, &i.Args, i.Args.getRule(grpcproxy.ActionDrop), &i.Runtime)
+}
+
+func (i *DropInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return recoverProxy(ctx, &i.Info, &i.Runtime)
+}
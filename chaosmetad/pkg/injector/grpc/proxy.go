@@ -0,0 +1,276 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/grpcproxy"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/net"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"strconv"
+	"strings"
+)
+
+// ProxyArgs are shared by all grpc faults
+type ProxyArgs struct {
+	Pid       int    `json:"pid,omitempty"`
+	Key       string `json:"key,omitempty"`
+	Port      int    `json:"port,omitempty"`
+	ProxyPort int    `json:"proxy_port,omitempty"`
+	Method    string `json:"method,omitempty"`
+	Metadata  string `json:"metadata,omitempty"`
+	Percent   int    `json:"percent,omitempty"`
+}
+
+// ProxyRuntime persists what inject actually did, recover relies on it only
+type ProxyRuntime struct {
+	Port      int    `json:"port,omitempty"`
+	ProxyPort int    `json:"proxy_port,omitempty"`
+	Rule      string `json:"rule,omitempty"`
+}
+
+func setProxyOption(cmd *cobra.Command, args *ProxyArgs) {
+	cmd.Flags().IntVarP(&args.Pid, "pid", "p", 0, "target process's pid, the port it listens on is intercepted")
+	cmd.Flags().StringVarP(&args.Key, "key", "k", "", "the key used to grep to get target process, the effect is equivalent to \"ps -ef | grep [key]\". if \"pid\" provided, \"key\" will be ignored")
+	cmd.Flags().IntVar(&args.Port, "port", 0, "target grpc port, must provide if the process listens on several ports or no process provided")
+	cmd.Flags().IntVar(&args.ProxyPort, "proxy-port", 0, fmt.Sprintf("port the proxy listens on（default port+%d）", DefaultProxyPortOffset))
+	cmd.Flags().StringVarP(&args.Method, "method", "m", "", "full method names, format: \"/pkg.Service/Method,/pkg.Service/*\", \"*\" means all methods of the service")
+	cmd.Flags().StringVar(&args.Metadata, "metadata", "", "only affect the calls with all the metadata, format: \"key1=value1,key2=value2\"")
+	cmd.Flags().IntVarP(&args.Percent, "percent", "P", 0, fmt.Sprintf("percent of the matched calls to affect, an integer in (0,100]（default %d）", DefaultPercent))
+}
+
+func (a *ProxyArgs) setDefault() {
+	if a.Percent == 0 {
+		a.Percent = DefaultPercent
+	}
+}
+
+func (a *ProxyArgs) validator(ctx context.Context, cr, cId string) error {
+	if a.Pid <= 0 && a.Key == "" && a.Port <= 0 {
+		return fmt.Errorf("must provide \"pid\"、\"key\" or \"port\"")
+	}
+
+	if a.Port < 0 || a.Port > 65535 {
+		return fmt.Errorf("\"port\" must be in (0,65535]")
+	}
+
+	if a.ProxyPort < 0 || a.ProxyPort > 65535 {
+		return fmt.Errorf("\"proxy-port\" must be in (0,65535]")
+	}
+
+	if _, err := getMethodList(a.Method); err != nil {
+		return fmt.Errorf("\"method\" is invalid: %s", err.Error())
+	}
+
+	if _, err := getMetadata(a.Metadata); err != nil {
+		return fmt.Errorf("\"metadata\" is invalid: %s", err.Error())
+	}
+
+	if a.Percent <= 0 || a.Percent > 100 {
+		return fmt.Errorf("\"percent\"[%d] must be in (0,100]", a.Percent)
+	}
+
+	if a.Pid > 0 || a.Key != "" {
+		if _, err := a.getPort(ctx, cr, cId); err != nil {
+			return fmt.Errorf("get target port error: %s", err.Error())
+		}
+	}
+
+	if !cmdexec.SupportCmd("iptables") {
+		return fmt.Errorf("not support cmd \"iptables\"")
+	}
+
+	return nil
+}
+
+// getPort returns the port listened by the selected process, the "port" args is used to choose one if there are several
+func (a *ProxyArgs) getPort(ctx context.Context, cr, cId string) (int, error) {
+	if a.Pid <= 0 && a.Key == "" {
+		return a.Port, nil
+	}
+
+	var pidListStr string
+	if a.Pid > 0 {
+		pidListStr = strconv.Itoa(a.Pid)
+	}
+
+	pidList, err := process.GetPidListByListStrAndKey(ctx, cr, cId, pidListStr, a.Key)
+	if err != nil {
+		return 0, fmt.Errorf("get target process's pid error: %s", err.Error())
+	}
+
+	portMap := make(map[int]bool)
+	for _, pid := range pidList {
+		portList, err := net.GetListenPortsByPid(ctx, cr, cId, pid)
+		if err != nil {
+			return 0, fmt.Errorf("get listen ports of process[%d] error: %s", pid, err.Error())
+		}
+
+		for _, port := range portList {
+			portMap[port] = true
+		}
+	}
+
+	if a.Port > 0 {
+		if !portMap[a.Port] {
+			return 0, fmt.Errorf("port[%d] is not listened by target process", a.Port)
+		}
+
+		return a.Port, nil
+	}
+
+	if len(portMap) != 1 {
+		return 0, fmt.Errorf("target process listens on %d ports, please provide \"port\"", len(portMap))
+	}
+
+	for port := range portMap {
+		return port, nil
+	}
+
+	return 0, nil
+}
+
+func getMethodList(methodStr string) ([]string, error) {
+	var methodList []string
+	for _, method := range strings.Split(methodStr, ",") {
+		method = strings.TrimSpace(method)
+		if method == "" {
+			continue
+		}
+
+		if err := grpcproxy.CheckMethod(method); err != nil {
+			return nil, fmt.Errorf("method[%s] is invalid: %s", method, err.Error())
+		}
+		methodList = append(methodList, method)
+	}
+
+	if len(methodList) == 0 {
+		return nil, fmt.Errorf("must provide")
+	}
+
+	return methodList, nil
+}
+
+func getMetadata(mdStr string) (map[string]string, error) {
+	md := make(map[string]string)
+	for _, kv := range strings.Split(mdStr, ",") {
+		kv = strings.TrimSpace(kv)
+		if kv == "" {
+			continue
+		}
+
+		items := strings.SplitN(kv, "=", 2)
+		if len(items) != 2 || strings.TrimSpace(items[0]) == "" {
+			return nil, fmt.Errorf("format of \"%s\" must be \"key=value\"", kv)
+		}
+		md[strings.ToLower(strings.TrimSpace(items[0]))] = strings.TrimSpace(items[1])
+	}
+
+	return md, nil
+}
+
+func (a *ProxyArgs) getRule(action string) *grpcproxy.Rule {
+	methodList, _ := getMethodList(a.Method)
+	md, _ := getMetadata(a.Metadata)
+	return &grpcproxy.Rule{
+		Methods:  methodList,
+		Metadata: md,
+		Action:   action,
+		Percent:  a.Percent,
+	}
+}
+
+// getComment identifies the redirect rules of this experiment
+func getComment(uid string) string {
+	return fmt.Sprintf("%s-%s", ProxyKey, uid)
+}
+
+// injectProxy starts the proxy and redirects the connections to the port to it, runtime is filled for recover
+func injectProxy(ctx context.Context, info *injector.BaseInfo, args *ProxyArgs, rule *grpcproxy.Rule, runtime *ProxyRuntime) error {
+	cr, cId := info.ContainerRuntime, info.ContainerId
+	port, err := args.getPort(ctx, cr, cId)
+	if err != nil {
+		return fmt.Errorf("get target port error: %s", err.Error())
+	}
+
+	proxyPort := args.ProxyPort
+	if proxyPort == 0 {
+		proxyPort = port + DefaultProxyPortOffset
+	}
+
+	if proxyPort == port || proxyPort > 65535 {
+		return fmt.Errorf("proxy port[%d] is invalid for port[%d], please provide \"proxy-port\"", proxyPort, port)
+	}
+
+	isRedirected, err := net.ExistPortRedirect(ctx, cr, cId, port)
+	if err != nil {
+		return fmt.Errorf("check redirect rule of port[%d] error: %s", port, err.Error())
+	}
+
+	if isRedirected {
+		return fmt.Errorf("port[%d] is already redirected by other rule", port)
+	}
+
+	pid, err := net.GetPidByPort(ctx, cr, cId, proxyPort, net.ProtocolTCP)
+	if err != nil {
+		return fmt.Errorf("get pid by port[%d] error: %s", proxyPort, err.Error())
+	}
+
+	if pid != utils.NoPid {
+		return fmt.Errorf("proxy port[%d] is occupied by process[%d]", proxyPort, pid)
+	}
+
+	ruleStr, err := grpcproxy.EncodeRule(rule)
+	if err != nil {
+		return fmt.Errorf("encode rule error: %s", err.Error())
+	}
+
+	// the proxy never exits by itself, the redirect rules must be deleted before it is killed in recover stage
+	cmd := fmt.Sprintf("%s %s %d %d %d %s %d", utils.GetToolPath(ProxyKey), info.Uid, proxyPort, port, net.ProxyMark, ruleStr, 0)
+	if err := cmdexec.WaitCommonWithNS(ctx, cr, cId, cmd, []string{namespace.NET, namespace.PID}); err != nil {
+		return fmt.Errorf("start proxy error: %s", err.Error())
+	}
+
+	if err := net.AddRedirectRule(ctx, cr, cId, port, proxyPort, net.ProxyMark, getComment(info.Uid)); err != nil {
+		if kErr := process.CheckExistAndKillByKey(ctx, fmt.Sprintf("%s %s", ProxyKey, info.Uid)); kErr != nil {
+			log.GetLogger(ctx).Warnf("undo proxy process error: %s", kErr.Error())
+		}
+
+		return fmt.Errorf("add redirect rule error: %s", err.Error())
+	}
+
+	runtime.Port, runtime.ProxyPort, runtime.Rule = port, proxyPort, ruleStr
+	return nil
+}
+
+// recoverProxy deletes the redirect rules first, so that no new connection goes to the proxy before it is killed
+func recoverProxy(ctx context.Context, info *injector.BaseInfo, runtime *ProxyRuntime) error {
+	if runtime.Port > 0 {
+		if err := net.DeleteRedirectRule(ctx, info.ContainerRuntime, info.ContainerId, runtime.Port, runtime.ProxyPort, net.ProxyMark, getComment(info.Uid)); err != nil {
+			return fmt.Errorf("delete redirect rule error: %s", err.Error())
+		}
+	}
+
+	return process.CheckExistAndKillByKey(ctx, fmt.Sprintf("%s %s", ProxyKey, info.Uid))
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"sync"
+)
+
+type uidLock struct {
+	sync.Mutex
+	ref int
+}
+
+var (
+	uidLockMap   = make(map[string]*uidLock)
+	uidLockMutex sync.Mutex
+)
+
+// lockUid serializes the injects of the same uid in one process, so that a retry of the server waits for the running one
+func lockUid(uid string) func() {
+	uidLockMutex.Lock()
+	l, ok := uidLockMap[uid]
+	if !ok {
+		l = &uidLock{}
+		uidLockMap[uid] = l
+	}
+	l.ref++
+	uidLockMutex.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		uidLockMutex.Lock()
+		l.ref--
+		if l.ref == 0 {
+			delete(uidLockMap, uid)
+		}
+		uidLockMutex.Unlock()
+	}
+}
+
+// checkSameExp checks whether the inject is a repeat of the existed experiment. reqArgs are the args after default,
+// so the fields without "omitempty" compare the defaults instead of the empty values. the fields omitted in reqArgs keep
+// the existed values, so the values filled by the validator of the first attempt do not make a conflict
+func checkSameExp(i IInjector, reqArgs []byte, exp *storage.Experiment) error {
+	info := i.GetInfo()
+	if info.Target != exp.Target || info.Fault != exp.Fault {
+		return fmt.Errorf("target[%s] and fault[%s] are different from the existed[%s, %s]", info.Target, info.Fault, exp.Target, exp.Fault)
+	}
+
+	if info.ContainerRuntime != exp.ContainerRuntime || info.ContainerId != exp.ContainerId {
+		return fmt.Errorf("container[%s://%s] is different from the existed[%s://%s]", info.ContainerRuntime, info.ContainerId, exp.ContainerRuntime, exp.ContainerId)
+	}
+
+	if info.Timeout != "" && info.Timeout != exp.Timeout {
+		return fmt.Errorf("timeout[%s] is different from the existed[%s]", info.Timeout, exp.Timeout)
+	}
+
+	if info.Ramp.IsEnabled() {
+		existedRamp, err := parseRamp(exp.Ramp)
+		if err != nil {
+			return err
+		}
+
+		if !info.Ramp.SameSpec(existedRamp) {
+			rampStr, _ := getRampStr(&info.Ramp)
+			return fmt.Errorf("ramp[%s] is different from the existed[%s]", rampStr, exp.Ramp)
+		}
+	}
+
+	existed, err := NewInjector(exp.Target, exp.Fault)
+	if err != nil {
+		return fmt.Errorf("get injector error: %s", err.Error())
+	}
+
+	if err := json.Unmarshal([]byte(exp.Args), existed.GetArgs()); err != nil {
+		return fmt.Errorf("load existed args error: %s", err.Error())
+	}
+
+	existedBytes, _ := json.Marshal(existed.GetArgs())
+	if err := json.Unmarshal(reqArgs, existed.GetArgs()); err != nil {
+		return fmt.Errorf("merge args error: %s", err.Error())
+	}
+
+	mergedBytes, _ := json.Marshal(existed.GetArgs())
+	if string(mergedBytes) != string(existedBytes) {
+		return fmt.Errorf("args[%s] are different from the existed[%s]", string(reqArgs), exp.Args)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector_test
+
+import (
+	"encoding/json"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/network"
+	"testing"
+)
+
+// newNetworkDelay creates the injector like the server does for the request body
+func newNetworkDelay(t *testing.T, reqArgs string) injector.IInjector {
+	i, err := injector.NewInjector(network.TargetNetwork, "delay")
+	if err != nil {
+		t.Fatalf("new injector error: %s", err.Error())
+	}
+
+	i.GetInfo().Uid = "test-uid"
+	if err := json.Unmarshal([]byte(reqArgs), i.GetArgs()); err != nil {
+		t.Fatalf("unmarshal args error: %s", err.Error())
+	}
+
+	i.SetDefault()
+	return i
+}
+
+func TestCheckSameExp_NetworkDelay(t *testing.T) {
+	first := newNetworkDelay(t, `{"interface":"eth0","latency":"100ms"}`)
+	exp, err := first.OptionToExp(first.GetArgs(), first.GetRuntime())
+	if err != nil {
+		t.Fatalf("create experiment error: %s", err.Error())
+	}
+
+	tests := []struct {
+		name    string
+		reqArgs string
+		wantErr bool
+	}{
+		{name: "retry", reqArgs: `{"interface":"eth0","latency":"100ms"}`},
+		{name: "default provided", reqArgs: `{"interface":"eth0","latency":"100ms","direction":"out","mode":"normal"}`},
+		{name: "different latency", reqArgs: `{"interface":"eth0","latency":"200ms"}`, wantErr: true},
+		{name: "different direction", reqArgs: `{"interface":"eth0","latency":"100ms","direction":"in"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := newNetworkDelay(t, tt.reqArgs)
+			reqArgs, _ := json.Marshal(i.GetArgs())
+			if err := injector.CheckSameExp(i, reqArgs, exp); (err != nil) != tt.wantErr {
+				t.Errorf("CheckSameExp() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"testing"
+)
+
+type testArgs struct {
+	Percent int `json:"percent"`
+	Count   int `json:"count,omitempty"`
+}
+
+type testInjector struct {
+	BaseInjector
+	Args testArgs
+}
+
+func (i *testInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func init() {
+	Register("test", "idempotent", func() IInjector { return &testInjector{} })
+}
+
+func Test_checkSameExp(t *testing.T) {
+	exp := &storage.Experiment{
+		Uid:     "test-uid",
+		Target:  "test",
+		Fault:   "idempotent",
+		Args:    `{"percent":50,"count":4}`,
+		Timeout: "10m",
+		Ramp:    `{"start":10,"end":50,"interval":"1m","shape":"linear","stage":3,"current":22}`,
+	}
+
+	tests := []struct {
+		name    string
+		fault   string
+		reqArgs string
+		timeout string
+		ramp    RampInfo
+		wantErr bool
+	}{
+		{name: "same", fault: "idempotent", reqArgs: `{"percent":50,"count":4}`, timeout: "10m", wantErr: false},
+		{name: "default omitted", fault: "idempotent", reqArgs: `{"percent":50}`, timeout: "", wantErr: false},
+		{name: "different args", fault: "idempotent", reqArgs: `{"percent":60}`, timeout: "10m", wantErr: true},
+		{name: "different timeout", fault: "idempotent", reqArgs: `{"percent":50}`, timeout: "5m", wantErr: true},
+		{name: "different fault", fault: "other", reqArgs: `{"percent":50}`, timeout: "", wantErr: true},
+		{name: "same ramp in other stage", fault: "idempotent", reqArgs: `{"percent":50}`, ramp: RampInfo{Start: 10, End: 50, Interval: "1m", Shape: RampShapeLinear}, wantErr: false},
+		{name: "different ramp", fault: "idempotent", reqArgs: `{"percent":50}`, ramp: RampInfo{Start: 10, End: 60, Interval: "1m", Shape: RampShapeLinear}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &testInjector{}
+			i.Info.Target, i.Info.Fault, i.Info.Timeout, i.Info.Ramp = "test", tt.fault, tt.timeout, tt.ramp
+			if err := checkSameExp(i, []byte(tt.reqArgs), exp); (err != nil) != tt.wantErr {
+				t.Errorf("checkSameExp() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_lockUid(t *testing.T) {
+	unlock := lockUid("test-uid")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lockUid("test-uid")()
+	}()
+	unlock()
+	<-done
+
+	if len(uidLockMap) != 0 {
+		t.Errorf("lock of uid is not released: %v", uidLockMap)
+	}
+}
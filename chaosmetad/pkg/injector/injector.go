@@ -40,6 +40,7 @@ type IInjector interface {
 
 	GetArgs() interface{}
 	GetRuntime() interface{}
+	GetInfo() *BaseInfo
 
 	SetOption(cmd *cobra.Command)
 	SetDefault()
@@ -69,6 +70,8 @@ type BaseInfo struct {
 	ContainerId      string `json:"container_id"`
 	ContainerRuntime string `json:"container_runtime"`
 	//ContainerNs      []string `json:"container_ns"`
+	// ramp information
+	Ramp RampInfo `json:"ramp"`
 }
 
 func (i *BaseInjector) GetArgs() interface{} {
@@ -81,6 +84,10 @@ func (i *BaseInjector) GetRuntime() interface{} {
 	return empty
 }
 
+func (i *BaseInjector) GetInfo() *BaseInfo {
+	return &i.Info
+}
+
 func (i *BaseInjector) SetCommonArgs(info *BaseInfo) {
 	if info == nil {
 		return
@@ -113,6 +120,10 @@ func (i *BaseInjector) SetCommonArgs(info *BaseInfo) {
 	if info.ContainerId != "" {
 		i.Info.ContainerId = info.ContainerId
 	}
+
+	if info.Ramp.IsSet() {
+		i.Info.Ramp = info.Ramp
+	}
 }
 
 func (i *BaseInjector) SetOption(cmd *cobra.Command) {
@@ -149,6 +160,10 @@ func (i *BaseInjector) SetDefault() {
 	if i.Info.ContainerId != "" && i.Info.ContainerRuntime == "" {
 		i.Info.ContainerRuntime = crclient.CrDocker
 	}
+
+	if i.Info.Ramp.IsEnabled() && i.Info.Ramp.Shape == "" {
+		i.Info.Ramp.Shape = RampShapeLinear
+	}
 }
 
 func (i *BaseInjector) Validator(ctx context.Context) error {
@@ -205,6 +220,12 @@ func (i *BaseInjector) LoadInjector(exp *storage.Experiment, argsVar, rVar inter
 	i.Info.ContainerRuntime = exp.ContainerRuntime
 	i.Info.ContainerId = exp.ContainerId
 
+	ramp, err := parseRamp(exp.Ramp)
+	if err != nil {
+		return err
+	}
+	i.Info.Ramp = *ramp
+
 	return nil
 }
 
@@ -217,6 +238,10 @@ func (i *BaseInjector) OptionToExp(args, r interface{}) (*storage.Experiment, er
 	if err != nil {
 		return nil, fmt.Errorf("runtime convert to string error: %s", err.Error())
 	}
+	rampStr, err := getRampStr(&i.Info.Ramp)
+	if err != nil {
+		return nil, err
+	}
 
 	exp := &storage.Experiment{
 		Uid:              i.Info.Uid,
@@ -230,6 +255,7 @@ func (i *BaseInjector) OptionToExp(args, r interface{}) (*storage.Experiment, er
 		Runtime:          string(runtimeByte),
 		ContainerRuntime: i.Info.ContainerRuntime,
 		ContainerId:      i.Info.ContainerId,
+		Ramp:             rampStr,
 	}
 
 	return exp, nil
@@ -246,24 +272,78 @@ func ProcessInject(ctx context.Context, i IInjector) (code int, msg string) {
 		}
 	}()
 
+	ctx = utils.GetCtxWithCmdRecorder(ctx)
 	i.SetDefault()
+	uid := i.GetInfo().Uid
 
-	if err := i.Validator(ctx); err != nil {
-		return errutil.BadArgsErr, fmt.Sprintf("args error: %s", err.Error())
+	// the args with default are used to compare with the existed experiment, which is stored with default too
+	reqArgs, err := json.Marshal(i.GetArgs())
+	if err != nil {
+		return errutil.BadArgsErr, fmt.Sprintf("args convert to string error: %s", err.Error())
 	}
 
+	unlock := lockUid(uid)
+	defer unlock()
+
 	db, err := storage.GetExperimentStore()
 	if err != nil {
-		return errutil.DBErr, fmt.Sprintf("connect db error: %s", err.Error())
+		return recordErrEvent(ctx, uid, errutil.DBErr, fmt.Sprintf("connect db error: %s", err.Error()))
+	}
+
+	// a repeat of the same uid returns the existed result, or resumes the experiment not finished
+	existed, err := db.FindByUid(uid)
+	if err != nil {
+		return errutil.DBErr, fmt.Sprintf("query experiment by uid[%s] error: %s", uid, err.Error())
 	}
 
+	if existed != nil {
+		if err := checkSameExp(i, reqArgs, existed); err != nil {
+			return errutil.ConflictErr, fmt.Sprintf("experiment[%s] is existed: %s", uid, err.Error())
+		}
+
+		if err := i.LoadInjector(existed, i.GetArgs(), i.GetRuntime()); err != nil {
+			return errutil.InternalErr, fmt.Sprintf("load existed experiment to injector error: %s", err.Error())
+		}
+
+		switch existed.Status {
+		case utils.StatusSuccess, utils.StatusDestroyed:
+			logger.Infof("experiment[%s] is existed with status: %s", uid, existed.Status)
+			return errutil.NoErr, "success"
+		case utils.StatusError:
+			return errutil.InjectErr, existed.Error
+		}
+
+		// the last attempt stopped in the middle, roll back what it may have done before inject again
+		logger.Infof("experiment[%s] is not finished, roll back and resume it", uid)
+		if err := i.Recover(ctx); err != nil {
+			logger.Warnf("roll back experiment[%s] error: %s", uid, err.Error())
+		}
+		recordEvent(ctx, uid, utils.EventResumed, "")
+	}
+
+	if i.GetInfo().Ramp.IsSet() {
+		if err := validRamp(i.GetInfo(), i); err != nil {
+			return recordErrEvent(ctx, uid, errutil.BadArgsErr, fmt.Sprintf("ramp args error: %s", err.Error()))
+		}
+	}
+
+	if err := i.Validator(ctx); err != nil {
+		return recordErrEvent(ctx, uid, errutil.BadArgsErr, fmt.Sprintf("args error: %s", err.Error()))
+	}
+	recordEvent(ctx, uid, utils.EventValidated, "")
+
 	exp, err := i.OptionToExp(i.GetArgs(), i.GetRuntime())
 	if err != nil {
-		return errutil.BadArgsErr, fmt.Sprintf("create experiment error: %s", err.Error())
+		return recordErrEvent(ctx, uid, errutil.BadArgsErr, fmt.Sprintf("create experiment error: %s", err.Error()))
 	}
 
-	if err := db.Insert(exp); err != nil {
-		return errutil.DBErr, fmt.Sprintf("insert new experiment error: %s", err.Error())
+	if existed == nil {
+		if err := db.Insert(exp); err != nil {
+			return recordErrEvent(ctx, uid, errutil.DBErr, fmt.Sprintf("insert new experiment error: %s", err.Error()))
+		}
+		recordEvent(ctx, uid, utils.EventCreated, fmt.Sprintf("target: %s, fault: %s, args: %s", exp.Target, exp.Fault, exp.Args))
+	} else if err := db.Update(exp); err != nil {
+		return recordErrEvent(ctx, uid, errutil.DBErr, fmt.Sprintf("update experiment error: %s", err.Error()))
 	}
 
 	logger.Infof("uid: %s", exp.Uid)
@@ -275,7 +355,7 @@ func ProcessInject(ctx context.Context, i IInjector) (code int, msg string) {
 			logger.Warnf("update status[%s] for experiment[%s] error: %s", utils.StatusError, exp.Uid, errMsg)
 		}
 
-		return errutil.InjectErr, errMsg
+		return recordErrEvent(ctx, uid, errutil.InjectErr, errMsg)
 	}
 
 	exp, _ = i.OptionToExp(i.GetArgs(), i.GetRuntime())
@@ -285,10 +365,11 @@ func ProcessInject(ctx context.Context, i IInjector) (code int, msg string) {
 		if err := i.Recover(ctx); err != nil {
 			logger.Warnf("recover error: %s", err.Error())
 		}
-		return errutil.DBErr, fmt.Sprintf("update status[%s] for experiment[%s] error: %s", exp.Status, exp.Uid, err.Error())
+		return recordErrEvent(ctx, uid, errutil.DBErr, fmt.Sprintf("update status[%s] for experiment[%s] error: %s", exp.Status, exp.Uid, err.Error()))
 	}
 
 	logger.Info("inject success")
+	recordEvent(ctx, uid, utils.EventInjected, "")
 
 	if exp.Timeout != "" {
 		timeSecond, _ := utils.GetTimeSecond(exp.Timeout)
@@ -297,6 +378,12 @@ func ProcessInject(ctx context.Context, i IInjector) (code int, msg string) {
 		}
 	}
 
+	if exp.Ramp != "" {
+		if err := startRamp(ctx, exp.Uid); err != nil {
+			logger.Warnf("inject success but start ramp cmd exec error: %s, the fault will keep the start value", err.Error())
+		}
+	}
+
 	return errutil.NoErr, "success"
 }
 
@@ -310,6 +397,7 @@ func ProcessRecover(ctx context.Context, uid string) (code int, msg string) {
 		}
 	}()
 
+	ctx = utils.GetCtxWithCmdRecorder(ctx)
 	logger.Debugf("uid: %s", uid)
 
 	db, err := storage.GetExperimentStore()
@@ -322,25 +410,47 @@ func ProcessRecover(ctx context.Context, uid string) (code int, msg string) {
 		return errutil.DBErr, fmt.Sprintf("query experiment by uid[%s] error: %s", uid, err.Error())
 	}
 
+	if exp.Ramp != "" {
+		if err := stopRamp(ctx, uid); err != nil {
+			return recordErrEvent(ctx, uid, errutil.RecoverErr, fmt.Sprintf("stop ramp error: %s", err.Error()))
+		}
+
+		// the ramp may have retuned the fault before it was stopped
+		if exp, err = db.GetByUid(uid); err != nil {
+			return errutil.DBErr, fmt.Sprintf("query experiment by uid[%s] error: %s", uid, err.Error())
+		}
+	}
+
 	i, err := NewInjector(exp.Target, exp.Fault)
 	if err != nil {
-		return errutil.InternalErr, fmt.Sprintf("find injector by target[%s] and fault[%s] error: %s", exp.Target, exp.Fault, err.Error())
+		return recordErrEvent(ctx, uid, errutil.InternalErr, fmt.Sprintf("find injector by target[%s] and fault[%s] error: %s", exp.Target, exp.Fault, err.Error()))
 	}
 
 	if err := i.LoadInjector(exp, i.GetArgs(), i.GetRuntime()); err != nil {
-		return errutil.InternalErr, fmt.Sprintf("load experiment to injector error: %s", err.Error())
+		return recordErrEvent(ctx, uid, errutil.InternalErr, fmt.Sprintf("load experiment to injector error: %s", err.Error()))
 	}
 
 	if err := i.Recover(ctx); err != nil {
-		return errutil.RecoverErr, fmt.Sprintf("recover error: %s", err.Error())
+		return recordErrEvent(ctx, uid, errutil.RecoverErr, fmt.Sprintf("recover error: %s", err.Error()))
 	}
 
 	logger.Info("recover success")
+	if utils.GetActor(ctx) == utils.ActorAutoRecover {
+		recordEvent(ctx, uid, utils.EventAutoRecovered, "")
+	} else {
+		recordEvent(ctx, uid, utils.EventManualRecovered, "")
+	}
 
 	if err := db.UpdateStatus(uid, utils.StatusDestroyed); err != nil {
 		logger.Warnf("update status[%s] for experiment[%s] error: %s", utils.StatusDestroyed, uid, err.Error())
 	}
 
+	if count, err := storage.Prune(); err != nil {
+		logger.Warnf("prune finished experiments error: %s", err.Error())
+	} else if count > 0 {
+		logger.Debugf("prune %d finished experiments", count)
+	}
+
 	return errutil.NoErr, "success"
 }
 
@@ -373,7 +483,7 @@ func NewCmdByTargetAndFault(target, fault string, infoArgs *BaseInfo) *cobra.Com
 
 			i.SetCommonArgs(infoArgs)
 			code, msg := ProcessInject(ctx, i)
-			errutil.SolveErr(ctx, code, msg)
+			errutil.SolveErrWithUid(ctx, i.GetInfo().Uid, code, msg)
 		},
 	}
 
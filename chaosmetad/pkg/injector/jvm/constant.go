@@ -18,12 +18,18 @@ package jvm
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
 	"os"
+	"regexp"
 	"strings"
 )
 
@@ -33,6 +39,13 @@ const (
 	FaultMethodDelay     = "methoddelay"
 	FaultMethodException = "methodexception"
 	FaultMethodReturn    = "methodreturn"
+	FaultMethodCpu       = "methodcpu"
+
+	FaultThreadPoolFull = "threadpoolfull"
+	FaultHeapFill       = "heapfill"
+	FaultGCStorm        = "gcstorm"
+
+	DefaultGCInterval = 100
 
 	JVMRuleDir      = "jvm_rule"
 	JVMContainerDir = "/tmp/chaosmeta_jvm"
@@ -44,6 +57,7 @@ const (
 
 	MethodRuleSplit  = ","
 	ClassMethodSplit = "@"
+	ConditionSplit   = "&&"
 
 	AttacherTool = "ChaosMetaJVMAttacher"
 	JVMAgentTool = "ChaosMetaJVMAgent.jar"
@@ -52,8 +66,9 @@ const (
 )
 
 type JVMRuleConfig struct {
-	Duration  int64           `json:"Duration"`
-	ClassList []*ClassJVMRule `json:"ClassList"`
+	Duration     int64              `json:"Duration"`
+	ClassList    []*ClassJVMRule    `json:"ClassList"`
+	ResourceList []*ResourceJVMRule `json:"ResourceList,omitempty"`
 }
 
 type ClassJVMRule struct {
@@ -67,6 +82,65 @@ type MethodJVMRule struct {
 	Content   string `json:"Content"`
 	ImportPkg string `json:"ImportPkg,omitempty"`
 	LineNum   int    `json:"LineNum"`
+	// the rule only takes effect on the calls which match all the conditions
+	Condition  string `json:"Condition,omitempty"`
+	Percent    int    `json:"Percent,omitempty"`
+	AfterCalls int    `json:"AfterCalls,omitempty"`
+}
+
+// MethodConditionArgs limits the method faults to part of the calls, can be updated while the experiment is running
+type MethodConditionArgs struct {
+	Condition  string `json:"condition,omitempty"` // args[0]=="userA"&&args[1]!=3
+	Percent    int    `json:"percent,omitempty"`
+	AfterCalls int    `json:"after_calls,omitempty"`
+}
+
+var argConditionRegexp = regexp.MustCompile(`^args\[(\d+)\]\s*(==|!=)\s*(.+)$`)
+
+// ResourceJVMRule is a fault running in the target JVM which is not bound to a method
+type ResourceJVMRule struct {
+	Fault    string `json:"Fault"`
+	Target   string `json:"Target,omitempty"`
+	Count    int    `json:"Count,omitempty"`
+	Percent  int    `json:"Percent,omitempty"`
+	Interval int    `json:"Interval,omitempty"`
+}
+
+func setConditionOption(cmd *cobra.Command, args *MethodConditionArgs) {
+	cmd.Flags().StringVar(&args.Condition, "condition", "", "only inject the calls whose arguments match, format: \"args[index]==value\", join multiple conditions with \"&&\", eg: args[0]==\"userA\"&&args[1]!=3")
+	cmd.Flags().IntVar(&args.Percent, "percent", 0, "only inject the percent of calls, range: [1, 100], default all the calls")
+	cmd.Flags().IntVar(&args.AfterCalls, "after", 0, "only inject the calls after the first N calls")
+}
+
+func (a *MethodConditionArgs) Validator() error {
+	if a.Percent < 0 || a.Percent > 100 {
+		return fmt.Errorf("\"percent\" must in [0, 100]")
+	}
+
+	if a.AfterCalls < 0 {
+		return fmt.Errorf("\"after\" must not less than 0")
+	}
+
+	if a.Condition != "" {
+		for _, unit := range strings.Split(a.Condition, ConditionSplit) {
+			if !argConditionRegexp.MatchString(strings.TrimSpace(unit)) {
+				return fmt.Errorf("\"condition\" is invalid: \"%s\" is not a valid format, format: args[index]==value", unit)
+			}
+		}
+	}
+
+	return nil
+}
+
+// methodUpdatableArgs the method and condition args can be updated, the target process keeps unchanged
+var methodUpdatableArgs = []string{"method", "condition", "percent", "after_calls"}
+
+func applyCondition(methodListMap map[string][]*MethodJVMRule, args *MethodConditionArgs) {
+	for _, methods := range methodListMap {
+		for _, method := range methods {
+			method.Condition, method.Percent, method.AfterCalls = args.Condition, args.Percent, args.AfterCalls
+		}
+	}
 }
 
 func getRuleDir(cId string) string {
@@ -98,10 +172,11 @@ func writeRule(ctx context.Context, cId string, pid int, ruleBytes []byte) error
 
 	// write rule to fileName
 	fileName := getRuleFile(cId, pid)
-	f, err := os.OpenFile(fileName, os.O_RDWR|os.O_CREATE, 0644)
+	f, err := os.OpenFile(fileName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
 	if err != nil {
 		return fmt.Errorf("open file[%s] fail: %s", fileName, err.Error())
 	}
+	defer f.Close()
 
 	if _, err := f.Write(ruleBytes); err != nil {
 		return fmt.Errorf("write jvm rule error: %s", err.Error())
@@ -196,6 +271,8 @@ func getMethodList(methodStr string, fault string) (map[string][]*MethodJVMRule,
 			rule, err = getMethodReturnRule(methodName, valueStr)
 		case FaultMethodException:
 			rule, err = getMethodExceptionRule(methodName, valueStr)
+		case FaultMethodCpu:
+			rule, err = getMethodCpuRule(methodName, valueStr)
 		default:
 			return nil, fmt.Errorf("not support fault: %s", fault)
 		}
@@ -224,3 +301,113 @@ func getRuleConfig(methodListMap map[string][]*MethodJVMRule, timeout int64) *JV
 		ClassList: classRuleList,
 	}
 }
+
+func getResourceRuleConfig(rule *ResourceJVMRule, timeout int64) *JVMRuleConfig {
+	return &JVMRuleConfig{
+		Duration:     timeout,
+		ClassList:    []*ClassJVMRule{},
+		ResourceList: []*ResourceJVMRule{rule},
+	}
+}
+
+// checkTargetProcess the target processes must exist and not have a running jvm experiment
+func checkTargetProcess(ctx context.Context, cr, cId string, pid int, key string) error {
+	pidList, err := process.GetPidListByPidOrKeyInContainer(ctx, cr, cId, pid, key)
+	if err != nil {
+		return fmt.Errorf("get target process's pid error: %s", err.Error())
+	}
+
+	for _, pid := range pidList {
+		ifExist, err := filesys.ExistFile(getRuleFile(cId, pid))
+		if err != nil {
+			return fmt.Errorf("check file of process[%d] exist error: %s", pid, err.Error())
+		}
+
+		if ifExist {
+			return fmt.Errorf("has jvm experiment running in process[%d]", pid)
+		}
+	}
+
+	return nil
+}
+
+// injectRuleConfig write rule config for every target process and attach agent, return the target pid list
+func injectRuleConfig(ctx context.Context, cr, cId string, pid int, key string, config *JVMRuleConfig) ([]int, error) {
+	logger := log.GetLogger(ctx)
+	pidList, _ := process.GetPidListByPidOrKeyInContainer(ctx, cr, cId, pid, key)
+	logger.Debugf("target pid list: %v", pidList)
+
+	ruleBytes, err := json.Marshal(config)
+	if err != nil {
+		return pidList, fmt.Errorf("get rule file bytes error: %s", err.Error())
+	}
+
+	logger.Debugf("rule json: %s", string(ruleBytes))
+	return pidList, doInject(ctx, cr, cId, pidList, ruleBytes)
+}
+
+// recoverRule remove rule file of target processes, the agent will recover after it finds the rule file not exist
+func recoverRule(ctx context.Context, cr, cId string, pidList []int) error {
+	logger := log.GetLogger(ctx)
+	var errMsg string
+	for _, pid := range pidList {
+		targetRule := getRuleFile(cId, pid)
+		logger.Debugf("check file: %s", targetRule)
+		ifExist, err := filesys.ExistFile(targetRule)
+		if err != nil {
+			errMsg = fmt.Sprintf("%s. %s", errMsg, fmt.Sprintf("check file[%s] exist error: %s", targetRule, err.Error()))
+			continue
+		}
+
+		if ifExist {
+			if cr != "" {
+				if err := filesys.RemoveFile(ctx, cr, cId, getContainerRuleFile(pid)); err != nil {
+					errMsg = fmt.Sprintf("%s. %s", errMsg, fmt.Sprintf("remove rule[%s] error: %s", targetRule, err.Error()))
+					continue
+				}
+			}
+			if err := os.RemoveAll(targetRule); err != nil {
+				errMsg = fmt.Sprintf("%s. %s", errMsg, fmt.Sprintf("remove rule[%s] error: %s", targetRule, err.Error()))
+			}
+		}
+	}
+
+	if errMsg != "" {
+		return errors.New(errMsg)
+	}
+
+	return nil
+}
+
+// updateRuleConfig rewrite the rule file of the running processes, the agent reloads the rules after it finds the file changed
+func updateRuleConfig(ctx context.Context, cr, cId string, pidList []int, config *JVMRuleConfig) error {
+	ruleBytes, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("get rule file bytes error: %s", err.Error())
+	}
+
+	log.GetLogger(ctx).Debugf("rule json: %s", string(ruleBytes))
+	for _, pid := range pidList {
+		ifExist, err := filesys.ExistFile(getRuleFile(cId, pid))
+		if err != nil {
+			return fmt.Errorf("check file of process[%d] exist error: %s", pid, err.Error())
+		}
+
+		if !ifExist {
+			return fmt.Errorf("no jvm experiment running in process[%d]", pid)
+		}
+
+		if err := writeRule(ctx, cId, pid, ruleBytes); err != nil {
+			return fmt.Errorf("write rule for process[%d] error: %s", pid, err.Error())
+		}
+
+		if cr != "" {
+			src, dst := getRuleFile(cId, pid), getContainerRuleFile(pid)
+			if err := cmdexec.CpContainerFile(ctx, cr, cId, src, dst); err != nil {
+				return fmt.Errorf("cp file[%s] to [%s] in container[%s] error: %s", src, dst, cId, err.Error())
+			}
+		}
+	}
+
+	return nil
+}
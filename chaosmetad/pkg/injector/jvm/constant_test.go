@@ -0,0 +1,152 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jvm
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func Test_getMethodCpuRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "valid", value: "200", wantErr: false},
+		{name: "zero", value: "0", wantErr: true},
+		{name: "negative", value: "-1", wantErr: true},
+		{name: "not number", value: "1s", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := getMethodCpuRule("doRequest", tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getMethodCpuRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil && (rule.Method != "doRequest" || rule.Fault != InsertBeforeInject) {
+				t.Errorf("getMethodCpuRule() = %+v, unexpected rule", rule)
+			}
+		})
+	}
+}
+
+func Test_getMethodList_MethodCpu(t *testing.T) {
+	methodMap, err := getMethodList("com.test.Client@doRequest@100,com.test.Client@doResponse@200,com.test.Server@handle@300", FaultMethodCpu)
+	if err != nil {
+		t.Fatalf("getMethodList() error = %v", err)
+	}
+
+	if len(methodMap["com.test.Client"]) != 2 || len(methodMap["com.test.Server"]) != 1 {
+		t.Errorf("getMethodList() = %v, want 2 methods of Client and 1 method of Server", methodMap)
+	}
+
+	if _, err := getMethodList("com.test.Client@doRequest", FaultMethodCpu); err == nil {
+		t.Errorf("getMethodList() without burn ms, want error")
+	}
+}
+
+func Test_getResourceRuleConfig(t *testing.T) {
+	config := getResourceRuleConfig(&ResourceJVMRule{Fault: FaultThreadPoolFull, Target: "com.test.Client@executor"}, 60)
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("marshal rule config error: %v", err)
+	}
+
+	want := `{"Duration":60,"ClassList":[],"ResourceList":[{"Fault":"threadpoolfull","Target":"com.test.Client@executor"}]}`
+	if string(configBytes) != want {
+		t.Errorf("getResourceRuleConfig() = %s, want %s", string(configBytes), want)
+	}
+}
+
+func TestMethodConditionArgs_Validator(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    MethodConditionArgs
+		wantErr bool
+	}{
+		{name: "empty", args: MethodConditionArgs{}, wantErr: false},
+		{name: "all", args: MethodConditionArgs{Condition: `args[0]=="userA" && args[1]!=3`, Percent: 50, AfterCalls: 10}, wantErr: false},
+		{name: "percent over 100", args: MethodConditionArgs{Percent: 101}, wantErr: true},
+		{name: "negative percent", args: MethodConditionArgs{Percent: -1}, wantErr: true},
+		{name: "negative after", args: MethodConditionArgs{AfterCalls: -1}, wantErr: true},
+		{name: "invalid operator", args: MethodConditionArgs{Condition: "args[0]>3"}, wantErr: true},
+		{name: "invalid index", args: MethodConditionArgs{Condition: `args[a]=="userA"`}, wantErr: true},
+		{name: "one of conditions invalid", args: MethodConditionArgs{Condition: `args[0]=="userA"&&userB`}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.args.Validator(); (err != nil) != tt.wantErr {
+				t.Errorf("Validator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_applyCondition(t *testing.T) {
+	methodMap, err := getMethodList("com.test.Client@doRequest@100,com.test.Server@handle@300", FaultMethodDelay)
+	if err != nil {
+		t.Fatalf("getMethodList() error = %v", err)
+	}
+
+	applyCondition(methodMap, &MethodConditionArgs{Condition: `args[0]=="userA"`, Percent: 30, AfterCalls: 5})
+	for className, methods := range methodMap {
+		for _, method := range methods {
+			if method.Condition != `args[0]=="userA"` || method.Percent != 30 || method.AfterCalls != 5 {
+				t.Errorf("rule of %s@%s = %+v, want the condition applied", className, method.Method, method)
+			}
+		}
+	}
+}
+
+func Test_updateRuleConfig(t *testing.T) {
+	ctx, cId := context.Background(), "test-update-rule"
+	defer os.RemoveAll(getRuleDir(cId))
+
+	if err := writeRule(ctx, cId, 100, []byte("{}")); err != nil {
+		t.Fatalf("writeRule() error = %v", err)
+	}
+
+	methodMap, _ := getMethodList("com.test.Client@doRequest@100", FaultMethodDelay)
+	applyCondition(methodMap, &MethodConditionArgs{Percent: 30})
+	config := getRuleConfig(methodMap, 60)
+	if err := updateRuleConfig(ctx, "", cId, []int{100}, config); err != nil {
+		t.Fatalf("updateRuleConfig() error = %v", err)
+	}
+
+	ruleBytes, err := os.ReadFile(getRuleFile(cId, 100))
+	if err != nil {
+		t.Fatalf("read rule file error = %v", err)
+	}
+
+	wantBytes, _ := json.Marshal(config)
+	if string(ruleBytes) != string(wantBytes) {
+		t.Errorf("rule file = %s, want %s", string(ruleBytes), string(wantBytes))
+	}
+
+	// the process without running experiment is not updated
+	if err := updateRuleConfig(ctx, "", cId, []int{100, 101}, config); err == nil {
+		t.Errorf("updateRuleConfig() of process without rule, want error")
+	}
+
+	if _, err := os.Stat(getRuleFile(cId, 101)); !os.IsNotExist(err) {
+		t.Errorf("rule file of process without experiment is created, error = %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jvm
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+)
+
+func init() {
+	injector.Register(TargetJVM, FaultHeapFill, func() injector.IInjector { return &HeapFillInjector{} })
+}
+
+type HeapFillInjector struct {
+	injector.BaseInjector
+	Args    HeapFillArgs
+	Runtime HeapFillRuntime
+}
+
+type HeapFillArgs struct {
+	Pid     int    `json:"pid,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Percent int    `json:"percent"`
+}
+
+type HeapFillRuntime struct {
+	AttackPids []int `json:"attack_pids"`
+}
+
+func (i *HeapFillInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *HeapFillInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *HeapFillInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(&i.Args.Pid, "pid", "p", 0, "target process's pid")
+	cmd.Flags().StringVarP(&i.Args.Key, "key", "k", "", "the key used to grep to get target process, the effect is equivalent to \"ps -ef | grep [key]\". if \"pid\" provided, \"key\" will be ignored")
+	cmd.Flags().IntVar(&i.Args.Percent, "percent", 0, "target heap usage percent of Xmx, an integer in (0,100) without \"%\", eg: \"80\" means \"80%\"")
+}
+
+func (i *HeapFillInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if err := checkTargetProcess(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key); err != nil {
+		return err
+	}
+
+	if i.Args.Percent <= 0 || i.Args.Percent >= 100 {
+		return fmt.Errorf("\"percent\"[%d] must be in (0,100)", i.Args.Percent)
+	}
+
+	if err := checkJavaCmd(ctx, i.Info.ContainerRuntime, i.Info.ContainerId); err != nil {
+		return fmt.Errorf("check java exec error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (i *HeapFillInjector) Inject(ctx context.Context) error {
+	var timeout int64
+	if i.Info.Timeout != "" {
+		timeout, _ = utils.GetTimeSecond(i.Info.Timeout)
+	}
+
+	rule := &ResourceJVMRule{
+		Fault:   FaultHeapFill,
+		Percent: i.Args.Percent,
+	}
+
+	pidList, err := injectRuleConfig(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key, getResourceRuleConfig(rule, timeout))
+	i.Runtime.AttackPids = pidList
+	if err != nil {
+		if rErr := i.Recover(ctx); rErr != nil {
+			log.GetLogger(ctx).Warnf("undo error: %s", rErr.Error())
+		}
+	}
+
+	return err
+}
+
+func (i *HeapFillInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return recoverRule(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Runtime.AttackPids)
+}
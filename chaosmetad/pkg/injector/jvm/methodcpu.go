@@ -0,0 +1,155 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jvm
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"strconv"
+)
+
+func init() {
+	injector.Register(TargetJVM, FaultMethodCpu, func() injector.IInjector { return &MethodCpuInjector{} })
+}
+
+type MethodCpuInjector struct {
+	injector.BaseInjector
+	Args    MethodCpuArgs
+	Runtime MethodCpuRuntime
+}
+
+type MethodCpuArgs struct {
+	Pid        int    `json:"pid,omitempty"`
+	Key        string `json:"key,omitempty"`
+	MethodList string `json:"method"` // class@method@200,
+	MethodConditionArgs
+}
+
+type MethodCpuRuntime struct {
+	AttackPids []int `json:"attack_pids"`
+}
+
+func (i *MethodCpuInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *MethodCpuInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *MethodCpuInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(&i.Args.Pid, "pid", "p", 0, "target process's pid")
+	cmd.Flags().StringVarP(&i.Args.Key, "key", "k", "", "the key used to grep to get target process, the effect is equivalent to \"ps -ef | grep [key]\". if \"pid\" provided, \"key\" will be ignored")
+	cmd.Flags().StringVarP(&i.Args.MethodList, "method", "m", "", "target method of the process, format: \"class1@method1@burn_ms,class1@method2@burn_ms\", eg: \"com.test.Client@sayHello@200\"")
+	setConditionOption(cmd, &i.Args.MethodConditionArgs)
+}
+
+func (i *MethodCpuInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if err := checkTargetProcess(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key); err != nil {
+		return err
+	}
+
+	if _, err := getMethodList(i.Args.MethodList, FaultMethodCpu); err != nil {
+		return fmt.Errorf("\"method\" is invalid: %s", err.Error())
+	}
+
+	if err := i.Args.MethodConditionArgs.Validator(); err != nil {
+		return err
+	}
+
+	if err := checkJavaCmd(ctx, i.Info.ContainerRuntime, i.Info.ContainerId); err != nil {
+		return fmt.Errorf("check java exec error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (i *MethodCpuInjector) Inject(ctx context.Context) error {
+	var timeout int64
+	if i.Info.Timeout != "" {
+		timeout, _ = utils.GetTimeSecond(i.Info.Timeout)
+	}
+
+	methodListMap, _ := getMethodList(i.Args.MethodList, FaultMethodCpu)
+	applyCondition(methodListMap, &i.Args.MethodConditionArgs)
+	pidList, err := injectRuleConfig(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key, getRuleConfig(methodListMap, timeout))
+	// save target
+	i.Runtime.AttackPids = pidList
+	if err != nil {
+		// undo recover
+		if rErr := i.Recover(ctx); rErr != nil {
+			log.GetLogger(ctx).Warnf("undo error: %s", rErr.Error())
+		}
+	}
+
+	return err
+}
+
+func (i *MethodCpuInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return recoverRule(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Runtime.AttackPids)
+}
+
+func (i *MethodCpuInjector) UpdateValidator(ctx context.Context) error {
+	if _, err := getMethodList(i.Args.MethodList, FaultMethodCpu); err != nil {
+		return fmt.Errorf("\"method\" is invalid: %s", err.Error())
+	}
+
+	return i.Args.MethodConditionArgs.Validator()
+}
+
+func (i *MethodCpuInjector) GetUpdatableArgs() []string {
+	return methodUpdatableArgs
+}
+
+func (i *MethodCpuInjector) Update(ctx context.Context) error {
+	var timeout int64
+	if i.Info.Timeout != "" {
+		timeout, _ = utils.GetTimeSecond(i.Info.Timeout)
+	}
+
+	methodListMap, _ := getMethodList(i.Args.MethodList, FaultMethodCpu)
+	applyCondition(methodListMap, &i.Args.MethodConditionArgs)
+	return updateRuleConfig(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Runtime.AttackPids, getRuleConfig(methodListMap, timeout))
+}
+
+func getMethodCpuRule(methodName, burnMsStr string) (*MethodJVMRule, error) {
+	burnMs, err := strconv.Atoi(burnMsStr)
+	if err != nil {
+		return nil, fmt.Errorf("is not a valid burn ms: %s", err.Error())
+	}
+	if burnMs <= 0 {
+		return nil, fmt.Errorf("burn ms must larger than 0")
+	}
+
+	return &MethodJVMRule{
+		Method:  methodName,
+		Fault:   InsertBeforeInject,
+		Content: fmt.Sprintf("{long chaosmetaBurnEnd = System.nanoTime() + %dL * 1000000L; while (System.nanoTime() < chaosmetaBurnEnd) {}}", burnMs),
+	}, nil
+}
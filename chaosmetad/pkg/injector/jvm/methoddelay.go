@@ -18,16 +18,11 @@ package jvm
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
-	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
-	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
-	"os"
 	"strconv"
 )
 
@@ -45,6 +40,7 @@ type MethodDelayArgs struct {
 	Pid        int    `json:"pid,omitempty"`
 	Key        string `json:"key,omitempty"`
 	MethodList string `json:"method"` // class@method@3000,
+	MethodConditionArgs
 }
 
 type MethodDelayRuntime struct {
@@ -63,6 +59,7 @@ func (i *MethodDelayInjector) SetOption(cmd *cobra.Command) {
 	cmd.Flags().IntVarP(&i.Args.Pid, "pid", "p", 0, "target process's pid")
 	cmd.Flags().StringVarP(&i.Args.Key, "key", "k", "", "the key used to grep to get target process, the effect is equivalent to \"ps -ef | grep [key]\". if \"pid\" provided, \"key\" will be ignored")
 	cmd.Flags().StringVarP(&i.Args.MethodList, "method", "m", "", "target method of the process, format: \"class1@method1@delay_ms,class1@method2@delay_ms\", eg: \"com.test.Client@sayHello@3000\"")
+	setConditionOption(cmd, &i.Args.MethodConditionArgs)
 }
 
 func (i *MethodDelayInjector) Validator(ctx context.Context) error {
@@ -70,25 +67,16 @@ func (i *MethodDelayInjector) Validator(ctx context.Context) error {
 		return err
 	}
 
-	pidList, err := process.GetPidListByPidOrKeyInContainer(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key)
-	if err != nil {
-		return fmt.Errorf("get target process's pid error: %s", err.Error())
+	if err := checkTargetProcess(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key); err != nil {
+		return err
 	}
 
-	for _, pid := range pidList {
-		ifExist, err := filesys.ExistFile(getRuleFile(i.Info.ContainerId, pid))
-		if err != nil {
-			return fmt.Errorf("check file of process[%d] exist error: %s", pid, err.Error())
-		}
-
-		if ifExist {
-			return fmt.Errorf("has jvm experiment running in process[%d]", pid)
-		}
+	if _, err := getMethodList(i.Args.MethodList, FaultMethodDelay); err != nil {
+		return fmt.Errorf("\"method\" is invalid: %s", err.Error())
 	}
 
-	_, err = getMethodList(i.Args.MethodList, FaultMethodDelay)
-	if err != nil {
-		return fmt.Errorf("\"method\" is invalid: %s", err.Error())
+	if err := i.Args.MethodConditionArgs.Validator(); err != nil {
+		return err
 	}
 
 	if err := checkJavaCmd(ctx, i.Info.ContainerRuntime, i.Info.ContainerId); err != nil {
@@ -99,34 +87,20 @@ func (i *MethodDelayInjector) Validator(ctx context.Context) error {
 }
 
 func (i *MethodDelayInjector) Inject(ctx context.Context) error {
-	var (
-		pidList []int
-		err     error
-		logger  = log.GetLogger(ctx)
-	)
-
-	pidList, _ = process.GetPidListByPidOrKeyInContainer(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key)
-	logger.Debugf("target pid list: %v", pidList)
-	// save target
-	i.Runtime.AttackPids = pidList
-
 	var timeout int64
 	if i.Info.Timeout != "" {
 		timeout, _ = utils.GetTimeSecond(i.Info.Timeout)
 	}
 
 	methodListMap, _ := getMethodList(i.Args.MethodList, FaultMethodDelay)
-	ruleBytes, err := json.Marshal(getRuleConfig(methodListMap, timeout))
-	if err != nil {
-		return fmt.Errorf("get rule file bytes error: %s", err.Error())
-	}
-
-	logger.Debugf("rule json: %s", string(ruleBytes))
-	err = doInject(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, pidList, ruleBytes)
+	applyCondition(methodListMap, &i.Args.MethodConditionArgs)
+	pidList, err := injectRuleConfig(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key, getRuleConfig(methodListMap, timeout))
+	// save target
+	i.Runtime.AttackPids = pidList
 	if err != nil {
 		// undo recover
 		if rErr := i.Recover(ctx); rErr != nil {
-			logger.Warnf("undo error: %s", rErr.Error())
+			log.GetLogger(ctx).Warnf("undo error: %s", rErr.Error())
 		}
 	}
 
@@ -138,35 +112,30 @@ func (i *MethodDelayInjector) Recover(ctx context.Context) error {
 		return nil
 	}
 
-	logger := log.GetLogger(ctx)
-	var errMsg string
-	for _, pid := range i.Runtime.AttackPids {
-		targetRule := getRuleFile(i.Info.ContainerId, pid)
-		logger.Debugf("check file: %s", targetRule)
-		ifExist, err := filesys.ExistFile(targetRule)
-		if err != nil {
-			errMsg = fmt.Sprintf("%s. %s", errMsg, fmt.Sprintf("check file[%s] exist error: %s", targetRule, err.Error()))
-			continue
-		}
+	return recoverRule(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Runtime.AttackPids)
+}
 
-		if ifExist {
-			if i.Info.ContainerRuntime != "" {
-				if err := filesys.RemoveFile(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, getContainerRuleFile(pid)); err != nil {
-					errMsg = fmt.Sprintf("%s. %s", errMsg, fmt.Sprintf("remove rule[%s] error: %s", targetRule, err.Error()))
-					continue
-				}
-			}
-			if err := os.RemoveAll(targetRule); err != nil {
-				errMsg = fmt.Sprintf("%s. %s", errMsg, fmt.Sprintf("remove rule[%s] error: %s", targetRule, err.Error()))
-			}
-		}
+func (i *MethodDelayInjector) UpdateValidator(ctx context.Context) error {
+	if _, err := getMethodList(i.Args.MethodList, FaultMethodDelay); err != nil {
+		return fmt.Errorf("\"method\" is invalid: %s", err.Error())
 	}
 
-	if errMsg != "" {
-		return errors.New(errMsg)
+	return i.Args.MethodConditionArgs.Validator()
+}
+
+func (i *MethodDelayInjector) GetUpdatableArgs() []string {
+	return methodUpdatableArgs
+}
+
+func (i *MethodDelayInjector) Update(ctx context.Context) error {
+	var timeout int64
+	if i.Info.Timeout != "" {
+		timeout, _ = utils.GetTimeSecond(i.Info.Timeout)
 	}
 
-	return nil
+	methodListMap, _ := getMethodList(i.Args.MethodList, FaultMethodDelay)
+	applyCondition(methodListMap, &i.Args.MethodConditionArgs)
+	return updateRuleConfig(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Runtime.AttackPids, getRuleConfig(methodListMap, timeout))
 }
 
 func getMethodDelayRule(methodName, delayMsStr string) (*MethodJVMRule, error) {
@@ -18,16 +18,11 @@ package jvm
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
-	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
-	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
-	"os"
 )
 
 func init() {
@@ -44,6 +39,7 @@ type MethodExceptionArgs struct {
 	Pid        int    `json:"pid,omitempty"`
 	Key        string `json:"key,omitempty"`
 	MethodList string `json:"method"` // class@method@"ok",
+	MethodConditionArgs
 }
 
 type MethodExceptionRuntime struct {
@@ -62,6 +58,7 @@ func (i *MethodExceptionInjector) SetOption(cmd *cobra.Command) {
 	cmd.Flags().IntVarP(&i.Args.Pid, "pid", "p", 0, "target process's pid")
 	cmd.Flags().StringVarP(&i.Args.Key, "key", "k", "", "the key used to grep to get target process, the effect is equivalent to \"ps -ef | grep [key]\". if \"pid\" provided, \"key\" will be ignored")
 	cmd.Flags().StringVarP(&i.Args.MethodList, "method", "m", "", "target method of the process, format: \"class1@method1@msg,class1@method2@msg\", eg: com.test.Client@sayHello@error,com.test.Client@sayHello@test")
+	setConditionOption(cmd, &i.Args.MethodConditionArgs)
 }
 
 func (i *MethodExceptionInjector) Validator(ctx context.Context) error {
@@ -69,25 +66,16 @@ func (i *MethodExceptionInjector) Validator(ctx context.Context) error {
 		return err
 	}
 
-	pidList, err := process.GetPidListByPidOrKeyInContainer(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key)
-	if err != nil {
-		return fmt.Errorf("get target process's pid error: %s", err.Error())
+	if err := checkTargetProcess(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key); err != nil {
+		return err
 	}
 
-	for _, pid := range pidList {
-		ifExist, err := filesys.ExistFile(getRuleFile(i.Info.ContainerId, pid))
-		if err != nil {
-			return fmt.Errorf("check file of process[%d] exist error: %s", pid, err.Error())
-		}
-
-		if ifExist {
-			return fmt.Errorf("has jvm experiment running in process[%d]", pid)
-		}
+	if _, err := getMethodList(i.Args.MethodList, FaultMethodException); err != nil {
+		return fmt.Errorf("\"method\" is invalid: %s", err.Error())
 	}
 
-	_, err = getMethodList(i.Args.MethodList, FaultMethodException)
-	if err != nil {
-		return fmt.Errorf("\"method\" is invalid: %s", err.Error())
+	if err := i.Args.MethodConditionArgs.Validator(); err != nil {
+		return err
 	}
 
 	if err := checkJavaCmd(ctx, i.Info.ContainerRuntime, i.Info.ContainerId); err != nil {
@@ -98,34 +86,20 @@ func (i *MethodExceptionInjector) Validator(ctx context.Context) error {
 }
 
 func (i *MethodExceptionInjector) Inject(ctx context.Context) error {
-	var (
-		pidList []int
-		err     error
-		logger  = log.GetLogger(ctx)
-	)
-
-	pidList, _ = process.GetPidListByPidOrKeyInContainer(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key)
-	logger.Debugf("target pid list: %v", pidList)
-	// save target
-	i.Runtime.AttackPids = pidList
-
 	var timeout int64
 	if i.Info.Timeout != "" {
 		timeout, _ = utils.GetTimeSecond(i.Info.Timeout)
 	}
 
 	methodListMap, _ := getMethodList(i.Args.MethodList, FaultMethodException)
-	ruleBytes, err := json.Marshal(getRuleConfig(methodListMap, timeout))
-	if err != nil {
-		return fmt.Errorf("get rule file bytes error: %s", err.Error())
-	}
-
-	logger.Debugf("rule json: %s", string(ruleBytes))
-	err = doInject(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, pidList, ruleBytes)
+	applyCondition(methodListMap, &i.Args.MethodConditionArgs)
+	pidList, err := injectRuleConfig(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key, getRuleConfig(methodListMap, timeout))
+	// save target
+	i.Runtime.AttackPids = pidList
 	if err != nil {
 		// undo recover
 		if rErr := i.Recover(ctx); rErr != nil {
-			logger.Warnf("undo error: %s", rErr.Error())
+			log.GetLogger(ctx).Warnf("undo error: %s", rErr.Error())
 		}
 	}
 
@@ -137,35 +111,30 @@ func (i *MethodExceptionInjector) Recover(ctx context.Context) error {
 		return nil
 	}
 
-	logger := log.GetLogger(ctx)
-	var errMsg string
-	for _, pid := range i.Runtime.AttackPids {
-		targetRule := getRuleFile(i.Info.ContainerId, pid)
-		logger.Debugf("check file: %s", targetRule)
-		ifExist, err := filesys.ExistFile(targetRule)
-		if err != nil {
-			errMsg = fmt.Sprintf("%s. %s", errMsg, fmt.Sprintf("check file[%s] exist error: %s", targetRule, err.Error()))
-			continue
-		}
+	return recoverRule(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Runtime.AttackPids)
+}
 
-		if ifExist {
-			if i.Info.ContainerRuntime != "" {
-				if err := filesys.RemoveFile(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, getContainerRuleFile(pid)); err != nil {
-					errMsg = fmt.Sprintf("%s. %s", errMsg, fmt.Sprintf("remove rule[%s] error: %s", targetRule, err.Error()))
-					continue
-				}
-			}
-			if err := os.RemoveAll(targetRule); err != nil {
-				errMsg = fmt.Sprintf("%s. %s", errMsg, fmt.Sprintf("remove rule[%s] error: %s", targetRule, err.Error()))
-			}
-		}
+func (i *MethodExceptionInjector) UpdateValidator(ctx context.Context) error {
+	if _, err := getMethodList(i.Args.MethodList, FaultMethodException); err != nil {
+		return fmt.Errorf("\"method\" is invalid: %s", err.Error())
 	}
 
-	if errMsg != "" {
-		return errors.New(errMsg)
+	return i.Args.MethodConditionArgs.Validator()
+}
+
+func (i *MethodExceptionInjector) GetUpdatableArgs() []string {
+	return methodUpdatableArgs
+}
+
+func (i *MethodExceptionInjector) Update(ctx context.Context) error {
+	var timeout int64
+	if i.Info.Timeout != "" {
+		timeout, _ = utils.GetTimeSecond(i.Info.Timeout)
 	}
 
-	return nil
+	methodListMap, _ := getMethodList(i.Args.MethodList, FaultMethodException)
+	applyCondition(methodListMap, &i.Args.MethodConditionArgs)
+	return updateRuleConfig(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Runtime.AttackPids, getRuleConfig(methodListMap, timeout))
 }
 
 func getMethodExceptionRule(methodName, valueStr string) (*MethodJVMRule, error) {
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jvm
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"strings"
+)
+
+func init() {
+	injector.Register(TargetJVM, FaultThreadPoolFull, func() injector.IInjector { return &ThreadPoolFullInjector{} })
+}
+
+type ThreadPoolFullInjector struct {
+	injector.BaseInjector
+	Args    ThreadPoolFullArgs
+	Runtime ThreadPoolFullRuntime
+}
+
+type ThreadPoolFullArgs struct {
+	Pid   int    `json:"pid,omitempty"`
+	Key   string `json:"key,omitempty"`
+	Pool  string `json:"pool,omitempty"` // class@field
+	Count int    `json:"count,omitempty"`
+}
+
+type ThreadPoolFullRuntime struct {
+	AttackPids []int `json:"attack_pids"`
+}
+
+func (i *ThreadPoolFullInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *ThreadPoolFullInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *ThreadPoolFullInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(&i.Args.Pid, "pid", "p", 0, "target process's pid")
+	cmd.Flags().StringVarP(&i.Args.Key, "key", "k", "", "the key used to grep to get target process, the effect is equivalent to \"ps -ef | grep [key]\". if \"pid\" provided, \"key\" will be ignored")
+	cmd.Flags().StringVar(&i.Args.Pool, "pool", "", "static field of target ThreadPoolExecutor, format: \"class@field\", eg: \"com.test.Client@executor\". if not provided, busy threads will be created")
+	cmd.Flags().IntVarP(&i.Args.Count, "count", "c", 0, "busy thread count to create, must provide if \"pool\" is empty")
+}
+
+func (i *ThreadPoolFullInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if err := checkTargetProcess(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key); err != nil {
+		return err
+	}
+
+	if i.Args.Pool != "" {
+		kv := strings.Split(i.Args.Pool, ClassMethodSplit)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return fmt.Errorf("\"pool\"[%s] is not a valid format, format: class@field", i.Args.Pool)
+		}
+	} else if i.Args.Count <= 0 {
+		return fmt.Errorf("\"count\" must larger than 0 if \"pool\" is empty")
+	}
+
+	if err := checkJavaCmd(ctx, i.Info.ContainerRuntime, i.Info.ContainerId); err != nil {
+		return fmt.Errorf("check java exec error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (i *ThreadPoolFullInjector) Inject(ctx context.Context) error {
+	var timeout int64
+	if i.Info.Timeout != "" {
+		timeout, _ = utils.GetTimeSecond(i.Info.Timeout)
+	}
+
+	rule := &ResourceJVMRule{
+		Fault:  FaultThreadPoolFull,
+		Target: i.Args.Pool,
+		Count:  i.Args.Count,
+	}
+
+	pidList, err := injectRuleConfig(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key, getResourceRuleConfig(rule, timeout))
+	i.Runtime.AttackPids = pidList
+	if err != nil {
+		if rErr := i.Recover(ctx); rErr != nil {
+			log.GetLogger(ctx).Warnf("undo error: %s", rErr.Error())
+		}
+	}
+
+	return err
+}
+
+func (i *ThreadPoolFullInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return recoverRule(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Runtime.AttackPids)
+}
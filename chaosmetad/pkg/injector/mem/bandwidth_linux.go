@@ -0,0 +1,156 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mem
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cgroup"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+)
+
+func init() {
+	injector.Register(TargetMem, FaultMemBandwidth, func() injector.IInjector { return &BandwidthInjector{} })
+}
+
+// BandwidthInjector runs a memory copy worker on each target core to saturate the memory bandwidth
+type BandwidthInjector struct {
+	injector.BaseInjector
+	Args    BandwidthArgs
+	Runtime BandwidthRuntime
+}
+
+type BandwidthArgs struct {
+	Count int    `json:"count,omitempty"`
+	List  string `json:"list,omitempty"`
+	Size  string `json:"size,omitempty"`
+}
+
+type BandwidthRuntime struct {
+}
+
+func (i *BandwidthInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *BandwidthInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *BandwidthInjector) getCmdExecutor() *cmdexec.CmdExecutor {
+	return &cmdexec.CmdExecutor{
+		ContainerId:      i.Info.ContainerId,
+		ContainerRuntime: i.Info.ContainerRuntime,
+		ContainerNs:      []string{namespace.PID},
+	}
+}
+
+func (i *BandwidthInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Size == "" {
+		i.Args.Size = DefaultBufferSize
+	}
+}
+
+func (i *BandwidthInjector) SetOption(cmd *cobra.Command) {
+	// i.BaseInjector.SetOption(cmd)
+
+	cmd.Flags().StringVarP(&i.Args.List, "list", "l", "", "core number list to run the workers, start from 0, eg: \"0-2,6\" means \"0,1,2,6\" core")
+	cmd.Flags().IntVarP(&i.Args.Count, "count", "c", 0, "core count to run the workers（default 0, means all core）. if provide args \"list\", \"count\" will be ignored.")
+	cmd.Flags().StringVarP(&i.Args.Size, "size", "s", "", fmt.Sprintf("memory copied by each worker, must be much larger than the cpu cache, support unit: KB/MB/GB（default %s）", DefaultBufferSize))
+}
+
+// Validator list > count
+func (i *BandwidthInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	kBytes, err := utils.GetKBytes(i.Args.Size)
+	if err != nil {
+		return fmt.Errorf("\"size\" is invalid: %s", err.Error())
+	}
+
+	if kBytes <= 0 {
+		return fmt.Errorf("\"size\" must larger than 0")
+	}
+
+	cpuList, err := cgroup.GetAvailableCpuList(ctx, i.Info.ContainerRuntime, i.Info.ContainerId)
+	if err != nil {
+		return fmt.Errorf("get all available cpu list error: %s", err.Error())
+	}
+
+	if _, err := utils.GetTargetCoreList(i.Args.List, i.Args.Count, cpuList); err != nil {
+		return err
+	}
+
+	if !cmdexec.SupportCmd("taskset") {
+		return fmt.Errorf("not support cmd \"taskset\"")
+	}
+
+	return nil
+}
+
+func (i *BandwidthInjector) Inject(ctx context.Context) error {
+	logger := log.GetLogger(ctx)
+
+	cpuList, err := cgroup.GetAvailableCpuList(ctx, i.Info.ContainerRuntime, i.Info.ContainerId)
+	if err != nil {
+		return fmt.Errorf("get all available cpu list error: %s", err.Error())
+	}
+
+	coreList, err := utils.GetTargetCoreList(i.Args.List, i.Args.Count, cpuList)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("memory bandwidth core list: %v", coreList)
+
+	var timeout int64
+	if i.Info.Timeout != "" {
+		timeout, _ = utils.GetTimeSecond(i.Info.Timeout)
+	}
+
+	e := i.getCmdExecutor()
+	for _, core := range coreList {
+		cmd := fmt.Sprintf("taskset -c %d %s %s %s %d", core, utils.GetToolPath(BandwidthKey), i.Info.Uid, i.Args.Size, timeout)
+		if err := e.StartCmdAndWait(ctx, cmd); err != nil {
+			if err := i.Recover(ctx); err != nil {
+				logger.Warnf("undo error: %s", err.Error())
+			}
+
+			return fmt.Errorf("start worker on core[%d] error: %s", core, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (i *BandwidthInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return process.CheckExistAndKillByKey(ctx, fmt.Sprintf("%s %s", BandwidthKey, i.Info.Uid))
+}
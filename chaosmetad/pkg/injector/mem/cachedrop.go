@@ -0,0 +1,135 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mem
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"strings"
+)
+
+func init() {
+	injector.Register(TargetMem, FaultMemCacheDrop, func() injector.IInjector { return &CacheDropInjector{} })
+}
+
+// CacheDropInjector evicts the page cache periodically until recover
+type CacheDropInjector struct {
+	injector.BaseInjector
+	Args    CacheDropArgs
+	Runtime CacheDropRuntime
+}
+
+type CacheDropArgs struct {
+	FileList string `json:"file_list,omitempty"`
+	Interval string `json:"interval,omitempty"`
+}
+
+type CacheDropRuntime struct {
+}
+
+func (i *CacheDropInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *CacheDropInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *CacheDropInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Interval == "" {
+		i.Args.Interval = DefaultDropInterval
+	}
+}
+
+func (i *CacheDropInjector) SetOption(cmd *cobra.Command) {
+	// i.BaseInjector.SetOption(cmd)
+
+	cmd.Flags().StringVarP(&i.Args.FileList, "file-list", "f", "", "files or dirs whose page cache is evicted, split by \",\", dir means all the files in it. empty means the page cache of the whole system")
+	cmd.Flags().StringVarP(&i.Args.Interval, "interval", "i", "", fmt.Sprintf("evict interval, support unit: \"s、m、h\"(default s)（default %s）", DefaultDropInterval))
+}
+
+func (i *CacheDropInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Info.ContainerId != "" || i.Info.ContainerRuntime != "" {
+		return fmt.Errorf("fault \"%s\" not support in container", FaultMemCacheDrop)
+	}
+
+	interval, err := utils.GetTimeSecond(i.Args.Interval)
+	if err != nil {
+		return fmt.Errorf("\"interval\" is invalid: %s", err.Error())
+	}
+
+	if interval <= 0 {
+		return fmt.Errorf("\"interval\" must larger than 0")
+	}
+
+	for _, f := range strings.Split(i.Args.FileList, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+
+		if strings.Contains(f, "'") {
+			return fmt.Errorf("\"file-list\" can not contain \"'\"")
+		}
+
+		isExist, err := filesys.ExistPathLocal(f)
+		if err != nil {
+			return fmt.Errorf("check %s exist error: %s", f, err.Error())
+		}
+
+		if !isExist {
+			return fmt.Errorf("%s is not exist", f)
+		}
+	}
+
+	return nil
+}
+
+func (i *CacheDropInjector) Inject(ctx context.Context) error {
+	var timeout int64
+	if i.Info.Timeout != "" {
+		timeout, _ = utils.GetTimeSecond(i.Info.Timeout)
+	}
+
+	interval, _ := utils.GetTimeSecond(i.Args.Interval)
+	cmd := fmt.Sprintf("%s %s %d %d '%s'", utils.GetToolPath(CacheDropKey), i.Info.Uid, interval, timeout, strings.TrimSpace(i.Args.FileList))
+	if err := cmdexec.WaitCommonWithNS(ctx, "", "", cmd, nil); err != nil {
+		return fmt.Errorf("start cache drop error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (i *CacheDropInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return process.CheckExistAndKillByKey(ctx, fmt.Sprintf("%s %s", CacheDropKey, i.Info.Uid))
+}
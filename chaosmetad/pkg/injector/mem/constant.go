@@ -33,4 +33,15 @@ const (
 	MemFillKey = "chaosmeta_memfill"
 
 	MemExec = "chaosmeta_mem"
+
+	FaultMemCacheDrop   = "cachedrop"
+	CacheDropKey        = "chaosmeta_cachedrop"
+	DefaultDropInterval = "1s"
+
+	FaultMemSwap   = "swap"
+	SwapSwappiness = "100"
+
+	FaultMemBandwidth = "bandwidth"
+	BandwidthKey      = "chaosmeta_membw"
+	DefaultBufferSize = "256MB"
 )
@@ -159,6 +159,29 @@ func (i *FillInjector) Inject(ctx context.Context) error {
 	return nil
 }
 
+func (i *FillInjector) CheckRampValue(value int) error {
+	if value <= 0 || value > 100 {
+		return fmt.Errorf("percent[%d] must be in (0,100]", value)
+	}
+
+	return nil
+}
+
+// SetRampValue ramp of mem fill is on "percent", so "bytes" is ignored
+func (i *FillInjector) SetRampValue(value int) {
+	i.Args.Percent = value
+	i.Args.Bytes = ""
+}
+
+// Retune releases the filled memory and fills again to the new target
+func (i *FillInjector) Retune(ctx context.Context) error {
+	if err := i.Recover(ctx); err != nil {
+		return fmt.Errorf("release old fill error: %s", err.Error())
+	}
+
+	return i.Inject(ctx)
+}
+
 func (i *FillInjector) Recover(ctx context.Context) error {
 	if i.BaseInjector.Recover(ctx) == nil {
 		return nil
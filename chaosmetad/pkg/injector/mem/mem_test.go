@@ -0,0 +1,123 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mem
+
+import (
+	"context"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newBaseInjector(cr, cId string) injector.BaseInjector {
+	return injector.BaseInjector{Info: injector.BaseInfo{Uid: "test-uid", ContainerRuntime: cr, ContainerId: cId}}
+}
+
+func TestCacheDropInjector_Validator(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "data")
+	if err := os.WriteFile(file, []byte("data"), 0644); err != nil {
+		t.Fatalf("write file error: %s", err.Error())
+	}
+
+	tests := []struct {
+		name    string
+		args    CacheDropArgs
+		wantErr string
+	}{
+		{name: "invalid interval", args: CacheDropArgs{Interval: "1x"}, wantErr: "\"interval\""},
+		{name: "zero interval", args: CacheDropArgs{Interval: "0"}, wantErr: "\"interval\""},
+		{name: "quote in file", args: CacheDropArgs{FileList: "/tmp/a'b"}, wantErr: "\"file-list\""},
+		{name: "file not exist", args: CacheDropArgs{FileList: filepath.Join(dir, "none")}, wantErr: "not exist"},
+		{name: "normal", args: CacheDropArgs{FileList: file + " , " + dir}},
+		{name: "whole system", args: CacheDropArgs{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &CacheDropInjector{BaseInjector: newBaseInjector("", ""), Args: tt.args}
+			i.SetDefault()
+			err := i.Validator(context.Background())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validator() error: %s", err.Error())
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validator() error = %v, want contains %s", err, tt.wantErr)
+			}
+		})
+	}
+
+	i := &CacheDropInjector{BaseInjector: newBaseInjector("", "c1")}
+	if err := i.Validator(context.Background()); err == nil || !strings.Contains(err.Error(), "not support in container") {
+		t.Errorf("Validator() in container, error = %v", err)
+	}
+}
+
+func TestSwapInjector_Validator(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    SwapArgs
+		wantErr string
+	}{
+		{name: "zero percent", args: SwapArgs{Percent: 0, Pid: 1}, wantErr: "\"percent\""},
+		{name: "full percent", args: SwapArgs{Percent: 100, Pid: 1}, wantErr: "\"percent\""},
+		{name: "no target", args: SwapArgs{Percent: 50}, wantErr: "must provide"},
+		{name: "cgroup not exist", args: SwapArgs{Percent: 50, Cgroup: "/chaosmeta-not-exist"}, wantErr: "check cgroup"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &SwapInjector{BaseInjector: newBaseInjector("", ""), Args: tt.args}
+			err := i.Validator(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validator() error = %v, want contains %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSwapInjector_Recover(t *testing.T) {
+	// nothing is changed if inject fails before the cgroup is recorded
+	i := &SwapInjector{BaseInjector: newBaseInjector("", "")}
+	if err := i.Recover(context.Background()); err != nil {
+		t.Errorf("Recover() without runtime error: %s", err.Error())
+	}
+}
+
+func TestBandwidthInjector_Validator(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    BandwidthArgs
+		wantErr string
+	}{
+		{name: "invalid size", args: BandwidthArgs{Size: "1XB"}, wantErr: "\"size\""},
+		{name: "zero size", args: BandwidthArgs{Size: "0MB"}, wantErr: "\"size\""},
+		{name: "negative count", args: BandwidthArgs{Count: -1}, wantErr: "\"count\""},
+		{name: "core not available", args: BandwidthArgs{List: "100000"}, wantErr: "\"core\"[100000]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &BandwidthInjector{BaseInjector: newBaseInjector("", ""), Args: tt.args}
+			i.SetDefault()
+			err := i.Validator(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validator() error = %v, want contains %s", err, tt.wantErr)
+			}
+		})
+	}
+}
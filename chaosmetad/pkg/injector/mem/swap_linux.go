@@ -0,0 +1,220 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mem
+
+import (
+	"context"
+	"fmt"
+	"github.com/shirou/gopsutil/mem"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cgroup"
+	"strconv"
+)
+
+func init() {
+	injector.Register(TargetMem, FaultMemSwap, func() injector.IInjector { return &SwapInjector{} })
+}
+
+// SwapInjector lowers the memory limit of the target cgroup under its usage, so that the kernel pushes its pages to swap.
+// cgroup v1: "memory.limit_in_bytes" is lowered and "memory.swappiness" is set to 100;
+// cgroup v2: "memory.high" is lowered, the processes are throttled instead of being killed if swap is not enough.
+type SwapInjector struct {
+	injector.BaseInjector
+	Args    SwapArgs
+	Runtime SwapRuntime
+}
+
+type SwapArgs struct {
+	Percent int    `json:"percent"`
+	Pid     int    `json:"pid,omitempty"`
+	Cgroup  string `json:"cgroup,omitempty"`
+}
+
+type SwapRuntime struct {
+	CgroupPath    string `json:"cgroup_path,omitempty"`
+	OldLimit      string `json:"old_limit,omitempty"`
+	OldSwappiness string `json:"old_swappiness,omitempty"`
+}
+
+func (i *SwapInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *SwapInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *SwapInjector) SetOption(cmd *cobra.Command) {
+	// i.BaseInjector.SetOption(cmd)
+
+	cmd.Flags().IntVarP(&i.Args.Percent, "percent", "p", 0, "percent of the cgroup's current memory usage to push to swap, an integer in (0,100)")
+	cmd.Flags().IntVar(&i.Args.Pid, "pid", 0, "the memory cgroup of the process is the target. if attack a container, the container's cgroup is the target")
+	cmd.Flags().StringVar(&i.Args.Cgroup, "cgroup", "", "target memory cgroup path relative to the hierarchy root, eg: \"/system.slice/demo.service\". if provided, \"pid\" will be ignored")
+}
+
+func (i *SwapInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Percent <= 0 || i.Args.Percent >= 100 {
+		return fmt.Errorf("\"percent\"[%d] must be in (0,100)", i.Args.Percent)
+	}
+
+	cgroupPath, err := i.getCgroupPath(ctx)
+	if err != nil {
+		return fmt.Errorf("get target cgroup error: %s", err.Error())
+	}
+
+	if _, err := cgroup.ReadCgroupFileStr(ctx, cgroupPath, cgroup.GetSubSys(cgroup.MEMORY), getMemoryUsageFile()); err != nil {
+		return fmt.Errorf("check cgroup[%s] error: %s", cgroupPath, err.Error())
+	}
+
+	swap, err := mem.SwapMemory()
+	if err != nil {
+		return fmt.Errorf("get swap info error: %s", err.Error())
+	}
+
+	if swap.Total == 0 {
+		return fmt.Errorf("swap is not enabled")
+	}
+
+	return nil
+}
+
+func (i *SwapInjector) getCgroupPath(ctx context.Context) (string, error) {
+	if i.Args.Cgroup != "" {
+		return i.Args.Cgroup, nil
+	}
+
+	pid := i.Args.Pid
+	if i.Info.ContainerRuntime != "" {
+		client, err := crclient.GetClient(ctx, i.Info.ContainerRuntime)
+		if err != nil {
+			return "", fmt.Errorf("get %s client error: %s", i.Info.ContainerRuntime, err.Error())
+		}
+
+		if pid, err = client.GetPidById(ctx, i.Info.ContainerId); err != nil {
+			return "", fmt.Errorf("get pid of container[%s] error: %s", i.Info.ContainerId, err.Error())
+		}
+	}
+
+	if pid <= 0 {
+		return "", fmt.Errorf("must provide \"cgroup\" or \"pid\"")
+	}
+
+	return cgroup.GetPidCgroupPath(ctx, pid, cgroup.MEMORY)
+}
+
+func getMemoryUsageFile() string {
+	if cgroup.IsCgroupV2() {
+		return cgroup.MemoryCurrentFile
+	}
+
+	return cgroup.MemoryUsageInBytesFile
+}
+
+func getMemoryLimitFile() string {
+	if cgroup.IsCgroupV2() {
+		return cgroup.MemoryHighFile
+	}
+
+	return cgroup.MemoryLimitInBytesFile
+}
+
+func (i *SwapInjector) Inject(ctx context.Context) error {
+	logger := log.GetLogger(ctx)
+	cgroupPath, err := i.getCgroupPath(ctx)
+	if err != nil {
+		return fmt.Errorf("get target cgroup error: %s", err.Error())
+	}
+
+	subSys := cgroup.GetSubSys(cgroup.MEMORY)
+	usageStr, err := cgroup.ReadCgroupFileStr(ctx, cgroupPath, subSys, getMemoryUsageFile())
+	if err != nil {
+		return fmt.Errorf("get memory usage error: %s", err.Error())
+	}
+
+	usage, err := strconv.ParseInt(usageStr, 10, 64)
+	if err != nil {
+		return fmt.Errorf("memory usage[%s] is not a num: %s", usageStr, err.Error())
+	}
+
+	pushBytes := usage * int64(i.Args.Percent) / 100
+	swap, err := mem.SwapMemory()
+	if err != nil {
+		return fmt.Errorf("get swap info error: %s", err.Error())
+	}
+
+	if uint64(pushBytes) > swap.Free {
+		return fmt.Errorf("swap is not enough, push: %dB, free: %dB", pushBytes, swap.Free)
+	}
+
+	i.Runtime.CgroupPath = cgroupPath
+	if i.Runtime.OldLimit, err = cgroup.ReadCgroupFileStr(ctx, cgroupPath, subSys, getMemoryLimitFile()); err != nil {
+		return fmt.Errorf("get memory limit error: %s", err.Error())
+	}
+
+	if !cgroup.IsCgroupV2() {
+		if i.Runtime.OldSwappiness, err = cgroup.ReadCgroupFileStr(ctx, cgroupPath, subSys, cgroup.MemorySwappinessFile); err != nil {
+			return fmt.Errorf("get swappiness error: %s", err.Error())
+		}
+
+		if err := cgroup.WriteCgroupFileStr(ctx, cgroupPath, subSys, cgroup.MemorySwappinessFile, SwapSwappiness); err != nil {
+			return fmt.Errorf("set swappiness error: %s", err.Error())
+		}
+	}
+
+	logger.Debugf("memory usage of cgroup[%s]: %dB, push to swap: %dB", cgroupPath, usage, pushBytes)
+	if err := cgroup.WriteCgroupFileStr(ctx, cgroupPath, subSys, getMemoryLimitFile(), strconv.FormatInt(usage-pushBytes, 10)); err != nil {
+		if err := i.Recover(ctx); err != nil {
+			logger.Warnf("undo error: %s", err.Error())
+		}
+
+		return fmt.Errorf("lower memory limit error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (i *SwapInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	if i.Runtime.CgroupPath == "" {
+		return nil
+	}
+
+	subSys := cgroup.GetSubSys(cgroup.MEMORY)
+	if i.Runtime.OldLimit != "" {
+		if err := cgroup.WriteCgroupFileStr(ctx, i.Runtime.CgroupPath, subSys, getMemoryLimitFile(), i.Runtime.OldLimit); err != nil {
+			return fmt.Errorf("restore memory limit error: %s", err.Error())
+		}
+	}
+
+	if i.Runtime.OldSwappiness != "" {
+		if err := cgroup.WriteCgroupFileStr(ctx, i.Runtime.CgroupPath, subSys, cgroup.MemorySwappinessFile, i.Runtime.OldSwappiness); err != nil {
+			return fmt.Errorf("restore swappiness error: %s", err.Error())
+		}
+	}
+
+	return nil
+}
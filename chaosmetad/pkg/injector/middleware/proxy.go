@@ -0,0 +1,229 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/mwproxy"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/net"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"regexp"
+	"time"
+)
+
+func init() {
+	injector.Register(TargetMiddleware, FaultRedis, func() injector.IInjector { return &ProxyInjector{} })
+	injector.Register(TargetMiddleware, FaultMySQL, func() injector.IInjector { return &ProxyInjector{} })
+}
+
+// ProxyInjector starts a protocol-aware proxy and redirects the connections to the middleware port to it
+type ProxyInjector struct {
+	injector.BaseInjector
+	Args    ProxyArgs
+	Runtime ProxyRuntime
+}
+
+type ProxyArgs struct {
+	Port      int    `json:"port,omitempty"`
+	ProxyPort int    `json:"proxy_port,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+	Action    string `json:"action,omitempty"`
+	Latency   string `json:"latency,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Code      int    `json:"code,omitempty"`
+	Percent   int    `json:"percent,omitempty"`
+}
+
+type ProxyRuntime struct {
+}
+
+func (i *ProxyInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *ProxyInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *ProxyInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.ProxyPort == 0 && i.Args.Port > 0 {
+		i.Args.ProxyPort = i.Args.Port + DefaultProxyPortOffset
+	}
+
+	if i.Args.Percent == 0 {
+		i.Args.Percent = DefaultPercent
+	}
+}
+
+func (i *ProxyInjector) SetOption(cmd *cobra.Command) {
+	// i.BaseInjector.SetOption(cmd)
+	cmd.Flags().IntVarP(&i.Args.Port, "port", "p", 0, "target middleware port")
+	cmd.Flags().IntVar(&i.Args.ProxyPort, "proxy-port", 0, fmt.Sprintf("port the proxy listens on（default port+%d）", DefaultProxyPortOffset))
+	cmd.Flags().StringVarP(&i.Args.Pattern, "pattern", "", "", "regexp to match the command(redis, joined by space, eg: \"SET key value\") or the query(mysql), empty means all")
+	cmd.Flags().StringVarP(&i.Args.Action, "action", "a", "",
+		fmt.Sprintf("action for the matched command or query, support: %s、%s、%s", mwproxy.ActionDelay, mwproxy.ActionError, mwproxy.ActionDrop))
+	cmd.Flags().StringVarP(&i.Args.Latency, "latency", "l", "", fmt.Sprintf("delay time, only used in action \"%s\", support unit: \"s、ms\", eg: 500ms", mwproxy.ActionDelay))
+	cmd.Flags().StringVarP(&i.Args.Message, "message", "m", "", fmt.Sprintf("error message, only used in action \"%s\"（redis default: \"%s\", mysql default: \"%s\"）",
+		mwproxy.ActionError, mwproxy.DefaultRedisMessage, mwproxy.DefaultMySQLMessage))
+	cmd.Flags().IntVar(&i.Args.Code, "code", 0, fmt.Sprintf("mysql error code, only used in action \"%s\"（default %d）", mwproxy.ActionError, mwproxy.DefaultMySQLCode))
+	cmd.Flags().IntVarP(&i.Args.Percent, "percent", "P", 0, fmt.Sprintf("percent of the matched commands or queries to affect, an integer in (0,100]（default %d）", DefaultPercent))
+}
+
+func (i *ProxyInjector) Validator(ctx context.Context) error {
+	if i.Args.Port <= 0 || i.Args.Port > 65535 {
+		return fmt.Errorf("\"port\" must be in (0,65535]")
+	}
+
+	if i.Args.ProxyPort <= 0 || i.Args.ProxyPort > 65535 {
+		return fmt.Errorf("\"proxy-port\" must be in (0,65535]")
+	}
+
+	if i.Args.ProxyPort == i.Args.Port {
+		return fmt.Errorf("\"proxy-port\" can not be the same as \"port\"")
+	}
+
+	if _, err := regexp.Compile(i.Args.Pattern); err != nil {
+		return fmt.Errorf("\"pattern\" is not a valid regexp: %s", err.Error())
+	}
+
+	switch i.Args.Action {
+	case mwproxy.ActionDelay:
+		if _, err := i.getDelay(); err != nil {
+			return fmt.Errorf("\"latency\" is invalid: %s", err.Error())
+		}
+	case mwproxy.ActionError, mwproxy.ActionDrop:
+	default:
+		return fmt.Errorf("\"action\" is not support %s", i.Args.Action)
+	}
+
+	if i.Args.Code < 0 || i.Args.Code > 65535 {
+		return fmt.Errorf("\"code\" must be in [0,65535]")
+	}
+
+	if i.Args.Percent <= 0 || i.Args.Percent > 100 {
+		return fmt.Errorf("\"percent\"[%d] must be in (0,100]", i.Args.Percent)
+	}
+
+	if !cmdexec.SupportCmd("iptables") {
+		return fmt.Errorf("not support cmd \"iptables\"")
+	}
+
+	return i.BaseInjector.Validator(ctx)
+}
+
+func (i *ProxyInjector) getDelay() (int64, error) {
+	if i.Args.Latency == "" {
+		return 0, fmt.Errorf("must provide")
+	}
+
+	d, err := time.ParseDuration(i.Args.Latency)
+	if err != nil {
+		return 0, err
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf("must larger than 0")
+	}
+
+	return d.Milliseconds(), nil
+}
+
+func (i *ProxyInjector) getRule() (string, error) {
+	rule := &mwproxy.Rule{
+		Pattern: i.Args.Pattern,
+		Action:  i.Args.Action,
+		Message: i.Args.Message,
+		Code:    i.Args.Code,
+		Percent: i.Args.Percent,
+	}
+
+	if i.Args.Action == mwproxy.ActionDelay {
+		rule.Delay, _ = i.getDelay()
+	}
+
+	return mwproxy.EncodeRule(rule)
+}
+
+// getComment identifies the redirect rules of this experiment
+func (i *ProxyInjector) getComment() string {
+	return fmt.Sprintf("%s-%s", ProxyKey, i.Info.Uid)
+}
+
+func (i *ProxyInjector) Inject(ctx context.Context) error {
+	cr, cId := i.Info.ContainerRuntime, i.Info.ContainerId
+	isRedirected, err := net.ExistPortRedirect(ctx, cr, cId, i.Args.Port)
+	if err != nil {
+		return fmt.Errorf("check redirect rule of port[%d] error: %s", i.Args.Port, err.Error())
+	}
+
+	if isRedirected {
+		return fmt.Errorf("port[%d] is already redirected by other rule", i.Args.Port)
+	}
+
+	pid, err := net.GetPidByPort(ctx, cr, cId, i.Args.ProxyPort, net.ProtocolTCP)
+	if err != nil {
+		return fmt.Errorf("get pid by port[%d] error: %s", i.Args.ProxyPort, err.Error())
+	}
+
+	if pid != utils.NoPid {
+		return fmt.Errorf("proxy port[%d] is occupied by process[%d]", i.Args.ProxyPort, pid)
+	}
+
+	rule, err := i.getRule()
+	if err != nil {
+		return fmt.Errorf("encode rule error: %s", err.Error())
+	}
+
+	// the proxy never exits by itself, the redirect rules must be deleted before it is killed in recover stage
+	cmd := fmt.Sprintf("%s %s %s %d %d %d %s %d", utils.GetToolPath(ProxyKey), i.Info.Uid, i.Info.Fault,
+		i.Args.ProxyPort, i.Args.Port, net.ProxyMark, rule, 0)
+	if err := cmdexec.WaitCommonWithNS(ctx, cr, cId, cmd, []string{namespace.NET, namespace.PID}); err != nil {
+		return fmt.Errorf("start proxy error: %s", err.Error())
+	}
+
+	if err := net.AddRedirectRule(ctx, cr, cId, i.Args.Port, i.Args.ProxyPort, net.ProxyMark, i.getComment()); err != nil {
+		if kErr := process.CheckExistAndKillByKey(ctx, fmt.Sprintf("%s %s", ProxyKey, i.Info.Uid)); kErr != nil {
+			log.GetLogger(ctx).Warnf("undo proxy process error: %s", kErr.Error())
+		}
+
+		return fmt.Errorf("add redirect rule error: %s", err.Error())
+	}
+
+	return nil
+}
+
+// Recover deletes the redirect rules first, so that no new connection goes to the proxy before it is killed
+func (i *ProxyInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	if err := net.DeleteRedirectRule(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Port, i.Args.ProxyPort, net.ProxyMark, i.getComment()); err != nil {
+		return fmt.Errorf("delete redirect rule error: %s", err.Error())
+	}
+
+	return process.CheckExistAndKillByKey(ctx, fmt.Sprintf("%s %s", ProxyKey, i.Info.Uid))
+}
@@ -55,6 +55,26 @@ func undoTcWithErr(ctx context.Context, cr, cId string, netInterface, msg string
 	return fmt.Errorf(msg)
 }
 
+// changeNetem re-tunes the netem qdiscs created by inject, the parents are the same as inject
+func changeNetem(ctx context.Context, cr, cId, netInterface, mode string, withFilter bool, fault, args string) error {
+	if !withFilter {
+		return net.ChangeNetemQdisc(ctx, cr, cId, netInterface, "", fault, args)
+	}
+
+	if mode == net.ModeNormal {
+		return net.ChangeNetemQdisc(ctx, cr, cId, netInterface, "1:4", fault, args)
+	}
+
+	for subIndex := 1; subIndex < 4; subIndex++ {
+		parent := fmt.Sprintf("1:%d", subIndex)
+		if err := net.ChangeNetemQdisc(ctx, cr, cId, netInterface, parent, fault, args); err != nil {
+			return fmt.Errorf("change parent %s netem qdisc for %s error: %s", parent, netInterface, err.Error())
+		}
+	}
+
+	return nil
+}
+
 func execRecover(ctx context.Context, cr, cId, netInterface string) error {
 	isTcExist, err := net.ExistTCRootQdisc(ctx, cr, cId, netInterface)
 	if err != nil {
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/net"
@@ -47,9 +48,12 @@ type DelayArgs struct {
 	SrcPort   string `json:"src_port,omitempty"`
 	DstPort   string `json:"dst_port,omitempty"`
 	Force     bool   `json:"force,omitempty"`
+	ProcessSelectorArgs
 }
 
-type DelayRuntime struct{}
+type DelayRuntime struct {
+	ProcessSelectorRuntime
+}
 
 func (i *DelayInjector) GetArgs() interface{} {
 	return &i.Args
@@ -86,6 +90,7 @@ func (i *DelayInjector) SetOption(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&i.Args.DstIp, "dst-ip", "", "filter condition: destination ip. eg: 10.10.0.0/16,192.168.2.5,192.168.1.0/24")
 	cmd.Flags().StringVar(&i.Args.SrcPort, "src-port", "", "filter condition: source port. eg: 8080,9090,12000/8")
 	cmd.Flags().StringVar(&i.Args.DstPort, "dst-port", "", "filter condition: destination port. eg: 8080,9090,12000/8")
+	setProcessSelectorOption(cmd, &i.Args.ProcessSelectorArgs)
 }
 
 // Validator Only one tc network failure can be executed at the same time
@@ -148,6 +153,12 @@ func (i *DelayInjector) Validator(ctx context.Context) error {
 		}
 	}
 
+	if i.Args.ProcessSelectorArgs.IsEnabled() {
+		if err := i.Args.ProcessSelectorArgs.Validator(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.withIpPortFilter()); err != nil {
+			return err
+		}
+	}
+
 	exist, err := net.ExistTCRootQdisc(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface)
 	if err != nil {
 		return fmt.Errorf("check tc rule error: %s", err.Error())
@@ -170,7 +181,7 @@ func (i *DelayInjector) Inject(ctx context.Context) error {
 		}
 	}
 
-	if i.Args.SrcIp == "" && i.Args.DstIp == "" && i.Args.SrcPort == "" && i.Args.DstPort == "" {
+	if !i.withIpPortFilter() && !i.Args.ProcessSelectorArgs.IsEnabled() {
 		return net.AddNetemQdisc(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface, "", FaultDelay, fmt.Sprintf("%s %s", i.Args.Latency, i.Args.Jitter))
 	}
 
@@ -192,6 +203,18 @@ func (i *DelayInjector) Inject(ctx context.Context) error {
 		}
 	}
 
+	if i.Args.ProcessSelectorArgs.IsEnabled() {
+		if err := injectProcessSelector(ctx, i.Info.Uid, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface, &i.Args.ProcessSelectorArgs, &i.Runtime.ProcessSelectorRuntime); err != nil {
+			if rErr := recoverProcessSelector(ctx, i.Info.Uid, i.Info.ContainerRuntime, i.Info.ContainerId, &i.Runtime.ProcessSelectorRuntime); rErr != nil {
+				log.GetLogger(ctx).Warnf("undo process selector error: %s", rErr.Error())
+			}
+
+			return undoTcWithErr(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface, fmt.Sprintf("add process selector for %s error: %s", i.Args.Interface, err.Error()))
+		}
+
+		return nil
+	}
+
 	if err := net.AddFilter(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface, "1:4", i.Args.SrcIp, i.Args.DstIp, i.Args.SrcPort, i.Args.DstPort); err != nil {
 		return undoTcWithErr(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface, fmt.Sprintf("add filter for %s error: %s", i.Args.Interface, err.Error()))
 	}
@@ -199,10 +222,37 @@ func (i *DelayInjector) Inject(ctx context.Context) error {
 	return nil
 }
 
+func (i *DelayInjector) CheckRampValue(value int) error {
+	if value < 0 {
+		return fmt.Errorf("latency ms[%d] can not less than 0", value)
+	}
+
+	return nil
+}
+
+func (i *DelayInjector) SetRampValue(value int) {
+	i.Args.Latency = fmt.Sprintf("%dms", value)
+}
+
+func (i *DelayInjector) Retune(ctx context.Context) error {
+	withFilter := i.withIpPortFilter() || i.Args.ProcessSelectorArgs.IsEnabled()
+	return changeNetem(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface, i.Args.Mode, withFilter, FaultDelay, fmt.Sprintf("%s %s", i.Args.Latency, i.Args.Jitter))
+}
+
 func (i *DelayInjector) Recover(ctx context.Context) error {
 	if i.BaseInjector.Recover(ctx) == nil {
 		return nil
 	}
 
+	if i.Args.ProcessSelectorArgs.IsEnabled() {
+		if err := recoverProcessSelector(ctx, i.Info.Uid, i.Info.ContainerRuntime, i.Info.ContainerId, &i.Runtime.ProcessSelectorRuntime); err != nil {
+			return fmt.Errorf("recover process selector error: %s", err.Error())
+		}
+	}
+
 	return execRecover(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface)
 }
+
+func (i *DelayInjector) withIpPortFilter() bool {
+	return i.Args.SrcIp != "" || i.Args.DstIp != "" || i.Args.SrcPort != "" || i.Args.DstPort != ""
+}
@@ -0,0 +1,224 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cgroup"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/containercgroup"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/net"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+)
+
+// the band of prio qdisc which the selected traffic is classified to, same as the target of ip and port filters
+const selectorClassId = "1:4"
+
+// ProcessSelectorArgs scopes the fault to the traffic sent by the target processes instead of the whole interface.
+// cgroup v1: the processes are moved to a net_cls cgroup with the classid, and a tc cgroup filter classifies by it;
+// cgroup v2: the packets of the processes' cgroups are classified by iptables, so all the processes in the same cgroup are affected.
+type ProcessSelectorArgs struct {
+	Pid int    `json:"pid,omitempty"`
+	Key string `json:"key,omitempty"`
+}
+
+type ProcessSelectorRuntime struct {
+	OldCgroupMap  map[int]string `json:"old_cgroup_map,omitempty"`
+	CgroupV2Paths []string       `json:"cgroup_v2_paths,omitempty"`
+}
+
+func setProcessSelectorOption(cmd *cobra.Command, args *ProcessSelectorArgs) {
+	cmd.Flags().IntVar(&args.Pid, "pid", 0, "filter condition: only the traffic sent by the process")
+	cmd.Flags().StringVar(&args.Key, "key", "", "filter condition: only the traffic sent by the processes got by \"ps -ef | grep [key]\". if \"pid\" provided, \"key\" will be ignored")
+}
+
+func (a *ProcessSelectorArgs) IsEnabled() bool {
+	return a.Pid != 0 || a.Key != ""
+}
+
+func (a *ProcessSelectorArgs) Validator(ctx context.Context, cr, cId string, withIpPortFilter bool) error {
+	if withIpPortFilter {
+		return fmt.Errorf("\"pid\" and \"key\" can not be used with ip and port filters")
+	}
+
+	if _, err := process.GetPidListByPidOrKeyInContainer(ctx, cr, cId, a.Pid, a.Key); err != nil {
+		return fmt.Errorf("get target process's pid error: %s", err.Error())
+	}
+
+	if cgroup.IsCgroupV2() {
+		if !cmdexec.SupportCmd("iptables") {
+			return fmt.Errorf("not support command \"iptables\"")
+		}
+
+		return nil
+	}
+
+	exist, err := filesys.ExistPathLocal(fmt.Sprintf("%s/%s", containercgroup.RootCgroupPath, cgroup.NETCLS))
+	if err != nil {
+		return fmt.Errorf("check %s cgroup exist error: %s", cgroup.NETCLS, err.Error())
+	}
+
+	if !exist {
+		return fmt.Errorf("%s cgroup is not mounted", cgroup.NETCLS)
+	}
+
+	return nil
+}
+
+// injectProcessSelector classifies the traffic of the target processes to the band "1:4" of the root prio qdisc
+func injectProcessSelector(ctx context.Context, uid, cr, cId, netInterface string, args *ProcessSelectorArgs, runtime *ProcessSelectorRuntime) error {
+	logger := log.GetLogger(ctx)
+	pidList, err := process.GetPidListByPidOrKeyInContainer(ctx, cr, cId, args.Pid, args.Key)
+	if err != nil {
+		return fmt.Errorf("get target process's pid error: %s", err.Error())
+	}
+	logger.Debugf("target pid list: %v", pidList)
+
+	if cgroup.IsCgroupV2() {
+		for _, pid := range pidList {
+			cgroupPath, err := cgroup.GetPidCurCgroupV2(ctx, pid)
+			if err != nil {
+				return err
+			}
+
+			if utils.StrListContain(runtime.CgroupV2Paths, cgroupPath) {
+				continue
+			}
+
+			if err := net.AddCgroupClassifyRule(ctx, cr, cId, cgroupPath, selectorClassId); err != nil {
+				return fmt.Errorf("add classify rule for cgroup[%s] error: %s", cgroupPath, err.Error())
+			}
+			runtime.CgroupV2Paths = append(runtime.CgroupV2Paths, cgroupPath)
+		}
+
+		return nil
+	}
+
+	runtime.OldCgroupMap, err = cgroup.GetPidListCurCgroup(ctx, pidList, cgroup.NETCLS)
+	if err != nil {
+		return fmt.Errorf("get old path error: %s", err.Error())
+	}
+	logger.Debugf("old cgroup path: %v", runtime.OldCgroupMap)
+
+	netClsPath, err := getNetClsPath(ctx, uid, cr, cId)
+	if err != nil {
+		return err
+	}
+
+	if err := cgroup.NewCgroup(ctx, netClsPath, cgroup.GetNetClsConfig(selectorClassId, netClsPath)); err != nil {
+		return fmt.Errorf("create cgroup[%s] error: %s", netClsPath, err.Error())
+	}
+
+	for _, pid := range pidList {
+		if err := cgroup.MoveProcToCgroup(ctx, pid, netClsPath); err != nil {
+			return fmt.Errorf("move pid[%d] to cgroup[%s] error: %s", pid, netClsPath, err.Error())
+		}
+	}
+
+	return net.AddCgroupFilter(ctx, cr, cId, netInterface)
+}
+
+// recoverProcessSelector the tc filter is removed with the root qdisc, so only the cgroups need to be recovered
+func recoverProcessSelector(ctx context.Context, uid, cr, cId string, runtime *ProcessSelectorRuntime) error {
+	for _, cgroupPath := range runtime.CgroupV2Paths {
+		if err := net.DeleteCgroupClassifyRule(ctx, cr, cId, cgroupPath, selectorClassId); err != nil {
+			return fmt.Errorf("delete classify rule for cgroup[%s] error: %s", cgroupPath, err.Error())
+		}
+	}
+
+	if cgroup.IsCgroupV2() {
+		return nil
+	}
+
+	netClsPath, err := getNetClsPath(ctx, uid, cr, cId)
+	if err != nil {
+		return err
+	}
+
+	isCgroupExist, err := filesys.ExistPathLocal(netClsPath)
+	if err != nil {
+		return fmt.Errorf("check cgroup[%s] exist error: %s", netClsPath, err.Error())
+	}
+
+	if !isCgroupExist {
+		return nil
+	}
+
+	for pid, oldPath := range runtime.OldCgroupMap {
+		exist, _ := process.ExistPid(ctx, pid)
+		if !exist {
+			continue
+		}
+
+		if err := cgroup.MoveProcToCgroup(ctx, pid, fmt.Sprintf("%s/%s%s", containercgroup.RootCgroupPath, cgroup.NETCLS, oldPath)); err != nil {
+			return fmt.Errorf("recover pid[%d] error: %s", pid, err.Error())
+		}
+	}
+
+	// the child processes created during the experiment are moved to the parent of the experiment cgroup
+	taskList, err := cgroup.GetPidStrListByCgroup(ctx, netClsPath)
+	if err != nil {
+		return fmt.Errorf("fail to get pid from cgroup[%s]: %s", netClsPath, err.Error())
+	}
+
+	if len(taskList) > 0 {
+		prefix, err := getNetClsPrefix(ctx, cr, cId)
+		if err != nil {
+			return err
+		}
+
+		for _, task := range taskList {
+			log.GetLogger(ctx).Warnf("fail to get task[%d]'s old cgroup path, move to the parent cgroup instead", task)
+			if err := cgroup.MoveTaskToCgroup(ctx, task, fmt.Sprintf("%s/%s%s", containercgroup.RootCgroupPath, cgroup.NETCLS, prefix)); err != nil {
+				return fmt.Errorf("recover task[%d] error: %s", task, err.Error())
+			}
+		}
+	}
+
+	if err := cgroup.RemoveCgroup(ctx, netClsPath); err != nil {
+		return fmt.Errorf("remove cgroup[%s] error: %s", netClsPath, err.Error())
+	}
+
+	return nil
+}
+
+func getNetClsPrefix(ctx context.Context, cr, cId string) (string, error) {
+	if cr == "" {
+		return "", nil
+	}
+
+	prefix, err := cgroup.GetContainerCgroupPath(ctx, cr, cId, cgroup.NETCLS)
+	if err != nil {
+		return "", fmt.Errorf("get cgroup path of container[%s] error: %s", cId, err.Error())
+	}
+
+	return prefix, nil
+}
+
+func getNetClsPath(ctx context.Context, uid, cr, cId string) (string, error) {
+	prefix, err := getNetClsPrefix(ctx, cr, cId)
+	if err != nil {
+		return "", err
+	}
+
+	return cgroup.GetNetClsCPath(uid, prefix), nil
+}
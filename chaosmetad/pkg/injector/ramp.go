@@ -0,0 +1,298 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"runtime/debug"
+	"time"
+)
+
+const (
+	RampShapeLinear = "linear"
+	RampShapeStep   = "step"
+)
+
+// IRampInjector is implemented by the injectors whose fault intensity can be changed while the fault is running
+type IRampInjector interface {
+	// CheckRampValue checks whether value is a valid intensity of the fault
+	CheckRampValue(value int) error
+	// SetRampValue sets the intensity arg of the fault
+	SetRampValue(value int)
+	// Retune applies the current intensity arg to the running fault
+	Retune(ctx context.Context) error
+}
+
+// RampInfo describes how the intensity of a fault changes from Start to End.
+// linear: the value moves from Start to End evenly over the experiment's timeout, re-tuned every Interval;
+// step: the value moves Step towards End every Interval.
+type RampInfo struct {
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	Step     int    `json:"step,omitempty"`
+	Interval string `json:"interval"`
+	Shape    string `json:"shape"`
+	// state information
+	Stage   int `json:"stage"`
+	Current int `json:"current"`
+}
+
+func (r *RampInfo) IsEnabled() bool {
+	return r.Interval != ""
+}
+
+// IsSet returns true if any ramp arg is provided, the ramp args without "ramp-interval" are invalid
+func (r *RampInfo) IsSet() bool {
+	return *r != RampInfo{}
+}
+
+// SameSpec reports whether the ramp args of r and o are the same, the state information is not compared
+func (r *RampInfo) SameSpec(o *RampInfo) bool {
+	return r.Start == o.Start && r.End == o.End && r.Step == o.Step && r.Interval == o.Interval && r.Shape == o.Shape
+}
+
+func (r *RampInfo) Validator(timeout string) error {
+	if !r.IsEnabled() {
+		return fmt.Errorf("\"ramp-interval\" must be provided when using ramp args")
+	}
+
+	interval, err := utils.GetTimeSecond(r.Interval)
+	if err != nil {
+		return fmt.Errorf("\"ramp-interval\" is not valid: %s", err.Error())
+	}
+
+	if interval <= 0 {
+		return fmt.Errorf("\"ramp-interval\" must larger than 0")
+	}
+
+	if r.Start == r.End {
+		return fmt.Errorf("\"ramp-start\" and \"ramp-end\" can not be equal")
+	}
+
+	switch r.Shape {
+	case RampShapeLinear:
+		if timeout == "" {
+			return fmt.Errorf("ramp shape \"%s\" must provide \"timeout\"", RampShapeLinear)
+		}
+
+		timeoutSec, _ := utils.GetTimeSecond(timeout)
+		if timeoutSec < interval {
+			return fmt.Errorf("\"timeout\" must not less than \"ramp-interval\"")
+		}
+	case RampShapeStep:
+		if r.Step <= 0 {
+			return fmt.Errorf("\"ramp-step\" must larger than 0 in ramp shape \"%s\"", RampShapeStep)
+		}
+	default:
+		return fmt.Errorf("\"ramp-shape\" is not support: %s, only support: %s, %s", r.Shape, RampShapeLinear, RampShapeStep)
+	}
+
+	return nil
+}
+
+// GetStageValue return the intensity of stage, stage 0 is Start
+func (r *RampInfo) GetStageValue(stage int, timeoutSec int64) int {
+	var value int
+	if r.Shape == RampShapeStep {
+		if r.End > r.Start {
+			value = r.Start + stage*r.Step
+		} else {
+			value = r.Start - stage*r.Step
+		}
+	} else {
+		if timeoutSec <= 0 {
+			return r.End
+		}
+
+		interval, _ := utils.GetTimeSecond(r.Interval)
+		value = r.Start + int(int64(r.End-r.Start)*int64(stage)*interval/timeoutSec)
+	}
+
+	if (r.End > r.Start && value > r.End) || (r.End < r.Start && value < r.End) {
+		value = r.End
+	}
+
+	return value
+}
+
+func parseRamp(rampStr string) (*RampInfo, error) {
+	ramp := &RampInfo{}
+	if rampStr == "" {
+		return ramp, nil
+	}
+
+	if err := json.Unmarshal([]byte(rampStr), ramp); err != nil {
+		return nil, fmt.Errorf("load ramp from experiment error: %s", err.Error())
+	}
+
+	return ramp, nil
+}
+
+func getRampStr(ramp *RampInfo) (string, error) {
+	if !ramp.IsEnabled() {
+		return "", nil
+	}
+
+	rampByte, err := json.Marshal(ramp)
+	if err != nil {
+		return "", fmt.Errorf("ramp convert to string error: %s", err.Error())
+	}
+
+	return string(rampByte), nil
+}
+
+func validRamp(info *BaseInfo, i IInjector) error {
+	rampI, ok := i.(IRampInjector)
+	if !ok {
+		return fmt.Errorf("fault[%s] of target[%s] not support ramp", info.Fault, info.Target)
+	}
+
+	if err := info.Ramp.Validator(info.Timeout); err != nil {
+		return err
+	}
+
+	if err := rampI.CheckRampValue(info.Ramp.Start); err != nil {
+		return fmt.Errorf("\"ramp-start\" is invalid: %s", err.Error())
+	}
+
+	if err := rampI.CheckRampValue(info.Ramp.End); err != nil {
+		return fmt.Errorf("\"ramp-end\" is invalid: %s", err.Error())
+	}
+
+	rampI.SetRampValue(info.Ramp.Start)
+	info.Ramp.Stage, info.Ramp.Current = 0, info.Ramp.Start
+	return nil
+}
+
+func startRamp(ctx context.Context, uid string) error {
+	return cmdexec.StartBashCmd(ctx, utils.GetRampCmd(uid))
+}
+
+// stopRamp kills the ramp process of the experiment, so that a retune in progress can not inject the fault again after recover
+func stopRamp(ctx context.Context, uid string) error {
+	return process.CheckExistAndKillByKey(ctx, utils.GetRampKey(uid))
+}
+
+// ProcessRamp re-tunes the running experiment stage by stage until the end value is reached or the experiment is not running
+func ProcessRamp(ctx context.Context, uid string) (code int, msg string) {
+	logger := log.GetLogger(ctx)
+	defer func() {
+		if err := recover(); err != any(nil) {
+			logger.Debug(string(debug.Stack()))
+			code, msg = errutil.UnknownErr, fmt.Sprintf("ProcessRamp Exception: %v", err)
+		}
+	}()
+
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return errutil.DBErr, fmt.Sprintf("connect db error: %s", err.Error())
+	}
+
+	for {
+		exp, err := db.GetByUid(uid)
+		if err != nil {
+			return errutil.DBErr, fmt.Sprintf("query experiment by uid[%s] error: %s", uid, err.Error())
+		}
+
+		ramp, err := parseRamp(exp.Ramp)
+		if err != nil {
+			return errutil.InternalErr, err.Error()
+		}
+
+		if !ramp.IsEnabled() {
+			return errutil.BadArgsErr, fmt.Sprintf("experiment[%s] has no ramp", uid)
+		}
+
+		if ramp.Current == ramp.End {
+			return errutil.NoErr, "ramp finish"
+		}
+
+		interval, _ := utils.GetTimeSecond(ramp.Interval)
+		time.Sleep(time.Duration(interval) * time.Second)
+
+		// status may be changed during sleep, such as recovered by user
+		exp, err = db.GetByUid(uid)
+		if err != nil {
+			return errutil.DBErr, fmt.Sprintf("query experiment by uid[%s] error: %s", uid, err.Error())
+		}
+
+		if exp.Status != utils.StatusSuccess {
+			return errutil.NoErr, fmt.Sprintf("experiment status is %s, stop ramp", exp.Status)
+		}
+
+		i, err := NewInjector(exp.Target, exp.Fault)
+		if err != nil {
+			return errutil.InternalErr, fmt.Sprintf("find injector by target[%s] and fault[%s] error: %s", exp.Target, exp.Fault, err.Error())
+		}
+
+		rampI, ok := i.(IRampInjector)
+		if !ok {
+			return errutil.InternalErr, fmt.Sprintf("fault[%s] of target[%s] not support ramp", exp.Fault, exp.Target)
+		}
+
+		if err := i.LoadInjector(exp, i.GetArgs(), i.GetRuntime()); err != nil {
+			return errutil.InternalErr, fmt.Sprintf("load experiment to injector error: %s", err.Error())
+		}
+
+		var timeoutSec int64
+		if exp.Timeout != "" {
+			timeoutSec, _ = utils.GetTimeSecond(exp.Timeout)
+		}
+
+		ramp.Stage++
+		ramp.Current = ramp.GetStageValue(ramp.Stage, timeoutSec)
+		rampI.SetRampValue(ramp.Current)
+		logger.Infof("ramp stage: %d, value: %d", ramp.Stage, ramp.Current)
+
+		stageCtx := utils.GetCtxWithCmdRecorder(ctx)
+		if err := rampI.Retune(stageCtx); err != nil {
+			errMsg := fmt.Sprintf("retune stage[%d] with value[%d] error: %s", ramp.Stage, ramp.Current, err.Error())
+			// the experiment may be recovered during retune, and the failure is expected then
+			updated, err := db.UpdateStatusAndErrIfStatus(uid, utils.StatusSuccess, utils.StatusError, errMsg)
+			if err != nil {
+				logger.Warnf("update status[%s] for experiment[%s] error: %s", utils.StatusError, uid, err.Error())
+			} else if !updated {
+				return errutil.NoErr, "experiment status is changed during retune, stop ramp"
+			}
+
+			return recordErrEvent(stageCtx, uid, errutil.InjectErr, errMsg)
+		}
+
+		i.SetCommonArgs(&BaseInfo{Ramp: *ramp})
+		newExp, err := i.OptionToExp(i.GetArgs(), i.GetRuntime())
+		if err != nil {
+			return errutil.InternalErr, fmt.Sprintf("create experiment error: %s", err.Error())
+		}
+
+		updated, err := db.UpdateIfStatus(newExp, utils.StatusSuccess)
+		if err != nil {
+			return errutil.DBErr, fmt.Sprintf("update ramp stage of experiment[%s] error: %s", uid, err.Error())
+		}
+
+		if !updated {
+			return errutil.NoErr, "experiment status is changed during retune, stop ramp"
+		}
+	}
+}
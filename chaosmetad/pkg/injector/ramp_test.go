@@ -0,0 +1,106 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import "testing"
+
+func TestRampInfo_GetStageValue(t *testing.T) {
+	type args struct {
+		stage      int
+		timeoutSec int64
+	}
+	tests := []struct {
+		name string
+		ramp RampInfo
+		args args
+		want int
+	}{
+		{name: "linear start",
+			ramp: RampInfo{Start: 10, End: 90, Interval: "10s", Shape: RampShapeLinear},
+			args: args{stage: 0, timeoutSec: 80},
+			want: 10,
+		},
+		{name: "linear middle",
+			ramp: RampInfo{Start: 10, End: 90, Interval: "10s", Shape: RampShapeLinear},
+			args: args{stage: 4, timeoutSec: 80},
+			want: 50,
+		},
+		{name: "linear over end",
+			ramp: RampInfo{Start: 10, End: 90, Interval: "10s", Shape: RampShapeLinear},
+			args: args{stage: 9, timeoutSec: 80},
+			want: 90,
+		},
+		{name: "linear down",
+			ramp: RampInfo{Start: 100, End: 0, Interval: "1m", Shape: RampShapeLinear},
+			args: args{stage: 1, timeoutSec: 240},
+			want: 75,
+		},
+		{name: "step up",
+			ramp: RampInfo{Start: 10, End: 50, Step: 15, Interval: "10s", Shape: RampShapeStep},
+			args: args{stage: 2, timeoutSec: 0},
+			want: 40,
+		},
+		{name: "step up over end",
+			ramp: RampInfo{Start: 10, End: 50, Step: 15, Interval: "10s", Shape: RampShapeStep},
+			args: args{stage: 3, timeoutSec: 0},
+			want: 50,
+		},
+		{name: "step down",
+			ramp: RampInfo{Start: 50, End: 10, Step: 15, Interval: "10s", Shape: RampShapeStep},
+			args: args{stage: 3, timeoutSec: 0},
+			want: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ramp.GetStageValue(tt.args.stage, tt.args.timeoutSec); got != tt.want {
+				t.Errorf("GetStageValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRampInfo_Validator(t *testing.T) {
+	tests := []struct {
+		name    string
+		ramp    RampInfo
+		timeout string
+		wantErr bool
+	}{
+		{name: "linear", ramp: RampInfo{Start: 10, End: 90, Interval: "10s", Shape: RampShapeLinear}, timeout: "1m"},
+		{name: "linear without timeout", ramp: RampInfo{Start: 10, End: 90, Interval: "10s", Shape: RampShapeLinear}, wantErr: true},
+		{name: "step", ramp: RampInfo{Start: 10, End: 90, Step: 20, Interval: "10s", Shape: RampShapeStep}},
+		{name: "step without step", ramp: RampInfo{Start: 10, End: 90, Interval: "10s", Shape: RampShapeStep}, wantErr: true},
+		{name: "start and end without interval", ramp: RampInfo{Start: 10, End: 90}, timeout: "1m", wantErr: true},
+		{name: "equal start and end", ramp: RampInfo{Start: 10, End: 10, Interval: "10s", Shape: RampShapeLinear}, timeout: "1m", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.ramp.Validator(tt.timeout); (err != nil) != tt.wantErr {
+				t.Errorf("Validator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBaseInjector_SetCommonArgs_ramp(t *testing.T) {
+	i := &BaseInjector{}
+	i.SetCommonArgs(&BaseInfo{Ramp: RampInfo{Start: 10, End: 90}})
+	if !i.Info.Ramp.IsSet() || i.Info.Ramp.IsEnabled() {
+		t.Errorf("SetCommonArgs() ramp = %+v, want the ramp args kept to be rejected by validation", i.Info.Ramp)
+	}
+}
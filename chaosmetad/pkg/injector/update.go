@@ -0,0 +1,160 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"reflect"
+	"runtime/debug"
+	"sort"
+	"strings"
+)
+
+// IUpdateInjector is implemented by the injectors whose args can be changed while the fault is running
+type IUpdateInjector interface {
+	// UpdateValidator checks the args after the new args are merged into them
+	UpdateValidator(ctx context.Context) error
+	// Update applies the current args to the running fault
+	Update(ctx context.Context) error
+	// GetUpdatableArgs returns the json keys of the args which can be changed, the others must keep unchanged
+	GetUpdatableArgs() []string
+}
+
+// ProcessUpdate merges argsStr into the args of the running experiment and applies them, the args not in argsStr keep unchanged
+func ProcessUpdate(ctx context.Context, uid, argsStr string) (code int, msg string) {
+	logger := log.GetLogger(ctx)
+	defer func() {
+		if err := recover(); err != any(nil) {
+			logger.Debug(string(debug.Stack()))
+			code, msg = errutil.UnknownErr, fmt.Sprintf("ProcessUpdate Exception: %v", err)
+		}
+	}()
+
+	ctx = utils.GetCtxWithCmdRecorder(ctx)
+	logger.Debugf("uid: %s, args: %s", uid, argsStr)
+
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return errutil.DBErr, fmt.Sprintf("connect db error: %s", err.Error())
+	}
+
+	exp, err := db.GetByUid(uid)
+	if err != nil {
+		return errutil.DBErr, fmt.Sprintf("query experiment by uid[%s] error: %s", uid, err.Error())
+	}
+
+	if exp.Status != utils.StatusSuccess {
+		return errutil.BadArgsErr, fmt.Sprintf("experiment status is %s, only running experiment can be updated", exp.Status)
+	}
+
+	i, err := NewInjector(exp.Target, exp.Fault)
+	if err != nil {
+		return errutil.InternalErr, fmt.Sprintf("find injector by target[%s] and fault[%s] error: %s", exp.Target, exp.Fault, err.Error())
+	}
+
+	updateI, ok := i.(IUpdateInjector)
+	if !ok {
+		return errutil.BadArgsErr, fmt.Sprintf("fault[%s] of target[%s] not support update", exp.Fault, exp.Target)
+	}
+
+	if err := i.LoadInjector(exp, i.GetArgs(), i.GetRuntime()); err != nil {
+		return errutil.InternalErr, fmt.Sprintf("load experiment to injector error: %s", err.Error())
+	}
+
+	oldArgs, err := json.Marshal(i.GetArgs())
+	if err != nil {
+		return errutil.InternalErr, fmt.Sprintf("args convert to string error: %s", err.Error())
+	}
+
+	if err := json.Unmarshal([]byte(argsStr), i.GetArgs()); err != nil {
+		return errutil.BadArgsErr, fmt.Sprintf("\"args\" is not a valid json: %s", err.Error())
+	}
+
+	if err := checkUpdatableArgs(oldArgs, i.GetArgs(), updateI.GetUpdatableArgs()); err != nil {
+		return errutil.BadArgsErr, fmt.Sprintf("args error: %s", err.Error())
+	}
+
+	if err := updateI.UpdateValidator(ctx); err != nil {
+		return errutil.BadArgsErr, fmt.Sprintf("args error: %s", err.Error())
+	}
+
+	if err := updateI.Update(ctx); err != nil {
+		return recordErrEvent(ctx, uid, errutil.InjectErr, fmt.Sprintf("update error: %s", err.Error()))
+	}
+
+	newExp, err := i.OptionToExp(i.GetArgs(), i.GetRuntime())
+	if err != nil {
+		return errutil.InternalErr, fmt.Sprintf("create experiment error: %s", err.Error())
+	}
+
+	if err := db.Update(newExp); err != nil {
+		return errutil.DBErr, fmt.Sprintf("update args of experiment[%s] error: %s", uid, err.Error())
+	}
+
+	logger.Infof("args: %s", newExp.Args)
+	logger.Info("update success")
+	recordEvent(ctx, uid, utils.EventUpdated, fmt.Sprintf("args: %s", newExp.Args))
+	return errutil.NoErr, "success"
+}
+
+// checkUpdatableArgs the args out of updatable, such as the target process, must keep the values of oldArgs,
+// because the running fault is not applied to the new targets
+func checkUpdatableArgs(oldArgs []byte, args interface{}, updatable []string) error {
+	newArgs, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("args convert to string error: %s", err.Error())
+	}
+
+	var oldMap, newMap map[string]interface{}
+	if err := json.Unmarshal(oldArgs, &oldMap); err != nil {
+		return fmt.Errorf("load old args error: %s", err.Error())
+	}
+
+	if err := json.Unmarshal(newArgs, &newMap); err != nil {
+		return fmt.Errorf("load new args error: %s", err.Error())
+	}
+
+	updatableSet := make(map[string]bool)
+	for _, key := range updatable {
+		updatableSet[key] = true
+	}
+
+	var keys []string
+	for key := range oldMap {
+		keys = append(keys, key)
+	}
+	for key := range newMap {
+		if _, ok := oldMap[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !updatableSet[key] && !reflect.DeepEqual(oldMap[key], newMap[key]) {
+			return fmt.Errorf("\"%s\" can not be updated, only support: %s", key, strings.Join(updatable, ", "))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "chaosmetad-injector")
+	if err != nil {
+		panic(err)
+	}
+
+	storage.Path = filepath.Join(dir, "chaosmetad.dat")
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+type testUpdateArgs struct {
+	Pid     int    `json:"pid,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Percent int    `json:"percent"`
+}
+
+type testUpdateRuntime struct {
+	AttackPids []int `json:"attack_pids"`
+}
+
+type testUpdateInjector struct {
+	BaseInjector
+	Args    testUpdateArgs
+	Runtime testUpdateRuntime
+}
+
+// testUpdated records the percent applied by Update
+var testUpdated = make(map[string]int)
+
+func (i *testUpdateInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *testUpdateInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *testUpdateInjector) UpdateValidator(ctx context.Context) error {
+	if i.Args.Percent <= 0 || i.Args.Percent > 100 {
+		return fmt.Errorf("\"percent\" must in (0, 100]")
+	}
+
+	return nil
+}
+
+func (i *testUpdateInjector) Update(ctx context.Context) error {
+	testUpdated[i.Info.Uid] = i.Args.Percent
+	return nil
+}
+
+func (i *testUpdateInjector) GetUpdatableArgs() []string {
+	return []string{"percent"}
+}
+
+func init() {
+	Register("test", "update", func() IInjector { return &testUpdateInjector{} })
+}
+
+func Test_checkUpdatableArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldArgs string
+		args    testUpdateArgs
+		wantErr bool
+	}{
+		{name: "updatable changed", oldArgs: `{"pid":10,"percent":50}`, args: testUpdateArgs{Pid: 10, Percent: 80}},
+		{name: "nothing changed", oldArgs: `{"key":"java","percent":50}`, args: testUpdateArgs{Key: "java", Percent: 50}},
+		{name: "pid changed", oldArgs: `{"pid":10,"percent":50}`, args: testUpdateArgs{Pid: 11, Percent: 50}, wantErr: true},
+		{name: "key added", oldArgs: `{"pid":10,"percent":50}`, args: testUpdateArgs{Pid: 10, Key: "java", Percent: 50}, wantErr: true},
+		{name: "pid removed", oldArgs: `{"pid":10,"percent":50}`, args: testUpdateArgs{Percent: 50}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkUpdatableArgs([]byte(tt.oldArgs), &tt.args, []string{"percent"}); (err != nil) != tt.wantErr {
+				t.Errorf("checkUpdatableArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProcessUpdate(t *testing.T) {
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		t.Fatalf("GetExperimentStore() error = %v", err)
+	}
+
+	for uid, status := range map[string]string{"update-success": utils.StatusSuccess, "update-destroyed": utils.StatusDestroyed} {
+		if err := db.Insert(&storage.Experiment{Uid: uid, Target: "test", Fault: "update", Args: `{"pid":10,"percent":50}`, Runtime: "{}", Status: status}); err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+	}
+
+	if err := db.Insert(&storage.Experiment{Uid: "update-other", Target: "test", Fault: "idempotent", Args: `{"percent":50}`, Runtime: "{}", Status: utils.StatusSuccess}); err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		uid      string
+		args     string
+		wantCode int
+		wantArgs string
+	}{
+		{name: "update percent", uid: "update-success", args: `{"percent":80}`, wantCode: errutil.NoErr, wantArgs: `{"pid":10,"percent":80}`},
+		{name: "update pid", uid: "update-success", args: `{"pid":11}`, wantCode: errutil.BadArgsErr, wantArgs: `{"pid":10,"percent":80}`},
+		{name: "invalid percent", uid: "update-success", args: `{"percent":200}`, wantCode: errutil.BadArgsErr, wantArgs: `{"pid":10,"percent":80}`},
+		{name: "invalid json", uid: "update-success", args: `{"percent":`, wantCode: errutil.BadArgsErr, wantArgs: `{"pid":10,"percent":80}`},
+		{name: "not running", uid: "update-destroyed", args: `{"percent":80}`, wantCode: errutil.BadArgsErr, wantArgs: `{"pid":10,"percent":50}`},
+		{name: "not support update", uid: "update-other", args: `{"percent":80}`, wantCode: errutil.BadArgsErr, wantArgs: `{"percent":50}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, msg := ProcessUpdate(context.Background(), tt.uid, tt.args); code != tt.wantCode {
+				t.Fatalf("ProcessUpdate() = %d, %s, want code %d", code, msg, tt.wantCode)
+			}
+
+			exp, err := db.GetByUid(tt.uid)
+			if err != nil {
+				t.Fatalf("GetByUid() error = %v", err)
+			}
+
+			if exp.Args != tt.wantArgs {
+				t.Errorf("args after ProcessUpdate() = %s, want %s", exp.Args, tt.wantArgs)
+			}
+		})
+	}
+
+	if testUpdated["update-success"] != 80 || len(testUpdated) != 1 {
+		t.Errorf("Update() is applied with %v, want only percent 80 of update-success", testUpdated)
+	}
+}
@@ -79,7 +79,12 @@ func setLogger() {
 	})
 	logger.SetLevel(getLogLevel(Level))
 	if Path == "" {
-		logger.SetOutput(os.Stdout)
+		// keep stdout clean for the structured result
+		if utils.Output != utils.OutputTable {
+			logger.SetOutput(os.Stderr)
+		} else {
+			logger.SetOutput(os.Stdout)
+		}
 	} else {
 		f, err := getLogPathFile()
 		if err != nil {
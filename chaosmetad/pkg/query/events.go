@@ -0,0 +1,103 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/bndr/gotabulate"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/handler"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/model"
+	"strings"
+)
+
+func PrintEventsByUid(ctx context.Context, uid string, offset, limit uint, format string) {
+	if format != TableFormat && format != JsonFormat {
+		errutil.SolveErr(ctx, errutil.BadArgsErr, fmt.Sprintf("not support format: %s", format))
+	}
+
+	if uid == "" {
+		errutil.SolveErr(ctx, errutil.BadArgsErr, "\"uid\" is empty")
+	}
+
+	db, dbErr := storage.GetEventStore()
+	if dbErr != nil {
+		errutil.SolveErr(ctx, errutil.DBErr, dbErr.Error())
+	}
+	events, total, queryErr := db.QueryByUid(uid, offset, limit)
+	if queryErr != nil {
+		errutil.SolveErr(ctx, errutil.DBErr, queryErr.Error())
+	}
+
+	reList := make([]model.EventDataUnit, len(events))
+	for i, event := range events {
+		reList[i] = handler.EventToEventDataUnit(event)
+	}
+
+	if utils.Output != utils.OutputTable {
+		errutil.SolveResult(ctx, &errutil.Result{Uid: uid, Code: errutil.NoErr, Message: "success", Data: &model.EventsResponseData{Events: reList, Total: total}})
+	}
+
+	if format == JsonFormat {
+		printEventsJson(ctx, reList, total)
+	} else {
+		printEventsTable(ctx, uid, reList, total)
+	}
+}
+
+func printEventsJson(ctx context.Context, events []model.EventDataUnit, total int64) {
+	res := &model.EventsResponseData{
+		Events: events,
+		Total:  total,
+	}
+
+	reBytes, err := json.Marshal(res)
+	if err != nil {
+		errutil.SolveErr(ctx, errutil.InternalErr, fmt.Sprintf("events response change to string error: %s", err.Error()))
+	}
+
+	if log.Path != "" {
+		log.GetLogger(ctx).Info(string(reBytes))
+	} else {
+		fmt.Println(string(reBytes))
+	}
+}
+
+func printEventsTable(ctx context.Context, uid string, events []model.EventDataUnit, total int64) {
+	var formatData string
+	if len(events) != 0 {
+		var data [][]interface{}
+		for _, event := range events {
+			data = append(data, []interface{}{event.CreateTime, event.Type, event.Actor, event.TraceId,
+				event.Message, strings.Join(event.Commands, "\n")})
+		}
+
+		t := gotabulate.Create(data)
+		t.SetHeaders([]string{"TIME", "TYPE", "ACTOR", "TRACE_ID", "MESSAGE", "COMMANDS"})
+		t.SetEmptyString("None")
+		t.SetAlign("left")
+		t.SetWrapStrings(true)
+		formatData = t.Render("grid")
+	}
+
+	log.GetLogger(ctx).Infof("total count of events of experiment[%s]: %d\n%s\n", uid, total, formatData)
+}
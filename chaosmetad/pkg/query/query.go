@@ -23,6 +23,7 @@ import (
 	"github.com/bndr/gotabulate"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/handler"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/model"
@@ -68,6 +69,10 @@ func PrintExpByOption(ctx context.Context, o *OptionExpQuery, ifAll bool, format
 		errutil.SolveErr(ctx, errutil.DBErr, queryErr.Error())
 	}
 
+	if utils.Output != utils.OutputTable {
+		errutil.SolveResult(ctx, &errutil.Result{Code: errutil.NoErr, Message: "success", Data: getQueryResponse(exps, total)})
+	}
+
 	if format == JsonFormat {
 		printJson(ctx, exps, total)
 	} else {
@@ -78,17 +83,7 @@ func PrintExpByOption(ctx context.Context, o *OptionExpQuery, ifAll bool, format
 
 func printJson(ctx context.Context, exps []*storage.Experiment, total int64) {
 	logger := log.GetLogger(ctx)
-	reList := make([]model.ExperimentDataUnit, len(exps))
-	for i, exp := range exps {
-		reList[i] = handler.ExpToExperimentDataUnit(exp)
-	}
-
-	res := &model.QueryResponseData{
-		Experiments: reList,
-		Total:       total,
-	}
-
-	reBytes, err := json.Marshal(res)
+	reBytes, err := json.Marshal(getQueryResponse(exps, total))
 	if err != nil {
 		errutil.SolveErr(ctx, errutil.InternalErr, fmt.Sprintf("query response change to string error: %s", err.Error()))
 	}
@@ -100,6 +95,18 @@ func printJson(ctx context.Context, exps []*storage.Experiment, total int64) {
 	}
 }
 
+func getQueryResponse(exps []*storage.Experiment, total int64) *model.QueryResponseData {
+	reList := make([]model.ExperimentDataUnit, len(exps))
+	for i, exp := range exps {
+		reList[i] = handler.ExpToExperimentDataUnit(exp)
+	}
+
+	return &model.QueryResponseData{
+		Experiments: reList,
+		Total:       total,
+	}
+}
+
 func printTable(ctx context.Context, exps []*storage.Experiment, total int64, ifAll bool) {
 	logger := log.GetLogger(ctx)
 	var formatData string
@@ -109,7 +116,7 @@ func printTable(ctx context.Context, exps []*storage.Experiment, total int64, if
 			var aData []interface{}
 			if ifAll {
 				aData = []interface{}{exp.Uid, exp.Status, exp.Target, exp.Fault, exp.Args, exp.Creator, exp.Runtime,
-					exp.ContainerId, exp.ContainerRuntime, exp.Timeout, exp.Error, exp.CreateTime, exp.UpdateTime}
+					exp.ContainerId, exp.ContainerRuntime, exp.Timeout, exp.Ramp, exp.Error, exp.CreateTime, exp.UpdateTime}
 			} else {
 				aData = []interface{}{exp.Uid, exp.Status, exp.Target, exp.Fault, exp.Args}
 			}
@@ -120,7 +127,7 @@ func printTable(ctx context.Context, exps []*storage.Experiment, total int64, if
 		t := gotabulate.Create(data)
 		if ifAll {
 			t.SetHeaders([]string{"UID", "STATUS", "TARGET", "FAULT", "ARGS", "CREATOR", "RUNTIME",
-				"CONTAINER_ID", "CONTAINER_RUNTIME", "TIMEOUT", "ERROR", "CREATE_TIME", "UPDATE_TIME"})
+				"CONTAINER_ID", "CONTAINER_RUNTIME", "TIMEOUT", "RAMP", "ERROR", "CREATE_TIME", "UPDATE_TIME"})
 		} else {
 			t.SetHeaders([]string{"UID", "STATUS", "TARGET", "FAULT", "ARGS"})
 		}
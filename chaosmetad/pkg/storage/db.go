@@ -22,26 +22,67 @@ import (
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"os"
 	"path"
+	"path/filepath"
 )
 
-const storageFile = "chaosmetad.dat"
+const (
+	storageFile = "chaosmetad.dat"
+
+	EnvDBPath = "CHAOSMETAD_DB_PATH"
+)
+
+// Path is set by the command line flag, env is used when it is empty, and then the default path under the run path
+var Path string
+
+var globalDB *dbStorage
 
 type dbStorage struct {
 	*gorm.DB
 }
 
+func getDBStorage() (*dbStorage, error) {
+	if globalDB == nil {
+		db, err := newDBStorage()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := migrate(db, getDBPath()); err != nil {
+			return nil, fmt.Errorf("migrate db error: %s", err.Error())
+		}
+		globalDB = db
+	}
+
+	return globalDB, nil
+}
+
+func getDBPath() string {
+	if Path != "" {
+		return Path
+	}
+
+	if envPath := os.Getenv(EnvDBPath); envPath != "" {
+		return envPath
+	}
+
+	return path.Join(utils.GetRunPath(), storageFile)
+}
+
 func newDBStorage() (*dbStorage, error) {
-	// TODO: db path can be config
-	dsn := path.Join(utils.GetRunPath(), storageFile)
+	dbPath := getDBPath()
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, fmt.Errorf("create dir of DB[%s] error: %s", dbPath, err.Error())
+	}
 
-	dsn += "?cache=shared&loc=Local"
+	dsn := dbPath + "?cache=shared&loc=Local"
 
 	gormDB, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to open DB: %s", err.Error())
+		return nil, fmt.Errorf("failed to open DB[%s]: %s", dbPath, err.Error())
 	}
 
 	tempDB, err := gormDB.DB()
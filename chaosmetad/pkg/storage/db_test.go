@@ -0,0 +1,48 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"path"
+	"testing"
+)
+
+func Test_getDBPath(t *testing.T) {
+	oldPath := Path
+	t.Cleanup(func() { Path = oldPath })
+
+	tests := []struct {
+		name string
+		path string
+		env  string
+		want string
+	}{
+		{name: "flag", path: "/tmp/flag.dat", env: "/tmp/env.dat", want: "/tmp/flag.dat"},
+		{name: "env", env: "/tmp/env.dat", want: "/tmp/env.dat"},
+		{name: "default", want: path.Join(utils.GetRunPath(), storageFile)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Path = tt.path
+			t.Setenv(EnvDBPath, tt.env)
+			if got := getDBPath(); got != tt.want {
+				t.Errorf("getDBPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"gorm.io/gorm"
+	"time"
+)
+
+var globalEventStorage *eventStore
+
+// eventStore is append-only, the events of an experiment are never updated or deleted
+type eventStore struct {
+	db *dbStorage
+}
+
+func GetEventStore() (*eventStore, error) {
+	if globalEventStorage == nil {
+		db, err := getDBStorage()
+		if err != nil {
+			return nil, fmt.Errorf("newDBStorage error: %s", err.Error())
+		}
+		globalEventStorage, err = newEventStore(db)
+		if err != nil {
+			return nil, fmt.Errorf("newEventStore error: %s", err.Error())
+		}
+	}
+
+	return globalEventStorage, nil
+}
+
+// the table is created by the migrations when the db is opened
+func newEventStore(db *dbStorage) (*eventStore, error) {
+	return &eventStore{db}, nil
+}
+
+func (e *eventStore) Insert(event *Event) error {
+	event.CreateTime = time.Now().Format(utils.TimeFormat)
+	if err := e.db.Model(Event{}).
+		Create(event).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// QueryByUid returns the events of an experiment in order of occurrence, limit 0 means no limit
+func (e *eventStore) QueryByUid(uid string, offset, limit uint) ([]*Event, int64, error) {
+	var events []*Event
+	db := e.db.Model(Event{}).Where("uid = ?", uid)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	db = db.Order("id ASC").Offset(int(offset))
+	if limit > 0 {
+		db = db.Limit(int(limit))
+	}
+
+	if err := db.
+		Find(&events).
+		Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
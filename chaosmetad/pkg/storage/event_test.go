@@ -0,0 +1,88 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"testing"
+)
+
+func TestEventStore_QueryByUid(t *testing.T) {
+	setTestDB(t)
+	store, err := GetEventStore()
+	if err != nil {
+		t.Fatalf("GetEventStore() error = %v", err)
+	}
+
+	// the events of two experiments are interleaved in the journal
+	for _, event := range []*Event{
+		{Uid: "a1", Type: "created", Actor: "cli:root", TraceId: "trace-1"},
+		{Uid: "a2", Type: "created", Actor: "http:10.0.0.1"},
+		{Uid: "a1", Type: "injected", Actor: "cli:root", TraceId: "trace-1", Commands: "[\"echo 1\"]"},
+		{Uid: "a1", Type: "auto-recovered", Actor: "auto-recover", TraceId: "trace-2"},
+	} {
+		if err := store.Insert(event); err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+
+		if event.Id == 0 || event.CreateTime == "" {
+			t.Errorf("Insert() event = %+v, want id and create time set", event)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		offset    uint
+		limit     uint
+		wantTypes []string
+	}{
+		{name: "all", wantTypes: []string{"created", "injected", "auto-recovered"}},
+		{name: "limit", limit: 2, wantTypes: []string{"created", "injected"}},
+		{name: "offset", offset: 1, limit: 1, wantTypes: []string{"injected"}},
+		{name: "offset over total", offset: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, total, err := store.QueryByUid("a1", tt.offset, tt.limit)
+			if err != nil {
+				t.Fatalf("QueryByUid() error = %v", err)
+			}
+
+			if total != 3 || len(events) != len(tt.wantTypes) {
+				t.Fatalf("QueryByUid() = %d events, total %d, want %d events, total 3", len(events), total, len(tt.wantTypes))
+			}
+
+			for i, event := range events {
+				if event.Uid != "a1" || event.Type != tt.wantTypes[i] {
+					t.Errorf("event[%d] = %s of %s, want %s of a1", i, event.Type, event.Uid, tt.wantTypes[i])
+				}
+			}
+		})
+	}
+
+	events, _, err := store.QueryByUid("a1", 0, 0)
+	if err != nil {
+		t.Fatalf("QueryByUid() error = %v", err)
+	}
+
+	if events[1].Actor != "cli:root" || events[1].TraceId != "trace-1" || events[1].Commands != "[\"echo 1\"]" {
+		t.Errorf("event = %+v, want actor, trace id and commands kept", events[1])
+	}
+
+	if events[2].Actor != "auto-recover" || events[2].TraceId != "trace-2" {
+		t.Errorf("event = %+v, want actor and trace id of auto recover", events[2])
+	}
+}
@@ -32,7 +32,7 @@ type experimentStore struct {
 
 func GetExperimentStore() (*experimentStore, error) {
 	if globalExpStorage == nil {
-		db, err := newDBStorage()
+		db, err := getDBStorage()
 		if err != nil {
 			return nil, fmt.Errorf("newDBStorage error: %s", err.Error())
 		}
@@ -45,11 +45,8 @@ func GetExperimentStore() (*experimentStore, error) {
 	return globalExpStorage, nil
 }
 
+// the table is created by the migrations when the db is opened
 func newExperimentStore(db *dbStorage) (*experimentStore, error) {
-	if err := db.AutoMigrate(&Experiment{}); err != nil {
-		return nil, err
-	}
-
 	return &experimentStore{db}, nil
 }
 
@@ -99,6 +96,32 @@ func (e *experimentStore) UpdateStatusAndErr(uid, status, errMsg string) error {
 	return nil
 }
 
+// UpdateIfStatus updates the experiment only if it is still in the expected status, and returns whether it is updated
+func (e *experimentStore) UpdateIfStatus(exp *Experiment, expectedStatus string) (bool, error) {
+	exp.UpdateTime = time.Now().Format(utils.TimeFormat)
+	res := e.db.Model(Experiment{}).
+		Where("uid = ? AND status = ?", exp.Uid, expectedStatus).
+		Updates(exp)
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// UpdateStatusAndErrIfStatus updates the status only if the experiment is still in the expected status,
+// so that the status changed concurrently, such as destroyed by recover, is not overwritten
+func (e *experimentStore) UpdateStatusAndErrIfStatus(uid, expectedStatus, status, errMsg string) (bool, error) {
+	res := e.db.Model(Experiment{}).
+		Where("uid = ? AND status = ?", uid, expectedStatus).
+		Updates(Experiment{Status: status, Error: errMsg, UpdateTime: time.Now().Format(utils.TimeFormat)})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
 func (e *experimentStore) GetByUid(uid string) (*Experiment, error) {
 	var exp = &Experiment{}
 	if err := e.db.Model(Experiment{}).
@@ -114,6 +137,19 @@ func (e *experimentStore) GetByUid(uid string) (*Experiment, error) {
 	return exp, nil
 }
 
+// FindByUid returns nil if the experiment is not found
+func (e *experimentStore) FindByUid(uid string) (*Experiment, error) {
+	exp, err := e.GetByUid(uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return exp, nil
+}
+
 func (e *experimentStore) QueryByOption(uid, status, target, fault, creator, cr, cId string, offset, limit uint) ([]*Experiment, int64, error) {
 	var exps []*Experiment
 	db := e.db.Model(Experiment{})
@@ -158,3 +194,24 @@ func (e *experimentStore) QueryByOption(uid, status, target, fault, creator, cr,
 
 	return exps, total, nil
 }
+
+func (e *experimentStore) CountByStatus() (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	if err := e.db.Model(Experiment{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).
+		Error; err != nil {
+		return nil, err
+	}
+
+	countMap := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		countMap[row.Status] = row.Count
+	}
+
+	return countMap, nil
+}
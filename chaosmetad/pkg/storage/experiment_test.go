@@ -0,0 +1,78 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"path/filepath"
+	"testing"
+)
+
+// setTestDB points the storage to a new db file in a temp dir
+func setTestDB(t *testing.T) {
+	oldPath := Path
+	Path, globalDB, globalExpStorage, globalEventStorage = filepath.Join(t.TempDir(), storageFile), nil, nil, nil
+	t.Cleanup(func() {
+		closeTestDB()
+		Path = oldPath
+	})
+}
+
+// closeTestDB closes the opened db, so that the next call of the stores opens the db of Path again
+func closeTestDB() {
+	if globalDB != nil {
+		if sqlDB, err := globalDB.DB.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	}
+	globalDB, globalExpStorage, globalEventStorage = nil, nil, nil
+}
+
+func TestExperimentStore_UpdateIfStatus(t *testing.T) {
+	setTestDB(t)
+	store, err := GetExperimentStore()
+	if err != nil {
+		t.Fatalf("GetExperimentStore() error = %v", err)
+	}
+
+	if err := store.Insert(&Experiment{Uid: "a1", Target: "cpu", Fault: "burn", Args: "{\"percent\":10}", Status: utils.StatusSuccess}); err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+
+	updated, err := store.UpdateIfStatus(&Experiment{Uid: "a1", Args: "{\"percent\":20}"}, utils.StatusSuccess)
+	if err != nil || !updated {
+		t.Fatalf("UpdateIfStatus() = %v, %v, want true", updated, err)
+	}
+
+	if err := store.UpdateStatus("a1", utils.StatusDestroyed); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+
+	updated, err = store.UpdateStatusAndErrIfStatus("a1", utils.StatusSuccess, utils.StatusError, "retune error")
+	if err != nil || updated {
+		t.Fatalf("UpdateStatusAndErrIfStatus() = %v, %v, want false", updated, err)
+	}
+
+	exp, err := store.GetByUid("a1")
+	if err != nil {
+		t.Fatalf("GetByUid() error = %v", err)
+	}
+
+	if exp.Status != utils.StatusDestroyed || exp.Error != "" || exp.Args != "{\"percent\":20}" {
+		t.Errorf("GetByUid() = status[%s], error[%s], args[%s], want destroyed status kept", exp.Status, exp.Error, exp.Args)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"gorm.io/gorm"
+	"io"
+	"os"
+	"time"
+)
+
+type SchemaMigration struct {
+	Version   int    `gorm:"primaryKey;autoIncrement:false" json:"version"`
+	Name      string `json:"name"`
+	ApplyTime string `json:"apply_time"`
+}
+
+type migration struct {
+	version int
+	name    string
+	migrate func(tx *gorm.DB) error
+}
+
+// migrations are applied in order of version, and each of them is applied only once.
+// A migration may only add tables, columns or indexes and must be idempotent (check with tx.Migrator() first),
+// so that the experiments still running are always readable by the upgraded binary to be recovered.
+// The version of a released migration must never be changed, append a new one instead.
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "create experiment table",
+		migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&Experiment{})
+		},
+	},
+	{
+		version: 2,
+		name:    "create event table",
+		migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&Event{})
+		},
+	},
+}
+
+// SchemaVersion is the latest schema version supported by this binary
+func SchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+func getCurrentVersion(db *dbStorage) (int, error) {
+	var version int
+	if err := db.Model(SchemaMigration{}).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&version).
+		Error; err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+func migrate(db *dbStorage, dbPath string) error {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("create migration table error: %s", err.Error())
+	}
+
+	curVersion, err := getCurrentVersion(db)
+	if err != nil {
+		return fmt.Errorf("get schema version error: %s", err.Error())
+	}
+
+	// a db migrated by a newer binary is still readable, because the migrations only add schema
+	if curVersion >= SchemaVersion() {
+		return nil
+	}
+
+	// the db created before the migrations has no version but has the experiment table
+	if curVersion > 0 || db.Migrator().HasTable(&Experiment{}) {
+		if err := backupDBFile(dbPath, curVersion); err != nil {
+			return fmt.Errorf("backup db before migration error: %s", err.Error())
+		}
+	}
+
+	for _, m := range migrations {
+		if m.version <= curVersion {
+			continue
+		}
+
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.migrate(tx); err != nil {
+				return err
+			}
+
+			return tx.Create(&SchemaMigration{
+				Version:   m.version,
+				Name:      m.name,
+				ApplyTime: time.Now().Format(utils.TimeFormat),
+			}).Error
+		}); err != nil {
+			return fmt.Errorf("apply migration[%d: %s] error: %s", m.version, m.name, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func backupDBFile(dbPath string, version int) error {
+	src, err := os.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(fmt.Sprintf("%s.v%d.bak", dbPath, version), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, src)
+	return err
+}
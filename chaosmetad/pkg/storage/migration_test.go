@@ -0,0 +1,98 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"os"
+	"testing"
+)
+
+// baselineSchema is the experiment table created by the binaries before the migrations
+const baselineSchema = "CREATE TABLE `experiments` (`uid` text,`target` text,`fault` text,`args` text,`runtime` text," +
+	"`timeout` text,`status` text,`creator` text,`error` text,`create_time` text,`update_time` text," +
+	"`container_id` text,`container_runtime` text,PRIMARY KEY (`uid`))"
+
+func TestMigrate_baseline(t *testing.T) {
+	setTestDB(t)
+	db, err := newDBStorage()
+	if err != nil {
+		t.Fatalf("newDBStorage() error = %v", err)
+	}
+
+	if err := db.Exec(baselineSchema).Error; err != nil {
+		t.Fatalf("create baseline schema error = %v", err)
+	}
+
+	if err := db.Exec("INSERT INTO `experiments` (`uid`,`target`,`fault`,`args`,`status`) VALUES (?,?,?,?,?)",
+		"a1", "cpu", "burn", "{\"percent\":10}", utils.StatusSuccess).Error; err != nil {
+		t.Fatalf("insert baseline experiment error = %v", err)
+	}
+
+	if sqlDB, err := db.DB.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
+
+	store, err := GetExperimentStore()
+	if err != nil {
+		t.Fatalf("GetExperimentStore() error = %v", err)
+	}
+
+	exp, err := store.GetByUid("a1")
+	if err != nil {
+		t.Fatalf("GetByUid() error = %v", err)
+	}
+
+	if exp.Status != utils.StatusSuccess || exp.Args != "{\"percent\":10}" || exp.Ramp != "" {
+		t.Errorf("GetByUid() = %+v, want the baseline experiment kept", exp)
+	}
+
+	if !globalDB.Migrator().HasColumn(&Experiment{}, "Ramp") || !globalDB.Migrator().HasTable(&Event{}) {
+		t.Errorf("migrate() did not add the ramp column and the event table")
+	}
+
+	version, err := getCurrentVersion(globalDB)
+	if err != nil || version != SchemaVersion() {
+		t.Errorf("getCurrentVersion() = %v, %v, want %v", version, err, SchemaVersion())
+	}
+
+	if _, err := os.Stat(Path + ".v0.bak"); err != nil {
+		t.Errorf("backup of baseline db error = %v", err)
+	}
+
+	// applied migrations are not applied again
+	if err := migrate(globalDB, Path); err != nil {
+		t.Errorf("migrate() again error = %v", err)
+	}
+
+	var count int64
+	if err := globalDB.Model(SchemaMigration{}).Count(&count).Error; err != nil || count != int64(len(migrations)) {
+		t.Errorf("count of schema migrations = %v, %v, want %v", count, err, len(migrations))
+	}
+}
+
+func TestMigrate_new(t *testing.T) {
+	setTestDB(t)
+	if _, err := GetExperimentStore(); err != nil {
+		t.Fatalf("GetExperimentStore() error = %v", err)
+	}
+
+	// nothing to back up for a new db
+	if _, err := os.Stat(Path + ".v0.bak"); !os.IsNotExist(err) {
+		t.Errorf("backup of new db exists, error = %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"gorm.io/gorm"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	EnvRetentionAge   = "CHAOSMETAD_RETENTION_AGE"
+	EnvRetentionCount = "CHAOSMETAD_RETENTION_COUNT"
+
+	DefaultRetentionAge   = "720h"
+	DefaultRetentionCount = 1000
+
+	// NoRetentionCount means the retention count is not set by the command line flag
+	NoRetentionCount = -1
+)
+
+// RetentionAge and RetentionCount are set by the command line flags, env is used when they are empty.
+// "0" means no limit of the dimension
+var (
+	RetentionAge   string
+	RetentionCount = NoRetentionCount
+)
+
+// ExportConfig exports the storage config set by flags to env, so that the sub processes of the auto recover
+// and ramp use the same db
+func ExportConfig() error {
+	if Path != "" {
+		if err := os.Setenv(EnvDBPath, Path); err != nil {
+			return err
+		}
+	}
+
+	if RetentionAge != "" {
+		if err := os.Setenv(EnvRetentionAge, RetentionAge); err != nil {
+			return err
+		}
+	}
+
+	if RetentionCount != NoRetentionCount {
+		if err := os.Setenv(EnvRetentionCount, strconv.Itoa(RetentionCount)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getRetention() (ageSec int64, count int, err error) {
+	ageStr, countStr := RetentionAge, ""
+	if ageStr == "" {
+		ageStr = os.Getenv(EnvRetentionAge)
+	}
+	if ageStr == "" {
+		ageStr = DefaultRetentionAge
+	}
+
+	ageSec, err = utils.GetTimeSecond(ageStr)
+	if err != nil || ageSec < 0 {
+		return 0, 0, fmt.Errorf("retention age[%s] is not valid: %v", ageStr, err)
+	}
+
+	count = RetentionCount
+	if count == NoRetentionCount {
+		if countStr = os.Getenv(EnvRetentionCount); countStr != "" {
+			count, err = strconv.Atoi(countStr)
+			if err != nil || count < 0 {
+				return 0, 0, fmt.Errorf("retention count[%s] is not valid: %v", countStr, err)
+			}
+		} else {
+			count = DefaultRetentionCount
+		}
+	} else if count < 0 {
+		return 0, 0, fmt.Errorf("retention count[%d] is not valid", count)
+	}
+
+	return ageSec, count, nil
+}
+
+// Prune deletes the finished experiments and their events, which are older than the retention age
+// or beyond the retention count. The running experiments are never deleted
+func Prune() (int64, error) {
+	ageSec, count, err := getRetention()
+	if err != nil {
+		return 0, err
+	}
+
+	if ageSec == 0 && count == 0 {
+		return 0, nil
+	}
+
+	db, err := getDBStorage()
+	if err != nil {
+		return 0, fmt.Errorf("get db error: %s", err.Error())
+	}
+
+	finished := []string{utils.StatusDestroyed, utils.StatusError}
+	var uidSet = make(map[string]bool)
+	if ageSec > 0 {
+		var uids []string
+		cutTime := time.Now().Add(-time.Duration(ageSec) * time.Second).Format(utils.TimeFormat)
+		if err := db.Model(Experiment{}).
+			Where("status IN ? AND update_time < ?", finished, cutTime).
+			Pluck("uid", &uids).
+			Error; err != nil {
+			return 0, fmt.Errorf("query expired experiments error: %s", err.Error())
+		}
+
+		for _, uid := range uids {
+			uidSet[uid] = true
+		}
+	}
+
+	if count > 0 {
+		var uids []string
+		if err := db.Model(Experiment{}).
+			Where("status IN ?", finished).
+			Order("update_time DESC").
+			Offset(count).
+			Pluck("uid", &uids).
+			Error; err != nil {
+			return 0, fmt.Errorf("query excess experiments error: %s", err.Error())
+		}
+
+		for _, uid := range uids {
+			uidSet[uid] = true
+		}
+	}
+
+	if len(uidSet) == 0 {
+		return 0, nil
+	}
+
+	uids := make([]string, 0, len(uidSet))
+	for uid := range uidSet {
+		uids = append(uids, uid)
+	}
+
+	var deleted int64
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("uid IN ?", uids).Delete(&Event{}).Error; err != nil {
+			return err
+		}
+
+		re := tx.Where("uid IN ?", uids).Delete(&Experiment{})
+		deleted = re.RowsAffected
+		return re.Error
+	}); err != nil {
+		return 0, fmt.Errorf("delete experiments error: %s", err.Error())
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"testing"
+	"time"
+)
+
+func Test_getRetention(t *testing.T) {
+	oldAge, oldCount := RetentionAge, RetentionCount
+	t.Cleanup(func() { RetentionAge, RetentionCount = oldAge, oldCount })
+
+	tests := []struct {
+		name      string
+		age       string
+		count     int
+		envAge    string
+		envCount  string
+		wantAge   int64
+		wantCount int
+		wantErr   bool
+	}{
+		{name: "default", count: NoRetentionCount, wantAge: 720 * 3600, wantCount: DefaultRetentionCount},
+		{name: "env", count: NoRetentionCount, envAge: "1h", envCount: "10", wantAge: 3600, wantCount: 10},
+		{name: "flag over env", age: "30m", count: 0, envAge: "1h", envCount: "10", wantAge: 1800, wantCount: 0},
+		{name: "invalid age", age: "1x", count: NoRetentionCount, wantErr: true},
+		{name: "invalid env count", count: NoRetentionCount, envCount: "-2", wantErr: true},
+		{name: "invalid flag count", count: -2, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			RetentionAge, RetentionCount = tt.age, tt.count
+			t.Setenv(EnvRetentionAge, tt.envAge)
+			t.Setenv(EnvRetentionCount, tt.envCount)
+			gotAge, gotCount, err := getRetention()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getRetention() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && (gotAge != tt.wantAge || gotCount != tt.wantCount) {
+				t.Errorf("getRetention() = %v, %v, want %v, %v", gotAge, gotCount, tt.wantAge, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestPrune(t *testing.T) {
+	setTestDB(t)
+	oldAge, oldCount := RetentionAge, RetentionCount
+	RetentionAge, RetentionCount = "1h", 1
+	t.Cleanup(func() { RetentionAge, RetentionCount = oldAge, oldCount })
+
+	eventStore, err := GetEventStore()
+	if err != nil {
+		t.Fatalf("GetEventStore() error = %v", err)
+	}
+
+	agoTime := func(d time.Duration) string {
+		return time.Now().Add(-d).Format(utils.TimeFormat)
+	}
+
+	for _, exp := range []*Experiment{
+		{Uid: "expired", Status: utils.StatusDestroyed, UpdateTime: agoTime(2 * time.Hour)},
+		{Uid: "latest", Status: utils.StatusDestroyed, UpdateTime: agoTime(time.Minute)},
+		{Uid: "excess", Status: utils.StatusError, UpdateTime: agoTime(2 * time.Minute)},
+		{Uid: "running", Status: utils.StatusSuccess, UpdateTime: agoTime(2 * time.Hour)},
+	} {
+		if err := globalDB.Create(exp).Error; err != nil {
+			t.Fatalf("insert experiment[%s] error = %v", exp.Uid, err)
+		}
+
+		if err := eventStore.Insert(&Event{Uid: exp.Uid, Type: "inject"}); err != nil {
+			t.Fatalf("insert event of experiment[%s] error = %v", exp.Uid, err)
+		}
+	}
+
+	deleted, err := Prune()
+	if err != nil || deleted != 2 {
+		t.Fatalf("Prune() = %v, %v, want 2", deleted, err)
+	}
+
+	var uids []string
+	if err := globalDB.Model(Experiment{}).Order("uid ASC").Pluck("uid", &uids).Error; err != nil {
+		t.Fatalf("query experiments error = %v", err)
+	}
+
+	if len(uids) != 2 || uids[0] != "latest" || uids[1] != "running" {
+		t.Errorf("experiments after Prune() = %v, want [latest running]", uids)
+	}
+
+	var eventCount int64
+	if err := globalDB.Model(Event{}).Where("uid IN ?", []string{"expired", "excess"}).Count(&eventCount).Error; err != nil || eventCount != 0 {
+		t.Errorf("events of pruned experiments = %v, %v, want 0", eventCount, err)
+	}
+}
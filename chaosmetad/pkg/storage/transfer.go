@@ -0,0 +1,130 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"gorm.io/gorm"
+	"io"
+	"os"
+	"time"
+)
+
+// Snapshot is the format of the exported db, used to move the experiments between hosts
+type Snapshot struct {
+	SchemaVersion int           `json:"schema_version"`
+	Host          string        `json:"host"`
+	ExportTime    string        `json:"export_time"`
+	Experiments   []*Experiment `json:"experiments"`
+	Events        []*Event      `json:"events"`
+}
+
+func Export(w io.Writer) (int, error) {
+	db, err := getDBStorage()
+	if err != nil {
+		return 0, fmt.Errorf("get db error: %s", err.Error())
+	}
+
+	host, _ := os.Hostname()
+	snapshot := &Snapshot{
+		SchemaVersion: SchemaVersion(),
+		Host:          host,
+		ExportTime:    time.Now().Format(utils.TimeFormat),
+	}
+
+	if err := db.Model(Experiment{}).Order("create_time ASC").Find(&snapshot.Experiments).Error; err != nil {
+		return 0, fmt.Errorf("query experiments error: %s", err.Error())
+	}
+
+	if err := db.Model(Event{}).Order("id ASC").Find(&snapshot.Events).Error; err != nil {
+		return 0, fmt.Errorf("query events error: %s", err.Error())
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(snapshot); err != nil {
+		return 0, fmt.Errorf("write snapshot error: %s", err.Error())
+	}
+
+	return len(snapshot.Experiments), nil
+}
+
+// Import inserts the experiments of the snapshot with their events, the experiments already existed are skipped.
+// The faults of the experiments not finished are on the exported host, so they are imported as errored
+// to never be recovered, re-tuned or reported as running on this host
+func Import(r io.Reader) (imported, skipped int, err error) {
+	var snapshot = &Snapshot{}
+	if err := json.NewDecoder(r).Decode(snapshot); err != nil {
+		return 0, 0, fmt.Errorf("read snapshot error: %s", err.Error())
+	}
+
+	if snapshot.SchemaVersion > SchemaVersion() {
+		return 0, 0, fmt.Errorf("schema version[%d] of snapshot is newer than supported[%d], please upgrade %s first",
+			snapshot.SchemaVersion, SchemaVersion(), utils.RootName)
+	}
+
+	db, err := getDBStorage()
+	if err != nil {
+		return 0, 0, fmt.Errorf("get db error: %s", err.Error())
+	}
+
+	eventMap := make(map[string][]*Event)
+	for _, event := range snapshot.Events {
+		eventMap[event.Uid] = append(eventMap[event.Uid], event)
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, exp := range snapshot.Experiments {
+			var count int64
+			if err := tx.Model(Experiment{}).Where("uid = ?", exp.Uid).Count(&count).Error; err != nil {
+				return err
+			}
+
+			if count > 0 {
+				skipped++
+				continue
+			}
+
+			if exp.Status != utils.StatusDestroyed && exp.Status != utils.StatusError {
+				exp.Error = fmt.Sprintf("imported from host[%s] with status[%s], the fault is not on this host", snapshot.Host, exp.Status)
+				exp.Status = utils.StatusError
+			}
+
+			if err := tx.Create(exp).Error; err != nil {
+				return fmt.Errorf("insert experiment[%s] error: %s", exp.Uid, err.Error())
+			}
+
+			for _, event := range eventMap[exp.Uid] {
+				// the id is regenerated to append to the local journal
+				event.Id = 0
+				if err := tx.Create(event).Error; err != nil {
+					return fmt.Errorf("insert event of experiment[%s] error: %s", exp.Uid, err.Error())
+				}
+			}
+			imported++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return imported, skipped, nil
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"strings"
+	"testing"
+)
+
+func TestExportImport(t *testing.T) {
+	setTestDB(t)
+	expStore, err := GetExperimentStore()
+	if err != nil {
+		t.Fatalf("GetExperimentStore() error = %v", err)
+	}
+
+	eventStore, err := GetEventStore()
+	if err != nil {
+		t.Fatalf("GetEventStore() error = %v", err)
+	}
+
+	for uid, status := range map[string]string{"a1": utils.StatusSuccess, "a2": utils.StatusSuccess, "a3": utils.StatusDestroyed} {
+		if err := expStore.Insert(&Experiment{Uid: uid, Target: "cpu", Fault: "burn", Args: "{\"percent\":10}", Status: status}); err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+
+		for _, eventType := range []string{"inject", "update"} {
+			if err := eventStore.Insert(&Event{Uid: uid, Type: eventType}); err != nil {
+				t.Fatalf("insert event error = %v", err)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	count, err := Export(&buf)
+	if err != nil || count != 3 {
+		t.Fatalf("Export() = %v, %v, want 3", count, err)
+	}
+
+	// import to the db of another host, which already has a1
+	closeTestDB()
+	setTestDB(t)
+	expStore, err = GetExperimentStore()
+	if err != nil {
+		t.Fatalf("GetExperimentStore() error = %v", err)
+	}
+
+	eventStore, err = GetEventStore()
+	if err != nil {
+		t.Fatalf("GetEventStore() error = %v", err)
+	}
+
+	if err := expStore.Insert(&Experiment{Uid: "a1", Target: "mem", Fault: "fill", Status: utils.StatusDestroyed}); err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+
+	if err := eventStore.Insert(&Event{Uid: "a1", Type: "recover"}); err != nil {
+		t.Fatalf("insert event error = %v", err)
+	}
+
+	imported, skipped, err := Import(bytes.NewReader(buf.Bytes()))
+	if err != nil || imported != 2 || skipped != 1 {
+		t.Fatalf("Import() = %v, %v, %v, want 2, 1", imported, skipped, err)
+	}
+
+	exp, err := expStore.GetByUid("a1")
+	if err != nil || exp.Target != "mem" || exp.Status != utils.StatusDestroyed {
+		t.Errorf("GetByUid(a1) = %+v, %v, want the local experiment kept", exp, err)
+	}
+
+	// the fault of running experiment is not on this host
+	exp, err = expStore.GetByUid("a2")
+	if err != nil || exp.Target != "cpu" || exp.Args != "{\"percent\":10}" || exp.Status != utils.StatusError || !strings.Contains(exp.Error, "imported from host") {
+		t.Errorf("GetByUid(a2) = %+v, %v, want the imported experiment with error status", exp, err)
+	}
+
+	exp, err = expStore.GetByUid("a3")
+	if err != nil || exp.Status != utils.StatusDestroyed || exp.Error != "" {
+		t.Errorf("GetByUid(a3) = %+v, %v, want the imported experiment with destroyed status", exp, err)
+	}
+
+	for uid, wantTypes := range map[string][]string{"a1": {"recover"}, "a2": {"inject", "update"}} {
+		events, _, err := eventStore.QueryByUid(uid, 0, 0)
+		if err != nil {
+			t.Fatalf("QueryByUid(%s) error = %v", uid, err)
+		}
+
+		var types []string
+		for _, event := range events {
+			types = append(types, event.Type)
+		}
+
+		if fmt.Sprint(types) != fmt.Sprint(wantTypes) {
+			t.Errorf("events of %s = %v, want %v", uid, types, wantTypes)
+		}
+	}
+}
+
+func TestImport_newerSchema(t *testing.T) {
+	setTestDB(t)
+	snapshot := fmt.Sprintf("{\"schema_version\": %d, \"experiments\": [{\"uid\": \"a1\"}]}", SchemaVersion()+1)
+	if _, _, err := Import(strings.NewReader(snapshot)); err == nil {
+		t.Errorf("Import() of newer schema error = nil, want error")
+	}
+}
@@ -30,4 +30,16 @@ type Experiment struct {
 	UpdateTime       string `json:"update_time"`
 	ContainerId      string `json:"container_id"`
 	ContainerRuntime string `json:"container_runtime"`
+	Ramp             string `json:"ramp"`
+}
+
+type Event struct {
+	Id         uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	Uid        string `gorm:"index:event_uid" json:"uid"`
+	Type       string `json:"type"`
+	Actor      string `json:"actor"`
+	TraceId    string `json:"trace_id"`
+	Message    string `json:"message"`
+	Commands   string `json:"commands"`
+	CreateTime string `json:"create_time"`
 }
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"strconv"
+	"strings"
 )
 
 func GetBlkioConfig(ctx context.Context, devList []string, rBytes, wBytes string, rIO, wIO int64, cgroupPath string) string {
@@ -47,6 +49,14 @@ func GetBlkioConfig(ctx context.Context, devList []string, rBytes, wBytes string
 	return re[:len(re)-len(utils.CmdSplit)]
 }
 
+// GetNetClsConfig classId format: major:minor in hex, eg: 1:4
+func GetNetClsConfig(classId string, cgroupPath string) string {
+	kv := strings.Split(classId, ":")
+	major, _ := strconv.ParseUint(kv[0], 16, 16)
+	minor, _ := strconv.ParseUint(kv[1], 16, 16)
+	return fmt.Sprintf("echo 0x%04x%04x > %s/%s", major, minor, cgroupPath, NetClsClassIdFile)
+}
+
 func getThrottleDeviceCmdStr(devList []string, value int64, filename string) string {
 	var re string
 	for _, unitDec := range devList {
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/containercgroup"
@@ -69,6 +70,31 @@ func GetBlkioCPath(uid string, prefix string) string {
 	return fmt.Sprintf("%s/%s%s/%s_%s", containercgroup.RootCgroupPath, BLKIO, prefix, BlkioCgroupName, uid)
 }
 
+func GetNetClsCPath(uid string, prefix string) string {
+	return fmt.Sprintf("%s/%s%s/%s_%s", containercgroup.RootCgroupPath, NETCLS, prefix, NetClsCgroupName, uid)
+}
+
+// IsCgroupV2 only the unified mode is regarded as v2, the hybrid mode still has the v1 controllers
+func IsCgroupV2() bool {
+	_, err := os.Stat(fmt.Sprintf("%s/%s", containercgroup.RootCgroupPath, CgroupV2ControllerFile))
+	return err == nil
+}
+
+func GetPidCurCgroupV2(ctx context.Context, pid int) (string, error) {
+	reByte, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return "", fmt.Errorf("read cgroup of process[%d] error: %s", pid, err.Error())
+	}
+
+	for _, line := range strings.Split(string(reByte), "\n") {
+		if strings.HasPrefix(line, CgroupV2Prefix) {
+			return strings.TrimPrefix(line, CgroupV2Prefix), nil
+		}
+	}
+
+	return "", fmt.Errorf("not found cgroup v2 path of process[%d]", pid)
+}
+
 func CheckPidListBlkioCgroup(ctx context.Context, pidList []int) error {
 	for _, unitP := range pidList {
 		oldPath, err := GetpidCurCgroup(ctx, unitP, BLKIO)
@@ -144,13 +170,14 @@ func MoveTaskToCgroup(ctx context.Context, pid int, cgroupPath string) error {
 	return nil
 }
 
-//func MoveProcToCgroup(pid int, cgroupPath string) error {
-//	if err := cmdexec.RunBashCmdWithoutOutput(fmt.Sprintf("echo %d > %s/cgroup.procs", pid, cgroupPath)); err != nil {
-//		return err
-//	}
-//
-//	return nil
-//}
+// MoveProcToCgroup move all the threads of the process
+func MoveProcToCgroup(ctx context.Context, pid int, cgroupPath string) error {
+	if err := cmdexec.RunBashCmdWithoutOutput(ctx, fmt.Sprintf("echo %d > %s/cgroup.procs", pid, cgroupPath)); err != nil {
+		return err
+	}
+
+	return nil
+}
 
 func GetPidStrListByCgroup(ctx context.Context, cgroupPath string) ([]int, error) {
 	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("cat %s/tasks", cgroupPath))
@@ -183,3 +210,77 @@ func RemoveCgroup(ctx context.Context, cgroupPath string) error {
 
 	return nil
 }
+
+// GetPidCgroupPath returns the cgroup path of the process in the hierarchy of subSys, or the unified path on cgroup v2
+func GetPidCgroupPath(ctx context.Context, pid int, subSys string) (string, error) {
+	if IsCgroupV2() {
+		return GetPidCurCgroupV2(ctx, pid)
+	}
+
+	return GetpidCurCgroup(ctx, pid, subSys)
+}
+
+// GetSubSys returns the hierarchy dir of subSys, all controllers are in the root dir on cgroup v2
+func GetSubSys(subSys string) string {
+	if IsCgroupV2() {
+		return ""
+	}
+
+	return subSys
+}
+
+// GetAvailableCpuList returns the effective cpu list of the host, or the effective cpuset of the container
+func GetAvailableCpuList(ctx context.Context, cr, cId string) ([]int, error) {
+	var cpusetPath = "/"
+	if cr != "" {
+		client, err := crclient.GetClient(ctx, cr)
+		if err != nil {
+			return nil, fmt.Errorf("get %s client error: %s", cr, err.Error())
+		}
+
+		pid, err := client.GetPidById(ctx, cId)
+		if err != nil {
+			return nil, fmt.Errorf("get pid of container[%s] error: %s", cId, err.Error())
+		}
+
+		cpusetPath, err = GetPidCgroupPath(ctx, pid, CPUSET)
+		if err != nil {
+			return nil, fmt.Errorf("get cgroup[%s] path of container[%s] error: %s", CPUSET, cId, err.Error())
+		}
+	}
+
+	return GetCpusetCpuList(ctx, cpusetPath)
+}
+
+// GetCpusetCpuList returns the effective cpu list of the cpuset cgroup
+func GetCpusetCpuList(ctx context.Context, cpusetPath string) ([]int, error) {
+	var effectiveFile = CpusetEffectiveCoreFile
+	if IsCgroupV2() {
+		effectiveFile = CpusetV2EffectiveCoreFile
+	}
+
+	// the effective file excludes the offline cores and is limited by the ancestors, old kernels do not have it
+	cpuListStr, err := ReadCgroupFileStr(ctx, cpusetPath, GetSubSys(CPUSET), effectiveFile)
+	if err != nil {
+		if cpuListStr, err = ReadCgroupFileStr(ctx, cpusetPath, GetSubSys(CPUSET), CpusetCoreFile); err != nil {
+			return nil, fmt.Errorf("read cpu list string error: %s", err.Error())
+		}
+	}
+
+	cpuList, err := utils.GetNumArrByList(cpuListStr)
+	if err != nil {
+		return nil, fmt.Errorf("format cpu list string error: %s", err.Error())
+	}
+
+	return cpuList, nil
+}
+
+func WriteCgroupFileStr(ctx context.Context, path, subSys, fileName, value string) error {
+	cgroupFile := fmt.Sprintf("%s/%s%s/%s", containercgroup.RootCgroupPath, subSys, path, fileName)
+	log.GetLogger(ctx).Debugf("write \"%s\" to %s", value, cgroupFile)
+	if err := os.WriteFile(cgroupFile, []byte(value), 0644); err != nil {
+		return fmt.Errorf("write to %s error: %s", cgroupFile, err.Error())
+	}
+
+	return nil
+}
@@ -63,3 +63,30 @@ func TestGetBlkioConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestGetNetClsConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		classId    string
+		cgroupPath string
+		want       string
+	}{
+		{
+			classId:    "1:4",
+			cgroupPath: "/sys/fs/cgroup/net_cls/chaosmeta_netcls_1241q52",
+			want:       "echo 0x00010004 > /sys/fs/cgroup/net_cls/chaosmeta_netcls_1241q52/net_cls.classid",
+		},
+		{
+			classId:    "10:ff",
+			cgroupPath: "/sys/fs/cgroup/net_cls/chaosmeta_netcls_1241q52",
+			want:       "echo 0x001000ff > /sys/fs/cgroup/net_cls/chaosmeta_netcls_1241q52/net_cls.classid",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetNetClsConfig(tt.classId, tt.cgroupPath); got != tt.want {
+				t.Errorf("GetNetClsConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
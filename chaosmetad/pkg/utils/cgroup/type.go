@@ -18,19 +18,33 @@ package cgroup
 
 const (
 	BLKIO  = "blkio"
+	CPU    = "cpu"
 	CPUSET = "cpuset"
 	MEMORY = "memory"
+	NETCLS = "net_cls"
 )
 
 const (
-	MemUnLimit             = 9223372036854771712
-	MemoryLimitInBytesFile = "memory.limit_in_bytes"
-	MemoryStatFile         = "memory.stat"
-	MemoryUsageInBytesFile = "memory.usage_in_bytes"
-	CpusetCoreFile         = "cpuset.cpus"
-	WriteBytesFile         = "blkio.throttle.write_bps_device"
-	ReadBytesFile          = "blkio.throttle.read_bps_device"
-	WriteIOFile            = "blkio.throttle.write_iops_device"
-	ReadIOFile             = "blkio.throttle.read_iops_device"
-	BlkioCgroupName        = "chaosmeta_blkio"
+	MemUnLimit                = 9223372036854771712
+	MemoryLimitInBytesFile    = "memory.limit_in_bytes"
+	MemoryStatFile            = "memory.stat"
+	MemoryUsageInBytesFile    = "memory.usage_in_bytes"
+	MemorySwappinessFile      = "memory.swappiness"
+	MemoryHighFile            = "memory.high"
+	MemoryCurrentFile         = "memory.current"
+	CpusetCoreFile            = "cpuset.cpus"
+	CpusetEffectiveCoreFile   = "cpuset.effective_cpus"
+	CpusetV2EffectiveCoreFile = "cpuset.cpus.effective"
+	CpuSharesFile             = "cpu.shares"
+	CpuWeightFile             = "cpu.weight"
+	CgroupProcsFile           = "cgroup.procs"
+	WriteBytesFile            = "blkio.throttle.write_bps_device"
+	ReadBytesFile             = "blkio.throttle.read_bps_device"
+	WriteIOFile               = "blkio.throttle.write_iops_device"
+	ReadIOFile                = "blkio.throttle.read_iops_device"
+	BlkioCgroupName           = "chaosmeta_blkio"
+	NetClsClassIdFile         = "net_cls.classid"
+	NetClsCgroupName          = "chaosmeta_netcls"
+	CgroupV2ControllerFile    = "cgroup.controllers"
+	CgroupV2Prefix            = "0::"
 )
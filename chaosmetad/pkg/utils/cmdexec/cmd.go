@@ -172,6 +172,7 @@ func SupportCmd(cmd string) bool {
 
 func RunBashCmdWithOutput(ctx context.Context, cmd string) (string, error) {
 	log.GetLogger(ctx).Debugf("run cmd with output: %s", cmd)
+	utils.RecordCmd(ctx, cmd)
 	c := exec.Command("/bin/bash", "-c", cmd)
 
 	reByte, err := c.CombinedOutput()
@@ -195,16 +196,19 @@ func RunBashCmdWithOutput(ctx context.Context, cmd string) (string, error) {
 
 func RunBashCmdWithoutOutput(ctx context.Context, cmd string) error {
 	log.GetLogger(ctx).Debugf("run cmd: %s", cmd)
+	utils.RecordCmd(ctx, cmd)
 	return exec.Command("/bin/bash", "-c", cmd).Run()
 }
 
 func StartBashCmd(ctx context.Context, cmd string) error {
 	log.GetLogger(ctx).Debugf("start cmd: %s", cmd)
+	utils.RecordCmd(ctx, cmd)
 	return exec.Command("/bin/bash", "-c", cmd).Start()
 }
 
 func StartBashCmdAndWaitPid(ctx context.Context, cmd string, timeoutSec int) (int, error) {
 	log.GetLogger(ctx).Debugf("start cmd: %s", cmd)
+	utils.RecordCmd(ctx, cmd)
 
 	c := exec.Command("/bin/bash", "-c", cmd)
 	var stdout, stderr bytes.Buffer
@@ -223,6 +227,7 @@ func StartBashCmdAndWaitPid(ctx context.Context, cmd string, timeoutSec int) (in
 
 func StartBashCmdAndWaitByUser(ctx context.Context, cmd, user string) error {
 	log.GetLogger(ctx).Debugf("user: %s, start cmd: %s", user, cmd)
+	utils.RecordCmd(ctx, fmt.Sprintf("runuser -l %s -c %s", user, cmd))
 
 	c := exec.Command("runuser", "-l", user, "-c", cmd)
 	var stdout, stderr bytes.Buffer
@@ -261,6 +266,7 @@ func ExecContainer(ctx context.Context, cr, containerID string, namespaces []str
 	var stdout, stderr bytes.Buffer
 	c.Stdout, c.Stderr = &stdout, &stderr
 	logger.Debugf("container exec cmd: %s", c.Args)
+	utils.RecordCmd(ctx, c.Args[len(c.Args)-1])
 	if err := c.Start(); err != nil {
 		return "", fmt.Errorf("start process error: %s", err.Error())
 	}
@@ -23,6 +23,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -36,11 +37,21 @@ const (
 	RootName   = "chaosmetad"
 	TimeFormat = "2006-01-02 15:04:05"
 	RecoverLog = "/tmp/chaosmetad_recover.log"
+	RampLog    = "/tmp/chaosmetad_ramp.log"
 )
 
 // TraceId for command line
 var TraceId string
 
+// Output format of the command result for command line
+var Output = OutputTable
+
+const (
+	OutputTable = "table"
+	OutputJson  = "json"
+	OutputYaml  = "yaml"
+)
+
 // os
 const (
 	DARWIN = "darwin"
@@ -48,7 +59,24 @@ const (
 )
 
 const (
-	CtxTraceId = "TraceId"
+	CtxTraceId     = "TraceId"
+	CtxActor       = "Actor"
+	CtxCmdRecorder = "CmdRecorder"
+)
+
+// actor of auto recover started by timeout
+const ActorAutoRecover = "auto-recover"
+
+// experiment event type
+const (
+	EventCreated         = "created"
+	EventValidated       = "validated"
+	EventInjected        = "injected"
+	EventResumed         = "resumed"
+	EventUpdated         = "updated"
+	EventAutoRecovered   = "auto-recovered"
+	EventManualRecovered = "manually-recovered"
+	EventErrored         = "errored"
 )
 
 const (
@@ -117,7 +145,16 @@ func GetContainerPath(tool string) string {
 }
 
 func GetSleepRecoverCmd(sleepTime int64, uid string) string {
-	return fmt.Sprintf("sleep %ds; %s/%s recover %s >> %s 2>&1", sleepTime, GetRunPath(), RootName, uid, RecoverLog)
+	return fmt.Sprintf("sleep %ds; %s/%s recover %s --auto >> %s 2>&1", sleepTime, GetRunPath(), RootName, uid, RecoverLog)
+}
+
+func GetRampCmd(uid string) string {
+	return fmt.Sprintf("%s/%s >> %s 2>&1", GetRunPath(), GetRampKey(uid), RampLog)
+}
+
+// GetRampKey is the key to find the ramp process of the experiment
+func GetRampKey(uid string) string {
+	return fmt.Sprintf("%s ramp %s", RootName, uid)
 }
 
 func GetTraceId(ctx context.Context) string {
@@ -136,6 +173,53 @@ func GetCtxWithTraceId(ctx context.Context, traceId string) context.Context {
 	return context.WithValue(ctx, CtxTraceId, traceId)
 }
 
+func GetActor(ctx context.Context) string {
+	if ctx.Value(CtxActor) == nil {
+		return ""
+	}
+
+	return ctx.Value(CtxActor).(string)
+}
+
+func GetCtxWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, CtxActor, actor)
+}
+
+type cmdRecorder struct {
+	lock sync.Mutex
+	cmds []string
+}
+
+// GetCtxWithCmdRecorder returns a ctx which collects the commands executed with it
+func GetCtxWithCmdRecorder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, CtxCmdRecorder, &cmdRecorder{})
+}
+
+func RecordCmd(ctx context.Context, cmd string) {
+	r, ok := ctx.Value(CtxCmdRecorder).(*cmdRecorder)
+	if !ok {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.cmds = append(r.cmds, cmd)
+}
+
+// PopRecordedCmds returns the commands recorded so far and clears them
+func PopRecordedCmds(ctx context.Context) []string {
+	r, ok := ctx.Value(CtxCmdRecorder).(*cmdRecorder)
+	if !ok {
+		return nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	cmds := r.cmds
+	r.cmds = nil
+	return cmds
+}
+
 func GetNumArrByList(listStr string) ([]int, error) {
 	var listArr []int
 	var ifExist = make(map[int]bool)
@@ -201,3 +285,37 @@ func GetNumArrByCount(count int, listArr []int) []int {
 
 	return listArr[:count]
 }
+
+// GetTargetCoreList returns the cores to run the workers on, "list" takes precedence over "count",
+// "count" 0 means all the available cores
+func GetTargetCoreList(listStr string, count int, availList []int) ([]int, error) {
+	if listStr == "" {
+		if count < 0 {
+			return nil, fmt.Errorf("\"count\"[%d] can not less than 0", count)
+		}
+
+		if count == 0 || count > len(availList) {
+			count = len(availList)
+		}
+
+		return GetNumArrByCount(count, availList), nil
+	}
+
+	targetList, err := GetNumArrByList(listStr)
+	if err != nil {
+		return nil, fmt.Errorf("\"list\"[%s] is not valid: %s", listStr, err.Error())
+	}
+
+	var availMap = make(map[int]bool)
+	for _, core := range availList {
+		availMap[core] = true
+	}
+
+	for _, core := range targetList {
+		if !availMap[core] {
+			return nil, fmt.Errorf("\"core\"[%d] is not available", core)
+		}
+	}
+
+	return targetList, nil
+}
@@ -18,6 +18,7 @@ package utils
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -165,6 +166,37 @@ func Test_getNumArrByCount(t *testing.T) {
 	}
 }
 
+func TestGetTargetCoreList(t *testing.T) {
+	avail := []int{0, 1, 2, 3}
+	tests := []struct {
+		name    string
+		list    string
+		count   int
+		want    []int
+		wantErr bool
+	}{
+		{name: "all", want: []int{0, 1, 2, 3}},
+		{name: "count", count: 2, want: []int{0, 1}},
+		{name: "count too large", count: 10, want: []int{0, 1, 2, 3}},
+		{name: "negative count", count: -1, wantErr: true},
+		{name: "list", list: "1,3", count: 1, want: []int{1, 3}},
+		{name: "list not available", list: "2-4", wantErr: true},
+		{name: "list invalid", list: "a", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetTargetCoreList(tt.list, tt.count, avail)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetTargetCoreList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTargetCoreList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestIsValidUid(t *testing.T) {
 	type args struct {
 		uid string
@@ -218,3 +250,10 @@ func TestIsValidUid(t *testing.T) {
 		})
 	}
 }
+
+func TestGetRampKey(t *testing.T) {
+	// the ramp process is found by the key to be stopped when recover
+	if cmd, key := GetRampCmd("a1"), GetRampKey("a1"); !strings.Contains(cmd, "/"+key+" ") {
+		t.Errorf("GetRampCmd() = %s, want to contain key[%s]", cmd, key)
+	}
+}
@@ -0,0 +1,242 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dm
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	TargetDelay  = "delay"
+	TargetFlakey = "flakey"
+	TargetDust   = "dust"
+
+	MapperDir  = "/dev/mapper"
+	SectorSize = 512
+
+	ModeRead  = "read"
+	ModeWrite = "write"
+	ModeAll   = "all"
+
+	// MaxSectorCount limits the sectors of GetSectorList, dm-dust adds bad blocks one by one
+	MaxSectorCount = 1024
+)
+
+// GetDevSectors returns the size of block device in 512-byte sectors
+func GetDevSectors(ctx context.Context, dev string) (int64, error) {
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("blockdev --getsz %s", dev))
+	if err != nil {
+		return 0, err
+	}
+
+	re = strings.TrimSpace(re)
+	sectors, err := strconv.ParseInt(re, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("sectors[%s] is not a num: %s", re, err.Error())
+	}
+
+	return sectors, nil
+}
+
+// GetName returns the name of device-mapper device, empty means dev is not a device-mapper device
+func GetName(ctx context.Context, dev string) (string, error) {
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("if dmsetup info %s > /dev/null 2>&1; then dmsetup info -c --noheadings -o name %s; fi", dev, dev))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(re), nil
+}
+
+func GetTable(ctx context.Context, name string) (string, error) {
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("dmsetup table %s", name))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(re), nil
+}
+
+func Exist(ctx context.Context, name string) (bool, error) {
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("dmsetup info %s > /dev/null 2>&1 && echo 1 || echo 0", name))
+	if err != nil {
+		return false, err
+	}
+
+	return strings.TrimSpace(re) == "1", nil
+}
+
+// Create creates a device-mapper device, table may have several lines
+func Create(ctx context.Context, name, table string) error {
+	_, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("printf '%%s\\n' '%s' | dmsetup create %s", table, name))
+	return err
+}
+
+// Reload replaces the table of an active device. The new table is loaded into the inactive slot first, then "resume"
+// suspends the device, swaps the tables and resumes it in one step, so that the device is never suspended while
+// running other commands(it may back the root filesystem) and is never left suspended on error
+func Reload(ctx context.Context, name, table string) error {
+	if _, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("printf '%%s\\n' '%s' | dmsetup load %s", table, name)); err != nil {
+		// the live table is not touched, only drop the inactive one
+		if _, cErr := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("dmsetup clear %s", name)); cErr != nil {
+			log.GetLogger(ctx).Warnf("clear inactive table of %s error: %s", name, cErr.Error())
+		}
+
+		return fmt.Errorf("load error: %s", err.Error())
+	}
+
+	if _, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("dmsetup resume %s", name)); err != nil {
+		// the device may be suspended before the swap fails, resume it with the live table
+		if _, rErr := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("dmsetup clear %s; dmsetup resume %s", name, name)); rErr != nil {
+			log.GetLogger(ctx).Warnf("resume %s with live table error: %s", name, rErr.Error())
+		}
+
+		return fmt.Errorf("resume error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func Remove(ctx context.Context, name string) error {
+	_, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("dmsetup remove %s", name))
+	return err
+}
+
+func Message(ctx context.Context, name, msg string) error {
+	_, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("dmsetup message %s 0 %s", name, msg))
+	return err
+}
+
+// AddBadSectors marks the sectors of a dm-dust device as bad blocks, it stops at the first failure
+func AddBadSectors(ctx context.Context, name string, sectorList []int64) error {
+	var sectors strings.Builder
+	for _, sector := range sectorList {
+		sectors.WriteString(fmt.Sprintf(" %d", sector))
+	}
+
+	_, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("for s in%s; do dmsetup message %s 0 addbadblock $s || exit 1; done", sectors.String(), name))
+	return err
+}
+
+// SupportTarget checks the target type is supported by kernel, the module is loaded if it is not
+func SupportTarget(ctx context.Context, target string) bool {
+	_, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("dmsetup targets | grep -qw %s || (modprobe dm-%s && dmsetup targets | grep -qw %s)", target, target, target))
+	return err == nil
+}
+
+// IsInUse checks if the block device is mounted or held by other devices, eg: device-mapper, raid
+func IsInUse(ctx context.Context, dev string) (bool, error) {
+	realDev, err := filepath.EvalSymlinks(dev)
+	if err != nil {
+		return false, fmt.Errorf("get real path of %s error: %s", dev, err.Error())
+	}
+
+	devName := filepath.Base(realDev)
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("(findmnt -n -S %s; ls /sys/class/block/%s/holders 2>/dev/null) | wc -l", realDev, devName))
+	if err != nil {
+		return false, err
+	}
+
+	return strings.TrimSpace(re) != "0", nil
+}
+
+// GetPathDev returns the block device the path is located in
+func GetPathDev(ctx context.Context, path string) (string, error) {
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("findmnt -n -o SOURCE --target %s", path))
+	if err != nil {
+		return "", err
+	}
+
+	dev := strings.TrimSpace(re)
+	if !strings.HasPrefix(dev, "/dev/") {
+		return "", fmt.Errorf("%s is not located in a block device, source: %s", path, dev)
+	}
+
+	return dev, nil
+}
+
+// DelayTable delays read and write I/O, 0 means no delay
+func DelayTable(sectors int64, dev string, readMs, writeMs int64) string {
+	return fmt.Sprintf("0 %d %s %s 0 %d %s 0 %d", sectors, TargetDelay, dev, readMs, dev, writeMs)
+}
+
+// FlakeyTable makes the device available for "up" seconds, then unreliable for "down" seconds periodically
+func FlakeyTable(sectors int64, dev string, up, down int64, mode string) string {
+	table := fmt.Sprintf("0 %d %s %s 0 %d %d", sectors, TargetFlakey, dev, up, down)
+	switch mode {
+	case ModeRead:
+		table += " 1 error_reads"
+	case ModeWrite:
+		table += " 1 error_writes"
+	}
+
+	return table
+}
+
+// DustTable uses 512 bytes as block size, so that the bad blocks are sectors
+func DustTable(sectors int64, dev string) string {
+	return fmt.Sprintf("0 %d %s %s 0 %d", sectors, TargetDust, dev, SectorSize)
+}
+
+// GetSectorList parses sectors, format: "10,20-30", the total count can not exceed MaxSectorCount
+func GetSectorList(sectorStr string) ([]int64, error) {
+	var sectorList []int64
+	for _, unit := range strings.Split(sectorStr, ",") {
+		unit = strings.TrimSpace(unit)
+		if unit == "" {
+			continue
+		}
+
+		items := strings.Split(unit, "-")
+		if len(items) > 2 {
+			return nil, fmt.Errorf("sector[%s] format must be \"N\" or \"N-M\"", unit)
+		}
+
+		start, err := strconv.ParseInt(strings.TrimSpace(items[0]), 10, 64)
+		if err != nil || start < 0 {
+			return nil, fmt.Errorf("sector[%s] is not a valid num", items[0])
+		}
+
+		end := start
+		if len(items) == 2 {
+			end, err = strconv.ParseInt(strings.TrimSpace(items[1]), 10, 64)
+			if err != nil || end < start {
+				return nil, fmt.Errorf("sector range[%s] is invalid", unit)
+			}
+		}
+
+		if end-start >= int64(MaxSectorCount-len(sectorList)) {
+			return nil, fmt.Errorf("sector count exceeds %d", MaxSectorCount)
+		}
+
+		for s := start; s <= end; s++ {
+			sectorList = append(sectorList, s)
+		}
+	}
+
+	if len(sectorList) == 0 {
+		return nil, fmt.Errorf("sector list is empty")
+	}
+
+	return sectorList, nil
+}
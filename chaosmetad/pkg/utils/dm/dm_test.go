@@ -0,0 +1,146 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetSectorList(t *testing.T) {
+	tests := []struct {
+		name    string
+		str     string
+		want    []int64
+		wantErr bool
+	}{
+		{name: "normal", str: "1,5-7", want: []int64{1, 5, 6, 7}},
+		{name: "space", str: " 3 , 8 - 9 ", want: []int64{3, 8, 9}},
+		{name: "reverse range", str: "9-8", wantErr: true},
+		{name: "not num", str: "a", wantErr: true},
+		{name: "empty", str: " , ", wantErr: true},
+		{name: "max count", str: fmt.Sprintf("0-%d", MaxSectorCount-1), want: func() []int64 {
+			var re []int64
+			for s := int64(0); s < MaxSectorCount; s++ {
+				re = append(re, s)
+			}
+			return re
+		}()},
+		{name: "too many", str: fmt.Sprintf("5,0-%d", MaxSectorCount-1), wantErr: true},
+		{name: "huge range", str: "0-9223372036854775807", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSectorList(tt.str)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetSectorList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetSectorList() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTable(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "delay", got: DelayTable(2048, "/dev/loop0", 100, 0), want: "0 2048 delay /dev/loop0 0 100 /dev/loop0 0 0"},
+		{name: "flakey all", got: FlakeyTable(2048, "/dev/loop0", 0, 5, ModeAll), want: "0 2048 flakey /dev/loop0 0 0 5"},
+		{name: "flakey write", got: FlakeyTable(2048, "/dev/loop0", 3, 5, ModeWrite), want: "0 2048 flakey /dev/loop0 0 3 5 1 error_writes"},
+		{name: "dust", got: DustTable(2048, "/dev/loop0"), want: "0 2048 dust /dev/loop0 0 512"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s table = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+// fakeDmsetup puts a "dmsetup" into PATH which logs its args, and fails on the sub command "fail"
+func fakeDmsetup(t *testing.T, fail string) string {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "dmsetup.log")
+	script := fmt.Sprintf("#!/bin/bash\necho \"$*\" >> %s\n[ \"$1\" != \"%s\" ]\n", logFile, fail)
+	if err := os.WriteFile(filepath.Join(dir, "dmsetup"), []byte(script), 0755); err != nil {
+		t.Fatalf("write fake dmsetup error: %s", err.Error())
+	}
+	t.Setenv("PATH", dir+":"+os.Getenv("PATH"))
+
+	return logFile
+}
+
+func readCalls(t *testing.T, logFile string) []string {
+	b, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("read dmsetup log error: %s", err.Error())
+	}
+
+	return strings.Split(strings.TrimSpace(string(b)), "\n")
+}
+
+func TestReload(t *testing.T) {
+	tests := []struct {
+		name    string
+		fail    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "normal", want: []string{"load vg-data", "resume vg-data"}},
+		{name: "load fail", fail: "load", want: []string{"load vg-data", "clear vg-data"}, wantErr: true},
+		{name: "resume fail", fail: "resume", want: []string{"load vg-data", "resume vg-data", "clear vg-data", "resume vg-data"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logFile := fakeDmsetup(t, tt.fail)
+			err := Reload(context.Background(), "vg-data", "0 2048 delay /dev/loop0 0 100")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got := readCalls(t, logFile); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dmsetup calls = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddBadSectors(t *testing.T) {
+	logFile := fakeDmsetup(t, "")
+	if err := AddBadSectors(context.Background(), "chaosmeta-1", []int64{1, 5}); err != nil {
+		t.Fatalf("AddBadSectors() error: %s", err.Error())
+	}
+
+	want := []string{"message chaosmeta-1 0 addbadblock 1", "message chaosmeta-1 0 addbadblock 5"}
+	if got := readCalls(t, logFile); !reflect.DeepEqual(got, want) {
+		t.Errorf("dmsetup calls = %v, want %v", got, want)
+	}
+
+	fakeDmsetup(t, "message")
+	if err := AddBadSectors(context.Background(), "chaosmeta-1", []int64{1, 5}); err == nil {
+		t.Errorf("expect error when message fails")
+	}
+}
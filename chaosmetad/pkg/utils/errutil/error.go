@@ -18,19 +18,33 @@ package errutil
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"gopkg.in/yaml.v2"
 	"os"
 )
 
+// The error code is also the exit code of the process, the values are stable and must not be changed:
+// 0 NoErr: success
+// 1 BadArgsErr: invalid args or command
+// 2 DBErr: the local db is unavailable, or the target experiment is not found
+// 3 InjectErr: the fault inject failed, the experiment is recorded as error
+// 4 InternalErr: unexpected error of chaosmetad itself
+// 5 RecoverErr: the fault recover failed, the experiment is kept for retry
+// 6 UnknownErr: unknown error
+// 7 ConflictErr: the uid is used by an experiment with different args
+// 99 ExpectedErr: the exec tool failed with an expected reason
 const (
-	NoErr = iota
-	BadArgsErr
-	DBErr
-	InjectErr
-	InternalErr
-	RecoverErr
-	UnknownErr
+	NoErr       = 0
+	BadArgsErr  = 1
+	DBErr       = 2
+	InjectErr   = 3
+	InternalErr = 4
+	RecoverErr  = 5
+	UnknownErr  = 6
+	ConflictErr = 7
 )
 
 const (
@@ -38,15 +52,109 @@ const (
 	//TestFileErr = 1
 )
 
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
+
+var codeNameMap = map[int]string{
+	NoErr:       "NoErr",
+	BadArgsErr:  "BadArgsErr",
+	DBErr:       "DBErr",
+	InjectErr:   "InjectErr",
+	InternalErr: "InternalErr",
+	RecoverErr:  "RecoverErr",
+	UnknownErr:  "UnknownErr",
+	ConflictErr: "ConflictErr",
+	ExpectedErr: "ExpectedErr",
+}
+
+// Result is the machine-readable result of a command, printed to stdout if the output format is not table
+type Result struct {
+	Uid     string      `json:"uid,omitempty"`
+	Status  string      `json:"status"`
+	Code    int         `json:"code"`
+	Reason  string      `json:"reason"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// GetExitCode the unknown code is mapped to UnknownErr
+func GetExitCode(code int) int {
+	if _, ok := codeNameMap[code]; !ok {
+		return UnknownErr
+	}
+
+	return code
+}
+
+func CheckOutput(output string) error {
+	if output != utils.OutputTable && output != utils.OutputJson && output != utils.OutputYaml {
+		return fmt.Errorf("not support output: %s, support: %s, %s, %s", output, utils.OutputTable, utils.OutputJson, utils.OutputYaml)
+	}
+
+	return nil
+}
+
 func SolveErr(ctx context.Context, code int, msg string) {
+	SolveResult(ctx, &Result{Code: code, Message: msg})
+}
+
+func SolveErrWithUid(ctx context.Context, uid string, code int, msg string) {
+	SolveResult(ctx, &Result{Uid: uid, Code: code, Message: msg})
+}
+
+// SolveResult prints the result and exits with the code
+func SolveResult(ctx context.Context, r *Result) {
+	code := GetExitCode(r.Code)
+	if utils.Output != utils.OutputTable {
+		r.Code, r.Reason = code, codeNameMap[code]
+		if code == NoErr {
+			r.Status = StatusSuccess
+		} else {
+			r.Status = StatusFailed
+		}
+
+		if err := PrintResult(r); err != nil {
+			log.GetLogger(ctx).Errorf("print result error: %s", err.Error())
+			os.Exit(InternalErr)
+		}
+	}
+
 	if code == NoErr {
-		log.GetLogger(ctx).Debug(msg)
+		log.GetLogger(ctx).Debug(r.Message)
 		os.Exit(NoErr)
 	}
-	log.GetLogger(ctx).Error(msg)
+	log.GetLogger(ctx).Error(r.Message)
 	os.Exit(code)
 }
 
+// PrintResult prints the data to stdout by the output format
+func PrintResult(data interface{}) error {
+	reBytes, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("format to json error: %s", err.Error())
+	}
+
+	if utils.Output == utils.OutputYaml {
+		// json is a subset of yaml, so the field names and order of the json tags are kept
+		var obj yaml.MapSlice
+		if err := yaml.Unmarshal(reBytes, &obj); err != nil {
+			return fmt.Errorf("parse json error: %s", err.Error())
+		}
+
+		if reBytes, err = yaml.Marshal(obj); err != nil {
+			return fmt.Errorf("format to yaml error: %s", err.Error())
+		}
+
+		fmt.Print(string(reBytes))
+		return nil
+	}
+
+	fmt.Println(string(reBytes))
+	return nil
+}
+
 func ExitExpectedErr(msg string) {
 	fmt.Printf("[error]%s\n", msg)
 	os.Exit(ExpectedErr)
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package errutil
+
+import (
+	"testing"
+)
+
+func TestGetExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		code int
+		want int
+	}{
+		{name: "success", code: NoErr, want: 0},
+		{name: "bad args", code: BadArgsErr, want: 1},
+		{name: "db", code: DBErr, want: 2},
+		{name: "inject", code: InjectErr, want: 3},
+		{name: "internal", code: InternalErr, want: 4},
+		{name: "recover", code: RecoverErr, want: 5},
+		{name: "unknown", code: UnknownErr, want: 6},
+		{name: "conflict", code: ConflictErr, want: 7},
+		{name: "expected", code: ExpectedErr, want: 99},
+		{name: "not defined", code: 42, want: UnknownErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetExitCode(tt.code); got != tt.want {
+				t.Errorf("GetExitCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckOutput(t *testing.T) {
+	for _, output := range []string{"table", "json", "yaml"} {
+		if err := CheckOutput(output); err != nil {
+			t.Errorf("CheckOutput(%s) error: %v", output, err)
+		}
+	}
+
+	if err := CheckOutput("xml"); err == nil {
+		t.Errorf("CheckOutput(xml) expect error")
+	}
+}
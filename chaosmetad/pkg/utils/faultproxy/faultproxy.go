@@ -0,0 +1,245 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package faultproxy holds what the fault proxies(middleware, grpc) have in common: the rule passed through shell
+// args, the upstream of the redirected connections, the upstream dialer which bypasses the redirect rules and the
+// percent sampler
+package faultproxy
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	DialTimeout = 5 * time.Second
+
+	// soOriginalDst is SO_ORIGINAL_DST of linux/netfilter_ipv4.h
+	soOriginalDst = 80
+)
+
+// Server is implemented by the fault proxies and the stand-in servers of the tests
+type Server interface {
+	Serve(l net.Listener) error
+}
+
+// ServeFunc adapts a function to Server
+type ServeFunc func(l net.Listener) error
+
+func (f ServeFunc) Serve(l net.Listener) error {
+	return f(l)
+}
+
+// EncodeRule encodes rule to a string which is safe to pass through shell args
+func EncodeRule(rule interface{}) (string, error) {
+	b, err := json.Marshal(rule)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// DecodeRule decodes the string generated by EncodeRule into rule, rule must be a pointer
+func DecodeRule(s string, rule interface{}) error {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("base64 decode error: %s", err.Error())
+	}
+
+	if err := json.Unmarshal(b, rule); err != nil {
+		return fmt.Errorf("json unmarshal error: %s", err.Error())
+	}
+
+	return nil
+}
+
+// NewDialer creates a dialer for the upstream, the connections are marked with "mark" if it is not 0,
+// so that they can bypass the redirect rules
+func NewDialer(mark int) *net.Dialer {
+	d := &net.Dialer{Timeout: DialTimeout}
+	if mark != 0 {
+		d.Control = func(network, address string, c syscall.RawConn) error {
+			var sErr error
+			if err := c.Control(func(fd uintptr) {
+				sErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_MARK, mark)
+			}); err != nil {
+				return err
+			}
+
+			return sErr
+		}
+	}
+
+	return d
+}
+
+// UpstreamFunc returns the address which the client connection is forwarded to
+type UpstreamFunc func(client net.Conn) (string, error)
+
+// FixedUpstream forwards all the client connections to addr
+func FixedUpstream(addr string) UpstreamFunc {
+	return func(client net.Conn) (string, error) {
+		return addr, nil
+	}
+}
+
+// RedirectedUpstream forwards the connections redirected from "port" by iptables to their original destination,
+// the other connections, e.g. the ones to the proxy port directly, are rejected
+func RedirectedUpstream(port int) UpstreamFunc {
+	return func(client net.Conn) (string, error) {
+		dst, err := OriginalDst(client)
+		if err != nil {
+			return "", fmt.Errorf("get original destination error: %s", err.Error())
+		}
+
+		if dst.Port != port {
+			return "", fmt.Errorf("connection to %s is not redirected from port %d", dst.String(), port)
+		}
+
+		return dst.String(), nil
+	}
+}
+
+// OriginalDst returns the destination of the ipv4 tcp connection before it is redirected by iptables
+func OriginalDst(conn net.Conn) (*net.TCPAddr, error) {
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return nil, fmt.Errorf("not a tcp connection")
+	}
+
+	rawConn, err := tcpConn.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		addr *net.TCPAddr
+		sErr error
+	)
+	if err := rawConn.Control(func(fd uintptr) {
+		// the result is a sockaddr_in: family(2 bytes), port(2 bytes, big endian), ipv4 address(4 bytes)
+		sa, err := syscall.GetsockoptIPv6Mreq(int(fd), syscall.IPPROTO_IP, soOriginalDst)
+		if err != nil {
+			sErr = err
+			return
+		}
+
+		b := sa.Multiaddr
+		addr = &net.TCPAddr{IP: net.IPv4(b[4], b[5], b[6], b[7]), Port: int(b[2])<<8 | int(b[3])}
+	}); err != nil {
+		return nil, err
+	}
+
+	return addr, sErr
+}
+
+// Conn is an accepted client connection with its upstream
+type Conn struct {
+	net.Conn
+	Upstream string
+}
+
+type upstreamListener struct {
+	net.Listener
+	upstream UpstreamFunc
+}
+
+// NewListener wraps l to resolve the upstream of the accepted connections, which are returned as *Conn,
+// the connections failing to resolve are closed
+func NewListener(l net.Listener, upstream UpstreamFunc) net.Listener {
+	return &upstreamListener{Listener: l, upstream: upstream}
+}
+
+func (l *upstreamListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		addr, err := l.upstream(conn)
+		if err != nil {
+			_ = conn.Close()
+			continue
+		}
+
+		return &Conn{Conn: conn, Upstream: addr}, nil
+	}
+}
+
+// Sampler decides whether a matched request is affected, it is safe for concurrent use
+type Sampler struct {
+	percent int
+
+	lock sync.Mutex
+	rand *rand.Rand
+}
+
+func NewSampler(percent int) *Sampler {
+	return &Sampler{
+		percent: percent,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Hit reports whether the request should be affected
+func (s *Sampler) Hit() bool {
+	if s.percent >= 100 {
+		return true
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.rand.Intn(100) < s.percent
+}
+
+// ServeConn accepts connections until the listener is closed, each connection is handled in its own goroutine
+// and closed after handle returns
+func ServeConn(l net.Listener, handle func(conn net.Conn)) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			defer conn.Close()
+			handle(conn)
+		}()
+	}
+}
+
+// Start listens on addr and serves in background, the error of serving is sent to the returned channel
+func Start(addr string, s Server) (net.Listener, <-chan error, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.Serve(l)
+	}()
+
+	return l, errCh, nil
+}
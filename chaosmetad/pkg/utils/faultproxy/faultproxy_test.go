@@ -0,0 +1,159 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package faultproxy
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+type testRule struct {
+	Pattern string `json:"pattern"`
+	Percent int    `json:"percent"`
+}
+
+func TestRuleCodec(t *testing.T) {
+	s, err := EncodeRule(&testRule{Pattern: "^GET 'a b'$", Percent: 50})
+	if err != nil {
+		t.Fatalf("encode error: %s", err.Error())
+	}
+
+	r := &testRule{}
+	if err := DecodeRule(s, r); err != nil {
+		t.Fatalf("decode error: %s", err.Error())
+	}
+
+	if r.Pattern != "^GET 'a b'$" || r.Percent != 50 {
+		t.Errorf("unexpected rule: %+v", r)
+	}
+
+	if err := DecodeRule("not base64!", r); err == nil {
+		t.Errorf("expect error for invalid string")
+	}
+}
+
+func TestSampler_Hit(t *testing.T) {
+	cases := []struct {
+		percent int
+		min     int
+		max     int
+	}{
+		{percent: 100, min: 1000, max: 1000},
+		{percent: 0, min: 0, max: 0},
+		{percent: 50, min: 350, max: 650},
+	}
+
+	for _, c := range cases {
+		s, hit := NewSampler(c.percent), 0
+		for i := 0; i < 1000; i++ {
+			if s.Hit() {
+				hit++
+			}
+		}
+
+		if hit < c.min || hit > c.max {
+			t.Errorf("percent %d: hit %d times of 1000, expect [%d,%d]", c.percent, hit, c.min, c.max)
+		}
+	}
+}
+
+func TestStart(t *testing.T) {
+	l, errCh, err := Start("127.0.0.1:0", ServeFunc(func(l net.Listener) error {
+		return ServeConn(l, func(conn net.Conn) {
+			_, _ = io.Copy(conn, conn)
+		})
+	}))
+	if err != nil {
+		t.Fatalf("start error: %s", err.Error())
+	}
+
+	conn, err := NewDialer(0).Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("dial error: %s", err.Error())
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatalf("write error: %s", err.Error())
+	}
+
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "ping" {
+		t.Errorf("unexpected echo: %q, error: %v", buf, err)
+	}
+
+	_ = l.Close()
+	if err := <-errCh; err == nil {
+		t.Errorf("expect serve error after listener closed")
+	}
+}
+
+func TestNewListener(t *testing.T) {
+	cases := []struct {
+		name     string
+		upstream UpstreamFunc
+		accept   bool
+	}{
+		{name: "fixed", upstream: FixedUpstream("127.0.0.1:3306"), accept: true},
+		// a direct connection is not redirected from port 3306
+		{name: "redirected", upstream: RedirectedUpstream(3306), accept: false},
+	}
+
+	for _, c := range cases {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("listen error: %s", err.Error())
+		}
+
+		connCh := make(chan net.Conn, 1)
+		go func() {
+			conn, err := NewListener(l, c.upstream).Accept()
+			if err == nil {
+				connCh <- conn
+			}
+		}()
+
+		client, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			t.Fatalf("%s: dial error: %s", c.name, err.Error())
+		}
+
+		var conn net.Conn
+		select {
+		case conn = <-connCh:
+		case <-time.After(time.Second):
+		}
+
+		if c.accept {
+			if conn == nil || conn.(*Conn).Upstream != "127.0.0.1:3306" {
+				t.Errorf("%s: expect connection with upstream 127.0.0.1:3306, got %v", c.name, conn)
+			}
+		} else {
+			if conn != nil {
+				t.Errorf("%s: expect connection rejected", c.name)
+			}
+			if _, err := client.Read(make([]byte, 1)); err == nil {
+				t.Errorf("%s: expect rejected connection closed", c.name)
+			}
+		}
+
+		_ = client.Close()
+		_ = l.Close()
+	}
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package proxytest provides the helpers for testing the fault proxies
+package proxytest
+
+import (
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/faultproxy"
+	"net"
+	"testing"
+)
+
+// Start serves s on a random local port until the test ends, returns the listened address
+func Start(t *testing.T, s faultproxy.Server) string {
+	l, _, err := faultproxy.Start("127.0.0.1:0", s)
+	if err != nil {
+		t.Fatalf("listen error: %s", err.Error())
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	return l.Addr().String()
+}
+
+// StartConn is like Start, handle is called for each connection
+func StartConn(t *testing.T, handle func(conn net.Conn)) string {
+	return Start(t, faultproxy.ServeFunc(func(l net.Listener) error {
+		return faultproxy.ServeConn(l, handle)
+	}))
+}
@@ -0,0 +1,280 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpcproxy
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/faultproxy"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ActionStatus = "status"
+	ActionDelay  = "delay"
+	ActionDrop   = "drop"
+
+	DefaultMessage = "chaosmeta injected error"
+
+	codeUnavailable = 14
+)
+
+// Codes are the names of the grpc status codes
+var Codes = []string{"OK", "CANCELLED", "UNKNOWN", "INVALID_ARGUMENT", "DEADLINE_EXCEEDED", "NOT_FOUND", "ALREADY_EXISTS",
+	"PERMISSION_DENIED", "RESOURCE_EXHAUSTED", "FAILED_PRECONDITION", "ABORTED", "OUT_OF_RANGE", "UNIMPLEMENTED", "INTERNAL",
+	"UNAVAILABLE", "DATA_LOSS", "UNAUTHENTICATED"}
+
+// ParseCode supports the number or the name of the grpc status code, eg: "14" or "UNAVAILABLE"
+func ParseCode(codeStr string) (int, error) {
+	codeStr = strings.TrimSpace(codeStr)
+	if code, err := strconv.Atoi(codeStr); err == nil {
+		if code < 0 || code >= len(Codes) {
+			return 0, fmt.Errorf("code must be in [0,%d]", len(Codes)-1)
+		}
+
+		return code, nil
+	}
+
+	for code, name := range Codes {
+		if strings.EqualFold(name, codeStr) {
+			return code, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown code: %s", codeStr)
+}
+
+// Rule decides which calls are affected and how
+type Rule struct {
+	// Methods are full method names, eg: "/pkg.Service/Method", "/pkg.Service/*" matches all methods of the service
+	Methods  []string          `json:"methods"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Action   string            `json:"action"`
+	Code     int               `json:"code,omitempty"`
+	Message  string            `json:"message,omitempty"`
+	Delay    int64             `json:"delay,omitempty"` // millisecond
+	Percent  int               `json:"percent"`
+}
+
+// EncodeRule encodes rule to a string which is safe to pass through shell args
+func EncodeRule(r *Rule) (string, error) {
+	return faultproxy.EncodeRule(r)
+}
+
+func DecodeRule(s string) (*Rule, error) {
+	r := &Rule{}
+	if err := faultproxy.DecodeRule(s, r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// CheckMethod checks the format of full method name: "/pkg.Service/Method" or "/pkg.Service/*"
+func CheckMethod(method string) error {
+	items := strings.Split(method, "/")
+	if len(items) != 3 || items[0] != "" || items[1] == "" || items[2] == "" {
+		return fmt.Errorf("format of method must be \"/pkg.Service/Method\"")
+	}
+
+	return nil
+}
+
+// upstreamKey is the context key of the upstream address of the client connection
+type upstreamKey struct{}
+
+// Proxy is a h2c(http2 without tls) reverse proxy, the streams are forwarded one by one, so that the fault of a call
+// does not affect the other calls in the same connection
+type Proxy struct {
+	upstream  faultproxy.UpstreamFunc
+	rule      *Rule
+	transport *http2.Transport
+	sampler   *faultproxy.Sampler
+}
+
+// NewProxy creates a proxy which forwards to upstream, the connections to upstream are marked with "mark" if it is not 0,
+// so that they can bypass the redirect rules
+func NewProxy(upstream faultproxy.UpstreamFunc, mark int, rule *Rule) (*Proxy, error) {
+	if rule.Action != ActionStatus && rule.Action != ActionDelay && rule.Action != ActionDrop {
+		return nil, fmt.Errorf("not support action: %s", rule.Action)
+	}
+
+	if rule.Percent <= 0 || rule.Percent > 100 {
+		return nil, fmt.Errorf("percent must be in (0,100]")
+	}
+
+	if len(rule.Methods) == 0 {
+		return nil, fmt.Errorf("methods is empty")
+	}
+
+	for _, m := range rule.Methods {
+		if err := CheckMethod(m); err != nil {
+			return nil, fmt.Errorf("method[%s] is invalid: %s", m, err.Error())
+		}
+	}
+
+	if rule.Message == "" {
+		rule.Message = DefaultMessage
+	}
+
+	dialer := faultproxy.NewDialer(mark)
+	return &Proxy{
+		upstream: upstream,
+		rule:     rule,
+		sampler:  faultproxy.NewSampler(rule.Percent),
+		transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				return dialer.Dial(network, addr)
+			},
+		},
+	}, nil
+}
+
+// Serve accepts client connections until the listener is closed
+func (p *Proxy) Serve(l net.Listener) error {
+	server := &http.Server{
+		Handler: h2c.NewHandler(p, &http2.Server{}),
+		ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+			return context.WithValue(ctx, upstreamKey{}, c.(*faultproxy.Conn).Upstream)
+		},
+	}
+	return server.Serve(faultproxy.NewListener(l, p.upstream))
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if p.hit(r) {
+		switch p.rule.Action {
+		case ActionDelay:
+			time.Sleep(time.Duration(p.rule.Delay) * time.Millisecond)
+		case ActionDrop:
+			// the http2 server resets the stream
+			panic(http.ErrAbortHandler)
+		case ActionStatus:
+			writeStatus(w, p.rule.Code, p.rule.Message)
+			return
+		}
+	}
+
+	p.forward(w, r)
+}
+
+// hit reports whether the rule should be applied on the call
+func (p *Proxy) hit(r *http.Request) bool {
+	if !matchMethod(p.rule.Methods, r.URL.Path) {
+		return false
+	}
+
+	for k, v := range p.rule.Metadata {
+		if r.Header.Get(k) != v {
+			return false
+		}
+	}
+
+	return p.sampler.Hit()
+}
+
+func matchMethod(methods []string, path string) bool {
+	for _, m := range methods {
+		if m == path {
+			return true
+		}
+
+		if strings.HasSuffix(m, "/*") && strings.HasPrefix(path, strings.TrimSuffix(m, "*")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Proxy) forward(w http.ResponseWriter, r *http.Request) {
+	out := r.Clone(r.Context())
+	out.URL.Scheme, out.URL.Host, out.RequestURI = "http", r.Context().Value(upstreamKey{}).(string), ""
+
+	resp, err := p.transport.RoundTrip(out)
+	if err != nil {
+		writeStatus(w, codeUnavailable, fmt.Sprintf("chaosmeta proxy forward error: %s", err.Error()))
+		return
+	}
+	defer resp.Body.Close()
+
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.StatusCode)
+
+	// flush every message of streaming calls, the headers are sent with the first message, so that the
+	// trailers-only response of upstream keeps being trailers-only
+	flusher, _ := w.(http.Flusher)
+	buf := make([]byte, 32*1024)
+	for {
+		n, rErr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return
+			}
+
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+
+		if rErr == io.EOF {
+			break
+		}
+
+		if rErr != nil {
+			panic(http.ErrAbortHandler)
+		}
+	}
+
+	for k, v := range resp.Trailer {
+		w.Header()[http.TrailerPrefix+k] = v
+	}
+}
+
+// writeStatus writes a trailers-only response
+func writeStatus(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Grpc-Status", strconv.Itoa(code))
+	w.Header().Set("Grpc-Message", encodeGrpcMessage(msg))
+	w.WriteHeader(http.StatusOK)
+}
+
+// encodeGrpcMessage percent-encodes the message as the grpc protocol requires
+func encodeGrpcMessage(msg string) string {
+	var sb strings.Builder
+	for j := 0; j < len(msg); j++ {
+		c := msg[j]
+		if c >= ' ' && c <= '~' && c != '%' {
+			sb.WriteByte(c)
+		} else {
+			sb.WriteString(fmt.Sprintf("%%%02X", c))
+		}
+	}
+
+	return sb.String()
+}
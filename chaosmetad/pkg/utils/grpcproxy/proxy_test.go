@@ -0,0 +1,159 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpcproxy
+
+import (
+	"crypto/tls"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/faultproxy"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/faultproxy/proxytest"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// startStandIn starts a fake grpc server which echoes the method in body and replies status OK in trailers
+func startStandIn(t *testing.T) string {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/grpc")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(r.URL.Path))
+		w.Header().Set(http.TrailerPrefix+"Grpc-Status", "0")
+	})
+
+	return proxytest.Start(t, &http.Server{Handler: h2c.NewHandler(handler, &http2.Server{})})
+}
+
+func startProxy(t *testing.T, upstream string, rule *Rule) string {
+	p, err := NewProxy(faultproxy.FixedUpstream(upstream), 0, rule)
+	if err != nil {
+		t.Fatalf("new proxy error: %s", err.Error())
+	}
+
+	return proxytest.Start(t, p)
+}
+
+type callResult struct {
+	status string
+	body   string
+	err    error
+}
+
+func call(addr, method string, md map[string]string) callResult {
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+			return net.Dial(network, addr)
+		},
+	}}
+
+	req, _ := http.NewRequest(http.MethodPost, "http://"+addr+method, strings.NewReader("ping"))
+	req.Header.Set("Content-Type", "application/grpc")
+	for k, v := range md {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return callResult{err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return callResult{err: err}
+	}
+
+	// trailers-only response carries the status in headers
+	status := resp.Trailer.Get("Grpc-Status")
+	if status == "" {
+		status = resp.Header.Get("Grpc-Status")
+	}
+
+	return callResult{status: status, body: string(body)}
+}
+
+func TestProxyStatus(t *testing.T) {
+	addr := startProxy(t, startStandIn(t), &Rule{
+		Methods:  []string{"/demo.Greeter/SayHello", "/demo.Admin/*"},
+		Metadata: map[string]string{"x-user": "chaos"},
+		Action:   ActionStatus,
+		Code:     codeUnavailable,
+		Percent:  100,
+	})
+
+	tests := []struct {
+		name       string
+		method     string
+		md         map[string]string
+		wantStatus string
+		wantBody   string
+	}{
+		{name: "hit", method: "/demo.Greeter/SayHello", md: map[string]string{"x-user": "chaos"}, wantStatus: "14"},
+		{name: "hit service", method: "/demo.Admin/Reset", md: map[string]string{"x-user": "chaos"}, wantStatus: "14"},
+		{name: "other method", method: "/demo.Greeter/SayBye", md: map[string]string{"x-user": "chaos"}, wantStatus: "0", wantBody: "/demo.Greeter/SayBye"},
+		{name: "metadata not match", method: "/demo.Greeter/SayHello", wantStatus: "0", wantBody: "/demo.Greeter/SayHello"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := call(addr, tt.method, tt.md)
+			if got.err != nil {
+				t.Fatalf("call error: %s", got.err.Error())
+			}
+
+			if got.status != tt.wantStatus || got.body != tt.wantBody {
+				t.Errorf("got status %q body %q, want status %q body %q", got.status, got.body, tt.wantStatus, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestProxyDrop(t *testing.T) {
+	addr := startProxy(t, startStandIn(t), &Rule{Methods: []string{"/demo.Greeter/SayHello"}, Action: ActionDrop, Percent: 100})
+
+	if got := call(addr, "/demo.Greeter/SayHello", nil); got.err == nil {
+		t.Errorf("stream should be reset, got status %q body %q", got.status, got.body)
+	}
+
+	if got := call(addr, "/demo.Greeter/SayBye", nil); got.err != nil || got.status != "0" {
+		t.Errorf("other method should not be affected, got status %q error %v", got.status, got.err)
+	}
+}
+
+func TestParseCode(t *testing.T) {
+	tests := []struct {
+		code    string
+		want    int
+		wantErr bool
+	}{
+		{code: "14", want: 14},
+		{code: "unavailable", want: 14},
+		{code: "DEADLINE_EXCEEDED", want: 4},
+		{code: "17", wantErr: true},
+		{code: "BAD", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCode(tt.code)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+			t.Errorf("ParseCode(%q) = %d, %v, want %d, wantErr %v", tt.code, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mwproxy
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+)
+
+const (
+	mysqlHeaderLen      = 4
+	mysqlMaxPayloadLen  = 0xffffff
+	mysqlComQuery       = 0x03
+	mysqlComStmtPrepare = 0x16
+	mysqlClientCompress = 0x00000020
+	mysqlClientSSL      = 0x00000800
+	mysqlSQLState       = "HY000"
+)
+
+// handleMySQL inspects the client packets only. The classic protocol is request-response, so the server stream is
+// copied as it is, and an injected ERR packet is written when the server is idle for this connection
+func (p *Proxy) handleMySQL(client, server net.Conn) {
+	var (
+		cr   = bufio.NewReader(client)
+		lock sync.Mutex
+	)
+
+	go func() {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := server.Read(buf)
+			if n > 0 {
+				lock.Lock()
+				_, wErr := client.Write(buf[:n])
+				lock.Unlock()
+				if wErr != nil {
+					break
+				}
+			}
+
+			if err != nil {
+				break
+			}
+		}
+
+		_ = client.Close()
+		_ = server.Close()
+	}()
+
+	// handshake response, the packets can not be inspected if ssl or compression is used
+	pkt, err := readMySQLPacket(cr)
+	if err != nil {
+		return
+	}
+
+	if _, err := server.Write(pkt); err != nil {
+		return
+	}
+
+	if len(pkt) >= mysqlHeaderLen+4 {
+		capability := binary.LittleEndian.Uint32(pkt[mysqlHeaderLen : mysqlHeaderLen+4])
+		if capability&(mysqlClientSSL|mysqlClientCompress) != 0 {
+			_, _ = io.Copy(server, cr)
+			return
+		}
+	}
+
+	for {
+		pkt, err = readMySQLPacket(cr)
+		if err != nil {
+			return
+		}
+
+		// command packet always starts a new sequence, packets of auth exchange do not
+		payload := pkt[mysqlHeaderLen:]
+		if pkt[3] == 0 && len(payload) > 0 && (payload[0] == mysqlComQuery || payload[0] == mysqlComStmtPrepare) &&
+			p.hit(string(payload[1:])) {
+			switch p.rule.Action {
+			case ActionDelay:
+				p.delay()
+			case ActionDrop:
+				return
+			case ActionError:
+				// the statement larger than 16M is split into several packets
+				for len(payload) == mysqlMaxPayloadLen {
+					if pkt, err = readMySQLPacket(cr); err != nil {
+						return
+					}
+					payload = pkt[mysqlHeaderLen:]
+				}
+
+				lock.Lock()
+				_, err = client.Write(mysqlErrPacket(1, p.rule.Code, p.rule.Message))
+				lock.Unlock()
+				if err != nil {
+					return
+				}
+				continue
+			}
+		}
+
+		if _, err := server.Write(pkt); err != nil {
+			return
+		}
+	}
+}
+
+// readMySQLPacket returns the whole packet including the header
+func readMySQLPacket(r *bufio.Reader) ([]byte, error) {
+	header := make([]byte, mysqlHeaderLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	length := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	pkt := make([]byte, mysqlHeaderLen+length)
+	copy(pkt, header)
+	if _, err := io.ReadFull(r, pkt[mysqlHeaderLen:]); err != nil {
+		return nil, err
+	}
+
+	return pkt, nil
+}
+
+func mysqlErrPacket(seq byte, code int, msg string) []byte {
+	payload := make([]byte, 0, 9+len(msg))
+	payload = append(payload, 0xff, byte(code), byte(code>>8), '#')
+	payload = append(payload, mysqlSQLState...)
+	payload = append(payload, msg...)
+
+	length := len(payload)
+	return append([]byte{byte(length), byte(length >> 8), byte(length >> 16), seq}, payload...)
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mwproxy
+
+import (
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/faultproxy"
+	"net"
+	"regexp"
+	"time"
+)
+
+const (
+	ProtocolRedis = "redis"
+	ProtocolMySQL = "mysql"
+
+	ActionDelay = "delay"
+	ActionError = "error"
+	ActionDrop  = "drop"
+
+	DefaultRedisMessage = "ERR chaosmeta injected error"
+	DefaultMySQLMessage = "chaosmeta injected error"
+	DefaultMySQLCode    = 1105 // ER_UNKNOWN_ERROR
+)
+
+// Rule decides which commands(redis) or queries(mysql) are affected and how
+type Rule struct {
+	Pattern string `json:"pattern"`
+	Action  string `json:"action"`
+	Delay   int64  `json:"delay,omitempty"` // millisecond
+	Message string `json:"message,omitempty"`
+	Code    int    `json:"code,omitempty"`
+	Percent int    `json:"percent"`
+}
+
+// EncodeRule encodes rule to a string which is safe to pass through shell args
+func EncodeRule(r *Rule) (string, error) {
+	return faultproxy.EncodeRule(r)
+}
+
+func DecodeRule(s string) (*Rule, error) {
+	r := &Rule{}
+	if err := faultproxy.DecodeRule(s, r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+type Proxy struct {
+	protocol string
+	upstream faultproxy.UpstreamFunc
+	dialer   *net.Dialer
+	rule     *Rule
+	re       *regexp.Regexp
+	sampler  *faultproxy.Sampler
+}
+
+// NewProxy creates a proxy which forwards to upstream, the connections to upstream are marked with "mark" if it is not 0,
+// so that they can bypass the redirect rules
+func NewProxy(protocol string, upstream faultproxy.UpstreamFunc, mark int, rule *Rule) (*Proxy, error) {
+	if protocol != ProtocolRedis && protocol != ProtocolMySQL {
+		return nil, fmt.Errorf("not support protocol: %s", protocol)
+	}
+
+	if rule.Action != ActionDelay && rule.Action != ActionError && rule.Action != ActionDrop {
+		return nil, fmt.Errorf("not support action: %s", rule.Action)
+	}
+
+	if rule.Percent <= 0 || rule.Percent > 100 {
+		return nil, fmt.Errorf("percent must be in (0,100]")
+	}
+
+	re, err := regexp.Compile(rule.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("pattern is not a valid regexp: %s", err.Error())
+	}
+
+	if rule.Message == "" {
+		if protocol == ProtocolRedis {
+			rule.Message = DefaultRedisMessage
+		} else {
+			rule.Message = DefaultMySQLMessage
+		}
+	}
+
+	if rule.Code == 0 {
+		rule.Code = DefaultMySQLCode
+	}
+
+	return &Proxy{
+		protocol: protocol,
+		upstream: upstream,
+		dialer:   faultproxy.NewDialer(mark),
+		rule:     rule,
+		re:       re,
+		sampler:  faultproxy.NewSampler(rule.Percent),
+	}, nil
+}
+
+// Serve accepts client connections until the listener is closed
+func (p *Proxy) Serve(l net.Listener) error {
+	return faultproxy.ServeConn(faultproxy.NewListener(l, p.upstream), p.handle)
+}
+
+func (p *Proxy) handle(client net.Conn) {
+	server, err := p.dialer.Dial("tcp", client.(*faultproxy.Conn).Upstream)
+	if err != nil {
+		return
+	}
+	defer server.Close()
+
+	if p.protocol == ProtocolRedis {
+		p.handleRedis(client, server)
+	} else {
+		p.handleMySQL(client, server)
+	}
+}
+
+// hit reports whether the rule should be applied on the statement
+func (p *Proxy) hit(statement string) bool {
+	if !p.re.MatchString(statement) {
+		return false
+	}
+
+	return p.sampler.Hit()
+}
+
+func (p *Proxy) delay() {
+	time.Sleep(time.Duration(p.rule.Delay) * time.Millisecond)
+}
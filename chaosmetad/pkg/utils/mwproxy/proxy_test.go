@@ -0,0 +1,206 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mwproxy
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/faultproxy"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/faultproxy/proxytest"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func startProxy(t *testing.T, protocol, upstream string, rule *Rule) string {
+	p, err := NewProxy(protocol, faultproxy.FixedUpstream(upstream), 0, rule)
+	if err != nil {
+		t.Fatalf("new proxy error: %s", err.Error())
+	}
+
+	return proxytest.Start(t, p)
+}
+
+// redisStandIn replies "$<len>\r\n<key>\r\n" for GET and "+OK" for others
+func redisStandIn(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	for {
+		_, args, err := readRedisCommand(r)
+		if err != nil {
+			return
+		}
+
+		reply := "+OK\r\n"
+		if strings.ToUpper(args[0]) == "GET" && len(args) > 1 {
+			reply = "$" + strconv.Itoa(len(args[1])) + "\r\n" + args[1] + "\r\n"
+		}
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func readRedisReplies(t *testing.T, r *bufio.Reader, n int) []string {
+	var re []string
+	for j := 0; j < n; j++ {
+		var buf bytes.Buffer
+		if err := readRedisReply(r, &buf); err != nil {
+			t.Fatalf("read reply error: %s", err.Error())
+		}
+		re = append(re, buf.String())
+	}
+
+	return re
+}
+
+func TestRedisErrorKeepsPipelineOrder(t *testing.T) {
+	upstream := proxytest.StartConn(t, redisStandIn)
+	addr := startProxy(t, ProtocolRedis, upstream, &Rule{Pattern: "^GET b$", Action: ActionError, Percent: 100})
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("dial error: %s", err.Error())
+	}
+	defer conn.Close()
+
+	pipeline := "*2\r\n$3\r\nGET\r\n$1\r\na\r\n*2\r\n$3\r\nGET\r\n$1\r\nb\r\nGET c\r\n"
+	if _, err := conn.Write([]byte(pipeline)); err != nil {
+		t.Fatalf("write error: %s", err.Error())
+	}
+
+	got := readRedisReplies(t, bufio.NewReader(conn), 3)
+	want := []string{"$1\r\na\r\n", "-" + DefaultRedisMessage + "\r\n", "$1\r\nc\r\n"}
+	for j := range want {
+		if got[j] != want[j] {
+			t.Errorf("reply[%d] = %q, want %q", j, got[j], want[j])
+		}
+	}
+}
+
+func TestRedisDelayAndDrop(t *testing.T) {
+	upstream := proxytest.StartConn(t, redisStandIn)
+	delayAddr := startProxy(t, ProtocolRedis, upstream, &Rule{Pattern: "^SET ", Action: ActionDelay, Delay: 200, Percent: 100})
+
+	conn, err := net.Dial("tcp", delayAddr)
+	if err != nil {
+		t.Fatalf("dial error: %s", err.Error())
+	}
+	defer conn.Close()
+
+	start := time.Now()
+	_, _ = conn.Write([]byte("SET k v\r\n"))
+	if got := readRedisReplies(t, bufio.NewReader(conn), 1); got[0] != "+OK\r\n" {
+		t.Errorf("reply = %q, want +OK", got[0])
+	}
+	if cost := time.Since(start); cost < 200*time.Millisecond {
+		t.Errorf("reply cost %s, want delay at least 200ms", cost)
+	}
+
+	dropAddr := startProxy(t, ProtocolRedis, upstream, &Rule{Pattern: "^DEL ", Action: ActionDrop, Percent: 100})
+	dConn, err := net.Dial("tcp", dropAddr)
+	if err != nil {
+		t.Fatalf("dial error: %s", err.Error())
+	}
+	defer dConn.Close()
+
+	_, _ = dConn.Write([]byte("DEL k\r\n"))
+	_ = dConn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := dConn.Read(make([]byte, 16)); err == nil {
+		t.Errorf("connection should be closed")
+	}
+}
+
+func writeMySQLPacket(conn net.Conn, seq byte, payload []byte) error {
+	length := len(payload)
+	_, err := conn.Write(append([]byte{byte(length), byte(length >> 8), byte(length >> 16), seq}, payload...))
+	return err
+}
+
+var mysqlOK = []byte{0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00}
+
+// mysqlStandIn sends a greeting, accepts any handshake response and replies OK for each command
+func mysqlStandIn(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	if err := writeMySQLPacket(conn, 0, []byte{0x0a, '8', 0x00}); err != nil {
+		return
+	}
+
+	if _, err := readMySQLPacket(r); err != nil {
+		return
+	}
+
+	if err := writeMySQLPacket(conn, 2, mysqlOK); err != nil {
+		return
+	}
+
+	for {
+		if _, err := readMySQLPacket(r); err != nil {
+			return
+		}
+
+		if err := writeMySQLPacket(conn, 1, mysqlOK); err != nil {
+			return
+		}
+	}
+}
+
+func TestMySQLError(t *testing.T) {
+	upstream := proxytest.StartConn(t, mysqlStandIn)
+	addr := startProxy(t, ProtocolMySQL, upstream, &Rule{Pattern: "(?i)^select .* from user", Action: ActionError, Code: 1213, Message: "deadlock", Percent: 100})
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("dial error: %s", err.Error())
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	if _, err := readMySQLPacket(r); err != nil {
+		t.Fatalf("read greeting error: %s", err.Error())
+	}
+
+	// protocol 41 without ssl and compression
+	if err := writeMySQLPacket(conn, 1, []byte{0x00, 0x02, 0x00, 0x00, 0, 0, 0, 0}); err != nil {
+		t.Fatalf("write handshake response error: %s", err.Error())
+	}
+	if _, err := readMySQLPacket(r); err != nil {
+		t.Fatalf("read auth result error: %s", err.Error())
+	}
+
+	tests := []struct {
+		query string
+		want  []byte
+	}{
+		{query: "SELECT id FROM user", want: mysqlErrPacket(1, 1213, "deadlock")},
+		{query: "SELECT id FROM order", want: append([]byte{byte(len(mysqlOK)), 0, 0, 1}, mysqlOK...)},
+	}
+	for _, tt := range tests {
+		if err := writeMySQLPacket(conn, 0, append([]byte{mysqlComQuery}, tt.query...)); err != nil {
+			t.Fatalf("write query error: %s", err.Error())
+		}
+
+		got, err := readMySQLPacket(r)
+		if err != nil {
+			t.Fatalf("read query result error: %s", err.Error())
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("query %q got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mwproxy
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const redisPendingSize = 1024
+
+// redisPending is a reply the client is waiting for, in the order of commands
+type redisPending struct {
+	reply       []byte // injected reply, nil means the reply comes from server
+	passthrough bool   // replies are not one-to-one after this command, e.g. subscribe
+}
+
+// handleRedis supports pipeline: commands are forwarded as soon as they are read, and replies are written back in
+// the order of commands, so an injected error reply never overtakes the replies of earlier commands
+func (p *Proxy) handleRedis(client, server net.Conn) {
+	var (
+		cr      = bufio.NewReader(client)
+		sr      = bufio.NewReader(server)
+		pending = make(chan *redisPending, redisPendingSize)
+	)
+
+	go writeRedisReplies(client, server, sr, pending)
+	defer close(pending)
+
+	for {
+		raw, args, err := readRedisCommand(cr)
+		if err != nil {
+			return
+		}
+
+		// redis ignores empty inline command without any reply
+		if len(args) == 0 {
+			continue
+		}
+
+		if p.hit(strings.Join(args, " ")) {
+			switch p.rule.Action {
+			case ActionDelay:
+				p.delay()
+			case ActionDrop:
+				return
+			case ActionError:
+				pending <- &redisPending{reply: []byte(fmt.Sprintf("-%s\r\n", p.rule.Message))}
+				continue
+			}
+		}
+
+		if _, err := server.Write(raw); err != nil {
+			return
+		}
+
+		switch strings.ToUpper(args[0]) {
+		case "SUBSCRIBE", "PSUBSCRIBE", "SSUBSCRIBE", "MONITOR":
+			pending <- &redisPending{passthrough: true}
+			_, _ = io.Copy(server, cr)
+			return
+		default:
+			pending <- &redisPending{}
+		}
+	}
+}
+
+func writeRedisReplies(client, server net.Conn, sr *bufio.Reader, pending chan *redisPending) {
+	var buf bytes.Buffer
+	for item := range pending {
+		if item.passthrough {
+			_, _ = io.Copy(client, sr)
+			break
+		}
+
+		reply := item.reply
+		if reply == nil {
+			buf.Reset()
+			if err := readRedisReply(sr, &buf); err != nil {
+				break
+			}
+			reply = buf.Bytes()
+		}
+
+		if _, err := client.Write(reply); err != nil {
+			break
+		}
+	}
+
+	_ = client.Close()
+	_ = server.Close()
+	for range pending {
+	}
+}
+
+// readRedisCommand reads a RESP array command or an inline command, returns the raw bytes and the arguments
+func readRedisCommand(r *bufio.Reader) ([]byte, []string, error) {
+	var buf bytes.Buffer
+	line, err := readRedisLine(r, &buf)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(line) == 0 || line[0] != '*' {
+		return buf.Bytes(), strings.Fields(line), nil
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid multibulk length: %s", line)
+	}
+
+	args := make([]string, 0, n)
+	for j := 0; j < n; j++ {
+		line, err = readRedisLine(r, &buf)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(line) == 0 || line[0] != '$' {
+			return nil, nil, fmt.Errorf("expected bulk string, got: %s", line)
+		}
+
+		arg, err := readRedisBulk(r, &buf, line[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		args = append(args, arg)
+	}
+
+	return buf.Bytes(), args, nil
+}
+
+// readRedisReply reads one complete reply of RESP2 or RESP3 into buf
+func readRedisReply(r *bufio.Reader, buf *bytes.Buffer) error {
+	line, err := readRedisLine(r, buf)
+	if err != nil {
+		return err
+	}
+
+	if len(line) == 0 {
+		return fmt.Errorf("empty reply line")
+	}
+
+	switch line[0] {
+	case '+', '-', ':', '_', ',', '#', '(':
+		return nil
+	case '$', '!', '=':
+		_, err = readRedisBulk(r, buf, line[1:])
+		return err
+	case '*', '~', '>', '%', '|':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return fmt.Errorf("invalid aggregate length: %s", line)
+		}
+
+		// map and attribute have key and value for each element
+		if line[0] == '%' || line[0] == '|' {
+			n *= 2
+		}
+
+		for j := 0; j < n; j++ {
+			if err := readRedisReply(r, buf); err != nil {
+				return err
+			}
+		}
+
+		// attribute is followed by the real reply
+		if line[0] == '|' {
+			return readRedisReply(r, buf)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unknown reply type: %c", line[0])
+	}
+}
+
+func readRedisBulk(r *bufio.Reader, buf *bytes.Buffer, lenStr string) (string, error) {
+	n, err := strconv.Atoi(lenStr)
+	if err != nil {
+		return "", fmt.Errorf("invalid bulk length: %s", lenStr)
+	}
+
+	// null bulk string
+	if n < 0 {
+		return "", nil
+	}
+
+	data := make([]byte, n+2)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return "", err
+	}
+	buf.Write(data)
+
+	return string(data[:n]), nil
+}
+
+// readRedisLine reads a line ended with "\r\n" or "\n", returns the line without the ending
+func readRedisLine(r *bufio.Reader, buf *bytes.Buffer) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	buf.WriteString(line)
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
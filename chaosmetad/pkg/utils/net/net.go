@@ -38,8 +38,13 @@ const (
 	ProtocolTCP6 = "tcp6"
 	ProtocolUDP  = "udp"
 	ProtocolUDP6 = "udp6"
+
+	// ProxyMark marks the connections from the fault proxies to the real service, so that they bypass the redirect rules
+	ProxyMark = 0x4d50
 )
 
+var RedirectChains = []string{"OUTPUT", "PREROUTING"}
+
 func getExistTCRootQdiscCmd(netInterface string) string {
 	return fmt.Sprintf("tc qdisc ls dev %s | grep -w '1: root' | grep -v grep | wc -l", netInterface)
 }
@@ -58,6 +63,34 @@ func getAddNetemQdiscCmd(netInterface, parent, fault string, args string) string
 	return fmt.Sprintf("tc qdisc add dev %s %s netem %s %s", netInterface, parent, fault, args)
 }
 
+func getChangeNetemQdiscCmd(netInterface, parent, fault string, args string) string {
+	if parent == "" {
+		parent = "root handle 1:"
+	} else {
+		parent = fmt.Sprintf("parent %s", parent)
+	}
+
+	return fmt.Sprintf("tc qdisc change dev %s %s netem %s %s", netInterface, parent, fault, args)
+}
+
+func getAddCgroupFilterCmd(netInterface string) string {
+	return fmt.Sprintf("tc filter add dev %s parent 1: protocol ip prio 1 handle 1: cgroup", netInterface)
+}
+
+func getCgroupClassifyRuleCmd(action, cgroupPath, classId string) string {
+	return fmt.Sprintf("iptables -t mangle %s OUTPUT -m cgroup --path %s -j CLASSIFY --set-class %s", action, cgroupPath, classId)
+}
+
+// getRedirectRuleCmd redirects the tcp connections to local "port" to "proxyPort", except the ones with "mark"
+func getRedirectRuleCmd(action, chain string, port, proxyPort, mark int, comment string) string {
+	return fmt.Sprintf("iptables -t nat %s %s -p tcp -m addrtype --dst-type LOCAL --dport %d -m mark ! --mark %d -m comment --comment %s -j REDIRECT --to-ports %d",
+		action, chain, port, mark, comment, proxyPort)
+}
+
+func getExistPortRedirectCmd(chain string, port int) string {
+	return fmt.Sprintf("iptables -t nat -S %s | grep -w -- '--dport %d' | grep -w REDIRECT | wc -l", chain, port)
+}
+
 func getAddPrioQdiscCmd(netInterface, parent, name string) string {
 	if parent == "" {
 		parent = "root"
@@ -102,6 +135,83 @@ func AddFilter(ctx context.Context, cr, cId, netInterface, target, srcIpListStr,
 	return err
 }
 
+// AddCgroupFilter classify the packets by the net_cls classid of the sending process's cgroup(v1)
+func AddCgroupFilter(ctx context.Context, cr, cId, netInterface string) error {
+	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getAddCgroupFilterCmd(netInterface), []string{namespace.NET})
+	return err
+}
+
+// AddCgroupClassifyRule set the class of the packets sent by the processes in cgroup(v2), the prio qdisc uses it as the band directly
+func AddCgroupClassifyRule(ctx context.Context, cr, cId, cgroupPath, classId string) error {
+	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getCgroupClassifyRuleCmd("-A", cgroupPath, classId), []string{namespace.NET})
+	return err
+}
+
+func DeleteCgroupClassifyRule(ctx context.Context, cr, cId, cgroupPath, classId string) error {
+	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getCgroupClassifyRuleCmd("-D", cgroupPath, classId), []string{namespace.NET})
+	return err
+}
+
+// AddRedirectRule redirects the connections from local(OUTPUT) and remote(PREROUTING) clients
+func AddRedirectRule(ctx context.Context, cr, cId string, port, proxyPort, mark int, comment string) error {
+	for j, chain := range RedirectChains {
+		if _, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getRedirectRuleCmd("-A", chain, port, proxyPort, mark, comment), []string{namespace.NET}); err != nil {
+			for _, added := range RedirectChains[:j] {
+				if _, dErr := cmdexec.ExecCommonWithNS(ctx, cr, cId, getRedirectRuleCmd("-D", added, port, proxyPort, mark, comment), []string{namespace.NET}); dErr != nil {
+					log.GetLogger(ctx).Warnf("undo redirect rule of chain %s error: %s", added, dErr.Error())
+				}
+			}
+
+			return fmt.Errorf("add redirect rule to chain %s error: %s", chain, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// DeleteRedirectRule deletes the rules added by AddRedirectRule, the rules not exist are ignored
+func DeleteRedirectRule(ctx context.Context, cr, cId string, port, proxyPort, mark int, comment string) error {
+	for _, chain := range RedirectChains {
+		checkCmd := fmt.Sprintf("%s > /dev/null 2>&1 && echo 1 || echo 0", getRedirectRuleCmd("-C", chain, port, proxyPort, mark, comment))
+		reStr, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, checkCmd, []string{namespace.NET})
+		if err != nil {
+			return fmt.Errorf("check redirect rule of chain %s error: %s", chain, err.Error())
+		}
+
+		if strings.TrimSpace(reStr) != "1" {
+			continue
+		}
+
+		if _, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getRedirectRuleCmd("-D", chain, port, proxyPort, mark, comment), []string{namespace.NET}); err != nil {
+			return fmt.Errorf("delete redirect rule of chain %s error: %s", chain, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// ExistPortRedirect check if the connections to "port" are already redirected by any rule
+func ExistPortRedirect(ctx context.Context, cr, cId string, port int) (bool, error) {
+	for _, chain := range RedirectChains {
+		reStr, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getExistPortRedirectCmd(chain, port), []string{namespace.NET})
+		if err != nil {
+			return false, fmt.Errorf("exec cmd error: %s", err.Error())
+		}
+
+		reStr = strings.TrimSpace(reStr)
+		count, err := strconv.Atoi(reStr)
+		if err != nil {
+			return false, fmt.Errorf("redirect rule count is not a num: %s, output: %s", err.Error(), reStr)
+		}
+
+		if count != 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func AddPrioQdisc(ctx context.Context, cr, cId, netInterface, parent, name string) error {
 	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getAddPrioQdiscCmd(netInterface, parent, name), []string{namespace.NET})
 	return err
@@ -112,6 +222,12 @@ func AddNetemQdisc(ctx context.Context, cr, cId, netInterface, parent, fault str
 	return err
 }
 
+// ChangeNetemQdisc change the args of an existing netem qdisc in place
+func ChangeNetemQdisc(ctx context.Context, cr, cId, netInterface, parent, fault string, args string) error {
+	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getChangeNetemQdiscCmd(netInterface, parent, fault, args), []string{namespace.NET})
+	return err
+}
+
 func ClearTcRule(ctx context.Context, cr, cId, netInterface string) error {
 	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, GetClearTcRuleCmd(netInterface), []string{namespace.NET})
 	return err
@@ -357,3 +473,34 @@ func GetPidByPort(ctx context.Context, cr, cId string, port int, proto string) (
 
 	return pid, nil
 }
+
+// GetListenPortsByPid returns the tcp ports the process listens on, pid is in host's pid namespace
+func GetListenPortsByPid(ctx context.Context, cr, cId string, pid int) ([]int, error) {
+	cmd := fmt.Sprintf("netstat -lntp | awk '$7 ~ /^%d\\//{print $4}' | awk -F: '{print $NF}' | sort -u", pid)
+	if cr != "" {
+		cmd = fmt.Sprintf("netstat -lntp | awk '\\$7 ~ /^%d\\//{print \\$4}' | awk -F: '{print \\$NF}' | sort -u", pid)
+	}
+
+	log.GetLogger(ctx).Debugf("get listen ports by pid cmd: %s", cmd)
+
+	reStr, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, cmd, []string{namespace.NET})
+	if err != nil {
+		return nil, fmt.Errorf("cmd exec error: %s", err.Error())
+	}
+
+	var portList []int
+	for _, portStr := range strings.Split(strings.TrimSpace(reStr), "\n") {
+		portStr = strings.TrimSpace(portStr)
+		if portStr == "" {
+			continue
+		}
+
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			return nil, fmt.Errorf("port[%s] to int error: %s", portStr, err.Error())
+		}
+		portList = append(portList, port)
+	}
+
+	return portList, nil
+}
@@ -21,9 +21,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
 )
 
 func PrintVersion(ctx context.Context) {
+	if utils.Output != utils.OutputTable {
+		errutil.SolveResult(ctx, &errutil.Result{Code: errutil.NoErr, Message: "success", Data: GetVersion()})
+	}
+
 	logger := log.GetLogger(ctx)
 	reBytes, _ := json.Marshal(GetVersion())
 	if log.Path == "" {
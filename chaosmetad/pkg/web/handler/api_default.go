@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"net/http"
 )
 
@@ -37,3 +38,7 @@ func WriteResponse(ctx context.Context, w http.ResponseWriter, res interface{})
 		logger.Errorf("write data error: %s", err.Error())
 	}
 }
+
+func getCtxWithHttpActor(ctx context.Context, r *http.Request) context.Context {
+	return utils.GetCtxWithActor(ctx, "http:"+r.RemoteAddr)
+}
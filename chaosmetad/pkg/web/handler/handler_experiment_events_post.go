@@ -0,0 +1,101 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/model"
+	"net/http"
+)
+
+func ExperimentEventsPost(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+
+	var (
+		ctx       = context.Background()
+		eventsReq = &model.EventsRequest{}
+		eventsRes *model.EventsResponse
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(eventsReq); err != nil {
+		eventsRes = getExperimentEventsPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("req body format error: %s", err.Error()), nil, 0)
+	} else if eventsReq.Uid == "" {
+		eventsRes = getExperimentEventsPostResponse(ctx, errutil.BadArgsErr, "\"uid\" is empty", nil, 0)
+	} else {
+		ctx = utils.GetCtxWithTraceId(ctx, eventsReq.TraceId)
+		db, dbErr := storage.GetEventStore()
+		if dbErr != nil {
+			eventsRes = getExperimentEventsPostResponse(ctx, errutil.DBErr, fmt.Sprintf("get db error: %s", dbErr.Error()), nil, 0)
+		} else {
+			events, total, qErr := db.QueryByUid(eventsReq.Uid, uint(eventsReq.Offset), uint(eventsReq.Limit))
+			if qErr != nil {
+				eventsRes = getExperimentEventsPostResponse(ctx, errutil.DBErr, fmt.Sprintf("db query error: %s", qErr.Error()), nil, 0)
+			} else {
+				eventsRes = getExperimentEventsPostResponse(ctx, errutil.NoErr, "success", events, total)
+			}
+		}
+	}
+
+	WriteResponse(ctx, w, eventsRes)
+}
+
+func getExperimentEventsPostResponse(ctx context.Context, code int, msg string, events []*storage.Event, total int64) *model.EventsResponse {
+	var re = &model.EventsResponse{
+		Code:    code,
+		Message: msg,
+		TraceId: utils.GetTraceId(ctx),
+	}
+	if events != nil {
+		reList := make([]model.EventDataUnit, len(events))
+		for i, event := range events {
+			reList[i] = EventToEventDataUnit(event)
+		}
+
+		re.Data = &model.EventsResponseData{
+			Events: reList,
+			Total:  total,
+		}
+	}
+
+	return re
+}
+
+func EventToEventDataUnit(event *storage.Event) model.EventDataUnit {
+	var cmds []string
+	if event.Commands != "" {
+		// commands are always stored as a json list, keep the raw value if it is broken
+		if err := json.Unmarshal([]byte(event.Commands), &cmds); err != nil {
+			cmds = []string{event.Commands}
+		}
+	}
+
+	return model.EventDataUnit{
+		Uid:        event.Uid,
+		Type:       event.Type,
+		Actor:      event.Actor,
+		TraceId:    event.TraceId,
+		Message:    event.Message,
+		Commands:   cmds,
+		CreateTime: event.CreateTime,
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/model"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "chaosmetad-handler")
+	if err != nil {
+		panic(err)
+	}
+
+	storage.Path = filepath.Join(dir, "chaosmetad.dat")
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestExperimentEventsPost(t *testing.T) {
+	db, err := storage.GetEventStore()
+	if err != nil {
+		t.Fatalf("GetEventStore() error = %v", err)
+	}
+
+	for _, event := range []*storage.Event{
+		{Uid: "a1", Type: "created", Actor: "http:10.0.0.1", TraceId: "trace-1"},
+		{Uid: "a1", Type: "injected", Actor: "http:10.0.0.1", TraceId: "trace-1", Commands: "[\"echo 1\",\"echo 2\"]"},
+		{Uid: "a1", Type: "manually-recovered", Actor: "cli:root"},
+	} {
+		if err := db.Insert(event); err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		body      string
+		wantCode  int
+		wantTotal int64
+		wantTypes []string
+	}{
+		{name: "all", body: `{"uid":"a1","trace_id":"trace-query"}`, wantCode: errutil.NoErr, wantTotal: 3,
+			wantTypes: []string{"created", "injected", "manually-recovered"}},
+		{name: "page", body: `{"uid":"a1","offset":1,"limit":1}`, wantCode: errutil.NoErr, wantTotal: 3, wantTypes: []string{"injected"}},
+		{name: "no events", body: `{"uid":"a2"}`, wantCode: errutil.NoErr},
+		{name: "empty uid", body: `{}`, wantCode: errutil.BadArgsErr},
+		{name: "invalid body", body: `{"uid":`, wantCode: errutil.BadArgsErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ExperimentEventsPost(w, httptest.NewRequest(http.MethodPost, "/v1/experiment/events", bytes.NewBufferString(tt.body)))
+
+			res := &model.EventsResponse{}
+			if err := json.NewDecoder(w.Body).Decode(res); err != nil {
+				t.Fatalf("decode response error = %v", err)
+			}
+
+			if res.Code != tt.wantCode {
+				t.Fatalf("ExperimentEventsPost() code = %d, msg = %s, want %d", res.Code, res.Message, tt.wantCode)
+			}
+
+			if tt.wantCode != errutil.NoErr {
+				return
+			}
+
+			if res.Data == nil || res.Data.Total != tt.wantTotal || len(res.Data.Events) != len(tt.wantTypes) {
+				t.Fatalf("ExperimentEventsPost() data = %+v, want total %d and %d events", res.Data, tt.wantTotal, len(tt.wantTypes))
+			}
+
+			for i, event := range res.Data.Events {
+				if event.Type != tt.wantTypes[i] {
+					t.Errorf("event[%d] = %s, want %s", i, event.Type, tt.wantTypes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEventToEventDataUnit(t *testing.T) {
+	unit := EventToEventDataUnit(&storage.Event{Uid: "a1", Type: "injected", Actor: "cli:root", TraceId: "trace-1", Commands: "[\"echo 1\",\"echo 2\"]"})
+	if unit.Actor != "cli:root" || unit.TraceId != "trace-1" || len(unit.Commands) != 2 || unit.Commands[1] != "echo 2" {
+		t.Errorf("EventToEventDataUnit() = %+v, want actor, trace id and command list", unit)
+	}
+
+	// the broken commands are kept as raw value
+	unit = EventToEventDataUnit(&storage.Event{Uid: "a1", Type: "injected", Commands: "echo 1"})
+	if len(unit.Commands) != 1 || unit.Commands[0] != "echo 1" {
+		t.Errorf("EventToEventDataUnit() commands = %v, want the raw value", unit.Commands)
+	}
+}
@@ -93,5 +93,6 @@ func ExpToExperimentDataUnit(exp *storage.Experiment) model.ExperimentDataUnit {
 		UpdateTime:       exp.UpdateTime,
 		ContainerId:      exp.ContainerId,
 		ContainerRuntime: exp.ContainerRuntime,
+		Ramp:             exp.Ramp,
 	}
 }
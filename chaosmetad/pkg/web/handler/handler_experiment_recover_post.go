@@ -40,7 +40,7 @@ func ExperimentRecoverPost(w http.ResponseWriter, r *http.Request) {
 	if err := json.NewDecoder(r.Body).Decode(recoverReq); err != nil {
 		recoverRes = getCommonResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("req body format error: %s", err.Error()))
 	} else {
-		ctx = utils.GetCtxWithTraceId(ctx, recoverReq.TraceId)
+		ctx = getCtxWithHttpActor(utils.GetCtxWithTraceId(ctx, recoverReq.TraceId), r)
 		code, msg := injector.ProcessRecover(ctx, recoverReq.Uid)
 		recoverRes = getCommonResponse(ctx, code, msg)
 	}
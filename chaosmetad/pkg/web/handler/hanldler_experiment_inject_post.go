@@ -41,47 +41,57 @@ func ExperimentInjectPost(w http.ResponseWriter, r *http.Request) {
 	if err := json.NewDecoder(r.Body).Decode(injectReq); err != nil {
 		injectRes = getExperimentInjectPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("req body format error: %s", err.Error()), nil)
 	} else {
-		ctx = utils.GetCtxWithTraceId(ctx, injectReq.TraceId)
-		i, err := injector.NewInjector(injectReq.Target, injectReq.Fault)
-		if err != nil {
-			injectRes = getExperimentInjectPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("get injector error: %s", err.Error()), nil)
-		} else {
-			creator := injectReq.Creator
-			if creator == "" {
-				creator = r.RemoteAddr
-			}
-
-			if err := i.LoadInjector(&storage.Experiment{
-				Uid:              injectReq.Uid,
-				Target:           injectReq.Target,
-				Fault:            injectReq.Fault,
-				Args:             injectReq.Args,
-				Timeout:          injectReq.Timeout,
-				ContainerRuntime: injectReq.ContainerRuntime,
-				ContainerId:      injectReq.ContainerId,
-				Creator:          creator,
-				Runtime:          "{}",
-			}, i.GetArgs(), i.GetRuntime()); err != nil {
-				injectRes = getExperimentInjectPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("args load error: %s", err.Error()), nil)
-			} else {
-				code, msg := injector.ProcessInject(ctx, i)
-				if code == errutil.NoErr {
-					exp, err := i.OptionToExp(i.GetArgs(), i.GetRuntime())
-					if err != nil {
-						injectRes = getExperimentInjectPostResponse(ctx, errutil.NoErr, fmt.Sprintf("inject success but get exp info error: %s", err.Error()), nil)
-					} else {
-						injectRes = getExperimentInjectPostResponse(ctx, errutil.NoErr, "success", exp)
-					}
-				} else {
-					injectRes = getExperimentInjectPostResponse(ctx, errutil.InjectErr, fmt.Sprintf("injector error: %s", msg), nil)
-				}
-			}
-		}
+		ctx = getCtxWithHttpActor(utils.GetCtxWithTraceId(ctx, injectReq.TraceId), r)
+		injectRes = ProcessInjectRequest(ctx, injectReq, r.RemoteAddr)
 	}
 
 	WriteResponse(ctx, w, injectRes)
 }
 
+// ProcessInjectRequest creates an experiment by the request, defaultCreator is used when the creator of request is empty
+func ProcessInjectRequest(ctx context.Context, injectReq *model.InjectRequest, defaultCreator string) *model.InjectResponse {
+	i, err := injector.NewInjector(injectReq.Target, injectReq.Fault)
+	if err != nil {
+		return getExperimentInjectPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("get injector error: %s", err.Error()), nil)
+	}
+
+	creator := injectReq.Creator
+	if creator == "" {
+		creator = defaultCreator
+	}
+
+	if err := i.LoadInjector(&storage.Experiment{
+		Uid:              injectReq.Uid,
+		Target:           injectReq.Target,
+		Fault:            injectReq.Fault,
+		Args:             injectReq.Args,
+		Timeout:          injectReq.Timeout,
+		ContainerRuntime: injectReq.ContainerRuntime,
+		ContainerId:      injectReq.ContainerId,
+		Creator:          creator,
+		Runtime:          "{}",
+		Ramp:             injectReq.Ramp,
+	}, i.GetArgs(), i.GetRuntime()); err != nil {
+		return getExperimentInjectPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("args load error: %s", err.Error()), nil)
+	}
+
+	code, msg := injector.ProcessInject(ctx, i)
+	if code == errutil.ConflictErr {
+		return getExperimentInjectPostResponse(ctx, code, msg, nil)
+	}
+
+	if code != errutil.NoErr {
+		return getExperimentInjectPostResponse(ctx, errutil.InjectErr, fmt.Sprintf("injector error: %s", msg), nil)
+	}
+
+	exp, err := i.OptionToExp(i.GetArgs(), i.GetRuntime())
+	if err != nil {
+		return getExperimentInjectPostResponse(ctx, errutil.NoErr, fmt.Sprintf("inject success but get exp info error: %s", err.Error()), nil)
+	}
+
+	return getExperimentInjectPostResponse(ctx, errutil.NoErr, "success", exp)
+}
+
 func getExperimentInjectPostResponse(ctx context.Context, code int, msg string, exp *storage.Experiment) *model.InjectResponse {
 	var re = &model.InjectResponse{
 		Code:    code,
@@ -30,4 +30,5 @@ type ExperimentDataUnit struct {
 	UpdateTime       string `json:"update_time,omitempty"`
 	ContainerId      string `json:"container_id,omitempty"`
 	ContainerRuntime string `json:"container_runtime,omitempty"`
+	Ramp             string `json:"ramp,omitempty"`
 }
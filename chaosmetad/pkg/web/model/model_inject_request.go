@@ -26,4 +26,5 @@ type InjectRequest struct {
 	ContainerRuntime string `json:"container_runtime"`
 	TraceId          string `json:"trace_id"`
 	Uid              string `json:"uid"`
+	Ramp             string `json:"ramp,omitempty"`
 }
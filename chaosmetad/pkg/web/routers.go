@@ -91,6 +91,13 @@ var routes = Routes{
 		handler.ExperimentRecoverPost,
 	},
 
+	Route{
+		"ExperimentEventsPost",
+		strings.ToUpper("Post"),
+		"/v1/experiment/events",
+		handler.ExperimentEventsPost,
+	},
+
 	Route{
 		"VersionGet",
 		strings.ToUpper("Get"),
@@ -0,0 +1,116 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/tools/common"
+	"golang.org/x/sys/unix"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const dropCachesFile = "/proc/sys/vm/drop_caches"
+
+// [uid] [interval second] [timeout] [file list, split by ",", empty means the whole system]
+func main() {
+	args := os.Args
+	if len(args) < 4 {
+		common.ExitWithErr("must provide at least 3 args: uid、interval、timeout")
+	}
+
+	interval, err := strconv.Atoi(args[2])
+	if err != nil || interval <= 0 {
+		common.ExitWithErr("interval must be a positive int")
+	}
+
+	timeout, err := strconv.Atoi(args[3])
+	if err != nil {
+		common.ExitWithErr(fmt.Sprintf("timeout value is not a valid int, error: %s", err.Error()))
+	}
+
+	var fileList []string
+	if len(args) > 4 {
+		for _, f := range strings.Split(args[4], ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				fileList = append(fileList, f)
+			}
+		}
+	}
+
+	// fail fast, the errors after inject success are ignored because the files may be deleted at any time
+	if err := drop(fileList); err != nil {
+		common.ExitWithErr(fmt.Sprintf("drop cache error: %s", err.Error()))
+	}
+
+	go func() {
+		for {
+			time.Sleep(time.Duration(interval) * time.Second)
+			_ = drop(fileList)
+		}
+	}()
+
+	fmt.Println("[success]inject success")
+
+	common.SleepWait(timeout)
+}
+
+func drop(fileList []string) error {
+	if len(fileList) == 0 {
+		// only the clean pages can be dropped
+		syscall.Sync()
+		return os.WriteFile(dropCachesFile, []byte("1"), 0644)
+	}
+
+	for _, root := range fileList {
+		if err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !d.Type().IsRegular() {
+				return nil
+			}
+
+			return dropFile(path)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func dropFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_ = unix.Fdatasync(int(f.Fd()))
+	if err := unix.Fadvise(int(f.Fd()), 0, 0, unix.FADV_DONTNEED); err != nil {
+		return fmt.Errorf("fadvise %s error: %s", path, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/faultproxy"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/grpcproxy"
+	"github.com/traas-stack/chaosmeta/chaosmetad/tools/common"
+	"os"
+)
+
+// [uid] [proxy port] [port] [mark] [rule] [timeout]
+func main() {
+	args := os.Args
+	if len(args) < 7 {
+		common.ExitWithErr("must provide 6 args: uid、proxy port、port、mark、rule、timeout")
+	}
+
+	common.RunProxy(args[2:], func(upstream faultproxy.UpstreamFunc, mark int, r string) (faultproxy.Server, error) {
+		rule, err := grpcproxy.DecodeRule(r)
+		if err != nil {
+			return nil, fmt.Errorf("decode rule error: %s", err.Error())
+		}
+
+		return grpcproxy.NewProxy(upstream, mark, rule)
+	})
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/tools/common"
+	"os"
+	"runtime"
+	"strconv"
+)
+
+// [uid] [buffer size: KB/MB/GB] [timeout]
+func main() {
+	args := os.Args
+	if len(args) < 4 {
+		common.ExitWithErr("must provide 3 args: uid、size、timeout")
+	}
+
+	kBytes, err := utils.GetKBytes(args[2])
+	if err != nil || kBytes <= 0 {
+		common.ExitWithErr(fmt.Sprintf("size[%s] is invalid", args[2]))
+	}
+
+	timeout, err := strconv.Atoi(args[3])
+	if err != nil {
+		common.ExitWithErr(fmt.Sprintf("timeout value is not a valid int, error: %s", err.Error()))
+	}
+
+	// two buffers much larger than the cpu cache, so that every copy goes through the memory
+	half := int(kBytes * 1024 / 2)
+	src, dst := make([]byte, half), make([]byte, half)
+	for j := range src {
+		src[j] = byte(j)
+	}
+
+	go func() {
+		for {
+			copy(dst, src)
+			copy(src, dst)
+			// memmove can not be preempted, yield to make the timeout work on a single core
+			runtime.Gosched()
+		}
+	}()
+
+	fmt.Println("[success]inject success")
+
+	common.SleepWait(timeout)
+}
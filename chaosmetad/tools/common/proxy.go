@@ -0,0 +1,70 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/faultproxy"
+	"strconv"
+)
+
+// NewProxyFunc creates the proxy forwarding to upstream from the encoded rule
+type NewProxyFunc func(upstream faultproxy.UpstreamFunc, mark int, rule string) (faultproxy.Server, error)
+
+// RunProxy is the main of the proxy tools, args: [proxy port] [port] [mark] [rule] [timeout].
+// The connections redirected from "port" are forwarded to their original destination, and the ones connecting
+// to "proxy port" directly are rejected, so that the proxy can not be used to reach other addresses
+func RunProxy(args []string, newProxy NewProxyFunc) {
+	pp, p, m, r, t := args[0], args[1], args[2], args[3], args[4]
+	proxyPort, err := strconv.Atoi(pp)
+	if err != nil || proxyPort <= 0 {
+		ExitWithErr("proxy port is invalid")
+	}
+
+	port, err := strconv.Atoi(p)
+	if err != nil || port <= 0 {
+		ExitWithErr("port is invalid")
+	}
+
+	mark, err := strconv.Atoi(m)
+	if err != nil {
+		ExitWithErr(fmt.Sprintf("mark value is not a valid int, error: %s", err.Error()))
+	}
+
+	timeout, err := strconv.Atoi(t)
+	if err != nil {
+		ExitWithErr(fmt.Sprintf("timeout value is not a valid int, error: %s", err.Error()))
+	}
+
+	proxy, err := newProxy(faultproxy.RedirectedUpstream(port), mark, r)
+	if err != nil {
+		ExitWithErr(fmt.Sprintf("create proxy error: %s", err.Error()))
+	}
+
+	_, errCh, err := faultproxy.Start(fmt.Sprintf(":%d", proxyPort), proxy)
+	if err != nil {
+		ExitWithErr(fmt.Sprintf("tcp listen on %d error: %s", proxyPort, err.Error()))
+	}
+
+	go func() {
+		ExitWithErr(fmt.Sprintf("proxy serve error: %s", (<-errCh).Error()))
+	}()
+
+	fmt.Println("[success]inject success")
+
+	SleepWait(timeout)
+}
@@ -46,6 +46,11 @@ public class ChaosMetaClassFileTransformer implements ClassFileTransformer {
 
     private void doInject(CtMethod ctMethod, ChaosMetaJVMMethodRule methodRule) throws Exception {
         System.out.printf("inject fault: %s\n", methodRule.fault);
+        if (methodRule.hasCondition()) {
+            doConditionalInject(ctMethod, methodRule);
+            return;
+        }
+
         switch (methodRule.fault) {
             case ChaosMetaJVMMethodRule.InsertBeforeFault:
                 ctMethod.insertBefore(methodRule.content);
@@ -64,6 +69,28 @@ public class ChaosMetaClassFileTransformer implements ClassFileTransformer {
         }
     }
 
+    // the content only takes effect when the gate of the rule passes, so setBody is converted to a return before the origin body
+    private void doConditionalInject(CtMethod ctMethod, ChaosMetaJVMMethodRule methodRule) throws Exception {
+        String content = String.format("if (ChaosMetaJVMRuleGate.pass(\"%s\", $args)) { %s }", methodRule.id, methodRule.content);
+        switch (methodRule.fault) {
+            case ChaosMetaJVMMethodRule.InsertBeforeFault:
+            case ChaosMetaJVMMethodRule.SetBodyFault:
+                ctMethod.insertBefore(content);
+                break;
+            case ChaosMetaJVMMethodRule.InsertAfterFault:
+                ctMethod.insertAfter(content);
+                break;
+            case ChaosMetaJVMMethodRule.InsertAtFault:
+                ctMethod.insertAt(methodRule.lineNum, content);
+                break;
+            default:
+                throw new Exception("not support fault: " + methodRule.fault);
+        }
+    }
+
+    public ChaosMetaJVMMethodRule[] getMethodRules() {
+        return methodRules;
+    }
 
     public String getMessage() {
         return message;
@@ -8,7 +8,10 @@ import java.lang.instrument.Instrumentation;
 import java.lang.reflect.Constructor;
 import java.time.Duration;
 import java.time.LocalDateTime;
+import java.util.ArrayList;
+import java.util.Collections;
 import java.util.HashMap;
+import java.util.List;
 import java.util.Map;
 
 public class ChaosMetaJVMAgent {
@@ -21,7 +24,14 @@ public class ChaosMetaJVMAgent {
 
         long durationSecond;
         JSONObject ruleJson = getRuleJson(args);
-        JSONArray classRuleList = ruleJson.getJSONArray("ClassList");
+        JSONArray classRuleList = ruleJson.optJSONArray("ClassList");
+        if (classRuleList == null) {
+            classRuleList = new JSONArray();
+        }
+        JSONArray resourceRuleList = ruleJson.optJSONArray("ResourceList");
+        if (resourceRuleList == null) {
+            resourceRuleList = new JSONArray();
+        }
         durationSecond = ruleJson.getLong("Duration");
         if (durationSecond < 0) {
             throw new Exception("Duration must >= 0");
@@ -43,17 +53,26 @@ public class ChaosMetaJVMAgent {
         // do inject
 //        boolean hasInjected;
         Map<Class, ClassFileTransformer> injectedTransformerMap = getTransformerMap(classRuleList, loadedClassesMap, constructor);
+        List<ChaosMetaJVMResourceRule> resourceRules = getResourceRules(resourceRuleList, loadedClassesMap);
+        registerGates(injectedTransformerMap);
         try {
             doInject(inst, injectedTransformerMap);
+            startResourceRules(resourceRules);
 //            hasInjected = true;
         } catch (Exception e) {
             String errMsg = String.format("[error]inject execute fail: %s", e.getMessage());
             System.out.println(errMsg);
             doRecover(inst, injectedTransformerMap);
+            stopResourceRules(resourceRules);
+            ChaosMetaJVMRuleGate.unregisterAll();
 //            hasInjected = false;
             throw new Exception(errMsg);
         }
 
+        File configFile = new File(args);
+        long lastModified = configFile.lastModified();
+        String classRuleStr = classRuleList.toString();
+        String resourceRuleStr = resourceRuleList.toString();
         while (true) {
             Boolean ifTimeout = isTimeout(startTime, durationSecond);
             FileReader fileReader = null;
@@ -65,6 +84,8 @@ public class ChaosMetaJVMAgent {
 
             if (fileReader == null || ifTimeout) {
                 doRecover(inst, injectedTransformerMap);
+                stopResourceRules(resourceRules);
+                ChaosMetaJVMRuleGate.unregisterAll();
                 if (fileReader != null) {
                     fileReader.close();
                 }
@@ -72,6 +93,39 @@ public class ChaosMetaJVMAgent {
             }
 
             fileReader.close();
+
+            // the rule file is rewritten when the rules of a running experiment are updated
+            if (configFile.lastModified() != lastModified) {
+                lastModified = configFile.lastModified();
+                JSONArray newClassRuleList;
+                JSONArray newResourceRuleList;
+                try {
+                    JSONObject newRuleJson = getRuleJson(args);
+                    newClassRuleList = newRuleJson.optJSONArray("ClassList");
+                    if (newClassRuleList == null) {
+                        newClassRuleList = new JSONArray();
+                    }
+                    newResourceRuleList = newRuleJson.optJSONArray("ResourceList");
+                    if (newResourceRuleList == null) {
+                        newResourceRuleList = new JSONArray();
+                    }
+                } catch (Exception e) {
+                    System.out.printf("[error]reload config file fail, keep the old rules: %s\n", e.getMessage());
+                    Thread.sleep(2000);
+                    continue;
+                }
+
+                if (!newClassRuleList.toString().equals(classRuleStr)) {
+                    injectedTransformerMap = reloadClassRules(inst, injectedTransformerMap, newClassRuleList, loadedClassesMap, constructor);
+                    classRuleStr = newClassRuleList.toString();
+                }
+
+                if (!newResourceRuleList.toString().equals(resourceRuleStr)) {
+                    resourceRules = reloadResourceRules(resourceRules, newResourceRuleList, loadedClassesMap);
+                    resourceRuleStr = newResourceRuleList.toString();
+                }
+            }
+
             Thread.sleep(2000);
         }
 
@@ -79,6 +133,85 @@ public class ChaosMetaJVMAgent {
         System.out.println("[info]agentmain finish");
     }
 
+    // If only the conditions of the rules are changed, the gates are updated without transforming the classes again
+    private static Map<Class, ClassFileTransformer> reloadClassRules(Instrumentation inst, Map<Class, ClassFileTransformer> oldTransformerMap, JSONArray newClassRuleList, Map<String, Class> loadedClassesMap, Constructor<?> constructor) {
+        System.out.println("[info]class rules changed, start to reload");
+        Map<Class, ClassFileTransformer> newTransformerMap;
+        try {
+            newTransformerMap = getTransformerMap(newClassRuleList, loadedClassesMap, constructor);
+        } catch (Exception e) {
+            System.out.printf("[error]parse new class rules fail, keep the old rules: %s\n", e.getMessage());
+            return oldTransformerMap;
+        }
+
+        if (getSignature(newTransformerMap).equals(getSignature(oldTransformerMap))) {
+            ChaosMetaJVMRuleGate.unregisterAll();
+            registerGates(newTransformerMap);
+            System.out.println("[info]update rule conditions success");
+            return newTransformerMap;
+        }
+
+        doRecover(inst, oldTransformerMap);
+        ChaosMetaJVMRuleGate.unregisterAll();
+        registerGates(newTransformerMap);
+        try {
+            doInject(inst, newTransformerMap);
+        } catch (Exception e) {
+            System.out.printf("[error]inject new class rules fail: %s\n", e.getMessage());
+            doRecover(inst, newTransformerMap);
+            ChaosMetaJVMRuleGate.unregisterAll();
+            return new HashMap<>();
+        }
+
+        System.out.println("[info]reload class rules success");
+        return newTransformerMap;
+    }
+
+    private static List<ChaosMetaJVMResourceRule> reloadResourceRules(List<ChaosMetaJVMResourceRule> oldResourceRules, JSONArray newResourceRuleList, Map<String, Class> loadedClassesMap) {
+        System.out.println("[info]resource rules changed, start to reload");
+        List<ChaosMetaJVMResourceRule> newResourceRules;
+        try {
+            newResourceRules = getResourceRules(newResourceRuleList, loadedClassesMap);
+        } catch (Exception e) {
+            System.out.printf("[error]parse new resource rules fail, keep the old rules: %s\n", e.getMessage());
+            return oldResourceRules;
+        }
+
+        stopResourceRules(oldResourceRules);
+        try {
+            startResourceRules(newResourceRules);
+        } catch (Exception e) {
+            System.out.printf("[error]start new resource rules fail: %s\n", e.getMessage());
+            stopResourceRules(newResourceRules);
+            return new ArrayList<>();
+        }
+
+        System.out.println("[info]reload resource rules success");
+        return newResourceRules;
+    }
+
+    private static String getSignature(Map<Class, ClassFileTransformer> transformerMap) {
+        List<String> signatures = new ArrayList<>();
+        for (Map.Entry<Class, ClassFileTransformer> entry : transformerMap.entrySet()) {
+            for (ChaosMetaJVMMethodRule methodRule : ((ChaosMetaClassFileTransformer) entry.getValue()).getMethodRules()) {
+                signatures.add(entry.getKey().getName() + "|" + methodRule.getSignature());
+            }
+        }
+
+        Collections.sort(signatures);
+        return String.join(",", signatures);
+    }
+
+    private static void registerGates(Map<Class, ClassFileTransformer> transformerMap) {
+        for (ClassFileTransformer transformer : transformerMap.values()) {
+            for (ChaosMetaJVMMethodRule methodRule : ((ChaosMetaClassFileTransformer) transformer).getMethodRules()) {
+                if (methodRule.hasCondition()) {
+                    ChaosMetaJVMRuleGate.register(methodRule);
+                }
+            }
+        }
+    }
+
     private static Boolean isTimeout(LocalDateTime startTime, long durationSecond) {
         if (durationSecond == 0) {
             return false;
@@ -107,6 +240,7 @@ public class ChaosMetaJVMAgent {
             ChaosMetaJVMMethodRule[] methodRules = new ChaosMetaJVMMethodRule[methodList.length()];
             for (int i = 0; i < methodList.length(); i++) {
                 methodRules[i] = new ChaosMetaJVMMethodRule((JSONObject) methodList.get(i));
+                methodRules[i].id = String.format("%s@%s@%d", className, methodRules[i].method, i);
             }
 
             Class targetLoadedClass = loadedClassesMap.get(className);
@@ -121,6 +255,27 @@ public class ChaosMetaJVMAgent {
         return injectedTransformerMap;
     }
 
+    private static List<ChaosMetaJVMResourceRule> getResourceRules(JSONArray resourceRuleList, Map<String, Class> loadedClassesMap) throws Exception {
+        List<ChaosMetaJVMResourceRule> resourceRules = new ArrayList<>();
+        for (Object resourceRule : resourceRuleList) {
+            resourceRules.add(new ChaosMetaJVMResourceRule((JSONObject) resourceRule, loadedClassesMap));
+        }
+
+        return resourceRules;
+    }
+
+    private static void startResourceRules(List<ChaosMetaJVMResourceRule> resourceRules) throws Exception {
+        for (ChaosMetaJVMResourceRule resourceRule : resourceRules) {
+            resourceRule.start();
+        }
+    }
+
+    private static void stopResourceRules(List<ChaosMetaJVMResourceRule> resourceRules) {
+        for (ChaosMetaJVMResourceRule resourceRule : resourceRules) {
+            resourceRule.stop();
+        }
+    }
+
     private static void doInject(Instrumentation inst, Map<Class, ClassFileTransformer> injectedTransformerMap) throws Exception {
         System.out.println("[info]start to inject");
         for (Map.Entry<Class, ClassFileTransformer> entry : injectedTransformerMap.entrySet()) {
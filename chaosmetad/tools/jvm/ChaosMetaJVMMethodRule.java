@@ -1,11 +1,26 @@
 import org.json.JSONObject;
 
+import java.util.ArrayList;
+import java.util.List;
+import java.util.concurrent.ThreadLocalRandom;
+import java.util.concurrent.atomic.AtomicLong;
+import java.util.regex.Matcher;
+import java.util.regex.Pattern;
+
 public class ChaosMetaJVMMethodRule {
+    String id;
     String method;
     String fault;
     String content;
     int lineNum;
     String importPkg;
+    String condition;
+    int percent;
+    long afterCalls;
+
+    private final List<ArgMatcher> argMatchers = new ArrayList<>();
+
+    private final AtomicLong calls = new AtomicLong();
 
     public static final String InsertAtFault = "insertAt";
     public static final String InsertAfterFault = "insertAfter";
@@ -20,6 +35,15 @@ public class ChaosMetaJVMMethodRule {
 
     public static final String ImportPkgKey = "ImportPkg";
 
+    public static final String ConditionKey = "Condition";
+    public static final String PercentKey = "Percent";
+    public static final String AfterCallsKey = "AfterCalls";
+
+    public static final String ConditionSplit = "&&";
+
+    // format: args[0]=="userA" or args[1]!=3
+    private static final Pattern ArgConditionPattern = Pattern.compile("^args\\[(\\d+)\\]\\s*(==|!=)\\s*(.+)$");
+
 
     public ChaosMetaJVMMethodRule(JSONObject jsonObject) throws Exception {
         if (jsonObject == null) {
@@ -53,5 +77,73 @@ public class ChaosMetaJVMMethodRule {
         if (jsonObject.has(ImportPkgKey)) {
             importPkg = jsonObject.getString(ImportPkgKey);
         }
+
+        condition = jsonObject.optString(ConditionKey, "");
+        percent = jsonObject.optInt(PercentKey, 0);
+        afterCalls = jsonObject.optLong(AfterCallsKey, 0);
+        if (percent < 0 || percent > 100) {
+            throw new Exception("\"Percent\" must in [0,100]");
+        }
+
+        if (afterCalls < 0) {
+            throw new Exception("\"AfterCalls\" must >= 0");
+        }
+
+        if (!condition.isEmpty()) {
+            for (String unit : condition.split(ConditionSplit)) {
+                argMatchers.add(new ArgMatcher(unit.trim()));
+            }
+        }
+    }
+
+    public boolean hasCondition() {
+        return !condition.isEmpty() || percent > 0 || afterCalls > 0;
+    }
+
+    // getSignature return the part of rule which needs to transform class, conditions are not included
+    public String getSignature() {
+        return String.format("%s|%s|%s|%d|%s|%b", method, fault, content, lineNum, importPkg, hasCondition());
+    }
+
+    public boolean match(Object[] args) {
+        if (calls.incrementAndGet() <= afterCalls) {
+            return false;
+        }
+
+        for (ArgMatcher argMatcher : argMatchers) {
+            if (!argMatcher.match(args)) {
+                return false;
+            }
+        }
+
+        return percent <= 0 || ThreadLocalRandom.current().nextInt(100) < percent;
+    }
+
+    private static class ArgMatcher {
+        int index;
+        boolean equal;
+        String value;
+
+        ArgMatcher(String expr) throws Exception {
+            Matcher matcher = ArgConditionPattern.matcher(expr);
+            if (!matcher.matches()) {
+                throw new Exception(String.format("condition \"%s\" is not valid, format: args[index]==value", expr));
+            }
+
+            index = Integer.parseInt(matcher.group(1));
+            equal = matcher.group(2).equals("==");
+            value = matcher.group(3).trim();
+            if (value.length() >= 2 && value.startsWith("\"") && value.endsWith("\"")) {
+                value = value.substring(1, value.length() - 1);
+            }
+        }
+
+        boolean match(Object[] args) {
+            if (args == null || index >= args.length) {
+                return false;
+            }
+
+            return String.valueOf(args[index]).equals(value) == equal;
+        }
     }
 }
\ No newline at end of file
@@ -0,0 +1,198 @@
+import org.json.JSONObject;
+
+import java.lang.reflect.Field;
+import java.lang.reflect.Modifier;
+import java.util.ArrayList;
+import java.util.List;
+import java.util.Map;
+import java.util.concurrent.RejectedExecutionException;
+import java.util.concurrent.ThreadPoolExecutor;
+
+public class ChaosMetaJVMResourceRule {
+    String fault;
+    String target;
+    int count;
+    int percent;
+    int interval;
+
+    public static final String ThreadPoolFullFault = "threadpoolfull";
+    public static final String HeapFillFault = "heapfill";
+    public static final String GCStormFault = "gcstorm";
+
+    public static final String FaultKey = "Fault";
+    public static final String TargetKey = "Target";
+    public static final String CountKey = "Count";
+    public static final String PercentKey = "Percent";
+    public static final String IntervalKey = "Interval";
+
+    private static final int HeapFillUnit = 1024 * 1024;
+    private static final long WorkerCheckInterval = 100;
+    // limits of the submitted tasks, the pools with unbounded threads or queue can never be full
+    private static final int MaxPoolFill = 10000;
+    private static final int MaxQueueFill = 10000;
+
+    private volatile boolean running;
+
+    private final List<Thread> workers = new ArrayList<>();
+
+    private final List<byte[]> retainedHeap = new ArrayList<>();
+
+    private ThreadPoolExecutor targetPool;
+
+    public ChaosMetaJVMResourceRule(JSONObject jsonObject, Map<String, Class> loadedClassesMap) throws Exception {
+        if (jsonObject == null) {
+            throw new Exception("must not null");
+        }
+
+        if (!jsonObject.has(FaultKey)) {
+            throw new Exception("fault is not provide");
+        }
+
+        fault = jsonObject.getString(FaultKey);
+        target = jsonObject.optString(TargetKey, "");
+        count = jsonObject.optInt(CountKey, 0);
+        percent = jsonObject.optInt(PercentKey, 0);
+        interval = jsonObject.optInt(IntervalKey, 0);
+
+        switch (fault) {
+            case ThreadPoolFullFault:
+                if (!target.isEmpty()) {
+                    targetPool = getTargetPool(target, loadedClassesMap);
+                } else if (count <= 0) {
+                    throw new Exception("must provide \"Target\" or \"Count\" in \"threadpoolfull\" fault");
+                }
+                break;
+            case HeapFillFault:
+                if (percent <= 0 || percent >= 100) {
+                    throw new Exception("\"Percent\" must in (0,100)");
+                }
+                break;
+            case GCStormFault:
+                if (interval <= 0) {
+                    throw new Exception("\"Interval\" must > 0");
+                }
+                break;
+            default:
+                throw new Exception("not support fault: " + fault);
+        }
+    }
+
+    // target format: class@field, the field must be a static ThreadPoolExecutor
+    private static ThreadPoolExecutor getTargetPool(String target, Map<String, Class> loadedClassesMap) throws Exception {
+        String[] kv = target.split("@");
+        if (kv.length != 2) {
+            throw new Exception(String.format("\"%s\" is not a valid format, format: class@field", target));
+        }
+
+        Class c = loadedClassesMap.get(kv[0]);
+        if (c == null) {
+            throw new Exception(String.format("not found class %s in JVM", kv[0]));
+        }
+
+        Field field = c.getDeclaredField(kv[1]);
+        if (!Modifier.isStatic(field.getModifiers())) {
+            throw new Exception(String.format("field %s is not static", target));
+        }
+
+        field.setAccessible(true);
+        Object pool = field.get(null);
+        if (!(pool instanceof ThreadPoolExecutor)) {
+            throw new Exception(String.format("field %s is not a ThreadPoolExecutor", target));
+        }
+
+        return (ThreadPoolExecutor) pool;
+    }
+
+    public void start() throws Exception {
+        running = true;
+        System.out.printf("[info]start resource fault: %s\n", fault);
+        switch (fault) {
+            case ThreadPoolFullFault:
+                if (targetPool != null) {
+                    fillPool();
+                } else {
+                    for (int i = 0; i < count; i++) {
+                        startWorker(() -> {
+                            while (running) {
+                            }
+                        });
+                    }
+                }
+                break;
+            case HeapFillFault:
+                fillHeap();
+                break;
+            case GCStormFault:
+                startWorker(() -> {
+                    while (running) {
+                        System.gc();
+                        sleepQuietly(interval);
+                    }
+                });
+                break;
+            default:
+                throw new Exception("not support fault: " + fault);
+        }
+    }
+
+    public void stop() {
+        System.out.printf("[info]stop resource fault: %s\n", fault);
+        running = false;
+        for (Thread worker : workers) {
+            try {
+                worker.join(WorkerCheckInterval * 10);
+            } catch (InterruptedException ignored) {
+            }
+        }
+        workers.clear();
+
+        if (!retainedHeap.isEmpty()) {
+            retainedHeap.clear();
+            System.gc();
+        }
+    }
+
+    // submit blocking tasks until all threads of the pool are busy and the queue is full
+    private void fillPool() {
+        long submitCount = (long) Math.min(targetPool.getMaximumPoolSize(), MaxPoolFill)
+                + Math.min(targetPool.getQueue().remainingCapacity(), MaxQueueFill);
+        for (long i = 0; i < submitCount; i++) {
+            try {
+                targetPool.execute(() -> {
+                    while (running) {
+                        sleepQuietly(WorkerCheckInterval);
+                    }
+                });
+            } catch (RejectedExecutionException e) {
+                break;
+            }
+        }
+    }
+
+    private void fillHeap() throws Exception {
+        Runtime runtime = Runtime.getRuntime();
+        long targetBytes = runtime.maxMemory() / 100 * percent;
+        try {
+            while (runtime.totalMemory() - runtime.freeMemory() < targetBytes) {
+                retainedHeap.add(new byte[HeapFillUnit]);
+            }
+        } catch (OutOfMemoryError e) {
+            retainedHeap.clear();
+            throw new Exception("fill heap out of memory");
+        }
+    }
+
+    private void startWorker(Runnable runnable) {
+        Thread worker = new Thread(runnable, "chaosmeta-" + fault);
+        worker.setDaemon(true);
+        worker.start();
+        workers.add(worker);
+    }
+
+    private static void sleepQuietly(long ms) {
+        try {
+            Thread.sleep(ms);
+        } catch (InterruptedException ignored) {
+        }
+    }
+}
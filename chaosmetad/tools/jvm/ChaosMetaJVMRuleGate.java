@@ -0,0 +1,25 @@
+import java.util.Map;
+import java.util.concurrent.ConcurrentHashMap;
+
+// Conditional method rules are compiled into the target method as a call of pass, so that the conditions can be
+// updated without transforming the class again
+public class ChaosMetaJVMRuleGate {
+    private static final Map<String, ChaosMetaJVMMethodRule> rules = new ConcurrentHashMap<>();
+
+    public static void register(ChaosMetaJVMMethodRule rule) {
+        rules.put(rule.id, rule);
+    }
+
+    public static void unregisterAll() {
+        rules.clear();
+    }
+
+    public static boolean pass(String id, Object[] args) {
+        ChaosMetaJVMMethodRule rule = rules.get(id);
+        if (rule == null) {
+            return false;
+        }
+
+        return rule.match(args);
+    }
+}